@@ -29,6 +29,7 @@ import (
 	"regexp"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/net/http2"
 	"k8s.io/client-go/tools/cache"
 	certificate2 "k8s.io/client-go/util/certificate"
 	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
@@ -41,6 +42,7 @@ import (
 	"kubevirt.io/kubevirt/pkg/certificates/bootstrap"
 	"kubevirt.io/kubevirt/pkg/controller"
 	"kubevirt.io/kubevirt/pkg/service"
+	tlsutil "kubevirt.io/kubevirt/pkg/util/tls"
 	webhooksutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 )
 
@@ -56,11 +58,12 @@ const (
 
 type exportProxyApp struct {
 	service.ServiceListen
-	tlsCertFilePath string
-	tlsKeyFilePath  string
-	certManager     certificate2.Manager
-	caManager       webhooksutils.ClientCAManager
-	exportInformer  cache.SharedIndexInformer
+	tlsCertFilePath  string
+	tlsKeyFilePath   string
+	certManager      certificate2.Manager
+	caManager        webhooksutils.ClientCAManager
+	exportInformer   cache.SharedIndexInformer
+	kubeVirtInformer cache.SharedIndexInformer
 }
 
 func NewExportProxyApp() service.Service {
@@ -91,17 +94,12 @@ func (app *exportProxyApp) Run() {
 	mux.Handle("/metrics", promhttp.Handler())
 
 	server := &http.Server{
-		Addr:    app.Address(),
-		Handler: mux,
-		TLSConfig: &tls.Config{
-			GetCertificate: func(info *tls.ClientHelloInfo) (certificate *tls.Certificate, err error) {
-				cert := app.certManager.Current()
-				if cert == nil {
-					return nil, fmt.Errorf("error getting cert")
-				}
-				return cert, nil
-			},
-		},
+		Addr:      app.Address(),
+		Handler:   mux,
+		TLSConfig: tlsutil.SetupExportProxyTLS(app.certManager, app.kubeVirtInformer),
+	}
+	if err := http2.ConfigureServer(server, &http2.Server{}); err != nil {
+		panic(err)
 	}
 
 	if err := server.ListenAndServeTLS("", ""); err != nil {
@@ -149,6 +147,19 @@ func (app *exportProxyApp) proxyHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			RootCAs: certPool,
+		},
+	}
+	// Setting TLSClientConfig above opts this Transport out of Go's automatic HTTP/2 support, so
+	// configure it explicitly to forward HTTP/2 all the way through to the exporter.
+	if err := http2.ConfigureTransport(transport); err != nil {
+		log.Log.Reason(err).Error("failed to configure HTTP/2 for the export proxy transport")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	p := &httputil.ReverseProxy{
 		Director: func(req *http.Request) {
 			req.URL.Scheme = "https"
@@ -160,11 +171,7 @@ func (app *exportProxyApp) proxyHandler(w http.ResponseWriter, r *http.Request)
 				req.Header.Set("User-Agent", "")
 			}
 		},
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: certPool,
-			},
-		},
+		Transport: transport,
 	}
 
 	p.ServeHTTP(w, r)
@@ -189,6 +196,7 @@ func (app *exportProxyApp) prepareInformers(stopChan <-chan struct{}) {
 	kubeInformerFactory := controller.NewKubeInformerFactory(virtCli.RestClient(), virtCli, aggregatorClient, namespace)
 	caInformer := kubeInformerFactory.KubeVirtExportCAConfigMap()
 	app.exportInformer = kubeInformerFactory.VirtualMachineExport()
+	app.kubeVirtInformer = kubeInformerFactory.KubeVirt()
 	kubeInformerFactory.Start(stopChan)
 	kubeInformerFactory.WaitForCacheSync(stopChan)
 
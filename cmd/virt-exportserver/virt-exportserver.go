@@ -20,13 +20,18 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	v1 "kubevirt.io/api/core/v1"
 	"kubevirt.io/client-go/log"
 
 	"kubevirt.io/kubevirt/pkg/service"
+	tlsutil "kubevirt.io/kubevirt/pkg/util/tls"
 
 	exportServer "kubevirt.io/kubevirt/pkg/storage/export/virt-exportserver"
 )
@@ -41,12 +46,38 @@ func main() {
 
 	certFile, keyFile := getCert()
 	config := exportServer.ExportServerConfig{
-		CertFile:   certFile,
-		KeyFile:    keyFile,
-		Deadline:   getDeadline(),
-		ListenAddr: getListenAddr(),
-		TokenFile:  getTokenFile(),
-		Volumes:    getVolumeInfo(),
+		CertFile:                    certFile,
+		KeyFile:                     keyFile,
+		Deadline:                    getDeadline(),
+		ListenAddr:                  getListenAddr(),
+		TokenFile:                   getTokenFile(),
+		AdditionalTokenFiles:        getAdditionalTokenFiles(),
+		ScopedTokens:                getScopedTokens(),
+		Volumes:                     getVolumeInfo(),
+		S3Upload:                    getS3UploadConfig(),
+		RegistryUpload:              getRegistryUploadConfig(),
+		ClusterUpload:               getClusterUploadConfig(),
+		Ova:                         getOvaConfig(),
+		AllURI:                      getAllURI(),
+		ManifestURI:                 getManifestURI(),
+		ManifestFile:                os.Getenv("MANIFEST_FILE"),
+		ExpandedManifestURI:         getExpandedManifestURI(),
+		ExpandedManifestFile:        os.Getenv("EXPANDED_MANIFEST_FILE"),
+		EncryptionKeyFile:           os.Getenv("ENCRYPTION_KEY_FILE"),
+		ZstdCompressionLevel:        getZstdCompressionLevel(),
+		GzipCompressionLevel:        getGzipCompressionLevel(),
+		ShutdownGracePeriod:         getShutdownGracePeriod(),
+		PerConnectionBandwidthLimit: getBandwidthLimit("PER_CONNECTION_BANDWIDTH_LIMIT"),
+		TotalBandwidthLimit:         getBandwidthLimit("TOTAL_BANDWIDTH_LIMIT"),
+		PerClientIPRequestLimit:     getPerClientIPRequestLimit(),
+		PerClientIPRequestBurst:     getPerClientIPRequestBurst(),
+		MaxConcurrentDownloads:      getMaxConcurrentDownloads(),
+		PrecomputeFormats:           splitCommaList(os.Getenv("PRECOMPUTE_FORMATS")),
+		MinTLSVersion:               getMinTLSVersion(),
+		CipherSuites:                tlsutil.CipherSuiteIds(getTLSCiphers()),
+		ClientCAFile:                os.Getenv("CLIENT_CA_FILE"),
+		NBDListenAddr:               os.Getenv("NBD_LISTEN_ADDR"),
+		Hooks:                       getHookConfig(),
 	}
 	server := exportServer.NewExportServer(config)
 	service.Setup(server)
@@ -60,11 +91,22 @@ func getVolumeInfo() []exportServer.VolumeInfo {
 		envPrefix := strings.TrimSuffix(kv[0], "_EXPORT_PATH")
 		if envPrefix != kv[0] {
 			vi := exportServer.VolumeInfo{
-				Path:       kv[1],
-				ArchiveURI: os.Getenv(envPrefix + "_EXPORT_ARCHIVE_URI"),
-				DirURI:     os.Getenv(envPrefix + "_EXPORT_DIR_URI"),
-				RawURI:     os.Getenv(envPrefix + "_EXPORT_RAW_URI"),
-				RawGzURI:   os.Getenv(envPrefix + "_EXPORT_RAW_GZIP_URI"),
+				Name:           os.Getenv(envPrefix + "_EXPORT_NAME"),
+				Path:           kv[1],
+				ArchiveURI:     os.Getenv(envPrefix + "_EXPORT_ARCHIVE_URI"),
+				DirURI:         os.Getenv(envPrefix + "_EXPORT_DIR_URI"),
+				RawURI:         os.Getenv(envPrefix + "_EXPORT_RAW_URI"),
+				RawGzURI:       os.Getenv(envPrefix + "_EXPORT_RAW_GZIP_URI"),
+				ZstdURI:        os.Getenv(envPrefix + "_EXPORT_ZSTD_URI"),
+				ArchiveZstdURI: os.Getenv(envPrefix + "_EXPORT_ARCHIVE_ZSTD_URI"),
+				Qcow2URI:       os.Getenv(envPrefix + "_EXPORT_QCOW2_URI"),
+				Qcow2GzURI:     os.Getenv(envPrefix + "_EXPORT_QCOW2_GZIP_URI"),
+				VmdkURI:        os.Getenv(envPrefix + "_EXPORT_VMDK_URI"),
+				VhdURI:         os.Getenv(envPrefix + "_EXPORT_VHD_URI"),
+				VhdxURI:        os.Getenv(envPrefix + "_EXPORT_VHDX_URI"),
+
+				IncludePatterns: splitCommaList(os.Getenv(envPrefix + "_EXPORT_INCLUDE_PATTERNS")),
+				ExcludePatterns: splitCommaList(os.Getenv(envPrefix + "_EXPORT_EXCLUDE_PATTERNS")),
 			}
 			result = append(result, vi)
 		}
@@ -72,6 +114,30 @@ func getVolumeInfo() []exportServer.VolumeInfo {
 	return result
 }
 
+// splitCommaList splits s on commas, returning nil for an empty string instead of a slice
+// containing one empty string.
+func splitCommaList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// getHookConfig reads the PRE_SERVE_COMMAND and POST_SERVE_COMMAND environment variables set by
+// the controller when spec.hooks is configured, each a comma-separated command and its arguments.
+// It returns nil if neither is set, since hooks are optional.
+func getHookConfig() *exportServer.HookConfig {
+	preServeCommand := splitCommaList(os.Getenv("PRE_SERVE_COMMAND"))
+	postServeCommand := splitCommaList(os.Getenv("POST_SERVE_COMMAND"))
+	if len(preServeCommand) == 0 && len(postServeCommand) == 0 {
+		return nil
+	}
+	return &exportServer.HookConfig{
+		PreServeCommand:  preServeCommand,
+		PostServeCommand: postServeCommand,
+	}
+}
+
 func getTokenFile() string {
 	tokenFile := os.Getenv("TOKEN_FILE")
 	if tokenFile == "" {
@@ -80,6 +146,40 @@ func getTokenFile() string {
 	return tokenFile
 }
 
+func getAdditionalTokenFiles() []string {
+	additionalTokenFiles := os.Getenv("ADDITIONAL_TOKEN_FILES")
+	if additionalTokenFiles == "" {
+		return nil
+	}
+	return strings.Split(additionalTokenFiles, ",")
+}
+
+// getScopedTokens builds one exportServer.TokenScope per spec.scopedTokenSecretRefs entry from
+// the SCOPED_TOKEN%d_FILE/_VOLUMES/_MAX_READS environment variables the controller sets,
+// mirroring the VOLUME%d_ convention getVolumeInfo uses for per-volume export paths.
+func getScopedTokens() []*exportServer.TokenScope {
+	var scopes []*exportServer.TokenScope
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		envPrefix := strings.TrimSuffix(kv[0], "_FILE")
+		if envPrefix == kv[0] || !strings.HasPrefix(envPrefix, "SCOPED_TOKEN") {
+			continue
+		}
+		scope := &exportServer.TokenScope{
+			TokenFile: kv[1],
+			Volumes:   splitCommaList(os.Getenv(envPrefix + "_VOLUMES")),
+		}
+		if maxReads := os.Getenv(envPrefix + "_MAX_READS"); maxReads != "" {
+			if n, err := strconv.Atoi(maxReads); err == nil {
+				n32 := int32(n)
+				scope.MaxReads = &n32
+			}
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
 func getCert() (certFile, keyFile string) {
 	certFile = os.Getenv("CERT_FILE")
 	keyFile = os.Getenv("KEY_FILE")
@@ -97,6 +197,222 @@ func getListenAddr() string {
 	return listenAddr
 }
 
+// getS3UploadConfig reads the S3_ENDPOINT, S3_BUCKET, S3_REGION, S3_ACCESS_KEY_ID_FILE and
+// S3_SECRET_ACCESS_KEY_FILE environment variables set by the controller when spec.s3Upload is
+// configured. It returns nil if S3_ENDPOINT is unset, since S3 upload is optional.
+func getS3UploadConfig() *exportServer.S3UploadConfig {
+	endpoint := os.Getenv("S3_ENDPOINT")
+	if endpoint == "" {
+		return nil
+	}
+	return &exportServer.S3UploadConfig{
+		Endpoint:            endpoint,
+		Bucket:              os.Getenv("S3_BUCKET"),
+		Region:              os.Getenv("S3_REGION"),
+		AccessKeyIDFile:     os.Getenv("S3_ACCESS_KEY_ID_FILE"),
+		SecretAccessKeyFile: os.Getenv("S3_SECRET_ACCESS_KEY_FILE"),
+	}
+}
+
+// getRegistryUploadConfig reads the REGISTRY_REPOSITORY, REGISTRY_TAG, REGISTRY_USERNAME_FILE and
+// REGISTRY_PASSWORD_FILE environment variables set by the controller when spec.registryUpload is
+// configured. It returns nil if REGISTRY_REPOSITORY is unset, since registry upload is optional.
+func getRegistryUploadConfig() *exportServer.RegistryUploadConfig {
+	repository := os.Getenv("REGISTRY_REPOSITORY")
+	if repository == "" {
+		return nil
+	}
+	return &exportServer.RegistryUploadConfig{
+		Repository:   repository,
+		Tag:          os.Getenv("REGISTRY_TAG"),
+		UsernameFile: os.Getenv("REGISTRY_USERNAME_FILE"),
+		PasswordFile: os.Getenv("REGISTRY_PASSWORD_FILE"),
+	}
+}
+
+// getClusterUploadConfig reads the CLUSTER_UPLOAD_NAMESPACE, CLUSTER_UPLOAD_PROXY_URL and
+// CLUSTER_UPLOAD_KUBECONFIG_FILE environment variables set by the controller when
+// spec.clusterUpload is configured. It returns nil if CLUSTER_UPLOAD_PROXY_URL is unset, since
+// cluster upload is optional.
+func getClusterUploadConfig() *exportServer.ClusterUploadConfig {
+	proxyURL := os.Getenv("CLUSTER_UPLOAD_PROXY_URL")
+	if proxyURL == "" {
+		return nil
+	}
+	return &exportServer.ClusterUploadConfig{
+		Namespace:         os.Getenv("CLUSTER_UPLOAD_NAMESPACE"),
+		CDIUploadProxyURL: proxyURL,
+		KubeconfigFile:    os.Getenv("CLUSTER_UPLOAD_KUBECONFIG_FILE"),
+	}
+}
+
+// getOvaConfig reads the OVA_URI and OVA_DESCRIPTOR_FILE environment variables set by the
+// controller when the export bundles an OVA, along with each volume's VOLUME%d_EXPORT_OVA_DISK_NAME
+// and VOLUME%d_EXPORT_PATH, to build the ordered list of disks to include in the bundle. It
+// returns nil if OVA_URI is unset, since OVA bundling is optional.
+func getOvaConfig() *exportServer.OvaConfig {
+	uri := os.Getenv("OVA_URI")
+	if uri == "" {
+		return nil
+	}
+
+	type indexedDisk struct {
+		index int
+		disk  exportServer.OvaDisk
+	}
+	var indexed []indexedDisk
+	for _, env := range os.Environ() {
+		kv := strings.Split(env, "=")
+		envPrefix := strings.TrimSuffix(kv[0], "_EXPORT_OVA_DISK_NAME")
+		if envPrefix == kv[0] {
+			continue
+		}
+		var index int
+		if _, err := fmt.Sscanf(envPrefix, "VOLUME%d", &index); err != nil {
+			continue
+		}
+		indexed = append(indexed, indexedDisk{
+			index: index,
+			disk: exportServer.OvaDisk{
+				Name: kv[1],
+				Path: os.Getenv(envPrefix + "_EXPORT_PATH"),
+			},
+		})
+	}
+	sort.Slice(indexed, func(i, j int) bool { return indexed[i].index < indexed[j].index })
+
+	disks := make([]exportServer.OvaDisk, len(indexed))
+	for i, d := range indexed {
+		disks[i] = d.disk
+	}
+
+	return &exportServer.OvaConfig{
+		URI:            uri,
+		DescriptorFile: os.Getenv("OVA_DESCRIPTOR_FILE"),
+		Disks:          disks,
+	}
+}
+
+// getManifestURI reads the MANIFEST_URI environment variable set by the controller when the
+// export source is a VirtualMachine. It returns "" if MANIFEST_URI is unset, since the manifest
+// is only generated for that source type.
+func getManifestURI() string {
+	return os.Getenv("MANIFEST_URI")
+}
+
+// getAllURI reads the ALL_URI environment variable set by the controller. It returns "" if
+// ALL_URI is unset, since the combined archive is only served once the controller has configured
+// it.
+func getAllURI() string {
+	return os.Getenv("ALL_URI")
+}
+
+// getExpandedManifestURI reads the EXPANDED_MANIFEST_URI environment variable set by the
+// controller when the export source is a VirtualMachine referencing an instancetype or
+// preference. It returns "" if EXPANDED_MANIFEST_URI is unset, since the expanded manifest is
+// only generated for that case.
+func getExpandedManifestURI() string {
+	return os.Getenv("EXPANDED_MANIFEST_URI")
+}
+
+// getZstdCompressionLevel reads the ZSTD_COMPRESSION_LEVEL environment variable set by the
+// controller. It returns 0 if unset or unparseable, which tells NewExportServer to fall back to
+// its own default.
+func getZstdCompressionLevel() int {
+	level, err := strconv.Atoi(os.Getenv("ZSTD_COMPRESSION_LEVEL"))
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+// getGzipCompressionLevel reads the GZIP_COMPRESSION_LEVEL environment variable set by the
+// controller. It returns 0 if unset or unparseable, which tells NewExportServer to fall back to
+// its own default.
+func getGzipCompressionLevel() int {
+	level, err := strconv.Atoi(os.Getenv("GZIP_COMPRESSION_LEVEL"))
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+// getShutdownGracePeriod reads the SHUTDOWN_GRACE_PERIOD environment variable set by the
+// controller. It returns 0 if unset or unparseable, which tells NewExportServer to fall back to
+// its own default.
+func getShutdownGracePeriod() time.Duration {
+	gracePeriod, err := time.ParseDuration(os.Getenv("SHUTDOWN_GRACE_PERIOD"))
+	if err != nil {
+		return 0
+	}
+	return gracePeriod
+}
+
+// getBandwidthLimit reads envVar as a bytes/second egress bandwidth limit set by the controller.
+// It returns 0 if envVar is unset or unparseable, which tells the exporter that limit is
+// unlimited.
+func getBandwidthLimit(envVar string) int64 {
+	limit, err := strconv.ParseInt(os.Getenv(envVar), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// getMaxConcurrentDownloads reads the MAX_CONCURRENT_DOWNLOADS environment variable set by the
+// controller. It returns 0 if unset or unparseable, which tells the exporter that the number of
+// concurrent downloads is unlimited.
+func getMaxConcurrentDownloads() int {
+	limit, err := strconv.Atoi(os.Getenv("MAX_CONCURRENT_DOWNLOADS"))
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// getPerClientIPRequestLimit reads the PER_CLIENT_IP_REQUEST_LIMIT environment variable set by
+// the controller. It returns 0 if unset or unparseable, which tells the exporter that per-client
+// IP request rate limiting is disabled.
+func getPerClientIPRequestLimit() float64 {
+	limit, err := strconv.ParseFloat(os.Getenv("PER_CLIENT_IP_REQUEST_LIMIT"), 64)
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// getPerClientIPRequestBurst reads the PER_CLIENT_IP_REQUEST_BURST environment variable set by
+// the controller. It returns 0 if unset or unparseable, which tells the exporter to fall back to
+// its own default burst size.
+func getPerClientIPRequestBurst() int {
+	burst, err := strconv.Atoi(os.Getenv("PER_CLIENT_IP_REQUEST_BURST"))
+	if err != nil {
+		return 0
+	}
+	return burst
+}
+
+// getMinTLSVersion reads the MIN_TLS_VERSION environment variable set by the controller. It
+// returns 0 if unset, which tells NewExportServer to fall back to its own default.
+func getMinTLSVersion() uint16 {
+	version := os.Getenv("MIN_TLS_VERSION")
+	if version == "" {
+		return 0
+	}
+	return tlsutil.TLSVersion(v1.TLSProtocolVersion(version))
+}
+
+// getTLSCiphers reads the comma-separated TLS_CIPHERS environment variable set by the
+// controller. It returns nil if unset, which tells the exporter to use Go's default cipher
+// suite selection.
+func getTLSCiphers() []string {
+	ciphers := os.Getenv("TLS_CIPHERS")
+	if ciphers == "" {
+		return nil
+	}
+	return strings.Split(ciphers, ",")
+}
+
 func getDeadline() (result time.Time) {
 	dl := os.Getenv("DEADLINE")
 	if dl != "" {
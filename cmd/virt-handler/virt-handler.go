@@ -82,6 +82,7 @@ import (
 	migrationproxy "kubevirt.io/kubevirt/pkg/virt-handler/migration-proxy"
 	nodelabeller "kubevirt.io/kubevirt/pkg/virt-handler/node-labeller"
 	"kubevirt.io/kubevirt/pkg/virt-handler/rest"
+	"kubevirt.io/kubevirt/pkg/virt-handler/seccomp"
 	"kubevirt.io/kubevirt/pkg/virt-handler/selinux"
 	virt_api "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 	"kubevirt.io/kubevirt/pkg/watchdog"
@@ -380,6 +381,10 @@ func (app *virtHandlerApp) Run() {
 	go gracefulShutdownInformer.Run(stop)
 	go domainSharedInformer.Run(stop)
 
+	if err := seccomp.NewSeccompProfileInstaller().InstallPolicy("/var/lib/kubelet/seccomp"); err != nil {
+		panic(fmt.Errorf("failed to install virt-launcher seccomp profiles: %v", err))
+	}
+
 	se, exists, err := selinux.NewSELinux()
 	if err == nil && exists {
 		log.DefaultLogger().Infof("SELinux is reported as '%s'", se.Mode())
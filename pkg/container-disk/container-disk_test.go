@@ -182,7 +182,7 @@ var _ = Describe("ContainerDisk", func() {
 						k8sv1.ResourceMemory: resource.MustParse("64M"),
 					},
 				}
-				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "/var/run/libvirt")
+				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "/var/run/libvirt", false)
 
 				containerResourceSpecs := []k8sv1.ResourceList{containers[0].Resources.Limits, containers[0].Resources.Requests}
 
@@ -194,7 +194,7 @@ var _ = Describe("ContainerDisk", func() {
 
 				vmi := api.NewMinimalVMI("fake-vmi")
 				appendContainerDisk(vmi, "r0")
-				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "/var/run/libvirt")
+				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "/var/run/libvirt", false)
 
 				expectedEphemeralStorageRequest := resource.MustParse(ephemeralStorageOverheadSize)
 
@@ -211,13 +211,21 @@ var _ = Describe("ContainerDisk", func() {
 				vmi := api.NewMinimalVMI("fake-vmi")
 				appendContainerDisk(vmi, "r1")
 				appendContainerDisk(vmi, "r0")
-				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "bin-volume")
+				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "bin-volume", false)
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(containers).To(HaveLen(2))
 				Expect(containers[0].ImagePullPolicy).To(Equal(k8sv1.PullAlways))
 				Expect(containers[1].ImagePullPolicy).To(Equal(k8sv1.PullAlways))
 			})
+			It("by verifying that the cache flag forces the IfNotPresent pull policy", func() {
+				vmi := api.NewMinimalVMI("fake-vmi")
+				appendContainerDisk(vmi, "r0")
+				containers := GenerateContainers(vmi, nil, "libvirt-runtime", "bin-volume", true)
+
+				Expect(containers).To(HaveLen(1))
+				Expect(containers[0].ImagePullPolicy).To(Equal(k8sv1.PullIfNotPresent))
+			})
 
 			Context("which checks socket paths", func() {
 
@@ -277,7 +285,7 @@ var _ = Describe("ContainerDisk", func() {
 				Expect(imageIDs).To(HaveKeyWithValue("disk2", "someimage@sha256:1"))
 				Expect(imageIDs).To(HaveLen(2))
 
-				newContainers := GenerateContainers(vmi, imageIDs, "a-name", "something")
+				newContainers := GenerateContainers(vmi, imageIDs, "a-name", "something", false)
 				Expect(newContainers[0].Image).To(Equal("someimage@sha256:0"))
 				Expect(newContainers[1].Image).To(Equal("someimage@sha256:1"))
 			})
@@ -296,8 +304,8 @@ var _ = Describe("ContainerDisk", func() {
 				Expect(imageIDs).To(HaveKeyWithValue("kernel-boot-volume", "someimage@sha256:bootcontainer"))
 				Expect(imageIDs).To(HaveLen(2))
 
-				newContainers := GenerateContainers(vmi, imageIDs, "a-name", "something")
-				newBootContainer := GenerateKernelBootContainer(vmi, imageIDs, "a-name", "something")
+				newContainers := GenerateContainers(vmi, imageIDs, "a-name", "something", false)
+				newBootContainer := GenerateKernelBootContainer(vmi, imageIDs, "a-name", "something", false)
 				newContainers = append(newContainers, *newBootContainer)
 				Expect(newContainers[0].Image).To(Equal("someimage@sha256:0"))
 				Expect(newContainers[1].Image).To(Equal("someimage@sha256:bootcontainer"))
@@ -381,7 +389,7 @@ func appendNonContainerDisk(vmi *v1.VirtualMachineInstance, diskName string) {
 
 func createMigrationSourcePod(vmi *v1.VirtualMachineInstance) *k8sv1.Pod {
 	pod := &k8sv1.Pod{Status: k8sv1.PodStatus{}}
-	containers := GenerateContainers(vmi, nil, "a-name", "something")
+	containers := GenerateContainers(vmi, nil, "a-name", "something", false)
 
 	for idx, container := range containers {
 		status := k8sv1.ContainerStatus{
@@ -391,7 +399,7 @@ func createMigrationSourcePod(vmi *v1.VirtualMachineInstance) *k8sv1.Pod {
 		}
 		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
 	}
-	bootContainer := GenerateKernelBootContainer(vmi, nil, "a-name", "something")
+	bootContainer := GenerateKernelBootContainer(vmi, nil, "a-name", "something", false)
 	if bootContainer != nil {
 		status := k8sv1.ContainerStatus{
 			Name:    bootContainer.Name,
@@ -18,7 +18,7 @@ const emptyDiskBaseDir = "/var/run/libvirt/empty-disks/"
 
 type emptyDiskCreator struct {
 	emptyDiskBaseDir string
-	discCreateFunc   func(filePath string, size string) error
+	discCreateFunc   func(filePath string, size string, preallocation v1.HostDiskPreallocation) error
 }
 
 func (c *emptyDiskCreator) CreateTemporaryDisks(vmi *v1.VirtualMachineInstance) error {
@@ -40,7 +40,7 @@ func (c *emptyDiskCreator) CreateTemporaryDisks(vmi *v1.VirtualMachineInstance)
 				return err
 			}
 			if _, err := os.Stat(file); os.IsNotExist(err) {
-				if err := c.discCreateFunc(file, size); err != nil {
+				if err := c.discCreateFunc(file, size, volume.EmptyDisk.Preallocation); err != nil {
 					return err
 				}
 			} else if err != nil {
@@ -63,9 +63,14 @@ func filePathForVolumeName(basedir string, volumeName string) string {
 	return path.Join(basedir, volumeName+".qcow2")
 }
 
-func createQCOW(file string, size string) error {
+func createQCOW(file string, size string, preallocation v1.HostDiskPreallocation) error {
+	args := []string{"create", "-f", "qcow2"}
+	if preallocation != "" {
+		args = append(args, "-o", fmt.Sprintf("preallocation=%s", preallocation))
+	}
+	args = append(args, file, size)
 	// #nosec No risk for attacket injection. Parameters are predefined strings
-	return exec.Command("qemu-img", "create", "-f", "qcow2", file, size).Run()
+	return exec.Command("qemu-img", args...).Run()
 }
 
 func NewEmptyDiskCreator() *emptyDiskCreator {
@@ -37,6 +37,18 @@ var _ = Describe("EmptyDisk", func() {
 		})
 	}
 
+	AppendEmptyDiskWithPreallocation := func(vmi *v1.VirtualMachineInstance, diskName string, preallocation v1.HostDiskPreallocation) {
+		vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+			Name: diskName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDisk: &v1.EmptyDiskSource{
+					Capacity:      resource.MustParse("3Gi"),
+					Preallocation: preallocation,
+				},
+			},
+		})
+	}
+
 	BeforeEach(func() {
 		var err error
 		emptyDiskBaseDir, err = ioutil.TempDir("", "emptydisk-dir")
@@ -74,6 +86,18 @@ var _ = Describe("EmptyDisk", func() {
 		It("should generate non-conflicting volume paths per disk", func() {
 			Expect(NewEmptyDiskCreator().FilePathForVolumeName("volume1")).ToNot(Equal(NewEmptyDiskCreator().FilePathForVolumeName("volume2")))
 		})
+		It("should forward the requested preallocation to the image creation func", func() {
+			var gotPreallocation v1.HostDiskPreallocation
+			creator.discCreateFunc = func(filePath string, size string, preallocation v1.HostDiskPreallocation) error {
+				gotPreallocation = preallocation
+				return fakeCreatorFunc(filePath, size, preallocation)
+			}
+			vmi := api.NewMinimalVMI("testvmi")
+			AppendEmptyDiskWithPreallocation(vmi, "testdisk", v1.HostDiskPreallocationFalloc)
+			err := creator.CreateTemporaryDisks(vmi)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gotPreallocation).To(Equal(v1.HostDiskPreallocationFalloc))
+		})
 		It("should leave pre-existing disks alone", func() {
 			vmi := api.NewMinimalVMI("testvmi")
 			AppendEmptyDisk(vmi, "testdisk")
@@ -88,7 +112,7 @@ var _ = Describe("EmptyDisk", func() {
 
 })
 
-func fakeCreatorFunc(filePath string, _ string) error {
+func fakeCreatorFunc(filePath string, _ string, _ v1.HostDiskPreallocation) error {
 	fmt.Println(filePath)
 	f, err := os.Create(filePath)
 	if err == nil {
@@ -22,6 +22,7 @@ package hostdisk
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"syscall"
@@ -171,6 +172,21 @@ func createSparseRaw(fullPath string, size int64) (err error) {
 	return nil
 }
 
+func createSparseQcow2(fullPath string, size int64, preallocation v1.HostDiskPreallocation) error {
+	args := []string{"create", "-f", "qcow2"}
+	if preallocation != "" {
+		args = append(args, "-o", fmt.Sprintf("preallocation=%s", preallocation))
+	}
+	args = append(args, fullPath, fmt.Sprintf("%d", size))
+	// #nosec No risk for attacket injection. Parameters are predefined strings
+	cmd := exec.Command("qemu-img", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("qemu-img failed with output '%s': %v", string(output), err)
+	}
+	return nil
+}
+
 func getPVCDiskImgPath(volumeName string, diskName string) string {
 	return path.Join(pvcBaseDir, volumeName, diskName)
 }
@@ -261,6 +277,15 @@ func (hdc *DiskImgCreator) handleRequestedSizeAndCreateSparseRaw(vmi *v1.Virtual
 			return err
 		}
 	}
+	if hostDisk.Qcow2 != nil && *hostDisk.Qcow2 {
+		err = createSparseQcow2(diskPath, requestedSize, hostDisk.Preallocation)
+		if err != nil {
+			log.Log.Reason(err).Errorf("Couldn't create a sparse qcow2 file for disk path: %s, error: %v", diskPath, err)
+			return err
+		}
+		return nil
+	}
+
 	err = createSparseRaw(diskPath, requestedSize)
 	if err != nil {
 		log.Log.Reason(err).Errorf("Couldn't create a sparse raw file for disk path: %s, error: %v", diskPath, err)
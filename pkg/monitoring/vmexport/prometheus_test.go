@@ -0,0 +1,62 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package vmexport
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	exportv1 "kubevirt.io/api/export/v1alpha1"
+)
+
+var _ = Describe("VirtualMachineExport phase count collector", func() {
+	It("should report the number of exports per phase", func() {
+		informer := cache.NewSharedIndexInformer(nil, &exportv1.VirtualMachineExport{}, 0, cache.Indexers{})
+		Expect(informer.GetIndexer().Add(&exportv1.VirtualMachineExport{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"},
+			Status:     &exportv1.VirtualMachineExportStatus{Phase: exportv1.Ready},
+		})).To(Succeed())
+		Expect(informer.GetIndexer().Add(&exportv1.VirtualMachineExport{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+			Status:     &exportv1.VirtualMachineExportStatus{Phase: exportv1.Pending},
+		})).To(Succeed())
+
+		co := &exportCollector{vmExportInformer: informer}
+		ch := make(chan prometheus.Metric, 2)
+		co.Collect(ch)
+		close(ch)
+
+		metrics := map[string]float64{}
+		for metric := range ch {
+			m := &dto.Metric{}
+			Expect(metric.Write(m)).To(Succeed())
+			metrics[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+
+		Expect(metrics).To(HaveKeyWithValue(string(exportv1.Ready), 1.0))
+		Expect(metrics).To(HaveKeyWithValue(string(exportv1.Pending), 1.0))
+	})
+})
@@ -0,0 +1,105 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package export
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	exportv1 "kubevirt.io/api/export/v1alpha1"
+	"kubevirt.io/client-go/log"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/controller"
+)
+
+func (ctrl *VMExportController) handleDataSource(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+
+	if dataSource, ok := obj.(*cdiv1.DataSource); ok {
+		dataSourceKey, _ := cache.MetaNamespaceKeyFunc(dataSource)
+		keys, err := ctrl.VMExportInformer.GetIndexer().IndexKeys("datasource", dataSourceKey)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+		for _, key := range keys {
+			log.Log.V(3).Infof("Adding VMExport due to DataSource %s", dataSourceKey)
+			ctrl.vmExportQueue.Add(key)
+		}
+	}
+}
+
+func (ctrl *VMExportController) isSourceDataSource(source *exportv1.VirtualMachineExportSpec) bool {
+	return source != nil && source.Source.APIGroup != nil && *source.Source.APIGroup == cdiv1.SchemeGroupVersion.Group && source.Source.Kind == "DataSource"
+}
+
+func (ctrl *VMExportController) getDataSource(namespace, name string) (*cdiv1.DataSource, bool, error) {
+	key := controller.NamespacedKey(namespace, name)
+	obj, exists, err := ctrl.DataSourceInformer.GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return obj.(*cdiv1.DataSource).DeepCopy(), true, nil
+}
+
+func (ctrl *VMExportController) getPVCFromSourceDataSource(vmExport *exportv1.VirtualMachineExport) (*sourceVolumes, error) {
+	dataSource, exists, err := ctrl.getDataSource(vmExport.Namespace, vmExport.Spec.Source.Name)
+	if err != nil {
+		return &sourceVolumes{}, err
+	}
+	if !exists {
+		return &sourceVolumes{
+			availableMessage: fmt.Sprintf("DataSource %s/%s does not exist", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
+	}
+	if dataSource.Spec.Source.PVC == nil {
+		return &sourceVolumes{
+			availableMessage: fmt.Sprintf("DataSource %s/%s does not have a PVC source", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
+	}
+
+	pvcNamespace := dataSource.Spec.Source.PVC.Namespace
+	if pvcNamespace == "" {
+		pvcNamespace = vmExport.Namespace
+	}
+	pvc, pvcExists, err := ctrl.getPvc(pvcNamespace, dataSource.Spec.Source.PVC.Name)
+	if err != nil {
+		return &sourceVolumes{}, err
+	}
+	if !pvcExists {
+		return &sourceVolumes{
+			availableMessage: fmt.Sprintf("pvc %s/%s not found", pvcNamespace, dataSource.Spec.Source.PVC.Name)}, nil
+	}
+
+	isPopulated, inUse, waitingForFirstConsumer, availableMessage, err := ctrl.isSourceAvailablePVC(vmExport, pvc)
+	if err != nil {
+		return &sourceVolumes{}, err
+	}
+	return &sourceVolumes{
+		volumes:                 []*corev1.PersistentVolumeClaim{pvc},
+		inUse:                   inUse,
+		isPopulated:             isPopulated,
+		waitingForFirstConsumer: waitingForFirstConsumer,
+		availableMessage:        availableMessage}, nil
+}
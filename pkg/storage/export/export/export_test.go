@@ -19,12 +19,16 @@
 package export
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,14 +36,21 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/openshift/library-go/pkg/build/naming"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 
+	appsv1 "k8s.io/api/apps/v1"
 	k8sv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
@@ -51,6 +62,7 @@ import (
 	kubevirtfake "kubevirt.io/client-go/generated/kubevirt/clientset/versioned/fake"
 
 	exportv1 "kubevirt.io/api/export/v1alpha1"
+	instancetypev1alpha1 "kubevirt.io/api/instancetype/v1alpha1"
 	"kubevirt.io/client-go/kubecli"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
@@ -59,6 +71,7 @@ import (
 	"kubevirt.io/kubevirt/pkg/certificates/bootstrap"
 	"kubevirt.io/kubevirt/pkg/certificates/triple"
 	virtcontroller "kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/instancetype"
 	"kubevirt.io/kubevirt/pkg/testutils"
 	"kubevirt.io/kubevirt/pkg/virt-controller/services"
 	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
@@ -80,6 +93,7 @@ var _ = Describe("Export controller", func() {
 		controller                 *VMExportController
 		recorder                   *record.FakeRecorder
 		pvcInformer                cache.SharedIndexInformer
+		pvInformer                 cache.SharedIndexInformer
 		podInformer                cache.SharedIndexInformer
 		cmInformer                 cache.SharedIndexInformer
 		vmExportInformer           cache.SharedIndexInformer
@@ -93,6 +107,7 @@ var _ = Describe("Export controller", func() {
 		vmiInformer                cache.SharedIndexInformer
 		k8sClient                  *k8sfake.Clientset
 		vmExportClient             *kubevirtfake.Clientset
+		dynamicClient              *dynamicfake.FakeDynamicClient
 		fakeVolumeSnapshotProvider *MockVolumeSnapshotProvider
 		mockVMExportQueue          *testutils.MockWorkQueue
 		routeCache                 cache.Store
@@ -106,6 +121,7 @@ var _ = Describe("Export controller", func() {
 	syncCaches := func(stop chan struct{}) {
 		go vmExportInformer.Run(stop)
 		go pvcInformer.Run(stop)
+		go pvInformer.Run(stop)
 		go podInformer.Run(stop)
 		go dvInformer.Run(stop)
 		go cmInformer.Run(stop)
@@ -119,6 +135,7 @@ var _ = Describe("Export controller", func() {
 			stop,
 			vmExportInformer.HasSynced,
 			pvcInformer.HasSynced,
+			pvInformer.HasSynced,
 			podInformer.HasSynced,
 			dvInformer.HasSynced,
 			cmInformer.HasSynced,
@@ -142,6 +159,7 @@ var _ = Describe("Export controller", func() {
 		writeCertsToDir(certDir)
 		virtClient := kubecli.NewMockKubevirtClient(ctrl)
 		pvcInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolumeClaim{})
+		pvInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolume{})
 		podInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Pod{})
 		cmInformer, _ = testutils.NewFakeInformerFor(&k8sv1.ConfigMap{})
 		serviceInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Service{})
@@ -163,16 +181,23 @@ var _ = Describe("Export controller", func() {
 		config, _, _ := testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{})
 		k8sClient = k8sfake.NewSimpleClientset()
 		vmExportClient = kubevirtfake.NewSimpleClientset()
+		dynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			httpRouteResource: "HTTPRouteList",
+		})
 		recorder = record.NewFakeRecorder(100)
 
 		virtClient.EXPECT().CoreV1().Return(k8sClient.CoreV1()).AnyTimes()
+		virtClient.EXPECT().AppsV1().Return(k8sClient.AppsV1()).AnyTimes()
+		virtClient.EXPECT().NetworkingV1().Return(k8sClient.NetworkingV1()).AnyTimes()
 		virtClient.EXPECT().VirtualMachineExport(testNamespace).
 			Return(vmExportClient.ExportV1alpha1().VirtualMachineExports(testNamespace)).AnyTimes()
+		virtClient.EXPECT().DynamicClient().Return(dynamicClient).AnyTimes()
 
 		controller = &VMExportController{
 			Client:                    virtClient,
 			Recorder:                  recorder,
 			PVCInformer:               pvcInformer,
+			PVInformer:                pvInformer,
 			PodInformer:               podInformer,
 			ConfigMapInformer:         cmInformer,
 			VMExportInformer:          vmExportInformer,
@@ -190,6 +215,8 @@ var _ = Describe("Export controller", func() {
 			VolumeSnapshotProvider:    fakeVolumeSnapshotProvider,
 			VMInformer:                vmInformer,
 			VMIInformer:               vmiInformer,
+			ClusterConfig:             config,
+			InstancetypeMethods:       instancetype.NewMethods(virtClient),
 		}
 		initCert = func(ctrl *VMExportController) {
 			go controller.caCertManager.Start()
@@ -613,6 +640,151 @@ var _ = Describe("Export controller", func() {
 		Expect(service.Status.Conditions[0].Type).To(Equal("test2"))
 	})
 
+	It("Should create a ClusterIP service by default", func() {
+		testVMExport := createPVCVMExport()
+		service := controller.createServiceManifest(testVMExport)
+		Expect(service.Spec.Type).To(BeEmpty())
+	})
+
+	It("Should expose a named metrics ServicePort alongside the exporter API port", func() {
+		testVMExport := createPVCVMExport()
+		service := controller.createServiceManifest(testVMExport)
+
+		var metricsPort *k8sv1.ServicePort
+		for i := range service.Spec.Ports {
+			if service.Spec.Ports[i].Name == "metrics" {
+				metricsPort = &service.Spec.Ports[i]
+			}
+		}
+		Expect(metricsPort).ToNot(BeNil())
+		Expect(metricsPort.TargetPort).To(Equal(intstr.FromInt(8443)))
+	})
+
+	It("Should create a NodePort or LoadBalancer service when requested by spec.serviceType", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ServiceType = k8sv1.ServiceTypeNodePort
+		service := controller.createServiceManifest(testVMExport)
+		Expect(service.Spec.Type).To(Equal(k8sv1.ServiceTypeNodePort))
+
+		testVMExport.Spec.ServiceType = k8sv1.ServiceTypeLoadBalancer
+		service = controller.createServiceManifest(testVMExport)
+		Expect(service.Spec.Type).To(Equal(k8sv1.ServiceTypeLoadBalancer))
+	})
+
+	It("Should create a NetworkPolicy restricting exporter pod ingress to virt-exportproxy and same-namespace clients", func() {
+		testVMExport := createPVCVMExport()
+		Expect(controller.getOrCreateExportNetworkPolicy(testVMExport)).To(Succeed())
+
+		networkPolicy, err := k8sClient.NetworkingV1().NetworkPolicies(testVMExport.Namespace).
+			Get(context.Background(), controller.getExportServiceName(testVMExport), metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(networkPolicy.OwnerReferences).To(HaveLen(1))
+		Expect(networkPolicy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{
+			exportServiceLabel: testVMExport.Name,
+		}))
+		Expect(networkPolicy.Spec.Ingress).To(HaveLen(1))
+		Expect(networkPolicy.Spec.Ingress[0].From).To(HaveLen(2))
+
+		// Calling it again once the NetworkPolicy already exists should be a no-op, not an error.
+		Expect(controller.getOrCreateExportNetworkPolicy(testVMExport)).To(Succeed())
+	})
+
+	It("Should publish the NodePort address as the external link host when there is no Ingress or Route", func() {
+		exporterPod := &k8sv1.Pod{
+			Status: k8sv1.PodStatus{
+				HostIP: "192.168.1.5",
+			},
+		}
+		service := &k8sv1.Service{
+			Spec: k8sv1.ServiceSpec{
+				Type: k8sv1.ServiceTypeNodePort,
+				Ports: []k8sv1.ServicePort{
+					{
+						NodePort: 31234,
+					},
+				},
+			},
+		}
+		host, cert := controller.getServiceExternalHostAndCert(exporterPod, service)
+		Expect(host).To(Equal("192.168.1.5:31234"))
+		Expect(cert).To(Equal("replace me with ca cert"))
+	})
+
+	It("Should publish the LoadBalancer ingress address as the external link host when there is no Ingress or Route", func() {
+		service := &k8sv1.Service{
+			Spec: k8sv1.ServiceSpec{
+				Type: k8sv1.ServiceTypeLoadBalancer,
+			},
+			Status: k8sv1.ServiceStatus{
+				LoadBalancer: k8sv1.LoadBalancerStatus{
+					Ingress: []k8sv1.LoadBalancerIngress{
+						{
+							IP: "203.0.113.10",
+						},
+					},
+				},
+			},
+		}
+		host, cert := controller.getServiceExternalHostAndCert(nil, service)
+		Expect(host).To(Equal("203.0.113.10"))
+		Expect(cert).To(Equal("replace me with ca cert"))
+	})
+
+	It("Should not publish an external link host for a ClusterIP service", func() {
+		service := &k8sv1.Service{
+			Spec: k8sv1.ServiceSpec{},
+		}
+		host, cert := controller.getServiceExternalHostAndCert(nil, service)
+		Expect(host).To(BeEmpty())
+		Expect(cert).To(BeEmpty())
+	})
+
+	It("Should bracket an IPv6 NodePort host address", func() {
+		exporterPod := &k8sv1.Pod{
+			Status: k8sv1.PodStatus{
+				HostIP: "2001:db8::1",
+			},
+		}
+		service := &k8sv1.Service{
+			Spec: k8sv1.ServiceSpec{
+				Type: k8sv1.ServiceTypeNodePort,
+				Ports: []k8sv1.ServicePort{
+					{
+						NodePort: 31234,
+					},
+				},
+			},
+		}
+		host, _ := controller.getServiceExternalHostAndCert(exporterPod, service)
+		Expect(host).To(Equal("[2001:db8::1]:31234"))
+	})
+
+	It("Should bracket an IPv6 LoadBalancer ingress address", func() {
+		service := &k8sv1.Service{
+			Spec: k8sv1.ServiceSpec{
+				Type: k8sv1.ServiceTypeLoadBalancer,
+			},
+			Status: k8sv1.ServiceStatus{
+				LoadBalancer: k8sv1.LoadBalancerStatus{
+					Ingress: []k8sv1.LoadBalancerIngress{
+						{
+							IP: "2001:db8::10",
+						},
+					},
+				},
+			},
+		}
+		host, _ := controller.getServiceExternalHostAndCert(nil, service)
+		Expect(host).To(Equal("[2001:db8::10]"))
+	})
+
+	It("Should request PreferDualStack for the exporter service so it works on IPv6-only clusters too", func() {
+		testVMExport := createPVCVMExport()
+		service := controller.createServiceManifest(testVMExport)
+		Expect(service.Spec.IPFamilyPolicy).ToNot(BeNil())
+		Expect(*service.Spec.IPFamilyPolicy).To(Equal(k8sv1.IPFamilyPolicyPreferDualStack))
+	})
+
 	It("Should create a pod based on the name of the VMExport", func() {
 		testPVC := &k8sv1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -630,7 +802,7 @@ var _ = Describe("Export controller", func() {
 			Expect(pod.GetNamespace()).To(Equal(testNamespace))
 			return true, pod, nil
 		})
-		pod, err := controller.createExporterPod(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC})
+		pod, err := controller.createExporterPod(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(pod).ToNot(BeNil())
 		Expect(pod.Name).To(Equal(fmt.Sprintf("%s-%s", exportPrefix, testVMExport.Name)))
@@ -677,166 +849,1815 @@ var _ = Describe("Export controller", func() {
 			MountPath: "/cert",
 		}))
 		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
-			Name:      testVMExport.Spec.TokenSecretRef,
+			Name:      *testVMExport.Spec.TokenSecretRef,
 			MountPath: "/token",
 		}))
 	})
 
-	It("Should create a secret based on the vm export", func() {
-		testVMExport := createPVCVMExport()
-		testExportPod := &k8sv1.Pod{
+	It("Should mount additional token secrets and advertise them to the exporter pod", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-export-pod",
+				Name:      testPVCName,
+				Namespace: testNamespace,
 			},
-			Spec: k8sv1.PodSpec{
-				Volumes: []k8sv1.Volume{
-					{
-						Name: certificates,
-						VolumeSource: k8sv1.VolumeSource{
-							Secret: &k8sv1.SecretVolumeSource{
-								SecretName: "test-secret",
-							},
-						},
-					},
+		}
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.AdditionalTokenSecretRefs = []string{"old-token"}
+		pod, err := controller.createExporterPod(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: "additional-token-0",
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "old-token",
 				},
 			},
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      "additional-token-0",
+			MountPath: "/additional-token-0",
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "ADDITIONAL_TOKEN_FILES",
+			Value: "/additional-token-0/token",
+		}))
+	})
+
+	It("Should mount scoped token secrets and advertise their scope to the exporter pod", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
 		}
-		k8sClient.Fake.PrependReactor("create", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
-			create, ok := action.(testing.CreateAction)
-			Expect(ok).To(BeTrue())
-			secret, ok := create.GetObject().(*k8sv1.Secret)
-			Expect(ok).To(BeTrue())
-			Expect(secret.GetName()).To(Equal(controller.getExportSecretName(testExportPod)))
-			Expect(secret.GetNamespace()).To(Equal(testNamespace))
-			return true, secret, nil
-		})
-		err := controller.getOrCreateCertSecret(testVMExport, testExportPod)
-		Expect(err).ToNot(HaveOccurred())
-		By("Creating again, and returning exists")
-		k8sClient.Fake.PrependReactor("create", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
-			create, ok := action.(testing.CreateAction)
-			Expect(ok).To(BeTrue())
-			secret, ok := create.GetObject().(*k8sv1.Secret)
-			Expect(ok).To(BeTrue())
-			Expect(secret.GetName()).To(Equal(controller.getExportSecretName(testExportPod)))
-			Expect(secret.GetNamespace()).To(Equal(testNamespace))
-			secret.Name = "something"
-			return true, secret, errors.NewAlreadyExists(schema.GroupResource{}, "already exists")
-		})
-		err = controller.getOrCreateCertSecret(testVMExport, testExportPod)
+		maxReads := int32(3)
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ScopedTokenSecretRefs = []exportv1.VirtualMachineExportScopedTokenSecretRef{
+			{
+				SecretRef: "scoped-token",
+				Volumes:   []string{testPVCName},
+				MaxReads:  &maxReads,
+			},
+		}
+		pod, err := controller.createExporterPod(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, nil)
 		Expect(err).ToNot(HaveOccurred())
-		k8sClient.Fake.PrependReactor("create", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
-			create, ok := action.(testing.CreateAction)
-			Expect(ok).To(BeTrue())
-			secret, ok := create.GetObject().(*k8sv1.Secret)
-			Expect(ok).To(BeTrue())
-			Expect(secret.GetName()).To(Equal(controller.getExportSecretName(testExportPod)))
-			Expect(secret.GetNamespace()).To(Equal(testNamespace))
-			return true, nil, fmt.Errorf("failure")
-		})
-		err = controller.getOrCreateCertSecret(testVMExport, testExportPod)
-		Expect(err).To(HaveOccurred())
+		Expect(pod.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: "scoped-token-0",
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "scoped-token",
+				},
+			},
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      "scoped-token-0",
+			MountPath: "/scoped-token-0",
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "SCOPED_TOKEN0_FILE",
+			Value: "/scoped-token-0/token",
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "SCOPED_TOKEN0_VOLUMES",
+			Value: testPVCName,
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "SCOPED_TOKEN0_MAX_READS",
+			Value: "3",
+		}))
 	})
 
-	DescribeTable("Should ignore invalid VMExports kind/api combinations", func(kind, apigroup string) {
+	It("Should use the hardcoded default deadline when neither the VMExport nor the cluster config set one", func() {
 		testVMExport := createPVCVMExport()
-		testVMExport.Spec.Source.Kind = kind
-		testVMExport.Spec.Source.APIGroup = &apigroup
-		retry, err := controller.updateVMExport(testVMExport)
-		Expect(err).ToNot(HaveOccurred())
-		Expect(retry).To(BeEquivalentTo(0))
-	},
-		Entry("VirtualMachineSnapshot kind blank apigroup", "VirtualMachineSnapshot", ""),
-		Entry("VirtualMachineSnapshot kind invalid apigroup", "VirtualMachineSnapshot", "invalid"),
-		Entry("PersistentVolumeClaim kind invalid apigroup", "PersistentVolumeClaim", "invalid"),
-		Entry("PersistentVolumeClaim kind VMSnapshot apigroup", "PersistentVolumeClaim", snapshotv1.SchemeGroupVersion.Group),
-	)
+		Expect(controller.getDeadline(testVMExport)).To(Equal(deadline))
+	})
 
-	DescribeTable("should find host when Ingress is defined", func(ingress *networkingv1.Ingress, hostname string) {
-		Expect(controller.IngressCache.Add(ingress)).To(Succeed())
-		host, _ := controller.getExternalLinkHostAndCert()
-		Expect(hostname).To(Equal(host))
-	},
-		Entry("ingress with default backend host", validIngressDefaultBackend(components.VirtExportProxyServiceName), "backend-host"),
-		Entry("ingress with default backend host different service", validIngressDefaultBackend("other-service"), ""),
-		Entry("ingress with rules host", validIngressRules(components.VirtExportProxyServiceName), "rule-host"),
-		Entry("ingress with rules host different service", validIngressRules("other-service"), ""),
-		Entry("ingress with no default backend service", ingressDefaultBackendNoService(), ""),
-		Entry("ingress with rules no backend service", ingressRulesNoBackend(), ""),
-	)
+	It("Should use the cluster default deadline when the VMExport does not set one", func() {
+		testVMExport := createPVCVMExport()
+		clusterDeadline := metav1.Duration{Duration: 2 * time.Hour}
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				Deadline: &clusterDeadline,
+			},
+		})
+		Expect(controller.getDeadline(testVMExport)).To(Equal(clusterDeadline.Duration))
+	})
 
-	DescribeTable("should find host when route is defined", func(route *routev1.Route, hostname, expectedCert string) {
-		Expect(controller.RouteCache.Add(route)).To(Succeed())
-		Expect(controller.RouteConfigMapInformer.GetStore().Add(createRouteConfigMap())).To(Succeed())
-		host, cert := controller.getExternalLinkHostAndCert()
-		Expect(hostname).To(Equal(host))
-		Expect(expectedCert).To(Equal(cert))
-	},
-		Entry("route with service and host", routeToHostAndService(components.VirtExportProxyServiceName), "virt-exportproxy-kubevirt.apps-crc.testing", expectedPem),
-		Entry("route with different service and host", routeToHostAndService("other-service"), "", ""),
-		Entry("route with service and no ingress", routeToHostAndNoIngress(), "", ""),
-	)
+	It("Should use the VMExport deadline over the cluster default", func() {
+		testVMExport := createPVCVMExport()
+		vmExportDeadline := metav1.Duration{Duration: time.Hour}
+		testVMExport.Spec.Deadline = &vmExportDeadline
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				Deadline: &metav1.Duration{Duration: 2 * time.Hour},
+			},
+		})
+		Expect(controller.getDeadline(testVMExport)).To(Equal(vmExportDeadline.Duration))
+	})
 
-	It("should pick ingress over route if both exist", func() {
-		Expect(
-			controller.IngressCache.Add(validIngressDefaultBackend(components.VirtExportProxyServiceName)),
-		).To(Succeed())
-		Expect(controller.RouteCache.Add(routeToHostAndService(components.VirtExportProxyServiceName))).To(Succeed())
-		host, _ := controller.getExternalLinkHostAndCert()
-		Expect("backend-host").To(Equal(host))
+	It("Should use the hardcoded default requeue interval when the cluster config does not set one", func() {
+		Expect(controller.getRequeueInterval()).To(Equal(requeueTime))
 	})
-})
 
-func verifyLinksEmpty(vmExport *exportv1.VirtualMachineExport) {
-	Expect(vmExport.Status).ToNot(BeNil())
-	Expect(vmExport.Status.Links).ToNot(BeNil())
-	Expect(vmExport.Status.Links.Internal).To(BeNil())
-	Expect(vmExport.Status.Links.External).To(BeNil())
-}
+	It("Should use the cluster default requeue interval when configured", func() {
+		clusterRequeueInterval := metav1.Duration{Duration: 30 * time.Second}
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				RequeueInterval: &clusterRequeueInterval,
+			},
+		})
+		Expect(controller.getRequeueInterval()).To(Equal(clusterRequeueInterval.Duration))
+	})
 
-func verifyLinksInternal(vmExport *exportv1.VirtualMachineExport, expectedVolumeFormats ...exportv1.VirtualMachineExportVolumeFormat) {
-	Expect(vmExport.Status).ToNot(BeNil())
-	Expect(vmExport.Status.Links).ToNot(BeNil())
-	Expect(vmExport.Status.Links.Internal).NotTo(BeNil())
-	Expect(vmExport.Status.Links.Internal.Cert).NotTo(BeEmpty())
-	Expect(vmExport.Status.Links.Internal.Volumes).To(HaveLen(len(expectedVolumeFormats) / 2))
-	for _, volume := range vmExport.Status.Links.Internal.Volumes {
-		Expect(volume.Formats).To(HaveLen(2))
-		Expect(expectedVolumeFormats).To(ContainElements(volume.Formats))
-	}
-}
+	It("Should use a source's requeueAfter over the configured requeue interval", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				RequeueInterval: &metav1.Duration{Duration: 30 * time.Second},
+			},
+		})
+		Expect(controller.requeueInterval(&sourceVolumes{requeueAfter: restoreRequeueTime})).To(Equal(restoreRequeueTime))
+	})
 
-func verifyLinksExternal(vmExport *exportv1.VirtualMachineExport, link1Format exportv1.ExportVolumeFormat, link1Url string, link2Format exportv1.ExportVolumeFormat, link2Url string) {
-	Expect(vmExport.Status.Links.External).ToNot(BeNil())
-	Expect(vmExport.Status.Links.External.Cert).To(BeEmpty())
-	Expect(vmExport.Status.Links.External.Volumes).To(HaveLen(1))
-	Expect(vmExport.Status.Links.External.Volumes[0].Formats).To(HaveLen(2))
-	Expect(vmExport.Status.Links.External.Volumes[0].Formats).To(ContainElements(exportv1.VirtualMachineExportVolumeFormat{
-		Format: link1Format,
-		Url:    link1Url,
-	}, exportv1.VirtualMachineExportVolumeFormat{
-		Format: link2Format,
-		Url:    link2Url,
-	}))
-}
+	It("Should not expire when neither the VMExport nor the cluster config set a ttlDuration", func() {
+		testVMExport := createPVCVMExport()
+		expired, requeueAfter := controller.checkTTL(testVMExport)
+		Expect(expired).To(BeFalse())
+		Expect(requeueAfter).To(BeZero())
+	})
 
-func verifyKubevirtInternal(vmExport *exportv1.VirtualMachineExport, exportName, namespace string, volumeNames ...string) {
-	exportVolumeFormats := make([]exportv1.VirtualMachineExportVolumeFormat, 0)
-	for _, volumeName := range volumeNames {
-		exportVolumeFormats = append(exportVolumeFormats, exportv1.VirtualMachineExportVolumeFormat{
-			Format: exportv1.KubeVirtRaw,
-			Url:    fmt.Sprintf("https://%s.%s.svc/volumes/%s/disk.img", fmt.Sprintf("%s-%s", exportPrefix, exportName), namespace, volumeName),
+	It("Should use the cluster default ttlDuration when the VMExport does not set one", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.CreationTimestamp = metav1.NewTime(currentTime().Add(-2 * time.Hour))
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				TTLDuration: &metav1.Duration{Duration: time.Hour},
+			},
 		})
-		exportVolumeFormats = append(exportVolumeFormats, exportv1.VirtualMachineExportVolumeFormat{
-			Format: exportv1.KubeVirtGz,
-			Url:    fmt.Sprintf("https://%s.%s.svc/volumes/%s/disk.img.gz", fmt.Sprintf("%s-%s", exportPrefix, exportName), namespace, volumeName),
+		expired, _ := controller.checkTTL(testVMExport)
+		Expect(expired).To(BeTrue())
+	})
+
+	It("Should use the VMExport ttlDuration over the cluster default", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.CreationTimestamp = metav1.NewTime(currentTime().Add(-2 * time.Hour))
+		testVMExport.Spec.TTLDuration = &metav1.Duration{Duration: 3 * time.Hour}
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				TTLDuration: &metav1.Duration{Duration: time.Hour},
+			},
 		})
-	}
-	verifyLinksInternal(vmExport, exportVolumeFormats...)
-}
+		expired, requeueAfter := controller.checkTTL(testVMExport)
+		Expect(expired).To(BeFalse())
+		Expect(requeueAfter).To(BeNumerically(">", 0))
+	})
 
-func verifyKubevirtExternal(vmExport *exportv1.VirtualMachineExport, exportName, namespace, volumeName string) {
-	verifyLinksExternal(vmExport,
+	It("Should apply the cluster default pod resource requirements to the exporter pod", func() {
+		testVMExport := createPVCVMExport()
+		resourceRequirements := k8sv1.ResourceRequirements{
+			Requests: k8sv1.ResourceList{
+				k8sv1.ResourceCPU: resource.MustParse("100m"),
+			},
+		}
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PodResourceRequirements: &resourceRequirements,
+			},
+		})
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Resources).To(Equal(resourceRequirements))
+	})
+
+	It("Should use the VMExport pod resource requirements over the cluster default", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.PodResourceRequirements = &k8sv1.ResourceRequirements{
+			Requests: k8sv1.ResourceList{
+				k8sv1.ResourceCPU: resource.MustParse("500m"),
+			},
+		}
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PodResourceRequirements: &k8sv1.ResourceRequirements{
+					Requests: k8sv1.ResourceList{
+						k8sv1.ResourceCPU: resource.MustParse("100m"),
+					},
+				},
+			},
+		})
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Resources).To(Equal(*testVMExport.Spec.PodResourceRequirements))
+	})
+
+	It("Should create a restricted PodSecurity-compliant exporter pod", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*manifest.Spec.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(manifest.Spec.SecurityContext.SeccompProfile.Type).To(Equal(k8sv1.SeccompProfileTypeRuntimeDefault))
+		containerSecurityContext := manifest.Spec.Containers[0].SecurityContext
+		Expect(*containerSecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(containerSecurityContext.Capabilities.Drop).To(ConsistOf(k8sv1.Capability("ALL")))
+		Expect(*containerSecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(containerSecurityContext.SeccompProfile.Type).To(Equal(k8sv1.SeccompProfileTypeRuntimeDefault))
+	})
+
+	It("Should run the exporter container with a read-only root filesystem backed by a scratch emptyDir", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*manifest.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+
+		var scratchVolume *k8sv1.Volume
+		for i, volume := range manifest.Spec.Volumes {
+			if volume.Name == scratchSpaceVolumeName {
+				scratchVolume = &manifest.Spec.Volumes[i]
+			}
+		}
+		Expect(scratchVolume).ToNot(BeNil())
+		Expect(scratchVolume.EmptyDir).ToNot(BeNil())
+		Expect(scratchVolume.EmptyDir.SizeLimit.String()).To(Equal("1Gi"))
+
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      scratchSpaceVolumeName,
+			MountPath: scratchSpaceMountPath,
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "TMPDIR",
+			Value: scratchSpaceMountPath,
+		}))
+	})
+
+	It("Should prefer spec.scratchSpaceSize over the cluster default for the scratch emptyDir", func() {
+		clusterDefault := resource.MustParse("2Gi")
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				ScratchSpaceSize: &clusterDefault,
+			},
+		})
+		testVMExport := createPVCVMExport()
+		specSize := resource.MustParse("5Gi")
+		testVMExport.Spec.ScratchSpaceSize = &specSize
+
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		var scratchVolume *k8sv1.Volume
+		for i, volume := range manifest.Spec.Volumes {
+			if volume.Name == scratchSpaceVolumeName {
+				scratchVolume = &manifest.Spec.Volumes[i]
+			}
+		}
+		Expect(scratchVolume).ToNot(BeNil())
+		Expect(scratchVolume.EmptyDir.SizeLimit.String()).To(Equal("5Gi"))
+	})
+
+	It("Should use the cluster configured RunAsUser and FSGroup for the exporter pod", func() {
+		testVMExport := createPVCVMExport()
+		runAsUser := int64(1001)
+		fsGroup := int64(1002)
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PodRunAsUser: &runAsUser,
+				PodFSGroup:   &fsGroup,
+			},
+		})
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*manifest.Spec.SecurityContext.RunAsUser).To(Equal(runAsUser))
+		Expect(*manifest.Spec.SecurityContext.FSGroup).To(Equal(fsGroup))
+	})
+
+	It("Should not mount S3 credentials or set S3 env vars when spec.s3Upload is not set", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(s3Credentials))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(HavePrefix("S3_"))
+		}
+	})
+
+	It("Should mount S3 credentials and set S3 env vars when spec.s3Upload is set", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.S3Upload = &exportv1.VirtualMachineExportS3Upload{
+			Endpoint:             "https://s3.example.com",
+			Bucket:               "my-bucket",
+			Region:               "us-west-2",
+			CredentialsSecretRef: "my-s3-creds",
+		}
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: s3Credentials,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "my-s3-creds",
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      s3Credentials,
+			MountPath: "/s3-credentials",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElements(
+			k8sv1.EnvVar{Name: "S3_ENDPOINT", Value: "https://s3.example.com"},
+			k8sv1.EnvVar{Name: "S3_BUCKET", Value: "my-bucket"},
+			k8sv1.EnvVar{Name: "S3_REGION", Value: "us-west-2"},
+			k8sv1.EnvVar{Name: "S3_ACCESS_KEY_ID_FILE", Value: "/s3-credentials/accessKeyId"},
+			k8sv1.EnvVar{Name: "S3_SECRET_ACCESS_KEY_FILE", Value: "/s3-credentials/secretAccessKey"},
+		))
+	})
+
+	It("Should not mount an encryption key or set ENCRYPTION_KEY_FILE when spec.encryptionSecretRef is not set", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(encryptionKeyVolume))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("ENCRYPTION_KEY_FILE"))
+		}
+	})
+
+	It("Should mount the referenced secret and set ENCRYPTION_KEY_FILE when spec.encryptionSecretRef is set", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.EncryptionSecretRef = pointer.String("my-encryption-key")
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: encryptionKeyVolume,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "my-encryption-key",
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      encryptionKeyVolume,
+			MountPath: "/encryption-key",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "ENCRYPTION_KEY_FILE", Value: "/encryption-key/key"},
+		))
+	})
+
+	It("Should not mount a client CA bundle or set CLIENT_CA_FILE when spec.clientCertificateAuthorityRef is not set", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(clientCAVolume))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("CLIENT_CA_FILE"))
+		}
+	})
+
+	It("Should mount the referenced secret and set CLIENT_CA_FILE when spec.clientCertificateAuthorityRef is set", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ClientCertificateAuthorityRef = pointer.String("my-client-ca")
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: clientCAVolume,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "my-client-ca",
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      clientCAVolume,
+			MountPath: "/client-ca",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "CLIENT_CA_FILE", Value: "/client-ca/ca.crt"},
+		))
+	})
+
+	It("Should default ZSTD_COMPRESSION_LEVEL to 3 when the cluster has not configured one", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "ZSTD_COMPRESSION_LEVEL", Value: "3"},
+		))
+	})
+
+	It("Should set ZSTD_COMPRESSION_LEVEL from the cluster-wide vmExport.zstdCompressionLevel setting", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				ZstdCompressionLevel: pointer.Int32(19),
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "ZSTD_COMPRESSION_LEVEL", Value: "19"},
+		))
+	})
+
+	It("Should default GZIP_COMPRESSION_LEVEL to -1 when the cluster has not configured one", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "GZIP_COMPRESSION_LEVEL", Value: "-1"},
+		))
+	})
+
+	It("Should set GZIP_COMPRESSION_LEVEL from the cluster-wide vmExport.gzipCompressionLevel setting", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				GzipCompressionLevel: pointer.Int32(9),
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "GZIP_COMPRESSION_LEVEL", Value: "9"},
+		))
+	})
+
+	It("Should default SHUTDOWN_GRACE_PERIOD to 30 seconds and set TerminationGracePeriodSeconds accordingly when the cluster has not configured one", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "SHUTDOWN_GRACE_PERIOD", Value: "30s"},
+		))
+		Expect(*manifest.Spec.TerminationGracePeriodSeconds).To(Equal(int64(40)))
+	})
+
+	It("Should set SHUTDOWN_GRACE_PERIOD from the cluster-wide vmExport.shutdownGracePeriod setting", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				ShutdownGracePeriod: &metav1.Duration{Duration: 2 * time.Minute},
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "SHUTDOWN_GRACE_PERIOD", Value: "2m0s"},
+		))
+		Expect(*manifest.Spec.TerminationGracePeriodSeconds).To(Equal(int64(130)))
+	})
+
+	It("Should not set bandwidth limit env vars when the cluster has not configured any", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("PER_CONNECTION_BANDWIDTH_LIMIT"))
+			Expect(env.Name).ToNot(Equal("TOTAL_BANDWIDTH_LIMIT"))
+		}
+	})
+
+	It("Should set bandwidth limit env vars from the cluster-wide vmExport bandwidth settings", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PerConnectionBandwidthLimit: resource.NewQuantity(1024*1024, resource.BinarySI),
+				TotalBandwidthLimit:         resource.NewQuantity(10*1024*1024, resource.BinarySI),
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "PER_CONNECTION_BANDWIDTH_LIMIT", Value: strconv.Itoa(1024 * 1024)},
+		))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "TOTAL_BANDWIDTH_LIMIT", Value: strconv.Itoa(10 * 1024 * 1024)},
+		))
+	})
+
+	It("Should not set MAX_CONCURRENT_DOWNLOADS when the cluster has not configured one", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("MAX_CONCURRENT_DOWNLOADS"))
+		}
+	})
+
+	It("Should set MAX_CONCURRENT_DOWNLOADS from the cluster-wide vmExport.maxConcurrentDownloads setting", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				MaxConcurrentDownloads: pointer.Int32(3),
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "MAX_CONCURRENT_DOWNLOADS", Value: "3"},
+		))
+	})
+
+	It("Should not set PER_CLIENT_IP_REQUEST_LIMIT when the cluster has not configured one", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("PER_CLIENT_IP_REQUEST_LIMIT"))
+			Expect(env.Name).ToNot(Equal("PER_CLIENT_IP_REQUEST_BURST"))
+		}
+	})
+
+	It("Should set PER_CLIENT_IP_REQUEST_LIMIT and PER_CLIENT_IP_REQUEST_BURST from the cluster-wide vmExport settings", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PerClientIPRequestLimit: pointer.Int32(5),
+				PerClientIPRequestBurst: pointer.Int32(2),
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "PER_CLIENT_IP_REQUEST_LIMIT", Value: "5"},
+		))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "PER_CLIENT_IP_REQUEST_BURST", Value: "2"},
+		))
+	})
+
+	It("Should not set PRECOMPUTE_FORMATS when neither the export nor the cluster has configured one", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("PRECOMPUTE_FORMATS"))
+		}
+	})
+
+	It("Should set PRECOMPUTE_FORMATS from the cluster-wide vmExport settings", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PrecomputeFormats: []string{"gz", "zstd"},
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "PRECOMPUTE_FORMATS", Value: "gz,zstd"},
+		))
+	})
+
+	It("Should prefer the export's own spec.precomputeFormats over the cluster-wide setting", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PrecomputeFormats: []string{"zstd"},
+			},
+		})
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.PrecomputeFormats = []string{"gz"}
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "PRECOMPUTE_FORMATS", Value: "gz"},
+		))
+	})
+
+	It("Should not set NBD_LISTEN_ADDR when the cluster has not enabled NBD", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("NBD_LISTEN_ADDR"))
+		}
+	})
+
+	It("Should set NBD_LISTEN_ADDR when the cluster has enabled NBD", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				EnableNBD: pointer.Bool(true),
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "NBD_LISTEN_ADDR", Value: "0.0.0.0:10810"},
+		))
+	})
+
+	It("Should not set MIN_TLS_VERSION or TLS_CIPHERS env vars when the cluster has not configured a TLS policy", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("MIN_TLS_VERSION"))
+			Expect(env.Name).ToNot(Equal("TLS_CIPHERS"))
+		}
+	})
+
+	It("Should set MIN_TLS_VERSION and TLS_CIPHERS env vars from the cluster-wide TLS policy", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			TLSConfiguration: &virtv1.TLSConfiguration{
+				MinTLSVersion: virtv1.VersionTLS13,
+				Ciphers:       []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"},
+			},
+		})
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "MIN_TLS_VERSION", Value: "VersionTLS13"},
+		))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "TLS_CIPHERS", Value: "TLS_AES_128_GCM_SHA256,TLS_AES_256_GCM_SHA384"},
+		))
+	})
+
+	It("Should not set PRE_SERVE_COMMAND or POST_SERVE_COMMAND env vars when spec.hooks is not set", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal("PRE_SERVE_COMMAND"))
+			Expect(env.Name).ToNot(Equal("POST_SERVE_COMMAND"))
+		}
+	})
+
+	It("Should set PRE_SERVE_COMMAND and POST_SERVE_COMMAND env vars from spec.hooks", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.Hooks = &exportv1.VirtualMachineExportHooks{
+			PreServeCommand:  []string{"/usr/bin/scan", "--quick"},
+			PostServeCommand: []string{"/usr/bin/cleanup"},
+		}
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "PRE_SERVE_COMMAND", Value: "/usr/bin/scan,--quick"},
+		))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "POST_SERVE_COMMAND", Value: "/usr/bin/cleanup"},
+		))
+	})
+
+	It("Should not mount registry credentials or set REGISTRY env vars when spec.registryUpload is not set", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(registryCredentials))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(HavePrefix("REGISTRY_"))
+		}
+	})
+
+	It("Should mount registry credentials and set REGISTRY env vars when spec.registryUpload is set", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.RegistryUpload = &exportv1.VirtualMachineExportRegistryUpload{
+			Repository:           "registry.example.com/exports/my-vm",
+			Tag:                  "v1",
+			CredentialsSecretRef: "my-registry-creds",
+		}
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: registryCredentials,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "my-registry-creds",
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      registryCredentials,
+			MountPath: "/registry-credentials",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElements(
+			k8sv1.EnvVar{Name: "REGISTRY_REPOSITORY", Value: "registry.example.com/exports/my-vm"},
+			k8sv1.EnvVar{Name: "REGISTRY_TAG", Value: "v1"},
+			k8sv1.EnvVar{Name: "REGISTRY_USERNAME_FILE", Value: "/registry-credentials/username"},
+			k8sv1.EnvVar{Name: "REGISTRY_PASSWORD_FILE", Value: "/registry-credentials/password"},
+		))
+	})
+
+	It("Should not mount cluster upload kubeconfig or set CLUSTER_UPLOAD env vars when spec.clusterUpload is not set", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(clusterUploadKubeconfig))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(HavePrefix("CLUSTER_UPLOAD_"))
+		}
+	})
+
+	It("Should mount cluster upload kubeconfig and set CLUSTER_UPLOAD env vars when spec.clusterUpload is set", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ClusterUpload = &exportv1.VirtualMachineExportClusterUpload{
+			Namespace:           "target-namespace",
+			CDIUploadProxyURL:   "https://cdi-uploadproxy.target-cluster.example.com",
+			KubeconfigSecretRef: "my-target-kubeconfig",
+		}
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: clusterUploadKubeconfig,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: "my-target-kubeconfig",
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      clusterUploadKubeconfig,
+			MountPath: "/cluster-upload-kubeconfig",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElements(
+			k8sv1.EnvVar{Name: "CLUSTER_UPLOAD_NAMESPACE", Value: "target-namespace"},
+			k8sv1.EnvVar{Name: "CLUSTER_UPLOAD_PROXY_URL", Value: "https://cdi-uploadproxy.target-cluster.example.com"},
+			k8sv1.EnvVar{Name: "CLUSTER_UPLOAD_KUBECONFIG_FILE", Value: "/cluster-upload-kubeconfig/kubeconfig"},
+		))
+	})
+
+	It("Should not mount an OVA descriptor secret when vmiSpec is nil", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(ovaDescriptor))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(HavePrefix("OVA_"))
+		}
+	})
+
+	It("should add the node affinity of the bound PV to the exporter pod for a ReadWriteOnce PVC", func() {
+		testVMExport := createPVCVMExport()
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+			Spec: k8sv1.PersistentVolumeClaimSpec{
+				AccessModes: []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteOnce},
+				VolumeName:  "test-pv",
+			},
+		}
+		requirement := k8sv1.NodeSelectorRequirement{
+			Key:      "kubernetes.io/hostname",
+			Operator: k8sv1.NodeSelectorOpIn,
+			Values:   []string{"node01"},
+		}
+		Expect(pvInformer.GetStore().Add(&k8sv1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-pv",
+			},
+			Spec: k8sv1.PersistentVolumeSpec{
+				NodeAffinity: &k8sv1.VolumeNodeAffinity{
+					Required: &k8sv1.NodeSelector{
+						NodeSelectorTerms: []k8sv1.NodeSelectorTerm{
+							{MatchExpressions: []k8sv1.NodeSelectorRequirement{requirement}},
+						},
+					},
+				},
+			},
+		})).To(Succeed())
+
+		manifest, err := controller.createExporterPodManifest(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Affinity).ToNot(BeNil())
+		Expect(manifest.Spec.Affinity.NodeAffinity).ToNot(BeNil())
+		Expect(manifest.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ContainElement(
+			k8sv1.NodeSelectorTerm{MatchExpressions: []k8sv1.NodeSelectorRequirement{requirement}},
+		))
+	})
+
+	It("should not add node affinity when the PVC is not ReadWriteOnce", func() {
+		testVMExport := createPVCVMExport()
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+			Spec: k8sv1.PersistentVolumeClaimSpec{
+				AccessModes: []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteMany},
+				VolumeName:  "test-pv",
+			},
+		}
+		Expect(pvInformer.GetStore().Add(&k8sv1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-pv",
+			},
+			Spec: k8sv1.PersistentVolumeSpec{
+				NodeAffinity: &k8sv1.VolumeNodeAffinity{
+					Required: &k8sv1.NodeSelector{
+						NodeSelectorTerms: []k8sv1.NodeSelectorTerm{
+							{MatchExpressions: []k8sv1.NodeSelectorRequirement{{
+								Key:      "kubernetes.io/hostname",
+								Operator: k8sv1.NodeSelectorOpIn,
+								Values:   []string{"node01"},
+							}}},
+						},
+					},
+				},
+			},
+		})).To(Succeed())
+
+		manifest, err := controller.createExporterPodManifest(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Affinity).To(BeNil())
+	})
+
+	It("Should mount a generated OVA descriptor secret and set OVA env vars when vmiSpec is set", func() {
+		testVMExport := createPVCVMExport()
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		vmiSpec := &virtv1.VirtualMachineInstanceSpec{}
+		manifest, err := controller.createExporterPodManifest(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, vmiSpec)
+		Expect(err).ToNot(HaveOccurred())
+
+		secretName := naming.GetName(ovaDescriptor, testVMExport.Name, validation.DNS1035LabelMaxLength)
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: ovaDescriptor,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: secretName,
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      ovaDescriptor,
+			MountPath: "/ova-descriptor",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElements(
+			k8sv1.EnvVar{Name: "OVA_URI", Value: ovaURLPath},
+			k8sv1.EnvVar{Name: "OVA_DESCRIPTOR_FILE", Value: "/ova-descriptor/" + ovaDescriptorKey},
+			k8sv1.EnvVar{Name: "VOLUME0_EXPORT_OVA_DISK_NAME", Value: ovaDiskName(testPVC)},
+		))
+
+		secret, err := k8sClient.CoreV1().Secrets(testNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKey(ovaDescriptorKey))
+	})
+
+	It("Should always set the ALL_URI env var", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElement(
+			k8sv1.EnvVar{Name: "ALL_URI", Value: allURLPath},
+		))
+	})
+
+	It("Should not mount a manifest secret when the source is not a VirtualMachine", func() {
+		testVMExport := createPVCVMExport()
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		for _, volume := range manifest.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(manifestSecretVolume))
+		}
+		for _, env := range manifest.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(HavePrefix("MANIFEST_"))
+		}
+	})
+
+	It("Should mount a generated manifest secret and set MANIFEST env vars when the source is a VirtualMachine", func() {
+		testVMExport := createVMVMExport()
+		vm := &virtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testVmName,
+				Namespace: testNamespace,
+			},
+		}
+		vmInformer.GetStore().Add(vm)
+
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		secretName := naming.GetName(manifestSecretVolume, testVMExport.Name, validation.DNS1035LabelMaxLength)
+		Expect(manifest.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+			Name: manifestSecretVolume,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: secretName,
+				},
+			},
+		}))
+		Expect(manifest.Spec.Containers[0].VolumeMounts).To(ContainElement(k8sv1.VolumeMount{
+			Name:      manifestSecretVolume,
+			MountPath: "/manifest",
+		}))
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElements(
+			k8sv1.EnvVar{Name: "MANIFEST_URI", Value: manifestURLPath},
+			k8sv1.EnvVar{Name: "MANIFEST_FILE", Value: "/manifest/" + manifestKey},
+		))
+
+		secret, err := k8sClient.CoreV1().Secrets(testNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKey(manifestKey))
+		Expect(string(secret.Data[manifestKey])).To(ContainSubstring("name: " + testVmName))
+	})
+
+	It("Should additionally mount an expanded manifest and set EXPANDED_MANIFEST env vars when the VM references an instancetype", func() {
+		testVMExport := createVMVMExport()
+		revisionName := "instancetype-revision"
+		instancetypeSpec := instancetypev1alpha1.VirtualMachineInstancetypeSpec{
+			CPU: instancetypev1alpha1.CPUInstancetype{
+				Guest: 2,
+				Model: "host-model",
+			},
+			Memory: instancetypev1alpha1.MemoryInstancetype{
+				Guest: resource.MustParse("1Gi"),
+			},
+		}
+		specBytes, err := json.Marshal(instancetypeSpec)
+		Expect(err).ToNot(HaveOccurred())
+		revisionBytes, err := json.Marshal(instancetypev1alpha1.VirtualMachineInstancetypeSpecRevision{
+			APIVersion: instancetypev1alpha1.SchemeGroupVersion.String(),
+			Spec:       specBytes,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = k8sClient.AppsV1().ControllerRevisions(testNamespace).Create(context.Background(), &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      revisionName,
+				Namespace: testNamespace,
+			},
+			Data: runtime.RawExtension{Raw: revisionBytes},
+		}, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		vm := &virtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testVmName,
+				Namespace: testNamespace,
+			},
+			Spec: virtv1.VirtualMachineSpec{
+				Instancetype: &virtv1.InstancetypeMatcher{
+					Name:         "my-instancetype",
+					RevisionName: revisionName,
+				},
+			},
+		}
+		vmInformer.GetStore().Add(vm)
+
+		manifest, err := controller.createExporterPodManifest(testVMExport, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		secretName := naming.GetName(manifestSecretVolume, testVMExport.Name, validation.DNS1035LabelMaxLength)
+		Expect(manifest.Spec.Containers[0].Env).To(ContainElements(
+			k8sv1.EnvVar{Name: "EXPANDED_MANIFEST_URI", Value: expandedManifestURLPath},
+			k8sv1.EnvVar{Name: "EXPANDED_MANIFEST_FILE", Value: "/manifest/" + expandedManifestKey},
+		))
+
+		secret, err := k8sClient.CoreV1().Secrets(testNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKey(expandedManifestKey))
+		Expect(string(secret.Data[expandedManifestKey])).To(ContainSubstring("model: host-model"))
+		Expect(string(secret.Data[expandedManifestKey])).ToNot(ContainSubstring("instancetype:"))
+	})
+
+	It("Should not create an exporter pod when the namespace has reached its export quota", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		readyExport := createPVCVMExport()
+		readyExport.Name = "other-export"
+		readyExport.Status = &exportv1.VirtualMachineExportStatus{
+			Phase: exportv1.Ready,
+		}
+		vmExportInformer.GetStore().Add(readyExport)
+
+		quota := int32(1)
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				MaxConcurrentNamespaceExports: &quota,
+			},
+		})
+
+		testVMExport := createPVCVMExport()
+		sourceVolumes := &sourceVolumes{
+			volumes:     []*k8sv1.PersistentVolumeClaim{testPVC},
+			isPopulated: true,
+		}
+		exceeded, err := controller.isNamespaceExportQuotaExceeded(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exceeded).To(BeTrue())
+
+		sourceVolumes.quotaExceeded = true
+		pod, _, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+	})
+
+	It("Should not create an exporter pod when the export is paused", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.Paused = pointer.BoolPtr(true)
+		sourceVolumes := &sourceVolumes{
+			volumes:     []*k8sv1.PersistentVolumeClaim{testPVC},
+			isPopulated: true,
+		}
+		pod, _, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+	})
+
+	It("Should delete an existing exporter pod when the export is paused", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.Paused = pointer.BoolPtr(true)
+		testExportPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controller.getExportPodName(testVMExport),
+				Namespace: testNamespace,
+			},
+		}
+		podInformer.GetStore().Add(testExportPod)
+		k8sClient.CoreV1().Pods(testNamespace).Create(context.Background(), testExportPod, metav1.CreateOptions{})
+
+		pod, _, err := controller.manageExporterPod(testVMExport, &sourceVolumes{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+
+		_, err = k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), testExportPod.Name, metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not create an exporter pod for an unclaimed on-demand export", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.OnDemand = pointer.BoolPtr(true)
+		sourceVolumes := &sourceVolumes{
+			volumes:     []*k8sv1.PersistentVolumeClaim{testPVC},
+			isPopulated: true,
+		}
+		pod, _, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+	})
+
+	It("Should create an exporter pod for a claimed on-demand export", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.OnDemand = pointer.BoolPtr(true)
+		testVMExport.Annotations = map[string]string{exportv1.AnnotationExportClaimed: "true"}
+		sourceVolumes := &sourceVolumes{
+			volumes:     []*k8sv1.PersistentVolumeClaim{testPVC},
+			isPopulated: true,
+		}
+		pod, _, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).ToNot(BeNil())
+	})
+
+	It("Should create the configured number of exporter pod replicas behind the export service", func() {
+		replicas := int32(3)
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				Replicas: &replicas,
+			},
+		})
+
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		testVMExport := createPVCVMExport()
+		sourceVolumes := &sourceVolumes{
+			volumes:     []*k8sv1.PersistentVolumeClaim{testPVC},
+			isPopulated: true,
+		}
+		pod, _, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).ToNot(BeNil())
+
+		for i := int32(1); i < replicas; i++ {
+			replicaPod, err := k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), controller.getReplicaExporterPodName(testVMExport, i), metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicaPod.Labels[exportServiceLabel]).To(Equal(testVMExport.Name))
+		}
+	})
+
+	It("Should create a dedicated exporter pod and service for each additional volume when PerVolumePods is set", func() {
+		testPVC1 := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testPVCName,
+				Namespace: testNamespace,
+			},
+		}
+		testPVC2 := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-pvc",
+				Namespace: testNamespace,
+			},
+		}
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.PerVolumePods = pointer.BoolPtr(true)
+		sourceVolumes := &sourceVolumes{
+			volumes:     []*k8sv1.PersistentVolumeClaim{testPVC1, testPVC2},
+			isPopulated: true,
+		}
+		pod, _, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).ToNot(BeNil())
+
+		perVolumeName := controller.getPerVolumePodName(testVMExport, testPVC2)
+		perVolumePod, err := k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), perVolumeName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(perVolumePod.Labels[exportVolumeLabel]).To(Equal(testPVC2.Name))
+
+		perVolumeService, err := k8sClient.CoreV1().Services(testNamespace).Get(context.Background(), perVolumeName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(perVolumeService.Spec.Selector[exportVolumeLabel]).To(Equal(testPVC2.Name))
+
+		_, err = k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), controller.getPerVolumePodName(testVMExport, testPVC1), metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// The primary Service's selector must not match the per-volume pod's labels, or its
+		// Endpoints would also include per-volume pods serving unrelated PVCs.
+		primaryService := controller.createServiceManifest(testVMExport)
+		Expect(pod.Labels[exportVolumeLabel]).To(Equal(primaryService.Spec.Selector[exportVolumeLabel]))
+		Expect(perVolumePod.Labels[exportVolumeLabel]).ToNot(Equal(primaryService.Spec.Selector[exportVolumeLabel]))
+	})
+
+	It("Should delete a completed exporter pod immediately when PodTTLAfterFinished is not set", func() {
+		testVMExport := createPVCVMExport()
+		testExportPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controller.getExportPodName(testVMExport),
+				Namespace: testNamespace,
+			},
+			Status: k8sv1.PodStatus{
+				Phase: k8sv1.PodSucceeded,
+				ContainerStatuses: []k8sv1.ContainerStatus{
+					{State: k8sv1.ContainerState{Terminated: &k8sv1.ContainerStateTerminated{FinishedAt: metav1.Now()}}},
+				},
+			},
+		}
+		podInformer.GetStore().Add(testExportPod)
+		k8sClient.CoreV1().Pods(testNamespace).Create(context.Background(), testExportPod, metav1.CreateOptions{})
+
+		sourceVolumes := &sourceVolumes{isPopulated: true}
+		pod, requeue, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+		Expect(requeue).To(BeZero())
+
+		_, err = k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), testExportPod.Name, metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should keep a completed exporter pod around and requeue while within PodTTLAfterFinished", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PodTTLAfterFinished: &metav1.Duration{Duration: time.Hour},
+			},
+		})
+
+		testVMExport := createPVCVMExport()
+		testExportPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controller.getExportPodName(testVMExport),
+				Namespace: testNamespace,
+			},
+			Status: k8sv1.PodStatus{
+				Phase: k8sv1.PodSucceeded,
+				ContainerStatuses: []k8sv1.ContainerStatus{
+					{State: k8sv1.ContainerState{Terminated: &k8sv1.ContainerStateTerminated{FinishedAt: metav1.Now()}}},
+				},
+			},
+		}
+		podInformer.GetStore().Add(testExportPod)
+		k8sClient.CoreV1().Pods(testNamespace).Create(context.Background(), testExportPod, metav1.CreateOptions{})
+
+		sourceVolumes := &sourceVolumes{isPopulated: true}
+		pod, requeue, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).ToNot(BeNil())
+		Expect(requeue).To(BeNumerically(">", 0))
+		Expect(requeue).To(BeNumerically("<=", time.Hour))
+
+		_, err = k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), testExportPod.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should delete a completed exporter pod once PodTTLAfterFinished has elapsed", func() {
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				PodTTLAfterFinished: &metav1.Duration{Duration: time.Millisecond},
+			},
+		})
+
+		testVMExport := createPVCVMExport()
+		testExportPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controller.getExportPodName(testVMExport),
+				Namespace: testNamespace,
+			},
+			Status: k8sv1.PodStatus{
+				Phase: k8sv1.PodSucceeded,
+				ContainerStatuses: []k8sv1.ContainerStatus{
+					{State: k8sv1.ContainerState{Terminated: &k8sv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(time.Now().Add(-time.Hour))}}},
+				},
+			},
+		}
+		podInformer.GetStore().Add(testExportPod)
+		k8sClient.CoreV1().Pods(testNamespace).Create(context.Background(), testExportPod, metav1.CreateOptions{})
+
+		sourceVolumes := &sourceVolumes{isPopulated: true}
+		pod, requeue, err := controller.manageExporterPod(testVMExport, sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).To(BeNil())
+		Expect(requeue).To(BeZero())
+
+		_, err = k8sClient.CoreV1().Pods(testNamespace).Get(context.Background(), testExportPod.Name, metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should record a bounded history of Ready condition reason transitions", func() {
+		testVMExport := createPVCVMExport()
+		testService := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service"}}
+		vmExportCopy := testVMExport.DeepCopy()
+
+		err := controller.updateCommonVMExportStatusFields(testVMExport, vmExportCopy, nil, testService, &sourceVolumes{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmExportCopy.Status.ConditionTransitions).To(HaveLen(1))
+		Expect(vmExportCopy.Status.ConditionTransitions[0].Reason).To(Equal(inUseReason))
+
+		for i := 0; i < maxConditionTransitions+5; i++ {
+			testVMExport = vmExportCopy.DeepCopy()
+			vmExportCopy = testVMExport.DeepCopy()
+			exporterPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodPending}}
+			if i%2 == 0 {
+				exporterPod.Status.Phase = k8sv1.PodSucceeded
+			}
+			err := controller.updateCommonVMExportStatusFields(testVMExport, vmExportCopy, exporterPod, testService, &sourceVolumes{isPopulated: true, volumes: []*k8sv1.PersistentVolumeClaim{{}}})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(len(vmExportCopy.Status.ConditionTransitions)).To(BeNumerically("<=", maxConditionTransitions))
+		lastTransition := vmExportCopy.Status.ConditionTransitions[len(vmExportCopy.Status.ConditionTransitions)-1]
+		Expect(lastTransition.Reason).To(Equal(podCompletedReason))
+	})
+
+	It("Should set a NoExternalEndpoint condition when no Ingress, Route, or external Service is found", func() {
+		testVMExport := createPVCVMExport()
+		testService := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service"}}
+		vmExportCopy := testVMExport.DeepCopy()
+		exporterPod := &k8sv1.Pod{Status: k8sv1.PodStatus{
+			Phase:      k8sv1.PodRunning,
+			Conditions: []k8sv1.PodCondition{{Type: k8sv1.PodReady, Status: k8sv1.ConditionTrue}},
+		}}
+
+		err := controller.updateCommonVMExportStatusFields(testVMExport, vmExportCopy, exporterPod, testService, &sourceVolumes{isPopulated: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmExportCopy.Status.Links.External).To(BeNil())
+
+		var externalLinkCondition *exportv1.Condition
+		for i := range vmExportCopy.Status.Conditions {
+			if vmExportCopy.Status.Conditions[i].Type == exportv1.ConditionExternalLink {
+				externalLinkCondition = &vmExportCopy.Status.Conditions[i]
+			}
+		}
+		Expect(externalLinkCondition).ToNot(BeNil())
+		Expect(externalLinkCondition.Status).To(Equal(k8sv1.ConditionFalse))
+		Expect(externalLinkCondition.Reason).To(Equal(noExternalEndpointReason))
+		Expect(externalLinkCondition.Message).ToNot(BeEmpty())
+	})
+
+	It("Should not publish links while the exporter pod is running but hasn't passed its readiness probe", func() {
+		testVMExport := createPVCVMExport()
+		testService := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service"}}
+		vmExportCopy := testVMExport.DeepCopy()
+		exporterPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodRunning}}
+
+		err := controller.updateCommonVMExportStatusFields(testVMExport, vmExportCopy, exporterPod, testService, &sourceVolumes{isPopulated: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmExportCopy.Status.Phase).To(Equal(exportv1.Pending))
+		Expect(vmExportCopy.Status.Links.Internal).To(BeNil())
+		Expect(vmExportCopy.Status.Links.External).To(BeNil())
+
+		var readyCondition *exportv1.Condition
+		for i := range vmExportCopy.Status.Conditions {
+			if vmExportCopy.Status.Conditions[i].Type == exportv1.ConditionReady {
+				readyCondition = &vmExportCopy.Status.Conditions[i]
+			}
+		}
+		Expect(readyCondition).ToNot(BeNil())
+		Expect(readyCondition.Status).To(Equal(k8sv1.ConditionFalse))
+		Expect(readyCondition.Reason).To(Equal(podNotReadyReason))
+	})
+
+	It("Should create a secret based on the vm export", func() {
+		testVMExport := createPVCVMExport()
+		testExportPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-export-pod",
+			},
+			Spec: k8sv1.PodSpec{
+				Volumes: []k8sv1.Volume{
+					{
+						Name: certificates,
+						VolumeSource: k8sv1.VolumeSource{
+							Secret: &k8sv1.SecretVolumeSource{
+								SecretName: "test-secret",
+							},
+						},
+					},
+				},
+			},
+		}
+		k8sClient.Fake.PrependReactor("create", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			create, ok := action.(testing.CreateAction)
+			Expect(ok).To(BeTrue())
+			secret, ok := create.GetObject().(*k8sv1.Secret)
+			Expect(ok).To(BeTrue())
+			Expect(secret.GetName()).To(Equal(controller.getExportSecretName(testExportPod)))
+			Expect(secret.GetNamespace()).To(Equal(testNamespace))
+			return true, secret, nil
+		})
+		err := controller.getOrCreateCertSecret(testVMExport, testExportPod)
+		Expect(err).ToNot(HaveOccurred())
+		By("Creating again, and returning exists")
+		k8sClient.Fake.PrependReactor("create", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			create, ok := action.(testing.CreateAction)
+			Expect(ok).To(BeTrue())
+			secret, ok := create.GetObject().(*k8sv1.Secret)
+			Expect(ok).To(BeTrue())
+			Expect(secret.GetName()).To(Equal(controller.getExportSecretName(testExportPod)))
+			Expect(secret.GetNamespace()).To(Equal(testNamespace))
+			secret.Name = "something"
+			return true, secret, errors.NewAlreadyExists(schema.GroupResource{}, "already exists")
+		})
+		err = controller.getOrCreateCertSecret(testVMExport, testExportPod)
+		Expect(err).ToNot(HaveOccurred())
+		k8sClient.Fake.PrependReactor("create", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			create, ok := action.(testing.CreateAction)
+			Expect(ok).To(BeTrue())
+			secret, ok := create.GetObject().(*k8sv1.Secret)
+			Expect(ok).To(BeTrue())
+			Expect(secret.GetName()).To(Equal(controller.getExportSecretName(testExportPod)))
+			Expect(secret.GetNamespace()).To(Equal(testNamespace))
+			return true, nil, fmt.Errorf("failure")
+		})
+		err = controller.getOrCreateCertSecret(testVMExport, testExportPod)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("Should ignore invalid VMExports kind/api combinations", func(kind, apigroup string) {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.Source.Kind = kind
+		testVMExport.Spec.Source.APIGroup = &apigroup
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+	},
+		Entry("VirtualMachineSnapshot kind blank apigroup", "VirtualMachineSnapshot", ""),
+		Entry("VirtualMachineSnapshot kind invalid apigroup", "VirtualMachineSnapshot", "invalid"),
+		Entry("PersistentVolumeClaim kind invalid apigroup", "PersistentVolumeClaim", "invalid"),
+		Entry("PersistentVolumeClaim kind VMSnapshot apigroup", "PersistentVolumeClaim", snapshotv1.SchemeGroupVersion.Group),
+	)
+
+	It("should delete the VirtualMachineExport once its ttlDuration has elapsed", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		testVMExport.Spec.TTLDuration = &metav1.Duration{Duration: time.Hour}
+		deleted := false
+		vmExportClient.Fake.PrependReactor("delete", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			deleted = true
+			return true, nil, nil
+		})
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		Expect(deleted).To(BeTrue())
+	})
+
+	It("should requeue a VirtualMachineExport with a ttlDuration that hasn't elapsed yet", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.CreationTimestamp = metav1.NewTime(time.Now())
+		testVMExport.Spec.TTLDuration = &metav1.Duration{Duration: time.Hour}
+		deleted := false
+		vmExportClient.Fake.PrependReactor("delete", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			deleted = true
+			return true, nil, nil
+		})
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			return true, update.GetObject(), nil
+		})
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeNumerically(">", 0))
+		Expect(retry).To(BeNumerically("<=", time.Hour))
+		Expect(deleted).To(BeFalse())
+	})
+
+	It("should strip the owner reference from the auto-generated token secret and remove the finalizer when deletionPolicy is Retain", func() {
+		testVMExport := createPVCVMExport()
+		retainPolicy := exportv1.VirtualMachineExportDeletionPolicyRetain
+		testVMExport.Spec.DeletionPolicy = &retainPolicy
+		testVMExport.Finalizers = []string{vmExportFinalizer}
+		testVMExport.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		tokenSecret := &k8sv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controller.getTokenSecretName(testVMExport),
+				Namespace: testNamespace,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(testVMExport, exportGVK),
+				},
+			},
+		}
+		k8sClient.Fake.PrependReactor("get", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			return true, tokenSecret, nil
+		})
+		updatedSecret := false
+		k8sClient.Fake.PrependReactor("update", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			secret, ok := update.GetObject().(*k8sv1.Secret)
+			Expect(ok).To(BeTrue())
+			Expect(secret.OwnerReferences).To(BeEmpty())
+			updatedSecret = true
+			return true, secret, nil
+		})
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			Expect(vmExport.Finalizers).ToNot(ContainElement(vmExportFinalizer))
+			return true, vmExport, nil
+		})
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		Expect(updatedSecret).To(BeTrue())
+	})
+
+	It("getVolumeProgress should return nil without error when the exporter pod is not running", func() {
+		testVMExport := createPVCVMExport()
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: testPVCName, Namespace: testNamespace},
+		}
+		pendingPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodPending}}
+		service := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+		Expect(controller.getVolumeProgress(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, pendingPod, service)).To(BeNil())
+	})
+
+	It("getVolumeProgress should return nil without error when the exporter pod can't be reached", func() {
+		testVMExport := createPVCVMExport()
+		testPVC := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: testPVCName, Namespace: testNamespace},
+		}
+		runningPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodRunning}}
+		service := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+		Expect(controller.getVolumeProgress(testVMExport, []*k8sv1.PersistentVolumeClaim{testPVC}, runningPod, service)).To(BeNil())
+	})
+
+	It("maybeExtendExporterDeadline should do nothing when the exporter pod is not running", func() {
+		testVMExport := createPVCVMExport()
+		pendingPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodPending}}
+		service := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+		controller.maybeExtendExporterDeadline(testVMExport, pendingPod, service)
+	})
+
+	It("maybeExtendExporterDeadline should not re-push a deadline that hasn't moved forward", func() {
+		testVMExport := createPVCVMExport()
+		runningPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					exporterPodDeadlineAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339),
+				},
+			},
+			Status: k8sv1.PodStatus{Phase: k8sv1.PodRunning},
+		}
+		service := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+		controller.maybeExtendExporterDeadline(testVMExport, runningPod, service)
+	})
+
+	It("extraDiskImageFormats should return a raw format entry for every extra disk image found by the exporter", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: testPVCName}}
+		artifactMetadataByURI := map[string]artifactMetadata{
+			rawURI(testPVC): {Size: 100, Checksum: "primary-checksum"},
+			path.Join(path.Dir(rawURI(testPVC)), "hotplug-disk1.img"): {Size: 200, Checksum: "extra-checksum"},
+		}
+
+		formats := extraDiskImageFormats(testPVC, "host/base", artifactMetadataByURI)
+		Expect(formats).To(HaveLen(1))
+		Expect(formats[0].Format).To(Equal(exportv1.KubeVirtRaw))
+		Expect(formats[0].Url).To(Equal("https://" + path.Join("host/base", path.Dir(rawURI(testPVC)), "hotplug-disk1.img")))
+		Expect(*formats[0].Size).To(BeEquivalentTo(200))
+		Expect(formats[0].Checksum).To(Equal("extra-checksum"))
+	})
+
+	It("extraDiskImageFormats should return nothing when the exporter has not found any extra disk images", func() {
+		testPVC := &k8sv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: testPVCName}}
+		artifactMetadataByURI := map[string]artifactMetadata{
+			rawURI(testPVC):     {Size: 100, Checksum: "primary-checksum"},
+			rawGzipURI(testPVC): {Size: 50, Checksum: "gz-checksum"},
+		}
+
+		Expect(extraDiskImageFormats(testPVC, "host/base", artifactMetadataByURI)).To(BeEmpty())
+	})
+
+	It("addVolumeEnvironmentVariables should set include/exclude pattern env vars for a filesystem PVC", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ArchiveIncludePatterns = []string{"*.txt", "data/*"}
+		testVMExport.Spec.ArchiveExcludePatterns = []string{"*.tmp"}
+		testPVC := createPVC(testPVCName, "archive")
+
+		exportContainer := &k8sv1.Container{}
+		controller.addVolumeEnvironmentVariables(exportContainer, testVMExport, testPVC, 0, "/mount")
+
+		Expect(exportContainer.Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "VOLUME0_EXPORT_INCLUDE_PATTERNS",
+			Value: "*.txt,data/*",
+		}))
+		Expect(exportContainer.Env).To(ContainElement(k8sv1.EnvVar{
+			Name:  "VOLUME0_EXPORT_EXCLUDE_PATTERNS",
+			Value: "*.tmp",
+		}))
+	})
+
+	It("addVolumeEnvironmentVariables should not set pattern env vars when none are configured", func() {
+		testVMExport := createPVCVMExport()
+		testPVC := createPVC(testPVCName, "archive")
+
+		exportContainer := &k8sv1.Container{}
+		controller.addVolumeEnvironmentVariables(exportContainer, testVMExport, testPVC, 0, "/mount")
+
+		for _, env := range exportContainer.Env {
+			Expect(env.Name).ToNot(Equal("VOLUME0_EXPORT_INCLUDE_PATTERNS"))
+			Expect(env.Name).ToNot(Equal("VOLUME0_EXPORT_EXCLUDE_PATTERNS"))
+		}
+	})
+
+	It("getArtifactMetadata should return nil without error when the exporter pod is not running", func() {
+		pendingPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodPending}}
+		service := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+		Expect(controller.getArtifactMetadata(pendingPod, service)).To(BeNil())
+	})
+
+	It("getArtifactMetadata should return nil without error when the exporter pod can't be reached", func() {
+		runningPod := &k8sv1.Pod{Status: k8sv1.PodStatus{Phase: k8sv1.PodRunning}}
+		service := &k8sv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+		Expect(controller.getArtifactMetadata(runningPod, service)).To(BeNil())
+	})
+
+	DescribeTable("should find host when Ingress is defined", func(ingress *networkingv1.Ingress, hostname string) {
+		Expect(controller.IngressCache.Add(ingress)).To(Succeed())
+		host, _ := controller.getExternalLinkHostAndCert()
+		Expect(hostname).To(Equal(host))
+	},
+		Entry("ingress with default backend host", validIngressDefaultBackend(components.VirtExportProxyServiceName), "backend-host"),
+		Entry("ingress with default backend host different service", validIngressDefaultBackend("other-service"), ""),
+		Entry("ingress with rules host", validIngressRules(components.VirtExportProxyServiceName), "rule-host"),
+		Entry("ingress with rules host different service", validIngressRules("other-service"), ""),
+		Entry("ingress with no default backend service", ingressDefaultBackendNoService(), ""),
+		Entry("ingress with rules no backend service", ingressRulesNoBackend(), ""),
+	)
+
+	DescribeTable("should find host when route is defined", func(route *routev1.Route, hostname, expectedCert string) {
+		Expect(controller.RouteCache.Add(route)).To(Succeed())
+		Expect(controller.RouteConfigMapInformer.GetStore().Add(createRouteConfigMap())).To(Succeed())
+		host, cert := controller.getExternalLinkHostAndCert()
+		Expect(hostname).To(Equal(host))
+		Expect(expectedCert).To(Equal(cert))
+	},
+		Entry("route with service and host", routeToHostAndService(components.VirtExportProxyServiceName), "virt-exportproxy-kubevirt.apps-crc.testing", expectedPem),
+		Entry("route with different service and host", routeToHostAndService("other-service"), "", ""),
+		Entry("route with service and no ingress", routeToHostAndNoIngress(), "", ""),
+	)
+
+	It("should pick ingress over route if both exist", func() {
+		Expect(
+			controller.IngressCache.Add(validIngressDefaultBackend(components.VirtExportProxyServiceName)),
+		).To(Succeed())
+		Expect(controller.RouteCache.Add(routeToHostAndService(components.VirtExportProxyServiceName))).To(Succeed())
+		host, _ := controller.getExternalLinkHostAndCert()
+		Expect("backend-host").To(Equal(host))
+	})
+
+	It("should return a hostAndCert entry for every matching Ingress and Route", func() {
+		Expect(
+			controller.IngressCache.Add(validIngressDefaultBackend(components.VirtExportProxyServiceName)),
+		).To(Succeed())
+		Expect(controller.RouteCache.Add(routeToHostAndService(components.VirtExportProxyServiceName))).To(Succeed())
+		Expect(controller.RouteConfigMapInformer.GetStore().Add(createRouteConfigMap())).To(Succeed())
+
+		hostsAndCerts := controller.getExternalLinkHostsAndCerts()
+		Expect(hostsAndCerts).To(HaveLen(2))
+		Expect(hostsAndCerts[0].host).To(Equal("backend-host"))
+		Expect(hostsAndCerts[1].host).To(Equal("virt-exportproxy-kubevirt.apps-crc.testing"))
+	})
+
+	It("should find host from a Gateway API HTTPRoute targeting the export proxy service", func() {
+		httpRoute := validHTTPRoute(components.VirtExportProxyServiceName, "gateway-host")
+		_, err := dynamicClient.Resource(httpRouteResource).Namespace(testNamespace).Create(context.Background(), httpRoute, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		host, cert := controller.getExternalLinkHostAndCert()
+		Expect(host).To(Equal("gateway-host"))
+		internalCert, err := controller.internalExportCa()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cert).To(Equal(internalCert))
+	})
+
+	It("should ignore a Gateway API HTTPRoute that doesn't target the export proxy service", func() {
+		httpRoute := validHTTPRoute("other-service", "gateway-host")
+		_, err := dynamicClient.Resource(httpRouteResource).Namespace(testNamespace).Create(context.Background(), httpRoute, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		host, _ := controller.getExternalLinkHostAndCert()
+		Expect(host).To(BeEmpty())
+	})
+
+	It("should ignore an Ingress that doesn't match the configured ingressSelector", func() {
+		unselectedIngress := validIngressDefaultBackend(components.VirtExportProxyServiceName)
+		Expect(controller.IngressCache.Add(unselectedIngress)).To(Succeed())
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				IngressSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"export-entrypoint": "public"},
+				},
+			},
+		})
+		host, _ := controller.getExternalLinkHostAndCert()
+		Expect(host).To(BeEmpty())
+	})
+
+	It("should use an Ingress matching the configured ingressSelector", func() {
+		selectedIngress := validIngressDefaultBackend(components.VirtExportProxyServiceName)
+		selectedIngress.Labels = map[string]string{"export-entrypoint": "public"}
+		Expect(controller.IngressCache.Add(selectedIngress)).To(Succeed())
+		controller.ClusterConfig, _, _ = testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{
+			ExportConfiguration: &virtv1.ExportConfiguration{
+				IngressSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"export-entrypoint": "public"},
+				},
+			},
+		})
+		host, _ := controller.getExternalLinkHostAndCert()
+		Expect(host).To(Equal("backend-host"))
+	})
+
+	It("should return empty host when spec.externalHostname is not set", func() {
+		testVMExport := createPVCVMExport()
+		host, cert, err := controller.getSpecExternalHostAndCert(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(host).To(BeEmpty())
+		Expect(cert).To(BeEmpty())
+	})
+
+	It("should use spec.externalHostname over an Ingress or Route", func() {
+		Expect(
+			controller.IngressCache.Add(validIngressDefaultBackend(components.VirtExportProxyServiceName)),
+		).To(Succeed())
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ExternalHostname = pointer.String("export.example.com")
+		host, _, err := controller.getSpecExternalHostAndCert(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(host).To(Equal("export.example.com"))
+	})
+
+	It("should publish the cert from spec.externalTLSSecretRef when set", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ExternalHostname = pointer.String("export.example.com")
+		testVMExport.Spec.ExternalTLSSecretRef = pointer.String("external-tls-secret")
+		_, err := k8sClient.CoreV1().Secrets(testVMExport.Namespace).Create(context.Background(), &k8sv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "external-tls-secret",
+				Namespace: testVMExport.Namespace,
+			},
+			Type: k8sv1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": []byte("custom cert"),
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		host, cert, err := controller.getSpecExternalHostAndCert(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(host).To(Equal("export.example.com"))
+		Expect(cert).To(Equal("custom cert"))
+	})
+
+	It("should return an empty cert when spec.externalTLSSecretRef doesn't exist yet", func() {
+		testVMExport := createPVCVMExport()
+		testVMExport.Spec.ExternalHostname = pointer.String("export.example.com")
+		testVMExport.Spec.ExternalTLSSecretRef = pointer.String("missing-secret")
+		host, cert, err := controller.getSpecExternalHostAndCert(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(host).To(Equal("export.example.com"))
+		Expect(cert).To(BeEmpty())
+	})
+
+	It("should expire a link at the earlier of the exporter pod deadline and its cert expiring", func() {
+		testVMExport := createPVCVMExport()
+		podCreationTime := metav1.Now()
+		exporterPod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: podCreationTime},
+		}
+
+		expiration := controller.getLinkExpiration(testVMExport, exporterPod)
+		Expect(expiration).ToNot(BeNil())
+		Expect(expiration.Time).To(Equal(podCreationTime.Add(certExpiry)))
+
+		testVMExport.Spec.Deadline = &metav1.Duration{Duration: time.Hour}
+		expiration = controller.getLinkExpiration(testVMExport, exporterPod)
+		Expect(expiration).ToNot(BeNil())
+		Expect(expiration.Time).To(Equal(podCreationTime.Add(time.Hour)))
+	})
+
+	It("should not compute a link expiration before the exporter pod exists", func() {
+		testVMExport := createPVCVMExport()
+		Expect(controller.getLinkExpiration(testVMExport, nil)).To(BeNil())
+	})
+})
+
+func verifyLinksEmpty(vmExport *exportv1.VirtualMachineExport) {
+	Expect(vmExport.Status).ToNot(BeNil())
+	Expect(vmExport.Status.Links).ToNot(BeNil())
+	Expect(vmExport.Status.Links.Internal).To(BeNil())
+	Expect(vmExport.Status.Links.External).To(BeNil())
+}
+
+func verifyLinksInternal(vmExport *exportv1.VirtualMachineExport, expectedVolumeFormats ...exportv1.VirtualMachineExportVolumeFormat) {
+	Expect(vmExport.Status).ToNot(BeNil())
+	Expect(vmExport.Status.Links).ToNot(BeNil())
+	Expect(vmExport.Status.Links.Internal).NotTo(BeNil())
+	Expect(vmExport.Status.Links.Internal.Cert).NotTo(BeEmpty())
+	Expect(vmExport.Status.Links.Internal.ExpirationTimestamp).NotTo(BeNil())
+	Expect(vmExport.Status.Links.Internal.Volumes).To(HaveLen(len(expectedVolumeFormats) / 2))
+	for _, volume := range vmExport.Status.Links.Internal.Volumes {
+		Expect(volume.Formats).To(HaveLen(2))
+		Expect(expectedVolumeFormats).To(ContainElements(volume.Formats))
+	}
+}
+
+func verifyLinksExternal(vmExport *exportv1.VirtualMachineExport, link1Format exportv1.ExportVolumeFormat, link1Url string, link2Format exportv1.ExportVolumeFormat, link2Url string) {
+	Expect(vmExport.Status.Links.External).ToNot(BeNil())
+	Expect(vmExport.Status.Links.External.Cert).To(BeEmpty())
+	Expect(vmExport.Status.Links.External.Volumes).To(HaveLen(1))
+	Expect(vmExport.Status.Links.External.Volumes[0].Formats).To(HaveLen(2))
+	Expect(vmExport.Status.Links.External.Volumes[0].Formats).To(ContainElements(exportv1.VirtualMachineExportVolumeFormat{
+		Format: link1Format,
+		Url:    link1Url,
+	}, exportv1.VirtualMachineExportVolumeFormat{
+		Format: link2Format,
+		Url:    link2Url,
+	}))
+}
+
+func verifyKubevirtInternal(vmExport *exportv1.VirtualMachineExport, exportName, namespace string, volumeNames ...string) {
+	exportVolumeFormats := make([]exportv1.VirtualMachineExportVolumeFormat, 0)
+	for _, volumeName := range volumeNames {
+		exportVolumeFormats = append(exportVolumeFormats, exportv1.VirtualMachineExportVolumeFormat{
+			Format: exportv1.KubeVirtRaw,
+			Url:    fmt.Sprintf("https://%s.%s.svc/volumes/%s/disk.img", fmt.Sprintf("%s-%s", exportPrefix, exportName), namespace, volumeName),
+		})
+		exportVolumeFormats = append(exportVolumeFormats, exportv1.VirtualMachineExportVolumeFormat{
+			Format: exportv1.KubeVirtGz,
+			Url:    fmt.Sprintf("https://%s.%s.svc/volumes/%s/disk.img.gz", fmt.Sprintf("%s-%s", exportPrefix, exportName), namespace, volumeName),
+		})
+	}
+	verifyLinksInternal(vmExport, exportVolumeFormats...)
+}
+
+func verifyKubevirtExternal(vmExport *exportv1.VirtualMachineExport, exportName, namespace, volumeName string) {
+	verifyLinksExternal(vmExport,
 		exportv1.KubeVirtRaw,
 		fmt.Sprintf("https://virt-exportproxy-kubevirt.apps-crc.testing/api/export.kubevirt.io/v1alpha1/namespaces/%s/virtualmachineexports/%s/volumes/%s/disk.img", namespace, exportName, volumeName),
 		exportv1.KubeVirtGz,
@@ -854,6 +2675,31 @@ func verifyArchiveInternal(vmExport *exportv1.VirtualMachineExport, exportName,
 		})
 }
 
+func validHTTPRoute(backendServiceName, hostname string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      "export-route",
+				"namespace": testNamespace,
+			},
+			"spec": map[string]interface{}{
+				"hostnames": []interface{}{hostname},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"backendRefs": []interface{}{
+							map[string]interface{}{
+								"name": backendServiceName,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func routeToHostAndService(serviceName string) *routev1.Route {
 	return &routev1.Route{
 		Spec: routev1.RouteSpec{
@@ -912,7 +2758,24 @@ func createPVCVMExport() *exportv1.VirtualMachineExport {
 				Kind:     "PersistentVolumeClaim",
 				Name:     testPVCName,
 			},
-			TokenSecretRef: "token",
+			TokenSecretRef: pointer.String("token"),
+		},
+	}
+}
+
+func createDataVolumeVMExport() *exportv1.VirtualMachineExport {
+	return &exportv1.VirtualMachineExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: testNamespace,
+		},
+		Spec: exportv1.VirtualMachineExportSpec{
+			Source: k8sv1.TypedLocalObjectReference{
+				APIGroup: &cdiv1.SchemeGroupVersion.Group,
+				Kind:     "DataVolume",
+				Name:     testPVCName,
+			},
+			TokenSecretRef: pointer.String("token"),
 		},
 	}
 }
@@ -930,7 +2793,7 @@ func createSnapshotVMExport() *exportv1.VirtualMachineExport {
 				Kind:     "VirtualMachineSnapshot",
 				Name:     testVmsnapshotName,
 			},
-			TokenSecretRef: "token",
+			TokenSecretRef: pointer.String("token"),
 		},
 	}
 }
@@ -948,7 +2811,41 @@ func createVMVMExport() *exportv1.VirtualMachineExport {
 				Kind:     "VirtualMachine",
 				Name:     testVmName,
 			},
-			TokenSecretRef: "token",
+			TokenSecretRef: pointer.String("token"),
+		},
+	}
+}
+
+func createVolumeSnapshotVMExport() *exportv1.VirtualMachineExport {
+	return &exportv1.VirtualMachineExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: testNamespace,
+		},
+		Spec: exportv1.VirtualMachineExportSpec{
+			Source: k8sv1.TypedLocalObjectReference{
+				APIGroup: &vsv1.SchemeGroupVersion.Group,
+				Kind:     "VolumeSnapshot",
+				Name:     testVolumeSnapshotSourceName,
+			},
+			TokenSecretRef: pointer.String("token"),
+		},
+	}
+}
+
+func createDataSourceVMExport() *exportv1.VirtualMachineExport {
+	return &exportv1.VirtualMachineExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: testNamespace,
+		},
+		Spec: exportv1.VirtualMachineExportSpec{
+			Source: k8sv1.TypedLocalObjectReference{
+				APIGroup: &cdiv1.SchemeGroupVersion.Group,
+				Kind:     "DataSource",
+				Name:     testDataSourceName,
+			},
+			TokenSecretRef: pointer.String("token"),
 		},
 	}
 }
@@ -20,18 +20,33 @@
 package export
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"net"
+	"net/http"
 	"path"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 
 	exportv1 "kubevirt.io/api/export/v1alpha1"
+	"kubevirt.io/client-go/log"
 
 	"kubevirt.io/kubevirt/pkg/certificates/triple/cert"
 	"kubevirt.io/kubevirt/pkg/controller"
@@ -44,54 +59,578 @@ const (
 	routeCaKey           = "ca.crt"
 	subjectAltNameId     = "2.5.29.17"
 
-	apiGroup              = "export.kubevirt.io"
-	apiVersion            = "v1alpha1"
-	exportResourceName    = "virtualmachineexports"
+	apiGroup           = "export.kubevirt.io"
+	apiVersion         = "v1alpha1"
+	exportResourceName = "virtualmachineexports"
+
 	gv                    = apiGroup + "/" + apiVersion
+	gatewayHTTPRouteGroup = "gateway.networking.k8s.io"
 	externalUrlLinkFormat = "/api/" + gv + "/namespaces/%s/" + exportResourceName + "/%s"
+
+	internalProgressPath         = "/internal/progress"
+	internalMetadataPath         = "/internal/metadata"
+	internalS3UploadPath         = "/internal/s3upload"
+	internalRegistryUploadPath   = "/internal/registryupload"
+	internalClusterUploadPath    = "/internal/clusterupload"
+	internalHooksPath            = "/internal/hooks"
+	internalDeadlinePath         = "/internal/deadline"
+	volumeProgressRequestTimeout = 5 * time.Second
 )
 
-func (ctrl *VMExportController) getInteralLinks(pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, service *corev1.Service) (*exportv1.VirtualMachineExportLink, error) {
+// httpRouteResource is the GroupVersionResource of the Gateway API HTTPRoute CRD. It is looked up
+// with the dynamic client instead of a typed client, since sigs.k8s.io/gateway-api is not a
+// dependency of this project and Gateway API support is optional, depending on the cluster.
+var httpRouteResource = schema.GroupVersionResource{Group: gatewayHTTPRouteGroup, Version: "v1", Resource: "httproutes"}
+
+func (ctrl *VMExportController) getInteralLinks(pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, service *corev1.Service, vmExport *exportv1.VirtualMachineExport) (*exportv1.VirtualMachineExportLink, error) {
 	internalCert, err := ctrl.internalExportCa()
 	if err != nil {
 		return nil, err
 	}
 	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
-	return ctrl.getLinks(pvcs, exporterPod, host, internalCert)
+	return ctrl.getLinks(pvcs, exporterPod, service, host, internalCert, vmExport)
 }
 
-func (ctrl *VMExportController) getExternalLinks(pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, export *exportv1.VirtualMachineExport) (*exportv1.VirtualMachineExportLink, error) {
+// getExternalLinks returns one VirtualMachineExportLink per externally reachable entry point for
+// export: spec.externalHostname if set, otherwise every Ingress and Route matching
+// ingressSelector that points at the export, or, failing that, the address of the Service created
+// for spec.serviceType NodePort or LoadBalancer. It returns nil, without error, if none of those
+// are available.
+func (ctrl *VMExportController) getExternalLinks(pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, service *corev1.Service, export *exportv1.VirtualMachineExport) ([]exportv1.VirtualMachineExportLink, error) {
 	urlPath := fmt.Sprintf(externalUrlLinkFormat, export.Namespace, export.Name)
-	externalLinkHost, cert := ctrl.getExternalLinkHostAndCert()
+
+	externalLinkHost, cert, err := ctrl.getSpecExternalHostAndCert(export)
+	if err != nil {
+		return nil, err
+	}
 	if externalLinkHost != "" {
-		hostAndBase := path.Join(externalLinkHost, urlPath)
-		return ctrl.getLinks(pvcs, exporterPod, hostAndBase, cert)
+		link, err := ctrl.getLinks(pvcs, exporterPod, service, path.Join(externalLinkHost, urlPath), cert, export)
+		if err != nil {
+			return nil, err
+		}
+		return []exportv1.VirtualMachineExportLink{*link}, nil
+	}
+
+	if hostsAndCerts := ctrl.getExternalLinkHostsAndCerts(); len(hostsAndCerts) > 0 {
+		links := make([]exportv1.VirtualMachineExportLink, 0, len(hostsAndCerts))
+		for _, hostAndCert := range hostsAndCerts {
+			link, err := ctrl.getLinks(pvcs, exporterPod, service, path.Join(hostAndCert.host, urlPath), hostAndCert.cert, export)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, *link)
+		}
+		return links, nil
+	}
+
+	// No Ingress or Route is available to reach the export externally. If the export's
+	// spec.serviceType requested a NodePort or LoadBalancer service, fall back to the address
+	// that service exposes, reaching the exporter pod directly rather than through virt-api.
+	if serviceHost, serviceCert := ctrl.getServiceExternalHostAndCert(exporterPod, service); serviceHost != "" {
+		link, err := ctrl.getLinks(pvcs, exporterPod, service, serviceHost, serviceCert, export)
+		if err != nil {
+			return nil, err
+		}
+		return []exportv1.VirtualMachineExportLink{*link}, nil
 	}
 	return nil, nil
 }
 
-func (ctrl *VMExportController) getLinks(pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, hostAndBase, cert string) (*exportv1.VirtualMachineExportLink, error) {
+// getSpecExternalHostAndCert returns the externally reachable host (and serving cert) explicitly
+// requested by export's spec.externalHostname and spec.externalTLSSecretRef, taking precedence
+// over auto-detecting them from an Ingress or Route. It returns "", "", nil if
+// spec.externalHostname isn't set, leaving auto-detection to take over.
+func (ctrl *VMExportController) getSpecExternalHostAndCert(export *exportv1.VirtualMachineExport) (string, string, error) {
+	if export.Spec.ExternalHostname == nil {
+		return "", "", nil
+	}
+	cert, err := ctrl.getExternalTLSSecretCert(export)
+	if err != nil {
+		return "", "", err
+	}
+	return *export.Spec.ExternalHostname, cert, nil
+}
+
+// getExternalTLSSecretCert returns the tls.crt contents of the secret referenced by export's
+// spec.externalTLSSecretRef. It returns "", without error, if that field isn't set or the secret
+// doesn't exist yet.
+func (ctrl *VMExportController) getExternalTLSSecretCert(export *exportv1.VirtualMachineExport) (string, error) {
+	if export.Spec.ExternalTLSSecretRef == nil {
+		return "", nil
+	}
+	secret, err := ctrl.Client.CoreV1().Secrets(export.Namespace).Get(context.Background(), *export.Spec.ExternalTLSSecretRef, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(secret.Data["tls.crt"])), nil
+}
+
+// bracketIPHost wraps host in square brackets if it is a literal IPv6 address, so it can be
+// safely embedded in a URL or concatenated with a ":port" suffix without the address's own colons
+// being misread as a port separator. It returns host unchanged for an IPv4 address or a hostname.
+func bracketIPHost(host string) string {
+	if ip := net.ParseIP(host); ip != nil && strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// getServiceExternalHostAndCert returns the externally reachable host (and serving CA cert) for
+// service, if its type is NodePort or LoadBalancer and that address is already known. It returns
+// "", "" if service is still ClusterIP, or if the address isn't available yet, for example while
+// waiting for a cloud provider to assign a LoadBalancer address.
+func (ctrl *VMExportController) getServiceExternalHostAndCert(exporterPod *corev1.Pod, service *corev1.Service) (string, string) {
+	var host string
+	switch service.Spec.Type {
+	case corev1.ServiceTypeNodePort:
+		if exporterPod == nil || exporterPod.Status.HostIP == "" || len(service.Spec.Ports) == 0 || service.Spec.Ports[0].NodePort == 0 {
+			return "", ""
+		}
+		host = net.JoinHostPort(exporterPod.Status.HostIP, fmt.Sprintf("%d", service.Spec.Ports[0].NodePort))
+	case corev1.ServiceTypeLoadBalancer:
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				host = ingress.Hostname
+			} else if ingress.IP != "" {
+				host = bracketIPHost(ingress.IP)
+			}
+			if host != "" {
+				break
+			}
+		}
+	}
+	if host == "" {
+		return "", ""
+	}
+	cert, err := ctrl.internalExportCa()
+	if err != nil {
+		return "", ""
+	}
+	return host, cert
+}
+
+// getInternalClient builds an HTTP client trusted to verify the exporter pod's serving
+// certificate, using the internal export CA bundle. It returns a nil client, without error, if
+// the CA bundle isn't available yet.
+func (ctrl *VMExportController) getInternalClient() (*http.Client, error) {
+	internalCert, err := ctrl.internalExportCa()
+	if err != nil || internalCert == "" {
+		return nil, err
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM([]byte(internalCert)) {
+		return nil, nil
+	}
+	return &http.Client{
+		Timeout:   volumeProgressRequestTimeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certPool}},
+	}, nil
+}
+
+// getVolumeProgress fetches the transfer progress of each volume in pvcs from the exporter pod's
+// internal progress endpoint, and, if set, the status of its uploads to vmExport.Spec.S3Upload
+// vmExport.Spec.RegistryUpload and vmExport.Spec.ClusterUpload, all reached through service's
+// cluster DNS name. It returns
+// nil, without error, if the exporter pod isn't running yet or the endpoints can't be reached,
+// since a momentarily unreachable exporter pod shouldn't fail the rest of the status update.
+func (ctrl *VMExportController) getVolumeProgress(vmExport *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, service *corev1.Service) []exportv1.VirtualMachineExportVolumeStatus {
+	if exporterPod == nil || exporterPod.Status.Phase != corev1.PodRunning {
+		return nil
+	}
+
+	client, err := ctrl.getInternalClient()
+	if err != nil || client == nil {
+		return nil
+	}
+	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	resp, err := client.Get(fmt.Sprintf("https://%s%s", host, internalProgressPath))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to fetch volume progress from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var activityByName map[string]volumeActivity
+	if err := json.NewDecoder(resp.Body).Decode(&activityByName); err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to decode volume progress from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+
+	var s3UploadStatusByName map[string]s3UploadStatus
+	if vmExport.Spec.S3Upload != nil {
+		s3UploadStatusByName = ctrl.getS3UploadStatuses(exporterPod, service, client, host)
+	}
+
+	var registryUploadStatusByName map[string]registryUploadStatus
+	if vmExport.Spec.RegistryUpload != nil {
+		registryUploadStatusByName = ctrl.getRegistryUploadStatuses(exporterPod, service, client, host)
+	}
+
+	var clusterUploadStatusByName map[string]clusterUploadStatus
+	if vmExport.Spec.ClusterUpload != nil {
+		clusterUploadStatusByName = ctrl.getClusterUploadStatuses(exporterPod, service, client, host)
+	}
+
+	var volumeStatuses []exportv1.VirtualMachineExportVolumeStatus
+	for _, pvc := range pvcs {
+		activity, hasProgress := activityByName[pvc.Name]
+		s3Status, hasS3Status := s3UploadStatusByName[pvc.Name]
+		registryStatus, hasRegistryStatus := registryUploadStatusByName[pvc.Name]
+		clusterStatus, hasClusterStatus := clusterUploadStatusByName[pvc.Name]
+		if !hasProgress && !hasS3Status && !hasRegistryStatus && !hasClusterStatus {
+			continue
+		}
+		volumeStatus := exportv1.VirtualMachineExportVolumeStatus{Name: pvc.Name}
+		if hasProgress {
+			bytesTransferred := activity.BytesTransferred
+			progress := &exportv1.VirtualMachineExportVolumeProgress{
+				BytesTransferred: bytesTransferred,
+			}
+			if totalSize, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+				if total := totalSize.Value(); total > 0 {
+					percent := bytesTransferred * 100 / total
+					if percent > 100 {
+						percent = 100
+					}
+					progress.PercentComplete = pointer.Int32(int32(percent))
+				}
+			}
+			volumeStatus.Progress = progress
+			if activity.LastDownloadStartTimestamp != nil {
+				volumeStatus.LastDownloadStartTimestamp = &metav1.Time{Time: *activity.LastDownloadStartTimestamp}
+			}
+			if activity.LastDownloadCompletionTimestamp != nil {
+				volumeStatus.LastDownloadCompletionTimestamp = &metav1.Time{Time: *activity.LastDownloadCompletionTimestamp}
+			}
+		}
+		if hasS3Status {
+			volumeStatus.S3Upload = &exportv1.VirtualMachineExportS3UploadStatus{
+				Phase:         exportv1.VirtualMachineExportS3UploadPhase(s3Status.Phase),
+				BytesUploaded: s3Status.BytesUploaded,
+				ObjectUrl:     s3Status.ObjectUrl,
+				Error:         s3Status.Error,
+			}
+		}
+		if hasRegistryStatus {
+			volumeStatus.RegistryUpload = &exportv1.VirtualMachineExportRegistryUploadStatus{
+				Phase:         exportv1.VirtualMachineExportRegistryUploadPhase(registryStatus.Phase),
+				BytesUploaded: registryStatus.BytesUploaded,
+				ImageUrl:      registryStatus.ImageUrl,
+				Error:         registryStatus.Error,
+			}
+		}
+		if hasClusterStatus {
+			volumeStatus.ClusterUpload = &exportv1.VirtualMachineExportClusterUploadStatus{
+				Phase:          exportv1.VirtualMachineExportClusterUploadPhase(clusterStatus.Phase),
+				BytesUploaded:  clusterStatus.BytesUploaded,
+				DataVolumeName: clusterStatus.DataVolumeName,
+				Error:          clusterStatus.Error,
+			}
+		}
+		volumeStatuses = append(volumeStatuses, volumeStatus)
+	}
+	return volumeStatuses
+}
+
+// maybeExtendExporterDeadline pushes vmExport's currently computed deadline to a running exporter
+// pod's internal deadline endpoint if it has moved past the deadline last pushed to that pod
+// (tracked in exporterPodDeadlineAnnotation), so editing spec.deadline to give a transfer that's
+// still running near expiry more time takes effect immediately, without recreating the pod. It
+// logs and swallows any error reaching the exporter pod, the same as getVolumeProgress, since a
+// momentarily unreachable exporter pod shouldn't fail the rest of the status update; the deadline
+// stays in effect until the next successful push.
+func (ctrl *VMExportController) maybeExtendExporterDeadline(vmExport *exportv1.VirtualMachineExport, exporterPod *corev1.Pod, service *corev1.Service) {
+	if exporterPod == nil || exporterPod.Status.Phase != corev1.PodRunning {
+		return
+	}
+
+	newDeadline := exporterPod.CreationTimestamp.Add(ctrl.getDeadline(vmExport))
+	if lastPushed, ok := exporterPod.Annotations[exporterPodDeadlineAnnotation]; ok {
+		if lastDeadline, err := time.Parse(time.RFC3339, lastPushed); err == nil && !newDeadline.After(lastDeadline) {
+			return
+		}
+	}
+
+	client, err := ctrl.getInternalClient()
+	if err != nil || client == nil {
+		return
+	}
+	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	body, err := json.Marshal(map[string]time.Time{"deadline": newDeadline})
+	if err != nil {
+		log.Log.Reason(err).Error("unable to marshal deadline extension request")
+		return
+	}
+	resp, err := client.Post(fmt.Sprintf("https://%s%s", host, internalDeadlinePath), "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to extend deadline on exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Log.V(4).Infof("exporter pod %s/%s rejected deadline extension with status %d", exporterPod.Namespace, exporterPod.Name, resp.StatusCode)
+		return
+	}
+
+	podCopy := exporterPod.DeepCopy()
+	if podCopy.Annotations == nil {
+		podCopy.Annotations = map[string]string{}
+	}
+	podCopy.Annotations[exporterPodDeadlineAnnotation] = newDeadline.Format(time.RFC3339)
+	if _, err := ctrl.Client.CoreV1().Pods(podCopy.Namespace).Update(context.Background(), podCopy, metav1.UpdateOptions{}); err != nil {
+		log.Log.Reason(err).Errorf("unable to record pushed deadline on exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+	}
+}
+
+// volumeActivity mirrors the download activity the exporter pod's internal progress endpoint
+// reports for a single volume.
+type volumeActivity struct {
+	BytesTransferred                int64      `json:"bytesTransferred"`
+	LastDownloadStartTimestamp      *time.Time `json:"lastDownloadStartTimestamp,omitempty"`
+	LastDownloadCompletionTimestamp *time.Time `json:"lastDownloadCompletionTimestamp,omitempty"`
+}
+
+// s3UploadStatus mirrors the status reported by the exporter pod's internal S3 upload endpoint.
+type s3UploadStatus struct {
+	Phase         string `json:"phase"`
+	BytesUploaded int64  `json:"bytesUploaded"`
+	ObjectUrl     string `json:"objectUrl"`
+	Error         string `json:"error"`
+}
+
+// getS3UploadStatuses fetches the S3 upload status of each volume from the exporter pod's
+// internal S3 upload endpoint, reusing client and host from the caller's progress request. It
+// returns nil, logging the failure, if the endpoint can't be reached.
+func (ctrl *VMExportController) getS3UploadStatuses(exporterPod *corev1.Pod, service *corev1.Service, client *http.Client, host string) map[string]s3UploadStatus {
+	resp, err := client.Get(fmt.Sprintf("https://%s%s", host, internalS3UploadPath))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to fetch S3 upload status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var statusByName map[string]s3UploadStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statusByName); err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to decode S3 upload status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	return statusByName
+}
+
+// registryUploadStatus mirrors the status reported by the exporter pod's internal registry
+// upload endpoint.
+type registryUploadStatus struct {
+	Phase         string `json:"phase"`
+	BytesUploaded int64  `json:"bytesUploaded"`
+	ImageUrl      string `json:"imageUrl"`
+	Error         string `json:"error"`
+}
+
+// getRegistryUploadStatuses fetches the registry upload status of each volume from the exporter
+// pod's internal registry upload endpoint, reusing client and host from the caller's progress
+// request. It returns nil, logging the failure, if the endpoint can't be reached.
+func (ctrl *VMExportController) getRegistryUploadStatuses(exporterPod *corev1.Pod, service *corev1.Service, client *http.Client, host string) map[string]registryUploadStatus {
+	resp, err := client.Get(fmt.Sprintf("https://%s%s", host, internalRegistryUploadPath))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to fetch registry upload status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var statusByName map[string]registryUploadStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statusByName); err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to decode registry upload status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	return statusByName
+}
+
+// clusterUploadStatus mirrors the status reported by the exporter pod's internal cluster upload
+// endpoint.
+type clusterUploadStatus struct {
+	Phase          string `json:"phase"`
+	BytesUploaded  int64  `json:"bytesUploaded"`
+	DataVolumeName string `json:"dataVolumeName"`
+	Error          string `json:"error"`
+}
+
+// getClusterUploadStatuses fetches the cluster upload status of each volume from the exporter
+// pod's internal cluster upload endpoint, reusing client and host from the caller's progress
+// request. It returns nil, logging the failure, if the endpoint can't be reached.
+func (ctrl *VMExportController) getClusterUploadStatuses(exporterPod *corev1.Pod, service *corev1.Service, client *http.Client, host string) map[string]clusterUploadStatus {
+	resp, err := client.Get(fmt.Sprintf("https://%s%s", host, internalClusterUploadPath))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to fetch cluster upload status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var statusByName map[string]clusterUploadStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statusByName); err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to decode cluster upload status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	return statusByName
+}
+
+// hookPending, hookRunning, and hookFailed mirror the hookStatus.Phase values the exporter pod's
+// internal hooks endpoint reports; a successful hook isn't checked against a matching constant
+// here since getHooksCondition only branches on pending/running and failed.
+const (
+	hookPending = "Pending"
+	hookRunning = "Running"
+	hookFailed  = "Failed"
+
+	// preServeHookName and postServeHookName are the keys the exporter pod's internal hooks
+	// endpoint reports each hook's outcome under.
+	preServeHookName  = "preServe"
+	postServeHookName = "postServe"
+)
+
+// hookStatus mirrors the status reported by the exporter pod's internal hooks endpoint for a
+// single hook (preServeHookName or postServeHookName).
+type hookStatus struct {
+	Phase string `json:"phase"`
+	Error string `json:"error,omitempty"`
+}
+
+// getHookStatuses fetches the status of spec.hooks' commands from the exporter pod's internal
+// hooks endpoint. It returns nil, logging the failure, if the exporter pod isn't running yet or
+// the endpoint can't be reached, since a momentarily unreachable exporter pod shouldn't fail the
+// rest of the status update.
+func (ctrl *VMExportController) getHookStatuses(exporterPod *corev1.Pod, service *corev1.Service) map[string]hookStatus {
+	if exporterPod == nil || exporterPod.Status.Phase != corev1.PodRunning {
+		return nil
+	}
+
+	client, err := ctrl.getInternalClient()
+	if err != nil || client == nil {
+		return nil
+	}
+	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	resp, err := client.Get(fmt.Sprintf("https://%s%s", host, internalHooksPath))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to fetch hook status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var statusByName map[string]hookStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statusByName); err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to decode hook status from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	return statusByName
+}
+
+// artifactMetadata is the logical size and sha256 checksum of a single downloadable artifact, as
+// reported by the exporter pod's internal metadata endpoint.
+type artifactMetadata struct {
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// getArtifactMetadata fetches the size and checksum of every artifact the exporter pod has
+// finished computing metadata for, keyed by the URI it is served at, through service's cluster
+// DNS name. It returns nil, without error, if the exporter pod isn't running yet or the endpoint
+// can't be reached, since a momentarily unreachable exporter pod shouldn't fail the rest of the
+// status update. Artifacts the exporter pod hasn't finished hashing yet are simply absent.
+func (ctrl *VMExportController) getArtifactMetadata(exporterPod *corev1.Pod, service *corev1.Service) map[string]artifactMetadata {
+	if exporterPod == nil || exporterPod.Status.Phase != corev1.PodRunning {
+		return nil
+	}
+
+	client, err := ctrl.getInternalClient()
+	if err != nil || client == nil {
+		return nil
+	}
+	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	resp, err := client.Get(fmt.Sprintf("https://%s%s", host, internalMetadataPath))
+	if err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to fetch artifact metadata from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var metadataByURI map[string]artifactMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadataByURI); err != nil {
+		log.Log.V(4).Reason(err).Infof("unable to decode artifact metadata from exporter pod %s/%s", exporterPod.Namespace, exporterPod.Name)
+		return nil
+	}
+	return metadataByURI
+}
+
+func (ctrl *VMExportController) getLinks(pvcs []*corev1.PersistentVolumeClaim, exporterPod *corev1.Pod, service *corev1.Service, hostAndBase, cert string, vmExport *exportv1.VirtualMachineExport) (*exportv1.VirtualMachineExportLink, error) {
+	artifactMetadataByURI := ctrl.getArtifactMetadata(exporterPod, service)
 	exportLink := &exportv1.VirtualMachineExportLink{
-		Volumes: []exportv1.VirtualMachineExportVolume{},
-		Cert:    cert,
+		Volumes:             []exportv1.VirtualMachineExportVolume{},
+		Cert:                cert,
+		ExpirationTimestamp: ctrl.getLinkExpiration(vmExport, exporterPod),
+	}
+	if ctrl.isSourceVM(&vmExport.Spec) && exporterPod != nil && exporterPod.Status.Phase == corev1.PodRunning {
+		ova := withArtifactMetadata(exportv1.VirtualMachineExportVolumeFormat{
+			Format: exportv1.Ova,
+			Url:    "https://" + path.Join(hostAndBase, ovaURLPath),
+		}, artifactMetadataByURI[ovaURLPath])
+		exportLink.Ova = &ova
+
+		exportLink.Manifests = []exportv1.VirtualMachineExportManifest{{
+			Type: exportv1.AllManifests,
+			Url:  "https://" + path.Join(hostAndBase, manifestURLPath),
+		}}
+
+		if vm, exists, err := ctrl.getVm(vmExport.Namespace, vmExport.Spec.Source.Name); err != nil {
+			return nil, err
+		} else if exists && (vm.Spec.Instancetype != nil || vm.Spec.Preference != nil) {
+			exportLink.Manifests = append(exportLink.Manifests, exportv1.VirtualMachineExportManifest{
+				Type: exportv1.AllManifestsWithExpandedSpec,
+				Url:  "https://" + path.Join(hostAndBase, expandedManifestURLPath),
+			})
+		}
+	}
+	if exporterPod != nil && exporterPod.Status.Phase == corev1.PodRunning && len(pvcs) > 0 {
+		all := withArtifactMetadata(exportv1.VirtualMachineExportVolumeFormat{
+			Format: exportv1.All,
+			Url:    "https://" + path.Join(hostAndBase, allURLPath),
+		}, artifactMetadataByURI[allURLPath])
+		exportLink.All = &all
 	}
 	for _, pvc := range pvcs {
 		if pvc != nil && exporterPod != nil && exporterPod.Status.Phase == corev1.PodRunning {
 			const scheme = "https://"
 
 			if ctrl.isKubevirtContentType(pvc) {
+				var formats []exportv1.VirtualMachineExportVolumeFormat
+				for _, rawCapableFormat := range []struct {
+					format exportv1.ExportVolumeFormat
+					uri    string
+				}{
+					{exportv1.KubeVirtRaw, rawURI(pvc)},
+					{exportv1.KubeVirtGz, rawGzipURI(pvc)},
+					{exportv1.KubeVirtZstd, zstdURI(pvc)},
+					{exportv1.KubeVirtQcow2, qcow2URI(pvc)},
+					{exportv1.KubeVirtQcow2Gz, qcow2GzipURI(pvc)},
+					{exportv1.KubeVirtVmdk, vmdkURI(pvc)},
+					{exportv1.KubeVirtVhd, vhdURI(pvc)},
+					{exportv1.KubeVirtVhdx, vhdxURI(pvc)},
+				} {
+					if !ctrl.isFormatEnabled(vmExport, rawCapableFormat.format) {
+						continue
+					}
+					formats = append(formats, withArtifactMetadata(exportv1.VirtualMachineExportVolumeFormat{
+						Format: rawCapableFormat.format,
+						Url:    scheme + path.Join(hostAndBase, rawCapableFormat.uri),
+					}, artifactMetadataByURI[rawCapableFormat.uri]))
+				}
+				if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtRaw) {
+					formats = append(formats, extraDiskImageFormats(pvc, hostAndBase, artifactMetadataByURI)...)
+				}
 				exportLink.Volumes = append(exportLink.Volumes, exportv1.VirtualMachineExportVolume{
-					Name: pvc.Name,
-					Formats: []exportv1.VirtualMachineExportVolumeFormat{
-						{
-							Format: exportv1.KubeVirtRaw,
-							Url:    scheme + path.Join(hostAndBase, rawURI(pvc)),
-						},
-						{
-							Format: exportv1.KubeVirtGz,
-							Url:    scheme + path.Join(hostAndBase, rawGzipURI(pvc)),
-						},
-					},
+					Name:    pvc.Name,
+					Formats: formats,
 				})
 			} else {
 				exportLink.Volumes = append(exportLink.Volumes, exportv1.VirtualMachineExportVolume{
@@ -101,10 +640,14 @@ func (ctrl *VMExportController) getLinks(pvcs []*corev1.PersistentVolumeClaim, e
 							Format: exportv1.Dir,
 							Url:    scheme + path.Join(hostAndBase, dirURI(pvc)),
 						},
-						{
+						withArtifactMetadata(exportv1.VirtualMachineExportVolumeFormat{
 							Format: exportv1.ArchiveGz,
 							Url:    scheme + path.Join(hostAndBase, archiveURI(pvc)),
-						},
+						}, artifactMetadataByURI[archiveURI(pvc)]),
+						withArtifactMetadata(exportv1.VirtualMachineExportVolumeFormat{
+							Format: exportv1.ArchiveZstd,
+							Url:    scheme + path.Join(hostAndBase, archiveZstdURI(pvc)),
+						}, artifactMetadataByURI[archiveZstdURI(pvc)]),
 					},
 				})
 			}
@@ -113,6 +656,56 @@ func (ctrl *VMExportController) getLinks(pvcs []*corev1.PersistentVolumeClaim, e
 	return exportLink, nil
 }
 
+// extraDiskImageFormats returns a raw-format entry for every disk image the exporter pod found
+// alongside pvc's disk.img, beyond the single image a filesystem PVC is normally assumed to hold.
+// It relies entirely on artifactMetadataByURI, since the controller has no filesystem access of
+// its own to the PVC: any key under disk.img's directory that isn't one of the fixed per-format
+// URIs is one of these extra images, and having an entry in artifactMetadataByURI at all already
+// means the exporter has finished hashing it.
+func extraDiskImageFormats(pvc *corev1.PersistentVolumeClaim, hostAndBase string, artifactMetadataByURI map[string]artifactMetadata) []exportv1.VirtualMachineExportVolumeFormat {
+	knownURIs := map[string]bool{
+		rawURI(pvc):         true,
+		rawGzipURI(pvc):     true,
+		zstdURI(pvc):        true,
+		qcow2URI(pvc):       true,
+		qcow2GzipURI(pvc):   true,
+		vmdkURI(pvc):        true,
+		vhdURI(pvc):         true,
+		vhdxURI(pvc):        true,
+		archiveURI(pvc):     true,
+		archiveZstdURI(pvc): true,
+	}
+	dir := path.Dir(rawURI(pvc)) + "/"
+
+	var uris []string
+	for uri := range artifactMetadataByURI {
+		if strings.HasPrefix(uri, dir) && !knownURIs[uri] {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+
+	var formats []exportv1.VirtualMachineExportVolumeFormat
+	for _, uri := range uris {
+		formats = append(formats, withArtifactMetadata(exportv1.VirtualMachineExportVolumeFormat{
+			Format: exportv1.KubeVirtRaw,
+			Url:    "https://" + path.Join(hostAndBase, uri),
+		}, artifactMetadataByURI[uri]))
+	}
+	return formats
+}
+
+// withArtifactMetadata populates format's Size and Checksum from metadata, if the exporter pod
+// has finished computing it, leaving them unset otherwise.
+func withArtifactMetadata(format exportv1.VirtualMachineExportVolumeFormat, metadata artifactMetadata) exportv1.VirtualMachineExportVolumeFormat {
+	if metadata.Checksum == "" {
+		return format
+	}
+	format.Size = pointer.Int64(metadata.Size)
+	format.Checksum = metadata.Checksum
+	return format
+}
+
 func (ctrl *VMExportController) internalExportCa() (string, error) {
 	key := controller.NamespacedKey(ctrl.KubevirtNamespace, components.KubeVirtExportCASecretName)
 	obj, exists, err := ctrl.ConfigMapInformer.GetStore().GetByKey(key)
@@ -124,24 +717,136 @@ func (ctrl *VMExportController) internalExportCa() (string, error) {
 	return strings.TrimSpace(bundle), nil
 }
 
+// externalHostAndCert is a single externally reachable hostname and the serving cert it presents.
+type externalHostAndCert struct {
+	host string
+	cert string
+}
+
+// getExternalLinkHostAndCert returns the first externally reachable host (and serving cert) found
+// by getExternalLinkHostsAndCerts, or "", "" if none are found.
 func (ctrl *VMExportController) getExternalLinkHostAndCert() (string, string) {
+	if hostsAndCerts := ctrl.getExternalLinkHostsAndCerts(); len(hostsAndCerts) > 0 {
+		return hostsAndCerts[0].host, hostsAndCerts[0].cert
+	}
+	return "", ""
+}
+
+// getExternalLinkHostsAndCerts returns every externally reachable host (and serving cert) of an
+// Ingress, Route, or Gateway API HTTPRoute pointing at the export proxy service whose labels match
+// ingressSelector, Ingresses first, then Routes, then HTTPRoutes.
+func (ctrl *VMExportController) getExternalLinkHostsAndCerts() []externalHostAndCert {
+	var hostsAndCerts []externalHostAndCert
+	selector := ctrl.ingressSelector()
 	for _, obj := range ctrl.IngressCache.List() {
-		if ingress, ok := obj.(*networkingv1.Ingress); ok {
+		if ingress, ok := obj.(*networkingv1.Ingress); ok && selector.Matches(labels.Set(ingress.Labels)) {
 			if host := getHostFromIngress(ingress); host != "" {
 				cert, _ := ctrl.getIngressCert(host, ingress)
-				return host, cert
+				hostsAndCerts = append(hostsAndCerts, externalHostAndCert{host: host, cert: cert})
 			}
 		}
 	}
 	for _, obj := range ctrl.RouteCache.List() {
-		if route, ok := obj.(*routev1.Route); ok {
+		if route, ok := obj.(*routev1.Route); ok && selector.Matches(labels.Set(route.Labels)) {
 			if host := getHostFromRoute(route); host != "" {
 				cert, _ := ctrl.getRouteCert(host)
-				return host, cert
+				hostsAndCerts = append(hostsAndCerts, externalHostAndCert{host: host, cert: cert})
 			}
 		}
 	}
-	return "", ""
+	for _, host := range ctrl.getHostsFromHTTPRoutes(selector) {
+		cert, _ := ctrl.internalExportCa()
+		hostsAndCerts = append(hostsAndCerts, externalHostAndCert{host: host, cert: cert})
+	}
+	return hostsAndCerts
+}
+
+// getHostsFromHTTPRoutes returns every hostname of a Gateway API HTTPRoute pointing at the export
+// proxy service whose labels match selector. Unlike Ingress and Route, there is no generated client
+// or informer available for HTTPRoute in this tree, so it is listed live through the dynamic client
+// on every call. A cluster without the Gateway API CRDs installed is not an error: this simply
+// returns no hosts, the same as if no HTTPRoute matched.
+//
+// HTTPRoute itself carries no serving certificate: TLS termination is configured on the Gateway's
+// listener, in a secret that may live in a different namespace than the HTTPRoute or the export. To
+// keep this lookup self-contained, matching HTTPRoute hosts are published with the internal export
+// CA cert, the same fallback used for a NodePort or LoadBalancer Service.
+func (ctrl *VMExportController) getHostsFromHTTPRoutes(selector labels.Selector) []string {
+	routes, err := ctrl.Client.DynamicClient().Resource(httpRouteResource).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		log.Log.V(4).Infof("Unable to list Gateway API HTTPRoutes, skipping: %v", err)
+		return nil
+	}
+	var hosts []string
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !selector.Matches(labels.Set(route.GetLabels())) {
+			continue
+		}
+		hosts = append(hosts, getHostsFromHTTPRoute(route)...)
+	}
+	return hosts
+}
+
+// getHostsFromHTTPRoute returns every hostname of route that is exposed through a backendRef
+// pointing at the export proxy service.
+func getHostsFromHTTPRoute(route *unstructured.Unstructured) []string {
+	if !httpRouteTargetsExportProxy(route) {
+		return nil
+	}
+	hostnames, _, err := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	if err != nil {
+		return nil
+	}
+	return hostnames
+}
+
+// httpRouteTargetsExportProxy returns true if any rule of route backends to the export proxy
+// service.
+func httpRouteTargetsExportProxy(route *unstructured.Unstructured) bool {
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil {
+		return false
+	}
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendRefs, _, err := unstructured.NestedSlice(ruleMap, "backendRefs")
+		if err != nil {
+			continue
+		}
+		for _, backendRef := range backendRefs {
+			backendRefMap, ok := backendRef.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(backendRefMap, "name"); name == components.VirtExportProxyServiceName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ingressSelector returns the labels.Selector matching ExportConfiguration.IngressSelector, so
+// external link auto-detection only considers Ingresses and Routes with matching labels. It
+// returns labels.Everything() if that field isn't configured, matching the behavior as before it
+// was introduced.
+func (ctrl *VMExportController) ingressSelector() labels.Selector {
+	labelSelector := ctrl.ClusterConfig.GetVMExportIngressSelector()
+	if labelSelector == nil {
+		return labels.Everything()
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return labels.Everything()
+	}
+	return selector
 }
 
 func (ctrl *VMExportController) getIngressCert(hostName string, ing *networkingv1.Ingress) (string, error) {
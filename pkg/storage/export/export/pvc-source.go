@@ -31,6 +31,7 @@ import (
 
 	exportv1 "kubevirt.io/api/export/v1alpha1"
 	"kubevirt.io/client-go/log"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/controller"
 	watchutil "kubevirt.io/kubevirt/pkg/virt-controller/watch/util"
@@ -57,7 +58,13 @@ func (ctrl *VMExportController) handlePVC(obj interface{}) {
 }
 
 func (ctrl *VMExportController) isSourcePvc(source *exportv1.VirtualMachineExportSpec) bool {
-	return source != nil && (source.Source.APIGroup == nil || *source.Source.APIGroup == corev1.SchemeGroupVersion.Group) && source.Source.Kind == "PersistentVolumeClaim"
+	if source == nil {
+		return false
+	}
+	if (source.Source.APIGroup == nil || *source.Source.APIGroup == corev1.SchemeGroupVersion.Group) && source.Source.Kind == "PersistentVolumeClaim" {
+		return true
+	}
+	return source.Source.APIGroup != nil && *source.Source.APIGroup == cdiv1.SchemeGroupVersion.Group && source.Source.Kind == "DataVolume"
 }
 
 func (ctrl *VMExportController) getPvc(namespace, name string) (*corev1.PersistentVolumeClaim, bool, error) {
@@ -69,28 +76,38 @@ func (ctrl *VMExportController) getPvc(namespace, name string) (*corev1.Persiste
 	return obj.(*corev1.PersistentVolumeClaim).DeepCopy(), true, nil
 }
 
-func (ctrl *VMExportController) isSourceAvailablePVC(vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim) (bool, bool, string, error) {
+func (ctrl *VMExportController) isSourceAvailablePVC(vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim) (bool, bool, bool, string, error) {
 	availableMessage := ""
 	isPopulated, err := ctrl.isPVCPopulated(pvc)
 	inUse := false
+	waitingForFirstConsumer := false
 	if err != nil {
-		return false, false, "", err
+		return false, false, false, "", err
 	}
 	if isPopulated {
 		inUse, err = ctrl.isPVCInUse(vmExport, pvc)
 		if err != nil {
-			return false, false, "", err
+			return false, false, false, "", err
 		}
 		if inUse {
 			availableMessage = fmt.Sprintf("pvc %s/%s is in use", pvc.Namespace, pvc.Name)
 		}
 	} else {
-		availableMessage = fmt.Sprintf("pvc %s/%s is not populated", pvc.Namespace, pvc.Name)
+		waitingForFirstConsumer, err = ctrl.isPVCWaitingForFirstConsumer(pvc)
+		if err != nil {
+			return false, false, false, "", err
+		}
+		if waitingForFirstConsumer {
+			availableMessage = fmt.Sprintf("pvc %s/%s is waiting for first consumer before populating", pvc.Namespace, pvc.Name)
+		} else {
+			availableMessage = fmt.Sprintf("pvc %s/%s is not populated", pvc.Namespace, pvc.Name)
+		}
 	}
-	return isPopulated, inUse, availableMessage, nil
+	return isPopulated, inUse, waitingForFirstConsumer, availableMessage, nil
 }
 
 func (ctrl *VMExportController) getPVCFromSourcePVC(vmExport *exportv1.VirtualMachineExport) (*sourceVolumes, error) {
+	// TODO, look up the correct PVC name based on the DataVolume, right now they match, but that will not always be true.
 	pvc, pvcExists, err := ctrl.getPvc(vmExport.Namespace, vmExport.Spec.Source.Name)
 	if err != nil {
 		return &sourceVolumes{}, err
@@ -103,15 +120,16 @@ func (ctrl *VMExportController) getPVCFromSourcePVC(vmExport *exportv1.VirtualMa
 			availableMessage: fmt.Sprintf("pvc %s/%s not found", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
 	}
 
-	isPopulated, inUse, availableMessage, err := ctrl.isSourceAvailablePVC(vmExport, pvc)
+	isPopulated, inUse, waitingForFirstConsumer, availableMessage, err := ctrl.isSourceAvailablePVC(vmExport, pvc)
 	if err != nil {
 		return &sourceVolumes{}, err
 	}
 	return &sourceVolumes{
-		volumes:          []*corev1.PersistentVolumeClaim{pvc},
-		inUse:            inUse,
-		isPopulated:      isPopulated,
-		availableMessage: availableMessage}, nil
+		volumes:                 []*corev1.PersistentVolumeClaim{pvc},
+		inUse:                   inUse,
+		isPopulated:             isPopulated,
+		waitingForFirstConsumer: waitingForFirstConsumer,
+		availableMessage:        availableMessage}, nil
 }
 
 func (ctrl *VMExportController) isPVCInUse(vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim) (bool, error) {
@@ -136,7 +154,7 @@ func (ctrl *VMExportController) updateVMExportPvcStatus(vmExport *exportv1.Virtu
 
 	if !sourceVolumes.isSourceAvailable() && len(sourceVolumes.volumes) > 0 {
 		log.Log.V(4).Infof("Source is not available %s, requeuing", sourceVolumes.availableMessage)
-		requeue = requeueTime
+		requeue = ctrl.requeueInterval(sourceVolumes)
 	}
 
 	vmExportCopy := vmExport.DeepCopy()
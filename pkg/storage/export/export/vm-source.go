@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 
 	virtv1 "kubevirt.io/api/core/v1"
@@ -35,10 +36,15 @@ import (
 	"kubevirt.io/kubevirt/pkg/controller"
 
 	storagetypes "kubevirt.io/kubevirt/pkg/storage/types"
+	launcherapi "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 )
 
 const (
 	noVolumeVMReason = "VMNoVolumes"
+
+	// quiesceFreezeTimeout is how long the guest file systems are allowed to
+	// stay frozen while the exporter pod is not yet serving the export
+	quiesceFreezeTimeout = 5 * time.Minute
 )
 
 func (ctrl *VMExportController) handleVMExport(obj interface{}) {
@@ -140,19 +146,71 @@ func (ctrl *VMExportController) getVMFromVMI(vmi *virtv1.VirtualMachineInstance)
 	return nil
 }
 
-func (ctrl *VMExportController) isSourceInUseVM(vmExport *exportv1.VirtualMachineExport) (bool, string, error) {
+func (ctrl *VMExportController) isSourceInUseVM(vmExport *exportv1.VirtualMachineExport) (bool, bool, string, error) {
 	vmi, exists, err := ctrl.getVmi(vmExport.Namespace, vmExport.Spec.Source.Name)
 	if err != nil {
-		return false, "", err
+		return false, false, "", err
+	}
+	if !exists {
+		return false, false, "", nil
+	}
+	if isVMIMigrating(vmi) {
+		return true, true, fmt.Sprintf("Virtual Machine %s/%s is being live migrated", vmi.Namespace, vmi.Name), nil
+	}
+	if quiesce(vmExport) {
+		condManager := controller.NewVirtualMachineInstanceConditionManager()
+		if condManager.HasConditionWithStatus(vmi, virtv1.VirtualMachineInstanceAgentConnected, corev1.ConditionTrue) {
+			if err := ctrl.freezeVMI(vmi); err != nil {
+				return false, false, "", err
+			}
+			return false, false, "", nil
+		}
+		log.Log.V(3).Infof("Quiesce requested for Virtual Machine %s/%s but no guest agent is connected", vmi.Namespace, vmi.Name)
+	}
+	return exists, false, fmt.Sprintf("Virtual Machine %s/%s is running", vmi.Namespace, vmi.Name), nil
+}
+
+// isVMIMigrating returns true while vmi's most recent migration is still in flight: it has
+// started but hasn't yet been recorded as completed, failed, or aborted.
+func isVMIMigrating(vmi *virtv1.VirtualMachineInstance) bool {
+	migrationState := vmi.Status.MigrationState
+	return migrationState != nil && migrationState.StartTimestamp != nil && migrationState.EndTimestamp == nil
+}
+
+// isHotplugVolume returns true if volumeName was attached to vmi via volume hotplug, rather than
+// being part of its original spec at start time.
+func isHotplugVolume(vmi *virtv1.VirtualMachineInstance, volumeName string) bool {
+	for _, volumeStatus := range vmi.Status.VolumeStatus {
+		if volumeStatus.Name == volumeName {
+			return volumeStatus.HotplugVolume != nil
+		}
 	}
-	if exists {
-		return exists, fmt.Sprintf("Virtual Machine %s/%s is running", vmi.Namespace, vmi.Name), nil
+	return false
+}
+
+func quiesce(vmExport *exportv1.VirtualMachineExport) bool {
+	return vmExport.Spec.Quiesce != nil && *vmExport.Spec.Quiesce
+}
+
+func (ctrl *VMExportController) freezeVMI(vmi *virtv1.VirtualMachineInstance) error {
+	if vmi.Status.FSFreezeStatus == launcherapi.FSFrozen {
+		return nil
 	}
-	return exists, "", nil
+	log.Log.V(3).Infof("Freezing Virtual Machine %s/%s guest file systems for a consistent export", vmi.Namespace, vmi.Name)
+	return ctrl.Client.VirtualMachineInstance(vmi.Namespace).Freeze(vmi.Name, quiesceFreezeTimeout)
+}
+
+func (ctrl *VMExportController) thawVMIIfFrozen(namespace, name string) error {
+	vmi, exists, err := ctrl.getVmi(namespace, name)
+	if err != nil || !exists || vmi.Status.FSFreezeStatus != launcherapi.FSFrozen {
+		return err
+	}
+	log.Log.V(3).Infof("Thawing Virtual Machine %s/%s guest file systems after export", vmi.Namespace, vmi.Name)
+	return ctrl.Client.VirtualMachineInstance(vmi.Namespace).Unfreeze(vmi.Name)
 }
 
 func (ctrl *VMExportController) getPVCFromSourceVM(vmExport *exportv1.VirtualMachineExport) (*sourceVolumes, error) {
-	pvcs, allPopulated, err := ctrl.getPVCsFromVM(vmExport.Namespace, vmExport.Spec.Source.Name)
+	pvcs, allPopulated, vmiSpec, err := ctrl.getPVCsFromVM(vmExport.Namespace, vmExport.Spec.Source.Name, vmExport.Spec.VolumeNames)
 	if err != nil {
 		return &sourceVolumes{}, err
 	}
@@ -162,42 +220,60 @@ func (ctrl *VMExportController) getPVCFromSourceVM(vmExport *exportv1.VirtualMac
 			volumes:          pvcs,
 			inUse:            false,
 			isPopulated:      allPopulated,
+			vmiSpec:          vmiSpec,
 			availableMessage: fmt.Sprintf("Not all volumes in the Virtual Machine %s/%s are populated", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
 	}
-	inUse, availableMessage, err := ctrl.isSourceInUseVM(vmExport)
+	inUse, migrating, availableMessage, err := ctrl.isSourceInUseVM(vmExport)
 	if err != nil {
 		return &sourceVolumes{}, err
 	}
+	requeueAfter := time.Duration(0)
+	if migrating {
+		requeueAfter = migrationRequeueTime
+	}
 	return &sourceVolumes{
 		volumes:          pvcs,
 		inUse:            inUse,
 		isPopulated:      allPopulated,
-		availableMessage: availableMessage}, nil
+		vmiSpec:          vmiSpec,
+		migrating:        migrating,
+		availableMessage: availableMessage,
+		requeueAfter:     requeueAfter}, nil
 }
 
-func (ctrl *VMExportController) getPVCsFromVM(vmNamespace, vmName string) ([]*corev1.PersistentVolumeClaim, bool, error) {
+// getPVCsFromVM returns the PVCs backing all of the VM's volumes, including any memory dump PVC
+// attached via virtctl memory-dump, since that is represented as a regular volume on the VM spec.
+// It also returns the VM's VirtualMachineInstanceSpec template, used to describe its CPU, memory
+// and network interfaces when generating an OVF descriptor for an OVA export.
+func (ctrl *VMExportController) getPVCsFromVM(vmNamespace, vmName string, volumeNames []string) ([]*corev1.PersistentVolumeClaim, bool, *virtv1.VirtualMachineInstanceSpec, error) {
 	var pvcs []*corev1.PersistentVolumeClaim
 	vm, exists, err := ctrl.getVm(vmNamespace, vmName)
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 	if !exists {
-		return nil, false, nil
+		return nil, false, nil, nil
 	}
+	wantedVolumes := sets.NewString(volumeNames...)
+	declaredVolumes := sets.NewString()
 	allPopulated := true
 	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		declaredVolumes.Insert(volume.Name)
+		if wantedVolumes.Len() > 0 && !wantedVolumes.Has(volume.Name) {
+			continue
+		}
 		pvcName := storagetypes.PVCNameFromVirtVolume(&volume)
 		if pvcName == "" {
 			continue
 		}
 		pvc, exists, err := ctrl.getPvc(vmNamespace, pvcName)
 		if err != nil {
-			return nil, false, nil
+			return nil, false, nil, nil
 		}
 		if exists {
 			populated, err := ctrl.isPVCPopulated(pvc)
 			if err != nil {
-				return nil, false, err
+				return nil, false, nil, err
 			}
 			pvcs = append(pvcs, pvc)
 			if !populated {
@@ -212,7 +288,46 @@ func (ctrl *VMExportController) getPVCsFromVM(vmNamespace, vmName string) ([]*co
 			allPopulated = false
 		}
 	}
-	return pvcs, allPopulated, nil
+
+	// Hotplugged volumes are attached directly to the running VirtualMachineInstance and are not
+	// necessarily reflected back onto the VirtualMachine's own volume list, so they have to be
+	// picked up separately to be included in the export.
+	if vmi, exists, err := ctrl.getVmi(vmNamespace, vmName); err != nil {
+		return nil, false, nil, err
+	} else if exists {
+		for _, volume := range vmi.Spec.Volumes {
+			if declaredVolumes.Has(volume.Name) {
+				continue
+			}
+			if wantedVolumes.Len() > 0 && !wantedVolumes.Has(volume.Name) {
+				continue
+			}
+			if !isHotplugVolume(vmi, volume.Name) {
+				continue
+			}
+			pvcName := storagetypes.PVCNameFromVirtVolume(&volume)
+			if pvcName == "" {
+				continue
+			}
+			pvc, exists, err := ctrl.getPvc(vmNamespace, pvcName)
+			if err != nil {
+				return nil, false, nil, nil
+			}
+			if !exists {
+				allPopulated = false
+				continue
+			}
+			populated, err := ctrl.isPVCPopulated(pvc)
+			if err != nil {
+				return nil, false, nil, err
+			}
+			pvcs = append(pvcs, pvc)
+			if !populated {
+				allPopulated = false
+			}
+		}
+	}
+	return pvcs, allPopulated, &vm.Spec.Template.Spec, nil
 }
 
 func (ctrl *VMExportController) updateVMExportVMStatus(vmExport *exportv1.VirtualMachineExport, exporterPod *corev1.Pod, service *corev1.Service, sourceVolumes *sourceVolumes) (time.Duration, error) {
@@ -226,8 +341,13 @@ func (ctrl *VMExportController) updateVMExportVMStatus(vmExport *exportv1.Virtua
 		vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionFalse, noVolumeVMReason, sourceVolumes.availableMessage))
 		vmExportCopy.Status.Phase = exportv1.Skipped
 	}
-	if !sourceVolumes.isPopulated {
-		requeue = requeueTime
+	if !sourceVolumes.isPopulated || sourceVolumes.migrating {
+		requeue = ctrl.requeueInterval(sourceVolumes)
+	}
+	if quiesce(vmExport) && vmExportCopy.Status.Phase == exportv1.Ready {
+		if err := ctrl.thawVMIIfFrozen(vmExport.Namespace, vmExport.Spec.Source.Name); err != nil {
+			return requeue, err
+		}
 	}
 	if err := ctrl.updateVMExportStatus(vmExport, vmExportCopy); err != nil {
 		return requeue, err
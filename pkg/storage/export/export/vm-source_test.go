@@ -75,6 +75,7 @@ var _ = Describe("PVC source", func() {
 		secretInformer             cache.SharedIndexInformer
 		vmInformer                 cache.SharedIndexInformer
 		vmiInformer                cache.SharedIndexInformer
+		vmiInterface               *kubecli.MockVirtualMachineInstanceInterface
 		k8sClient                  *k8sfake.Clientset
 		vmExportClient             *kubevirtfake.Clientset
 		fakeVolumeSnapshotProvider *MockVolumeSnapshotProvider
@@ -122,6 +123,8 @@ var _ = Describe("PVC source", func() {
 		virtClient.EXPECT().CoreV1().Return(k8sClient.CoreV1()).AnyTimes()
 		virtClient.EXPECT().VirtualMachineExport(testNamespace).
 			Return(vmExportClient.ExportV1alpha1().VirtualMachineExports(testNamespace)).AnyTimes()
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstance(testNamespace).Return(vmiInterface).AnyTimes()
 
 		controller = &VMExportController{
 			Client:                    virtClient,
@@ -144,6 +147,7 @@ var _ = Describe("PVC source", func() {
 			VolumeSnapshotProvider:    fakeVolumeSnapshotProvider,
 			VMInformer:                vmInformer,
 			VMIInformer:               vmiInformer,
+			ClusterConfig:             config,
 		}
 		initCert = func(ctrl *VMExportController) {
 			go controller.caCertManager.Start()
@@ -354,6 +358,66 @@ var _ = Describe("PVC source", func() {
 		Entry("Memorydump and pvc", createVMWithPVCandMemoryDump, "kubevirt", "archive", verifyMixedInternal),
 	)
 
+	It("Should expose a memory dump PVC attached to a stopped VM as an additional downloadable artifact", func() {
+		testVMExport := createVMVMExport()
+		vm := createVMWithoutVolumes()
+		vm.Spec.Template.Spec.Volumes = append(vm.Spec.Template.Spec.Volumes, virtv1.Volume{
+			Name: "memorydump",
+			VolumeSource: virtv1.VolumeSource{
+				MemoryDump: &virtv1.MemoryDumpVolumeSource{
+					PersistentVolumeClaimVolumeSource: virtv1.PersistentVolumeClaimVolumeSource{
+						PersistentVolumeClaimVolumeSource: k8sv1.PersistentVolumeClaimVolumeSource{
+							ClaimName: "memorydump",
+						},
+					},
+				},
+			},
+		})
+		controller.VMInformer.GetStore().Add(vm)
+		controller.PVCInformer.GetStore().Add(createPVC("memorydump", "archive"))
+		expectExporterCreate(k8sClient, k8sv1.PodRunning)
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			Expect(vmExport.Status.Links.Internal.Volumes).To(HaveLen(1))
+			Expect(vmExport.Status.Links.Internal.Volumes[0].Name).To(Equal("memorydump"))
+			Expect(vmExport.Status.Links.Internal.Volumes[0].Formats).To(ConsistOf(
+				exportv1.VirtualMachineExportVolumeFormat{Format: exportv1.Dir, Url: fmt.Sprintf("https://%s.%s.svc/volumes/memorydump/dir", fmt.Sprintf("%s-%s", exportPrefix, vmExport.Name), testNamespace)},
+				exportv1.VirtualMachineExportVolumeFormat{Format: exportv1.ArchiveGz, Url: fmt.Sprintf("https://%s.%s.svc/volumes/memorydump/disk.tar.gz", fmt.Sprintf("%s-%s", exportPrefix, vmExport.Name), testNamespace)},
+				exportv1.VirtualMachineExportVolumeFormat{Format: exportv1.ArchiveZstd, Url: fmt.Sprintf("https://%s.%s.svc/volumes/memorydump/disk.tar.zst", fmt.Sprintf("%s-%s", exportPrefix, vmExport.Name), testNamespace)},
+			))
+			return true, vmExport, nil
+		})
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		testutils.ExpectEvent(recorder, serviceCreatedEvent)
+	})
+
+	It("Should only export the volumes listed in VolumeNames", func() {
+		testVMExport := createVMVMExport()
+		testVMExport.Spec.VolumeNames = []string{"volume1"}
+		controller.VMInformer.GetStore().Add(createVMWithDataVolumes())
+		controller.PVCInformer.GetStore().Add(createPVC("volume1", "kubevirt"))
+		controller.PVCInformer.GetStore().Add(createPVC("volume2", "kubevirt"))
+		expectExporterCreate(k8sClient, k8sv1.PodRunning)
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			Expect(vmExport.Status.Links.Internal.Volumes).To(HaveLen(1))
+			Expect(vmExport.Status.Links.Internal.Volumes[0].Name).To(Equal("volume1"))
+			return true, vmExport, nil
+		})
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		testutils.ExpectEvent(recorder, serviceCreatedEvent)
+	})
+
 	It("Should NOT create VM export, when VM is started", func() {
 		testVMExport := createVMVMExport()
 		controller.VMInformer.GetStore().Add(createVMWithDataVolumes())
@@ -383,6 +447,155 @@ var _ = Describe("PVC source", func() {
 		testutils.ExpectEvent(recorder, serviceCreatedEvent)
 	})
 
+	It("Should NOT create VM export, and report MigrationInProgress, while the VM is being live migrated", func() {
+		testVMExport := createVMVMExport()
+		controller.VMInformer.GetStore().Add(createVMWithDataVolumes())
+		vmi := createVMIWithDataVolumes()
+		startTimestamp := metav1.NewTime(currentTime().Time)
+		vmi.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{
+			StartTimestamp: &startTimestamp,
+		}
+		controller.VMIInformer.GetStore().Add(vmi)
+		controller.PVCInformer.GetStore().Add(createPVC("volume1", "kubevirt"))
+		controller.PVCInformer.GetStore().Add(createPVC("volume2", "kubevirt"))
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			verifyLinksEmpty(vmExport)
+			for _, condition := range vmExport.Status.Conditions {
+				if condition.Type == exportv1.ConditionReady {
+					Expect(condition.Status).To(Equal(k8sv1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(migratingReason))
+					Expect(condition.Message).To(Equal(fmt.Sprintf("Virtual Machine %s/%s is being live migrated", vmi.Namespace, vmi.Name)))
+				}
+			}
+			return true, vmExport, nil
+		})
+
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(migrationRequeueTime))
+		testutils.ExpectEvent(recorder, serviceCreatedEvent)
+	})
+
+	It("Should freeze and export a running VM when Quiesce is requested and the guest agent is connected", func() {
+		testVMExport := createVMVMExport()
+		quiesce := true
+		testVMExport.Spec.Quiesce = &quiesce
+		controller.VMInformer.GetStore().Add(createVMWithDataVolumes())
+		vmi := createVMIWithDataVolumes()
+		vmi.Status.Conditions = append(vmi.Status.Conditions, virtv1.VirtualMachineInstanceCondition{
+			Type:   virtv1.VirtualMachineInstanceAgentConnected,
+			Status: k8sv1.ConditionTrue,
+		})
+		controller.VMIInformer.GetStore().Add(vmi)
+		controller.PVCInformer.GetStore().Add(createPVC("volume1", "kubevirt"))
+		controller.PVCInformer.GetStore().Add(createPVC("volume2", "kubevirt"))
+		expectExporterCreate(k8sClient, k8sv1.PodRunning)
+		vmiInterface.EXPECT().Freeze(vmi.Name, quiesceFreezeTimeout).Return(nil)
+		vmiInterface.EXPECT().Unfreeze(vmi.Name).Return(nil)
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			for _, condition := range vmExport.Status.Conditions {
+				if condition.Type == exportv1.ConditionReady {
+					Expect(condition.Status).To(Equal(k8sv1.ConditionTrue))
+					Expect(condition.Reason).To(Equal(podReadyReason))
+				}
+			}
+			return true, vmExport, nil
+		})
+
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		testutils.ExpectEvent(recorder, serviceCreatedEvent)
+	})
+
+	It("Should include a volume hotplugged onto a running VM in the export", func() {
+		testVMExport := createVMVMExport()
+		quiesce := true
+		testVMExport.Spec.Quiesce = &quiesce
+		controller.VMInformer.GetStore().Add(createVMWithDataVolumes())
+		vmi := createVMIWithDataVolumes()
+		vmi.Status.Conditions = append(vmi.Status.Conditions, virtv1.VirtualMachineInstanceCondition{
+			Type:   virtv1.VirtualMachineInstanceAgentConnected,
+			Status: k8sv1.ConditionTrue,
+		})
+		vmi.Spec.Volumes = append(vmi.Spec.Volumes, virtv1.Volume{
+			Name: "hotplugged",
+			VolumeSource: virtv1.VolumeSource{
+				PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: k8sv1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "hotplugged",
+					},
+				},
+			},
+		})
+		vmi.Status.VolumeStatus = append(vmi.Status.VolumeStatus, virtv1.VolumeStatus{
+			Name:          "hotplugged",
+			HotplugVolume: &virtv1.HotplugVolumeStatus{},
+		})
+		controller.VMIInformer.GetStore().Add(vmi)
+		controller.PVCInformer.GetStore().Add(createPVC("volume1", "kubevirt"))
+		controller.PVCInformer.GetStore().Add(createPVC("volume2", "kubevirt"))
+		controller.PVCInformer.GetStore().Add(createPVC("hotplugged", "kubevirt"))
+		expectExporterCreate(k8sClient, k8sv1.PodRunning)
+		vmiInterface.EXPECT().Freeze(vmi.Name, quiesceFreezeTimeout).Return(nil)
+		vmiInterface.EXPECT().Unfreeze(vmi.Name).Return(nil)
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			var volumeNames []string
+			for _, volume := range vmExport.Status.Links.Internal.Volumes {
+				volumeNames = append(volumeNames, volume.Name)
+			}
+			Expect(volumeNames).To(ContainElement("hotplugged"))
+			return true, vmExport, nil
+		})
+
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		testutils.ExpectEvent(recorder, serviceCreatedEvent)
+	})
+
+	It("Should NOT export a running VM when Quiesce is requested but no guest agent is connected", func() {
+		testVMExport := createVMVMExport()
+		quiesce := true
+		testVMExport.Spec.Quiesce = &quiesce
+		controller.VMInformer.GetStore().Add(createVMWithDataVolumes())
+		vmi := createVMIWithDataVolumes()
+		controller.VMIInformer.GetStore().Add(vmi)
+		controller.PVCInformer.GetStore().Add(createPVC("volume1", "kubevirt"))
+		controller.PVCInformer.GetStore().Add(createPVC("volume2", "kubevirt"))
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			verifyLinksEmpty(vmExport)
+			for _, condition := range vmExport.Status.Conditions {
+				if condition.Type == exportv1.ConditionReady {
+					Expect(condition.Status).To(Equal(k8sv1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(inUseReason))
+				}
+			}
+			return true, vmExport, nil
+		})
+
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		testutils.ExpectEvent(recorder, serviceCreatedEvent)
+	})
+
 	createPopulatingDataVolume := func(name string) *cdiv1.DataVolume {
 		return &cdiv1.DataVolume{
 			ObjectMeta: metav1.ObjectMeta{
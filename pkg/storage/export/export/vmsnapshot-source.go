@@ -46,6 +46,10 @@ const (
 	notAllPVCsCreated = "NotAllPVCsCreated"
 	allPVCsReady      = "AllPVCsReady"
 	notAllPVCsReady   = "NotAllPVCsReady"
+
+	// insufficientCapacityReason is used when a restore PVC could not be created because the
+	// namespace's storage ResourceQuota doesn't have room for it.
+	insufficientCapacityReason = "InsufficientCapacity"
 )
 
 func (ctrl *VMExportController) handleVMSnapshot(obj interface{}) {
@@ -80,10 +84,19 @@ func (ctrl *VMExportController) getPVCFromSourceVMSnapshot(vmExport *exportv1.Vi
 			availableMessage: fmt.Sprintf("VirtualMachineSnapshot %s/%s does not exist", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
 	}
 	if vmSnapshot.Status.ReadyToUse != nil && *vmSnapshot.Status.ReadyToUse {
-		pvcs, restoreableSnapshots, err := ctrl.handlePVCsForVirtualMachineSnapshot(vmExport, vmSnapshot)
+		pvcs, restoreableSnapshots, blockedMessage, err := ctrl.handlePVCsForVirtualMachineSnapshot(vmExport, vmSnapshot)
 		if err != nil {
 			return &sourceVolumes{}, err
 		}
+		if blockedMessage != "" {
+			return &sourceVolumes{
+				volumes:              nil,
+				inUse:                false,
+				isPopulated:          false,
+				availableMessage:     blockedMessage,
+				insufficientCapacity: true,
+				requeueAfter:         restoreRequeueTime}, nil
+		}
 		if len(pvcs) == restoreableSnapshots && restoreableSnapshots > 0 {
 			return &sourceVolumes{
 				volumes:          pvcs,
@@ -102,68 +115,91 @@ func (ctrl *VMExportController) getPVCFromSourceVMSnapshot(vmExport *exportv1.Vi
 			volumes:          nil,
 			inUse:            false,
 			isPopulated:      false,
-			availableMessage: "Not all PVCs have been successfully restored"}, nil
+			availableMessage: "Not all PVCs have been successfully restored",
+			requeueAfter:     restoreRequeueTime}, nil
 	}
 	return &sourceVolumes{
 		volumes:          nil,
 		inUse:            false,
 		isPopulated:      false,
-		availableMessage: fmt.Sprintf("VirtualMachineSnapshot %s/%s is not ready to use", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
+		availableMessage: fmt.Sprintf("VirtualMachineSnapshot %s/%s is not ready to use", vmExport.Namespace, vmExport.Spec.Source.Name),
+		requeueAfter:     restoreRequeueTime}, nil
 }
 
-func (ctrl *VMExportController) handlePVCsForVirtualMachineSnapshot(vmExport *exportv1.VirtualMachineExport, vmSnapshot *snapshotv1.VirtualMachineSnapshot) ([]*corev1.PersistentVolumeClaim, int, error) {
+func (ctrl *VMExportController) handlePVCsForVirtualMachineSnapshot(vmExport *exportv1.VirtualMachineExport, vmSnapshot *snapshotv1.VirtualMachineSnapshot) ([]*corev1.PersistentVolumeClaim, int, string, error) {
 	var content *snapshotv1.VirtualMachineSnapshotContent
 	var err error
 	var pvcs []*corev1.PersistentVolumeClaim
+	var blockedMessage string
 	exists := false
 	totalVolumes := 0
 
 	if vmSnapshot.Status.VirtualMachineSnapshotContentName != nil && *vmSnapshot.Status.VirtualMachineSnapshotContentName != "" {
 		content, exists, err = ctrl.getVmSnapshotContent(vmSnapshot.Namespace, *vmSnapshot.Status.VirtualMachineSnapshotContentName)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, "", err
 		}
 		if exists {
 			sourceVm := content.Spec.Source.VirtualMachine
 			totalVolumes = len(content.Status.VolumeSnapshotStatus)
 			for _, volumeBackup := range content.Spec.VolumeBackups {
-				if pvc, err := ctrl.getOrCreatePVCFromSnapshot(vmExport, &volumeBackup, sourceVm); err != nil {
-					return nil, 0, err
-				} else {
+				pvc, blocked, err := ctrl.getOrCreatePVCFromSnapshot(vmExport, &volumeBackup, sourceVm)
+				if err != nil {
+					return nil, 0, "", err
+				}
+				if blocked != "" && blockedMessage == "" {
+					blockedMessage = blocked
+				}
+				if pvc != nil {
 					pvcs = append(pvcs, pvc)
 				}
 			}
 		}
 	}
-	return pvcs, totalVolumes, err
+	return pvcs, totalVolumes, blockedMessage, err
 }
 
-func (ctrl *VMExportController) getOrCreatePVCFromSnapshot(vmExport *exportv1.VirtualMachineExport, volumeBackup *snapshotv1.VolumeBackup, sourceVm *snapshotv1.VirtualMachine) (*corev1.PersistentVolumeClaim, error) {
+// getOrCreatePVCFromSnapshot returns the restore PVC for volumeBackup, creating it if it doesn't
+// already exist. If the namespace's storage ResourceQuota doesn't have room for it, it creates
+// nothing and instead returns a message describing why, so the caller can surface an
+// InsufficientCapacity reason instead of leaving the export waiting on a PVC that will never be
+// created.
+func (ctrl *VMExportController) getOrCreatePVCFromSnapshot(vmExport *exportv1.VirtualMachineExport, volumeBackup *snapshotv1.VolumeBackup, sourceVm *snapshotv1.VirtualMachine) (*corev1.PersistentVolumeClaim, string, error) {
 	if volumeBackup.VolumeSnapshotName == nil {
 		log.Log.Errorf("VolumeSnapshot name missing %+v", volumeBackup)
-		return nil, fmt.Errorf("missing VolumeSnapshot name")
+		return nil, "", fmt.Errorf("missing VolumeSnapshot name")
 	}
 	restorePVCName := fmt.Sprintf("%s-%s", vmExport.Name, volumeBackup.PersistentVolumeClaim.Name)
 
 	if pvc, exists, err := ctrl.getPvc(vmExport.Namespace, restorePVCName); err != nil {
-		return nil, err
+		return nil, "", err
 	} else if exists {
-		return pvc, nil
+		return pvc, "", nil
 	}
 
 	volumeSnapshot, err := ctrl.VolumeSnapshotProvider.GetVolumeSnapshot(vmExport.Namespace, *volumeBackup.VolumeSnapshotName)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// leaving source name and namespace blank because we don't care in this context
 	pvc := snapshot.CreateRestorePVCDef(restorePVCName, volumeSnapshot, volumeBackup)
+	if vmExport.Spec.RestoreStorageClassName != nil {
+		pvc.Spec.StorageClassName = vmExport.Spec.RestoreStorageClassName
+	}
 	if volumeBackupIsKubeVirtContent(volumeBackup, sourceVm) {
 		if len(pvc.GetAnnotations()) == 0 {
 			pvc.SetAnnotations(make(map[string]string))
 		}
 		pvc.Annotations[annContentType] = string(cdiv1.DataVolumeKubeVirt)
 	}
+
+	if blocked, err := ctrl.checkStorageQuota(vmExport.Namespace, pvc); err != nil {
+		return nil, "", err
+	} else if blocked != "" {
+		return nil, blocked, nil
+	}
+
 	pvc.SetOwnerReferences([]metav1.OwnerReference{
 		{
 			APIVersion:         exportGVK.GroupVersion().String(),
@@ -177,29 +213,67 @@ func (ctrl *VMExportController) getOrCreatePVCFromSnapshot(vmExport *exportv1.Vi
 
 	pvc, err = ctrl.Client.CoreV1().PersistentVolumeClaims(vmExport.Namespace).Create(context.Background(), pvc, metav1.CreateOptions{})
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	return pvc, "", nil
+}
+
+// checkStorageQuota returns a message describing why restorePVC can't be provisioned if
+// namespace's storage ResourceQuota doesn't have enough requests.storage left for it, or "" if
+// there's no applicable quota, or the quota has room. It only checks quota, not the StorageClass's
+// actual backing capacity: most CSI drivers don't publish CSIStorageCapacity, so it would rarely
+// have anything to check, while an exceeded namespace quota is the common way a restore PVC is
+// left Pending indefinitely and is enough to give users an actionable reason.
+func (ctrl *VMExportController) checkStorageQuota(namespace string, restorePVC *corev1.PersistentVolumeClaim) (string, error) {
+	requested, ok := restorePVC.Spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return "", nil
+	}
+
+	quotas, err := ctrl.Client.CoreV1().ResourceQuotas(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, quota := range quotas.Items {
+		hard, hasHard := quota.Status.Hard[corev1.ResourceRequestsStorage]
+		if !hasHard {
+			continue
+		}
+		remaining := hard.DeepCopy()
+		if used, hasUsed := quota.Status.Used[corev1.ResourceRequestsStorage]; hasUsed {
+			remaining.Sub(used)
+		}
+		if requested.Cmp(remaining) > 0 {
+			return fmt.Sprintf("restoring volume %s needs %s of storage, but ResourceQuota %s only has %s of requests.storage left in namespace %s",
+				restorePVC.Name, requested.String(), quota.Name, remaining.String(), namespace), nil
+		}
 	}
-	return pvc, nil
+	return "", nil
 }
 
 func (ctrl *VMExportController) updateVMExporVMSnapshotStatus(vmExport *exportv1.VirtualMachineExport, exporterPod *corev1.Pod, service *corev1.Service, sourceVolumes *sourceVolumes) (time.Duration, error) {
+	var requeue time.Duration
+	if !sourceVolumes.isPopulated {
+		requeue = ctrl.requeueInterval(sourceVolumes)
+	}
+
 	vmExportCopy := vmExport.DeepCopy()
 
 	if err := ctrl.updateCommonVMExportStatusFields(vmExport, vmExportCopy, exporterPod, service, sourceVolumes); err != nil {
-		return 0, err
+		return requeue, err
 	}
 
-	if err := ctrl.updateVMSnapshotExportStatusConditions(vmExportCopy, sourceVolumes.volumes, sourceVolumes.availableMessage); err != nil {
-		return 0, err
+	if err := ctrl.updateVMSnapshotExportStatusConditions(vmExportCopy, sourceVolumes.volumes, sourceVolumes.availableMessage, sourceVolumes.insufficientCapacity); err != nil {
+		return requeue, err
 	}
 
 	if err := ctrl.updateVMExportStatus(vmExport, vmExportCopy); err != nil {
-		return 0, err
+		return requeue, err
 	}
-	return 0, nil
+	return requeue, nil
 }
 
-func (ctrl *VMExportController) updateVMSnapshotExportStatusConditions(vmExportCopy *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim, availableMessage string) error {
+func (ctrl *VMExportController) updateVMSnapshotExportStatusConditions(vmExportCopy *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim, availableMessage string, insufficientCapacity bool) error {
 	vmSnapshot, exists, err := ctrl.getVmSnapshot(vmExportCopy.Namespace, vmExportCopy.Spec.Source.Name)
 	if err != nil {
 		return err
@@ -209,6 +283,10 @@ func (ctrl *VMExportController) updateVMSnapshotExportStatusConditions(vmExportC
 		vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionFalse, initializingReason, ""))
 		return nil
 	}
+	if insufficientCapacity {
+		vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newVolumesCreatedCondition(corev1.ConditionFalse, insufficientCapacityReason, availableMessage))
+		return nil
+	}
 	if vmSnapshot.Status.VirtualMachineSnapshotContentName != nil && *vmSnapshot.Status.VirtualMachineSnapshotContentName != "" {
 		content, exists, err := ctrl.getVmSnapshotContent(vmSnapshot.Namespace, *vmSnapshot.Status.VirtualMachineSnapshotContentName)
 		if err != nil {
@@ -0,0 +1,85 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"kubevirt.io/client-go/log"
+)
+
+// accessLogResponseWriter wraps a http.ResponseWriter to record the number of bytes written to it
+// and its final status code, for accessLogger to report once the request completes.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	bytes      int64
+}
+
+func (w *accessLogResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *accessLogResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// tokenDisposition returns how a request's export token was handled, for the access log entry.
+// It has to be inferred from the response status code, since tokenChecker doesn't otherwise
+// surface a verdict.
+func tokenDisposition(statusCode int) string {
+	// tokenChecker answers 429 when a scoped token has used up its MaxReads.
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusTooManyRequests {
+		return "rejected"
+	}
+	return "accepted"
+}
+
+// accessLogger logs one structured JSON entry per completed request: the client's address, the
+// artifact path requested, how the request's export token was disposed of, the number of bytes
+// served, and how long the request took. This is the audit trail for who downloaded what from an
+// exporter pod.
+func accessLogger(nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		lw := &accessLogResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		nextHandler.ServeHTTP(lw, r)
+
+		clientIP := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			clientIP = host
+		}
+
+		log.Log.With(
+			"clientIP", clientIP,
+			"path", r.URL.Path,
+			"bytes", lw.bytes,
+			"durationSeconds", time.Since(start).Seconds(),
+			"statusCode", lw.statusCode,
+			"token", tokenDisposition(lw.statusCode),
+		).Info("export download request")
+	})
+}
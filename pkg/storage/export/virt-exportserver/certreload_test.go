@@ -0,0 +1,103 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// writeSelfSignedCert writes a freshly generated self-signed certificate/key pair, identified by
+// serial, to certFile/keyFile, so tests can tell two generated certificates apart.
+func writeSelfSignedCert(certFile, keyFile string, serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test.example.com"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+
+	Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)).To(Succeed())
+}
+
+var _ = Describe("certReloader", func() {
+	var certFile, keyFile string
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		certFile = filepath.Join(dir, "tls.crt")
+		keyFile = filepath.Join(dir, "tls.key")
+		writeSelfSignedCert(certFile, keyFile, 1)
+	})
+
+	It("should load the initial certificate", func() {
+		reloader, err := newCertReloader(certFile, keyFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		cert, err := reloader.GetCertificate(nil)
+		Expect(err).ToNot(HaveOccurred())
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leaf.SerialNumber.Int64()).To(Equal(int64(1)))
+	})
+
+	It("should fail to start if the certificate cannot be loaded", func() {
+		_, err := newCertReloader(filepath.Join(GinkgoT().TempDir(), "missing.crt"), keyFile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should pick up a renewed certificate written to the same path", func() {
+		reloader, err := newCertReloader(certFile, keyFile)
+		Expect(err).ToNot(HaveOccurred())
+		go reloader.watch()
+		<-reloader.watching
+
+		writeSelfSignedCert(certFile, keyFile, 2)
+
+		Eventually(func() int64 {
+			cert, err := reloader.GetCertificate(nil)
+			Expect(err).ToNot(HaveOccurred())
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			Expect(err).ToNot(HaveOccurred())
+			return leaf.SerialNumber.Int64()
+		}, 5*time.Second, 10*time.Millisecond).Should(Equal(int64(2)))
+	})
+})
@@ -23,16 +23,37 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	goflag "flag"
+	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	flag "github.com/spf13/pflag"
+	"golang.org/x/net/http2"
+	"golang.org/x/time/rate"
 
 	"kubevirt.io/client-go/log"
 
@@ -41,17 +62,101 @@ import (
 
 const (
 	authHeader = "x-kubevirt-export-token"
+
+	// ovaProgressName is the key an OVA download's progress is tracked under in
+	// internalProgressPath, since it isn't tied to a single volume.
+	ovaProgressName = "ova"
+
+	// allProgressName is the key a combined-archive download's progress is tracked under in
+	// internalProgressPath, since it isn't tied to a single volume.
+	allProgressName = "all"
+
+	// manifestProgressName is the key a manifest download's progress is tracked under in
+	// internalProgressPath, since it isn't tied to a single volume.
+	manifestProgressName = "manifest"
+
+	// expandedManifestProgressName is the key an expanded manifest download's progress is
+	// tracked under in internalProgressPath, since it isn't tied to a single volume.
+	expandedManifestProgressName = "expanded-manifest"
+
+	// internalProgressPath serves a JSON map of volume name to bytes transferred so far, for the
+	// controller to report per-volume download progress in VirtualMachineExport status. It isn't
+	// token-protected since it reveals nothing about the exported data itself.
+	internalProgressPath = "/internal/progress"
+
+	// internalMetadataPath serves a JSON map of download URI to the logical size and sha256
+	// checksum of the artifact served at that URI, for the controller to publish in
+	// VirtualMachineExport status. Entries appear once the exporter pod has finished computing
+	// them, which happens in the background and can take a while for large volumes.
+	internalMetadataPath = "/internal/metadata"
+
+	// internalDeadlinePath accepts a POST with a JSON body of the form {"deadline": <RFC3339
+	// timestamp>}, updating this server's deadline in place. It lets the controller give a
+	// transfer that's still running close to expiry more time, by pushing an updated
+	// spec.deadline here, without recreating the exporter pod. Like the other internal endpoints,
+	// it isn't token-protected.
+	internalDeadlinePath = "/internal/deadline"
+
+	// metricsPath serves this exporter pod's prometheus metrics: bytes served, active
+	// connections and completed downloads, broken down by download artifact. It isn't
+	// token-protected, matching the other internal endpoints, and is intended to be scraped by a
+	// ServiceMonitor pointed at the exporter Service's "metrics" port.
+	metricsPath = "/metrics"
+
+	// defaultZstdCompressionLevel is zstd's own default compression level, used when
+	// ExportServerConfig.ZstdCompressionLevel is not set.
+	defaultZstdCompressionLevel = 3
+
+	// defaultGzipCompressionLevel is compress/gzip's own default compression level, used when
+	// ExportServerConfig.GzipCompressionLevel is not set.
+	defaultGzipCompressionLevel = gzip.DefaultCompression
+
+	// defaultShutdownGracePeriod is how long this server waits for in-flight downloads to finish
+	// once it starts shutting down, used when ExportServerConfig.ShutdownGracePeriod is not set.
+	defaultShutdownGracePeriod = 30 * time.Second
+
+	// checksumURISuffix, appended to a raw, gzip, or archive download URI, serves that artifact's
+	// sha256 checksum as plain text.
+	checksumURISuffix = ".sha256"
+
+	// concurrencyLimitRetryAfterSeconds is the Retry-After value, in seconds, sent on the 503
+	// response returned once MaxConcurrentDownloads is reached.
+	concurrencyLimitRetryAfterSeconds = 5
+
+	// nbdkitSocketPollInterval and nbdkitStartupTimeout bound how long newQcow2Reader waits for
+	// nbdkit to create its NBD export's unix socket before giving up.
+	nbdkitSocketPollInterval = 10 * time.Millisecond
+	nbdkitStartupTimeout     = 10 * time.Second
 )
 
-type TokenGetterFunc func() (string, error)
+// TokenGetterFunc returns the set of tokens that are currently valid for authenticating
+// download requests. More than one token may be valid at a time while a VMExport's token
+// is being rotated, so that in-flight downloads started with the previous token keep working.
+type TokenGetterFunc func() ([]string, error)
 
 type VolumeInfo struct {
-	Path       string
-	ArchiveURI string
-	DirURI     string
-	RawURI     string
-	RawGzURI   string
+	Name           string
+	Path           string
+	ArchiveURI     string
+	ArchiveZstdURI string
+	DirURI         string
+	RawURI         string
+	RawGzURI       string
+	ZstdURI        string
+	Qcow2URI       string
+	Qcow2GzURI     string
+	VmdkURI        string
+	VhdURI         string
+	VhdxURI        string
+
+	// IncludePatterns, if non-empty, restricts a Dir/ArchiveURI/ArchiveZstdURI export of this
+	// volume to files whose path relative to Path matches at least one of these glob patterns.
+	IncludePatterns []string
+	// ExcludePatterns drops files whose path relative to Path matches any of these glob patterns
+	// from a Dir/ArchiveURI/ArchiveZstdURI export of this volume, applied after IncludePatterns.
+	ExcludePatterns []string
 }
+
 type ExportServerConfig struct {
 	Deadline time.Time
 
@@ -61,13 +166,148 @@ type ExportServerConfig struct {
 
 	TokenFile string
 
+	// AdditionalTokenFiles is a list of paths to files holding tokens that are also accepted
+	// in addition to the one in TokenFile, to support rotating the export token without
+	// invalidating in-flight downloads.
+	AdditionalTokenFiles []string
+
+	// ScopedTokens, if set, has this server also accept the token in each entry's TokenFile, but
+	// only for the volumes (and up to the number of reads) its scope allows, unlike the
+	// unrestricted tokens in TokenFile and AdditionalTokenFiles.
+	ScopedTokens []*TokenScope
+
 	Volumes []VolumeInfo
 
+	// S3Upload, if set, additionally has this server push each volume's raw artifact to an
+	// S3-compatible object store as it serves it for download.
+	S3Upload *S3UploadConfig
+
+	// RegistryUpload, if set, additionally has this server build each volume's raw artifact
+	// into a containerDisk image and push it to an OCI registry as it serves it for download.
+	RegistryUpload *RegistryUploadConfig
+
+	// ClusterUpload, if set, additionally has this server create a DataVolume for each volume's
+	// raw artifact on another cluster and stream the artifact into it as it serves it for
+	// download.
+	ClusterUpload *ClusterUploadConfig
+
+	// Ova, if set, additionally has this server bundle a generated OVF descriptor and every
+	// volume's disk into a single downloadable OVA archive.
+	Ova *OvaConfig
+
+	// AllURI, if set, additionally has this server bundle every volume's raw disk image, plus
+	// the generated manifests if present, into a single downloadable tar archive served at that
+	// path.
+	AllURI string
+
+	// ManifestURI, if set along with ManifestFile, additionally has this server serve the
+	// generated manifest file at that path.
+	ManifestURI string
+	// ManifestFile is the path to the generated manifest this server serves at ManifestURI.
+	ManifestFile string
+
+	// ExpandedManifestURI, if set along with ExpandedManifestFile, additionally has this server
+	// serve the generated manifest file with an expanded VM spec at that path.
+	ExpandedManifestURI string
+	// ExpandedManifestFile is the path to the generated manifest this server serves at
+	// ExpandedManifestURI.
+	ExpandedManifestFile string
+
+	// EncryptionKeyFile, if set, is the path to a file holding a 32-byte AES-256 key this server
+	// uses to encrypt every artifact it serves, on top of TLS and the download token. If this is
+	// not set, artifacts are served unencrypted, matching the behavior as before this field was
+	// introduced.
+	EncryptionKeyFile string
+
+	// ZstdCompressionLevel is the compression level used for the zstd and tar.zst formats, from 1
+	// (fastest) to 19 (smallest). If this is not set, it defaults to 3, zstd's own default.
+	ZstdCompressionLevel int
+
+	// GzipCompressionLevel is the compression level used for the gz and tar.gz formats, from 1
+	// (fastest) to 9 (smallest), or compress/gzip's special-cased -1 for its own default. If this
+	// is not set, it defaults to compress/gzip's own default.
+	GzipCompressionLevel int
+
+	// PerConnectionBandwidthLimit, if set, caps the egress bandwidth, in bytes per second, of
+	// each individual download connection this server serves. If this is not set, individual
+	// connections are unlimited.
+	PerConnectionBandwidthLimit int64
+
+	// TotalBandwidthLimit, if set, caps this server's combined egress bandwidth, in bytes per
+	// second, across every connection it currently has open. If this is not set, this server's
+	// total bandwidth is unlimited.
+	TotalBandwidthLimit int64
+
+	// MinTLSVersion, if set, is the minimum TLS version this server accepts connections with, as
+	// a crypto/tls MinVersion constant. If this is not set, it defaults to tls.VersionTLS12.
+	MinTLSVersion uint16
+
+	// CipherSuites, if set, is the list of TLS cipher suite IDs this server accepts connections
+	// with. If this is not set, Go's default cipher suite selection is used.
+	CipherSuites []uint16
+
+	// ClientCAFile, if set, is the path to a PEM-encoded CA bundle. Clients presenting a
+	// certificate signed by this CA are authenticated without needing a valid download token. If
+	// this is not set, only the download token is accepted, matching the behavior as before this
+	// field was introduced.
+	ClientCAFile string
+
+	// ShutdownGracePeriod is how long this server waits for in-flight downloads to finish once
+	// it starts shutting down, whether because Deadline was reached or because it received
+	// SIGTERM, before forcibly closing remaining connections. If this is not set, it defaults to
+	// 30 seconds.
+	ShutdownGracePeriod time.Duration
+
+	// PrecomputeFormats, if set, has this server pre-generate the listed raw-volume artifact
+	// formats into scratch space right after startup, instead of compressing them fresh on every
+	// download, so a frequently-downloaded export's first request doesn't pay that cost either.
+	// Accepted values are "gz" and "zstd". If this is not set, every download is compressed on
+	// demand as before.
+	PrecomputeFormats []string
+
+	// PerClientIPRequestLimit, if set, caps how many requests per second a single client IP may
+	// make against this server, to mitigate an abusive or runaway client hammering a download
+	// endpoint. A client that exceeds it immediately gets a 429 instead of queuing.
+	PerClientIPRequestLimit float64
+
+	// PerClientIPRequestBurst is how many requests a client IP may make in a single burst before
+	// PerClientIPRequestLimit applies. It defaults to 1 if PerClientIPRequestLimit is set but
+	// this is not.
+	PerClientIPRequestBurst int
+
+	// MaxConcurrentDownloads, if set, caps how many downloads this server serves at once, across
+	// every volume and format combined. Requests beyond the limit get a 503 with a Retry-After
+	// header instead of being served. If this is not set, the number of concurrent downloads is
+	// unlimited.
+	MaxConcurrentDownloads int
+
+	// NBDListenAddr, if set, additionally has this server expose each volume in Volumes over NBD
+	// (Network Block Device), so a client can read a disk with random access instead of
+	// downloading the whole image. Each volume gets its own NBD export on its own port, starting
+	// from the port in NBDListenAddr and incrementing by one per volume, in the order Volumes is
+	// given. NBD connections are authenticated with TLS-PSK, using the tokens from TokenGetter as
+	// the pre-shared keys. If this is not set, NBD is not exposed.
+	NBDListenAddr string
+
+	// Hooks, if set, has this server run external commands around serving its artifacts for
+	// download. If this is not set, no hooks run, matching the behavior as before this field was
+	// introduced.
+	Hooks *HookConfig
+
 	// unit testing helpers
-	ArchiveHandler func(string) http.Handler
-	DirHandler     func(string, string) http.Handler
-	FileHandler    func(string) http.Handler
-	GzipHandler    func(string) http.Handler
+	ArchiveHandler     func(mountPoint string, level int, includePatterns, excludePatterns []string) http.Handler
+	ArchiveZstdHandler func(mountPoint string, level int, includePatterns, excludePatterns []string) http.Handler
+	DirHandler         func(string, string) http.Handler
+	FileHandler        func(string) http.Handler
+	GzipHandler        func(string, int) http.Handler
+	ZstdHandler        func(string, int) http.Handler
+	Qcow2Handler       func(string) http.Handler
+	Qcow2GzHandler     func(string, int) http.Handler
+	VmdkHandler        func(string) http.Handler
+	VhdHandler         func(string) http.Handler
+	VhdxHandler        func(string) http.Handler
+	OvaHandler         func(OvaConfig) http.Handler
+	AllHandler         func(AllConfig) http.Handler
 
 	TokenGetter TokenGetterFunc
 }
@@ -76,11 +316,506 @@ type execReader struct {
 	cmd    *exec.Cmd
 	stdout io.ReadCloser
 	stderr io.ReadCloser
+	// closer, if set, is closed alongside stdout. It is used to release a reader piped into
+	// cmd's stdin, e.g. the tar reader newTarZstdReader chains into the zstd subprocess.
+	closer io.Closer
 }
 
 type exportServer struct {
 	ExportServerConfig
-	handler http.Handler
+	handler        http.Handler
+	progress       *volumeProgress
+	metadata       *artifactMetadataStore
+	metadataOnce   sync.Once
+	precomputed    *precomputedArtifactStore
+	precomputeOnce sync.Once
+	s3Status       *s3UploadStatusStore
+	s3UploadOnce   sync.Once
+	encryptionKey  []byte
+	clientCAPool   *x509.CertPool
+
+	registryStatus     *registryUploadStatusStore
+	registryUploadOnce sync.Once
+
+	clusterStatus     *clusterUploadStatusStore
+	clusterUploadOnce sync.Once
+
+	// hookStatus tracks the outcome of ExportServerConfig.Hooks' commands, if set, so the
+	// controller can poll it and report a hook failure in the VirtualMachineExport's
+	// HooksReady condition.
+	hookStatus *hookStatusStore
+
+	// totalLimiter enforces TotalBandwidthLimit across every connection this server has open. It
+	// is shared by every request, unlike the per-connection limiter each request gets its own
+	// instance of.
+	totalLimiter *rate.Limiter
+
+	// ipLimiter enforces PerClientIPRequestLimit, tracking one request-rate budget per client IP.
+	ipLimiter *ipRateLimiter
+
+	// activeDownloads is the number of downloads currently being served, across every volume and
+	// format, used to enforce MaxConcurrentDownloads. It is only ever accessed atomically.
+	activeDownloads int32
+
+	// nbdCmds holds the nbdkit processes started by startNBDExports, if NBDListenAddr is set, so
+	// Run can stop them alongside the HTTP server when draining.
+	nbdCmds []*exec.Cmd
+
+	// deadlineTimer fires drain when ExportServerConfig.Deadline is reached. extendDeadline resets
+	// it, so a POST to internalDeadlinePath can give a transfer close to expiry more time without
+	// restarting this process. It is nil until Run starts it, and deadlineMu guards both fields
+	// against Run and the internal deadline endpoint racing each other.
+	deadlineMu    sync.Mutex
+	deadlineTimer *time.Timer
+}
+
+// artifactMetadata is the logical size and sha256 checksum of a single downloadable artifact.
+type artifactMetadata struct {
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// artifactMetadataStore tracks artifactMetadata by download URI, once it has been computed.
+type artifactMetadataStore struct {
+	mu    sync.Mutex
+	byURI map[string]artifactMetadata
+}
+
+func newArtifactMetadataStore() *artifactMetadataStore {
+	return &artifactMetadataStore{byURI: make(map[string]artifactMetadata)}
+}
+
+func (s *artifactMetadataStore) set(uri string, metadata artifactMetadata) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byURI[uri] = metadata
+}
+
+func (s *artifactMetadataStore) snapshot() map[string]artifactMetadata {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]artifactMetadata, len(s.byURI))
+	for uri, metadata := range s.byURI {
+		result[uri] = metadata
+	}
+	return result
+}
+
+// volumeProgress tracks, for each volume by name, the number of bytes that have been written to
+// clients downloading it so far, across every request made for that volume, along with when its
+// most recently started download began and finished being served, so status can distinguish a
+// volume nobody has downloaded yet from one that has already been consumed.
+type volumeProgress struct {
+	mu                 sync.Mutex
+	bytesByName        map[string]int64
+	lastStartByName    map[string]time.Time
+	lastCompleteByName map[string]time.Time
+}
+
+func newVolumeProgress() *volumeProgress {
+	return &volumeProgress{
+		bytesByName:        make(map[string]int64),
+		lastStartByName:    make(map[string]time.Time),
+		lastCompleteByName: make(map[string]time.Time),
+	}
+}
+
+func (p *volumeProgress) add(name string, n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bytesByName[name] += n
+}
+
+// recordStart marks that a download of the volume name has just started being served.
+func (p *volumeProgress) recordStart(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastStartByName[name] = time.Now()
+}
+
+// recordComplete marks that a download of the volume name has just finished being served.
+func (p *volumeProgress) recordComplete(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastCompleteByName[name] = time.Now()
+}
+
+// volumeActivity is the JSON shape volumeProgress.snapshot reports for a single volume over
+// internalProgressPath.
+type volumeActivity struct {
+	BytesTransferred                int64      `json:"bytesTransferred"`
+	LastDownloadStartTimestamp      *time.Time `json:"lastDownloadStartTimestamp,omitempty"`
+	LastDownloadCompletionTimestamp *time.Time `json:"lastDownloadCompletionTimestamp,omitempty"`
+}
+
+func (p *volumeProgress) snapshot() map[string]volumeActivity {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := make(map[string]volumeActivity, len(p.bytesByName))
+	for name, bytes := range p.bytesByName {
+		result[name] = volumeActivity{BytesTransferred: bytes}
+	}
+	for name, start := range p.lastStartByName {
+		start := start
+		activity := result[name]
+		activity.LastDownloadStartTimestamp = &start
+		result[name] = activity
+	}
+	for name, complete := range p.lastCompleteByName {
+		complete := complete
+		activity := result[name]
+		activity.LastDownloadCompletionTimestamp = &complete
+		result[name] = activity
+	}
+	return result
+}
+
+// progressResponseWriter wraps a http.ResponseWriter to report every byte written to it to a
+// volumeProgress, regardless of which handler produced it.
+type progressResponseWriter struct {
+	http.ResponseWriter
+	name     string
+	progress *volumeProgress
+}
+
+func (w *progressResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.progress.add(w.name, int64(n))
+	return n, err
+}
+
+// encryptionResponseWriter wraps a http.ResponseWriter to encrypt every byte written to it with
+// AES-256 in CTR mode, prefixing the response body with the randomly generated IV the stream was
+// encrypted with. CTR is used instead of an AEAD like AES-GCM because it can be applied to an
+// unbounded stream in constant memory, which matters for multi-hundred-GB disk images; the
+// download token and TLS already authenticate the requester and the transport, so this is meant
+// to add defense in depth for artifacts that traverse shared ingress infrastructure, not to
+// additionally provide integrity checking of its own.
+type encryptionResponseWriter struct {
+	http.ResponseWriter
+	block cipher.Block
+	iv    []byte
+
+	ivWritten bool
+	stream    cipher.Stream
+}
+
+func newEncryptionResponseWriter(key []byte, w http.ResponseWriter) (*encryptionResponseWriter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := cryptorand.Read(iv); err != nil {
+		return nil, err
+	}
+	return &encryptionResponseWriter{ResponseWriter: w, block: block, iv: iv}, nil
+}
+
+// Write lazily prefixes the response body with the IV on the first call, instead of in
+// newEncryptionResponseWriter, so nextHandler still gets to set headers and the response status
+// (for example a Range handler's 206 and Content-Range) before net/http implicitly locks in a 200
+// on the first byte written.
+func (w *encryptionResponseWriter) Write(p []byte) (int, error) {
+	if !w.ivWritten {
+		if _, err := w.ResponseWriter.Write(w.iv); err != nil {
+			return 0, err
+		}
+		w.stream = cipher.NewCTR(w.block, w.iv)
+		w.ivWritten = true
+	}
+	ciphertext := make([]byte, len(p))
+	w.stream.XORKeyStream(ciphertext, p)
+	if _, err := w.ResponseWriter.Write(ciphertext); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// encryptor wraps nextHandler so every byte it writes to its ResponseWriter is encrypted, if key
+// is set. It is a no-op if key is nil, so callers can apply it unconditionally.
+func encryptor(key []byte, nextHandler http.Handler) http.Handler {
+	if key == nil {
+		return nextHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ew, err := newEncryptionResponseWriter(key, w)
+		if err != nil {
+			log.Log.Reason(err).Error("error setting up artifact encryption")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		nextHandler.ServeHTTP(ew, r)
+	})
+}
+
+// rateLimitedResponseWriter wraps a http.ResponseWriter so every byte written to it is throttled
+// to limiter's configured rate, blocking as needed. Writes larger than limiter's burst size are
+// split into burst-sized chunks, since rate.Limiter.WaitN rejects a request for more tokens than
+// the bucket can ever hold.
+type rateLimitedResponseWriter struct {
+	http.ResponseWriter
+	limiter *rate.Limiter
+}
+
+func (w *rateLimitedResponseWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if burst := w.limiter.Burst(); n > burst {
+			n = burst
+		}
+		if err := w.limiter.WaitN(context.Background(), n); err != nil {
+			return written, err
+		}
+		wn, err := w.ResponseWriter.Write(p[:n])
+		written += wn
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// perConnectionRateLimiter wraps nextHandler so every byte it writes to its ResponseWriter is
+// throttled to at most bytesPerSecond, giving every connection its own independent budget. It is
+// a no-op if bytesPerSecond is not positive, so callers can apply it unconditionally.
+func perConnectionRateLimiter(bytesPerSecond int64, nextHandler http.Handler) http.Handler {
+	if bytesPerSecond <= 0 {
+		return nextHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))
+		nextHandler.ServeHTTP(&rateLimitedResponseWriter{ResponseWriter: w, limiter: limiter}, r)
+	})
+}
+
+// totalRateLimiter wraps nextHandler so every byte it writes to its ResponseWriter draws from the
+// single limiter shared across every connection this server has open, bounding this exporter
+// pod's combined egress bandwidth regardless of how many connections are open at once. It is a
+// no-op if limiter is nil, so callers can apply it unconditionally.
+func totalRateLimiter(limiter *rate.Limiter, nextHandler http.Handler) http.Handler {
+	if limiter == nil {
+		return nextHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextHandler.ServeHTTP(&rateLimitedResponseWriter{ResponseWriter: w, limiter: limiter}, r)
+	})
+}
+
+// concurrencyLimiter wraps nextHandler so at most maxConcurrent requests are served at once,
+// sharing the counter referenced by active across every request it's applied to. Once the limit
+// is reached, further requests immediately get a 503 with a Retry-After header instead of
+// queuing, so a single export can't exhaust the pod's resources by opening more downloads than
+// it can actually serve. It is a no-op if maxConcurrent is not positive, so callers can apply it
+// unconditionally.
+func concurrencyLimiter(maxConcurrent int, active *int32, nextHandler http.Handler) http.Handler {
+	if maxConcurrent <= 0 {
+		return nextHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(active, 1) > int32(maxConcurrent) {
+			atomic.AddInt32(active, -1)
+			w.Header().Set("Retry-After", strconv.Itoa(concurrencyLimitRetryAfterSeconds))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		defer atomic.AddInt32(active, -1)
+		nextHandler.ServeHTTP(w, r)
+	})
+}
+
+// ipRateLimiter tracks one token-bucket request-rate budget per client IP, so PerClientIPRequestLimit
+// can be enforced independently for every client instead of sharing a single budget across all of
+// them the way totalLimiter does for bandwidth. Entries are never evicted, which is acceptable
+// given this server's exporter-pod lifetime is bounded by its VirtualMachineExport's deadline.
+type ipRateLimiter struct {
+	rate  rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newIPRateLimiter(requestsPerSecond float64, burst int) *ipRateLimiter {
+	return &ipRateLimiter{
+		rate:     rate.Limit(requestsPerSecond),
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// allow reports whether ip is still within its request-rate budget, creating a fresh budget for
+// an IP seen for the first time.
+func (l *ipRateLimiter) allow(ip string) bool {
+	l.mu.Lock()
+	limiter, ok := l.limiters[ip]
+	if !ok {
+		limiter = rate.NewLimiter(l.rate, l.burst)
+		l.limiters[ip] = limiter
+	}
+	l.mu.Unlock()
+	return limiter.Allow()
+}
+
+// clientIP returns the IP address r was made from, with any port stripped, falling back to the
+// raw RemoteAddr if it isn't in host:port form.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientIPRateLimiter wraps nextHandler so each client IP is limited to at most limiter's
+// configured request rate, guarding against a single abusive or runaway client overwhelming this
+// exporter pod. A client that exceeds its budget immediately gets a 429 instead of queuing. It is
+// a no-op if limiter is nil, so callers can apply it unconditionally.
+func clientIPRateLimiter(limiter *ipRateLimiter, nextHandler http.Handler) http.Handler {
+	if limiter == nil {
+		return nextHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.allow(clientIP(r)) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		nextHandler.ServeHTTP(w, r)
+	})
+}
+
+// readEncryptionKey reads the AES-256 key used by encryptor from keyFile, which must contain
+// exactly 32 bytes.
+func readEncryptionKey(keyFile string) ([]byte, error) {
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key in %s must be 32 bytes, got %d", keyFile, len(key))
+	}
+	return key, nil
+}
+
+// readClientCAPool reads the PEM-encoded CA bundle in caFile into a cert pool used to authenticate
+// clients presenting a certificate signed by that CA.
+func readClientCAPool(caFile string) (*x509.CertPool, error) {
+	pemBytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return pool, nil
+}
+
+func progressTracker(name string, progress *volumeProgress, nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		progress.recordStart(name)
+		nextHandler.ServeHTTP(&progressResponseWriter{ResponseWriter: w, name: name, progress: progress}, r)
+		progress.recordComplete(name)
+	})
+}
+
+func progressHandler(progress *volumeProgress) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(progress.snapshot()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+func metadataHandler(metadata *artifactMetadataStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(metadata.snapshot()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+// deadlineHandler decodes a JSON body of the form {"deadline": <RFC3339 timestamp>} and applies
+// it to s via extendDeadline, letting the controller give a transfer that's still running close
+// to expiry more time without recreating the exporter pod.
+func deadlineHandler(s *exportServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var body struct {
+			Deadline time.Time `json:"deadline"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Deadline.IsZero() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.extendDeadline(body.Deadline)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// checksumHandler serves the sha256 checksum of the artifact downloadable at uri, as plain text,
+// so a client can verify it directly against the exporter instead of trusting the checksum
+// published out of band in VirtualMachineExport status. computeArtifactMetadata fills this in
+// asynchronously, so until it finishes for uri this responds 503 Service Unavailable, the same as
+// a client would see if it asked before the download itself was ready.
+func checksumHandler(metadata *artifactMetadataStore, uri string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, ok := metadata.snapshot()[uri]
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintln(w, m.Checksum)
+	})
+}
+
+// contentLengthSetter sets the Content-Length header from metadata's precomputed size for uri,
+// before delegating to nextHandler, so download tools can show progress and validate completeness
+// on compressed formats whose size isn't known until the artifact has already been produced once.
+// computeArtifactMetadata fills this in asynchronously, so until it finishes for uri this serves
+// the download without a Content-Length, the same as before this was introduced. Range requests
+// are left alone, since a partial response's Content-Length describes the returned range, not the
+// full artifact.
+func contentLengthSetter(metadata *artifactMetadataStore, uri string, nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			if m, ok := metadata.snapshot()[uri]; ok {
+				w.Header().Set("Content-Length", strconv.FormatInt(m.Size, 10))
+			}
+		}
+		nextHandler.ServeHTTP(w, r)
+	})
+}
+
+// etagHandler sets an ETag header from metadata's precomputed sha256 checksum for uri, and honors
+// If-None-Match conditional requests by responding 304 Not Modified without re-serving the body,
+// so backup tooling that re-pulls the same export periodically can confirm nothing changed without
+// downloading the artifact again. Like contentLengthSetter, computeArtifactMetadata fills metadata
+// in asynchronously, so until it finishes for uri this serves the download without an ETag,
+// matching the behavior as before this was introduced.
+func etagHandler(metadata *artifactMetadataStore, uri string, nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, ok := metadata.snapshot()[uri]
+		if !ok {
+			nextHandler.ServeHTTP(w, r)
+			return
+		}
+		etag := `"` + m.Checksum + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		nextHandler.ServeHTTP(w, r)
+	})
 }
 
 func (er *execReader) Read(p []byte) (int, error) {
@@ -96,138 +831,1097 @@ func (er *execReader) Read(p []byte) (int, error) {
 }
 
 func (er *execReader) Close() error {
+	if er.closer != nil {
+		er.closer.Close()
+	}
 	return er.stdout.Close()
 }
 
-func (s *exportServer) initHandler() {
-	mux := http.NewServeMux()
-	for _, vi := range s.Volumes {
-		for path, handler := range s.getHandlerMap(vi) {
-			log.Log.Infof("Handling path %s\n", path)
-			mux.Handle(path, tokenChecker(s.TokenGetter, handler))
+func (s *exportServer) initHandler() {
+	if s.progress == nil {
+		s.progress = newVolumeProgress()
+	}
+	if s.metadata == nil {
+		s.metadata = newArtifactMetadataStore()
+	}
+	if s.precomputed == nil {
+		s.precomputed = newPrecomputedArtifactStore()
+	}
+	if s.s3Status == nil {
+		s.s3Status = newS3UploadStatusStore()
+	}
+	if s.registryStatus == nil {
+		s.registryStatus = newRegistryUploadStatusStore()
+	}
+	if s.clusterStatus == nil {
+		s.clusterStatus = newClusterUploadStatusStore()
+	}
+	if s.hookStatus == nil {
+		s.hookStatus = newHookStatusStore()
+		if s.Hooks != nil {
+			if len(s.Hooks.PreServeCommand) > 0 {
+				s.hookStatus.set(preServeHookName, hookStatus{Phase: hookPending})
+			}
+			if len(s.Hooks.PostServeCommand) > 0 {
+				s.hookStatus.set(postServeHookName, hookStatus{Phase: hookPending})
+			}
+		}
+	}
+	if s.EncryptionKeyFile != "" && s.encryptionKey == nil {
+		key, err := readEncryptionKey(s.EncryptionKeyFile)
+		if err != nil {
+			log.Log.Reason(err).Error("failed to read artifact encryption key")
+			panic(err)
+		}
+		s.encryptionKey = key
+	}
+	if s.ClientCAFile != "" && s.clientCAPool == nil {
+		pool, err := readClientCAPool(s.ClientCAFile)
+		if err != nil {
+			log.Log.Reason(err).Error("failed to read client CA bundle")
+			panic(err)
+		}
+		s.clientCAPool = pool
+	}
+	if s.TotalBandwidthLimit > 0 && s.totalLimiter == nil {
+		s.totalLimiter = rate.NewLimiter(rate.Limit(s.TotalBandwidthLimit), int(s.TotalBandwidthLimit))
+	}
+	if s.PerClientIPRequestLimit > 0 && s.ipLimiter == nil {
+		burst := s.PerClientIPRequestBurst
+		if burst <= 0 {
+			burst = 1
+		}
+		s.ipLimiter = newIPRateLimiter(s.PerClientIPRequestLimit, burst)
+	}
+
+	throttle := func(handler http.Handler) http.Handler {
+		return perConnectionRateLimiter(s.PerConnectionBandwidthLimit, totalRateLimiter(s.totalLimiter, encryptor(s.encryptionKey, handler)))
+	}
+
+	// A failed PreServeCommand keeps every artifact from being registered at all, so a malware
+	// scan or signing step that didn't succeed can't be worked around by a client requesting a
+	// different format or volume.
+	preServeOK := true
+	if s.Hooks != nil && len(s.Hooks.PreServeCommand) > 0 {
+		preServeOK = runHook(preServeHookName, s.Hooks.PreServeCommand, s.hookStatus)
+	}
+
+	mux := http.NewServeMux()
+	if preServeOK {
+		for _, vi := range s.Volumes {
+			for path, handler := range s.getHandlerMap(vi) {
+				log.Log.Infof("Handling path %s\n", path)
+				mux.Handle(path, clientIPRateLimiter(s.ipLimiter, accessLogger(tokenChecker(s.TokenGetter, s.ScopedTokens, vi.Name, concurrencyLimiter(s.MaxConcurrentDownloads, &s.activeDownloads, progressTracker(vi.Name, s.progress, metricsTracker(vi.Name, throttle(handler))))))))
+			}
+		}
+		if s.Ova != nil && s.Ova.URI != "" {
+			log.Log.Infof("Handling path %s\n", s.Ova.URI)
+			mux.Handle(s.Ova.URI, clientIPRateLimiter(s.ipLimiter, accessLogger(tokenChecker(s.TokenGetter, s.ScopedTokens, "", progressTracker(ovaProgressName, s.progress, metricsTracker(ovaProgressName, throttle(s.OvaHandler(*s.Ova))))))))
+		}
+		if s.AllURI != "" {
+			log.Log.Infof("Handling path %s\n", s.AllURI)
+			allConfig := AllConfig{
+				URI:                  s.AllURI,
+				Volumes:              s.Volumes,
+				ManifestFile:         s.ManifestFile,
+				ExpandedManifestFile: s.ExpandedManifestFile,
+			}
+			mux.Handle(s.AllURI, clientIPRateLimiter(s.ipLimiter, accessLogger(tokenChecker(s.TokenGetter, s.ScopedTokens, "", progressTracker(allProgressName, s.progress, metricsTracker(allProgressName, throttle(s.AllHandler(allConfig))))))))
+		}
+		if s.ManifestURI != "" && s.ManifestFile != "" {
+			log.Log.Infof("Handling path %s\n", s.ManifestURI)
+			mux.Handle(s.ManifestURI, clientIPRateLimiter(s.ipLimiter, accessLogger(tokenChecker(s.TokenGetter, s.ScopedTokens, "", progressTracker(manifestProgressName, s.progress, metricsTracker(manifestProgressName, throttle(s.FileHandler(s.ManifestFile))))))))
+		}
+		if s.ExpandedManifestURI != "" && s.ExpandedManifestFile != "" {
+			log.Log.Infof("Handling path %s\n", s.ExpandedManifestURI)
+			mux.Handle(s.ExpandedManifestURI, clientIPRateLimiter(s.ipLimiter, accessLogger(tokenChecker(s.TokenGetter, s.ScopedTokens, "", progressTracker(expandedManifestProgressName, s.progress, metricsTracker(expandedManifestProgressName, throttle(s.FileHandler(s.ExpandedManifestFile))))))))
+		}
+	} else {
+		log.Log.Error("pre-serve hook failed, refusing to serve any export artifact")
+	}
+
+	mux.Handle(internalProgressPath, progressHandler(s.progress))
+	mux.Handle(internalMetadataPath, metadataHandler(s.metadata))
+	mux.Handle(internalS3UploadPath, s3UploadHandler(s.s3Status))
+	mux.Handle(internalRegistryUploadPath, registryUploadHandler(s.registryStatus))
+	mux.Handle(internalClusterUploadPath, clusterUploadHandler(s.clusterStatus))
+	mux.Handle(internalHooksPath, hooksHandler(s.hookStatus))
+	mux.Handle(internalDeadlinePath, deadlineHandler(s))
+	mux.Handle(metricsPath, promhttp.Handler())
+
+	s.handler = mux
+
+	s.metadataOnce.Do(func() {
+		go s.computeArtifactMetadata()
+	})
+
+	if len(s.PrecomputeFormats) > 0 {
+		s.precomputeOnce.Do(func() {
+			go s.precomputeArtifacts()
+		})
+	}
+
+	if s.S3Upload != nil {
+		s.s3UploadOnce.Do(func() {
+			go computeS3Uploads(s.S3Upload, s.Volumes, s.s3Status)
+		})
+	}
+
+	if s.RegistryUpload != nil {
+		s.registryUploadOnce.Do(func() {
+			go computeRegistryUploads(s.RegistryUpload, s.Volumes, s.registryStatus)
+		})
+	}
+
+	if s.ClusterUpload != nil {
+		s.clusterUploadOnce.Do(func() {
+			go computeClusterUploads(s.ClusterUpload, s.Volumes, s.clusterStatus)
+		})
+	}
+}
+
+// computeArtifactMetadata computes, for every artifact this server exposes, its logical size and
+// sha256 checksum, and records the result in s.metadata as each one finishes. This reads every
+// byte of every volume, so for large volumes it can take a while; callers are expected to poll
+// internalMetadataPath rather than wait for it to complete up front.
+func (s *exportServer) computeArtifactMetadata() {
+	for _, vi := range s.Volumes {
+		vi := vi
+		if vi.RawURI != "" {
+			go s.computeRawArtifactMetadata(vi.RawURI, vi.Path)
+		}
+		if vi.RawGzURI != "" {
+			go s.computeGzipArtifactMetadata(vi.RawGzURI, vi.Path)
+		}
+		if vi.ArchiveURI != "" {
+			go s.computeArchiveArtifactMetadata(vi.ArchiveURI, vi.Path, vi.IncludePatterns, vi.ExcludePatterns)
+		}
+		if vi.Qcow2URI != "" {
+			go s.computeQcow2ArtifactMetadata(vi.Qcow2URI, vi.Path)
+		}
+		if vi.Qcow2GzURI != "" {
+			go s.computeQcow2GzArtifactMetadata(vi.Qcow2GzURI, vi.Path)
+		}
+		if vi.VmdkURI != "" {
+			go s.computeVmdkArtifactMetadata(vi.VmdkURI, vi.Path)
+		}
+		if vi.VhdURI != "" {
+			go s.computeVhdArtifactMetadata(vi.VhdURI, vi.Path)
+		}
+		if vi.VhdxURI != "" {
+			go s.computeVhdxArtifactMetadata(vi.VhdxURI, vi.Path)
+		}
+	}
+}
+
+func (s *exportServer) computeRawArtifactMetadata(uri, diskPath string) {
+	f, err := openDiskImage(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error opening %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeGzipArtifactMetadata(uri, diskPath string) {
+	f, err := openDiskImage(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error opening %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer f.Close()
+
+	sparse, err := newSparseReader(f)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error setting up sparse reading of %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	gzipReader := pipeToGzip(sparse, s.GzipCompressionLevel)
+	defer gzipReader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, gzipReader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeArchiveArtifactMetadata(uri, mountPoint string, includePatterns, excludePatterns []string) {
+	tarReader, err := newTarReader(mountPoint, includePatterns, excludePatterns)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error creating tar reader to compute metadata for %s", uri)
+		return
+	}
+	defer tarReader.Close()
+
+	gzipReader := pipeToGzip(tarReader, s.GzipCompressionLevel)
+	defer gzipReader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, gzipReader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading archive to compute metadata for %s", uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeQcow2ArtifactMetadata(uri, diskPath string) {
+	qcow2Reader, err := newQcow2Reader(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error converting %s to qcow2 to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer qcow2Reader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, qcow2Reader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeQcow2GzArtifactMetadata(uri, diskPath string) {
+	qcow2Reader, err := newQcow2Reader(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error converting %s to qcow2 to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer qcow2Reader.Close()
+
+	gzipReader := pipeToGzip(qcow2Reader, s.GzipCompressionLevel)
+	defer gzipReader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, gzipReader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeVmdkArtifactMetadata(uri, diskPath string) {
+	vmdkReader, err := newVmdkReader(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error converting %s to vmdk to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer vmdkReader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, vmdkReader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeVhdArtifactMetadata(uri, diskPath string) {
+	vhdReader, err := newVhdReader(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error converting %s to vhd to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer vhdReader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, vhdReader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+func (s *exportServer) computeVhdxArtifactMetadata(uri, diskPath string) {
+	vhdxReader, err := newVhdxReader(diskPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error converting %s to vhdx to compute metadata for %s", diskPath, uri)
+		return
+	}
+	defer vhdxReader.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, vhdxReader)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error reading %s to compute metadata for %s", diskPath, uri)
+		return
+	}
+	s.metadata.set(uri, artifactMetadata{Size: n, Checksum: hex.EncodeToString(h.Sum(nil))})
+}
+
+// extraDiskImageExtensions lists the file extensions recognized when scanning a filesystem PVC's
+// mount directory for disk images beyond the conventional disk.img, such as those left behind by
+// hotplug or a custom volume layout.
+var extraDiskImageExtensions = []string{".img", ".raw", ".qcow2"}
+
+// discoverDiskImages scans dir for regular files that look like additional disk images, other
+// than "disk.img" itself which is already served through the volume's own Raw/RawGz/... formats.
+// The result is sorted for a stable handler map across restarts. A filesystem PVC is normally
+// assumed to hold exactly one disk.img, but hotplug layouts or manually placed images can leave
+// more than one disk image in the same volume, and those would otherwise be invisible to callers
+// downloading by format.
+func discoverDiskImages(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []string
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == "disk.img" {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		for _, candidate := range extraDiskImageExtensions {
+			if ext == candidate {
+				found = append(found, entry.Name())
+				break
+			}
+		}
+	}
+	sort.Strings(found)
+	return found, nil
+}
+
+// openDiskImage opens diskPath for reading, resolving it to the raw disk image file if it is a
+// directory, matching the layout s.getHandlerMap() assumes for raw and gzip handlers.
+func openDiskImage(diskPath string) (*os.File, error) {
+	fi, err := os.Stat(diskPath)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		diskPath = path.Join(diskPath, "disk.img")
+	}
+	return os.Open(diskPath)
+}
+
+func (s *exportServer) getHandlerMap(vi VolumeInfo) map[string]http.Handler {
+	fi, err := os.Stat(vi.Path)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error statting %s", vi.Path)
+		return nil
+	}
+
+	var result = make(map[string]http.Handler)
+
+	if vi.ArchiveURI != "" {
+		result[vi.ArchiveURI] = etagHandler(s.metadata, vi.ArchiveURI, contentLengthSetter(s.metadata, vi.ArchiveURI, s.ArchiveHandler(vi.Path, s.GzipCompressionLevel, vi.IncludePatterns, vi.ExcludePatterns)))
+		result[vi.ArchiveURI+checksumURISuffix] = checksumHandler(s.metadata, vi.ArchiveURI)
+	}
+
+	if vi.ArchiveZstdURI != "" {
+		result[vi.ArchiveZstdURI] = s.ArchiveZstdHandler(vi.Path, s.ZstdCompressionLevel, vi.IncludePatterns, vi.ExcludePatterns)
+	}
+
+	if vi.DirURI != "" {
+		result[vi.DirURI] = s.DirHandler(vi.DirURI, vi.Path)
+	}
+
+	p := vi.Path
+	if fi.IsDir() {
+		p = path.Join(p, "disk.img")
+	}
+
+	if vi.RawURI != "" {
+		result[vi.RawURI] = etagHandler(s.metadata, vi.RawURI, s.FileHandler(p))
+		result[vi.RawURI+checksumURISuffix] = checksumHandler(s.metadata, vi.RawURI)
+	}
+
+	if vi.RawGzURI != "" {
+		result[vi.RawGzURI] = etagHandler(s.metadata, vi.RawGzURI, contentLengthSetter(s.metadata, vi.RawGzURI, s.precomputed.wrap(vi.RawGzURI, s.GzipHandler(p, s.GzipCompressionLevel))))
+		result[vi.RawGzURI+checksumURISuffix] = checksumHandler(s.metadata, vi.RawGzURI)
+	}
+
+	if vi.ZstdURI != "" {
+		result[vi.ZstdURI] = s.precomputed.wrap(vi.ZstdURI, s.ZstdHandler(p, s.ZstdCompressionLevel))
+	}
+
+	if vi.Qcow2URI != "" {
+		result[vi.Qcow2URI] = etagHandler(s.metadata, vi.Qcow2URI, s.Qcow2Handler(p))
+	}
+
+	if vi.Qcow2GzURI != "" {
+		result[vi.Qcow2GzURI] = etagHandler(s.metadata, vi.Qcow2GzURI, contentLengthSetter(s.metadata, vi.Qcow2GzURI, s.Qcow2GzHandler(p, s.GzipCompressionLevel)))
+	}
+
+	if vi.VmdkURI != "" {
+		result[vi.VmdkURI] = etagHandler(s.metadata, vi.VmdkURI, s.VmdkHandler(p))
+	}
+
+	if vi.VhdURI != "" {
+		result[vi.VhdURI] = etagHandler(s.metadata, vi.VhdURI, s.VhdHandler(p))
+	}
+
+	if vi.VhdxURI != "" {
+		result[vi.VhdxURI] = etagHandler(s.metadata, vi.VhdxURI, s.VhdxHandler(p))
+	}
+
+	if fi.IsDir() && vi.RawURI != "" {
+		extraImages, err := discoverDiskImages(vi.Path)
+		if err != nil {
+			log.Log.Reason(err).Errorf("error scanning %s for additional disk images", vi.Path)
+		}
+		for _, name := range extraImages {
+			imagePath := path.Join(vi.Path, name)
+			uri := path.Join(path.Dir(vi.RawURI), name)
+			result[uri] = etagHandler(s.metadata, uri, s.FileHandler(imagePath))
+			result[uri+checksumURISuffix] = checksumHandler(s.metadata, uri)
+			go s.computeRawArtifactMetadata(uri, imagePath)
+		}
+	}
+
+	return result
+}
+
+// extendDeadline updates s.Deadline and reschedules the running deadline timer to match, so a
+// transfer that's still active close to expiry can be given more time without restarting this
+// process. It is a no-op on the timer until Run has started it.
+func (s *exportServer) extendDeadline(newDeadline time.Time) {
+	s.deadlineMu.Lock()
+	defer s.deadlineMu.Unlock()
+
+	s.Deadline = newDeadline
+	if s.deadlineTimer == nil {
+		return
+	}
+	if !s.deadlineTimer.Stop() {
+		select {
+		case <-s.deadlineTimer.C:
+		default:
+		}
+	}
+	s.deadlineTimer.Reset(time.Until(newDeadline))
+	log.Log.Infof("Deadline extended to %s", newDeadline)
+}
+
+func (s *exportServer) Run() {
+	s.initHandler()
+
+	nbdCmds, err := s.startNBDExports()
+	if err != nil {
+		log.Log.Reason(err).Error("failed to start NBD exports")
+		panic(err)
+	}
+	s.nbdCmds = nbdCmds
+
+	certReloader, err := newCertReloader(s.CertFile, s.KeyFile)
+	if err != nil {
+		log.Log.Reason(err).Error("failed to load TLS certificate")
+		panic(err)
+	}
+	go certReloader.watch()
+
+	tlsConfig := &tls.Config{
+		MinVersion:     s.MinTLSVersion,
+		CipherSuites:   s.CipherSuites,
+		GetCertificate: certReloader.GetCertificate,
+	}
+	if s.clientCAPool != nil {
+		tlsConfig.ClientCAs = s.clientCAPool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	srv := &http.Server{
+		Addr:      s.ListenAddr,
+		Handler:   s.handler,
+		TLSConfig: tlsConfig,
+	}
+	// Configuring CipherSuites above disables Go's own automatic HTTP/2 setup unless the
+	// configured ciphers happen to satisfy the HTTP/2 spec's requirements, so configure it
+	// explicitly to keep HTTP/2 available for clients multiplexing several volume downloads over
+	// one connection.
+	if err := http2.ConfigureServer(srv, &http2.Server{}); err != nil {
+		log.Log.Reason(err).Error("failed to configure HTTP/2")
+		panic(err)
+	}
+
+	ch := make(chan error)
+
+	go func() {
+		// certFile and keyFile are left empty since tlsConfig.GetCertificate already supplies the
+		// certificate, and reloads it as certReloader picks up renewals.
+		err := srv.ListenAndServeTLS("", "")
+		ch <- err
+	}()
+
+	// The controller sends SIGTERM to this container when the VirtualMachineExport is deleted,
+	// giving it TerminationGracePeriodSeconds to drain before kubelet sends SIGKILL.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+
+	deadlineTimer := time.NewTimer(time.Hour)
+	deadlineTimer.Stop()
+	if !s.Deadline.IsZero() {
+		log.Log.Infof("Deadline set to %s", s.Deadline)
+		deadlineTimer.Reset(time.Until(s.Deadline))
+	}
+	s.deadlineMu.Lock()
+	s.deadlineTimer = deadlineTimer
+	s.deadlineMu.Unlock()
+
+	select {
+	case err := <-ch:
+		panic(err)
+	case <-deadlineTimer.C:
+		log.Log.Info("Deadline exceeded, draining active downloads")
+		s.drain(srv)
+	case sig := <-sigCh:
+		log.Log.Infof("Received %s, draining active downloads", sig)
+		s.drain(srv)
+	}
+}
+
+// drain stops srv from accepting new connections and gives in-flight downloads up to
+// s.ShutdownGracePeriod to finish, then forcibly closes any connections still open. Once no more
+// downloads can start, it runs ExportServerConfig.Hooks' PostServeCommand, if set: this exporter
+// pod is torn down once its VirtualMachineExport is deleted or its deadline is reached, so there
+// is no later "last download" event to hang the hook off of instead.
+func (s *exportServer) drain(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownGracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Log.Reason(err).Warning("shutdown grace period exceeded, closing remaining connections")
+		srv.Close()
+	}
+	if s.Hooks != nil && len(s.Hooks.PostServeCommand) > 0 {
+		runHook(postServeHookName, s.Hooks.PostServeCommand, s.hookStatus)
+	}
+	for _, cmd := range s.nbdCmds {
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+	}
+}
+
+func (s *exportServer) AddFlags() {
+	flag.CommandLine.AddGoFlag(goflag.CommandLine.Lookup("v"))
+}
+
+func NewExportServer(config ExportServerConfig) service.Service {
+	es := &exportServer{ExportServerConfig: config}
+
+	if es.ArchiveHandler == nil {
+		es.ArchiveHandler = archiveHandler
+	}
+
+	if es.ArchiveZstdHandler == nil {
+		es.ArchiveZstdHandler = archiveZstdHandler
+	}
+
+	if es.DirHandler == nil {
+		es.DirHandler = dirHandler
+	}
+
+	if es.FileHandler == nil {
+		es.FileHandler = fileHandler
+	}
+
+	if es.GzipHandler == nil {
+		es.GzipHandler = gzipHandler
+	}
+
+	if es.ZstdHandler == nil {
+		es.ZstdHandler = zstdHandler
+	}
+
+	if es.Qcow2Handler == nil {
+		es.Qcow2Handler = qcow2Handler
+	}
+
+	if es.Qcow2GzHandler == nil {
+		es.Qcow2GzHandler = qcow2GzHandler
+	}
+
+	if es.VmdkHandler == nil {
+		es.VmdkHandler = vmdkHandler
+	}
+
+	if es.VhdHandler == nil {
+		es.VhdHandler = vhdHandler
+	}
+
+	if es.VhdxHandler == nil {
+		es.VhdxHandler = vhdxHandler
+	}
+
+	if es.OvaHandler == nil {
+		es.OvaHandler = ovaHandler
+	}
+
+	if es.AllHandler == nil {
+		es.AllHandler = allHandler
+	}
+
+	if es.ZstdCompressionLevel == 0 {
+		es.ZstdCompressionLevel = defaultZstdCompressionLevel
+	}
+
+	if es.GzipCompressionLevel == 0 {
+		es.GzipCompressionLevel = defaultGzipCompressionLevel
+	}
+
+	if es.ShutdownGracePeriod == 0 {
+		es.ShutdownGracePeriod = defaultShutdownGracePeriod
+	}
+
+	if es.MinTLSVersion == 0 {
+		es.MinTLSVersion = tls.VersionTLS12
+	}
+
+	if es.TokenGetter == nil {
+		es.TokenGetter = func() ([]string, error) {
+			return getTokens(append([]string{es.TokenFile}, es.AdditionalTokenFiles...))
+		}
+	}
+
+	return es
+}
+
+// newTarReader streams mountPoint as a tar archive via the tar CLI. If includePatterns or
+// excludePatterns is non-empty, only the members selectTarMembers picks are archived; otherwise
+// this behaves exactly as archive exports always have, tarring up the whole directory.
+func newTarReader(mountPoint string, includePatterns, excludePatterns []string) (io.ReadCloser, error) {
+	if len(includePatterns) == 0 && len(excludePatterns) == 0 {
+		return newPlainTarReader(mountPoint)
+	}
+	return newFilteredTarReader(mountPoint, includePatterns, excludePatterns)
+}
+
+func newPlainTarReader(mountPoint string) (io.ReadCloser, error) {
+	cmd := exec.Command("/usr/bin/tar", "Scv", ".")
+	cmd.Dir = mountPoint
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: ioutil.NopCloser(&stderr)}, nil
+}
+
+// newFilteredTarReader streams a tar archive of only the members of mountPoint that
+// selectTarMembers selects, so a caller can export a subset of a large filesystem volume instead
+// of everything it contains. The member list is fed to the tar CLI on stdin rather than as
+// command-line arguments, since a volume can hold arbitrarily many matching files.
+func newFilteredTarReader(mountPoint string, includePatterns, excludePatterns []string) (io.ReadCloser, error) {
+	members, err := selectTarMembers(mountPoint, includePatterns, excludePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("/usr/bin/tar", "--sparse", "-c", "-v", "--no-recursion", "--files-from=-")
+	cmd.Dir = mountPoint
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer stdin.Close()
+		for _, member := range members {
+			fmt.Fprintln(stdin, member)
+		}
+	}()
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: ioutil.NopCloser(&stderr)}, nil
+}
+
+// matchesAny reports whether name matches any of patterns, using the same glob syntax as
+// filepath.Match.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// selectTarMembers walks mountPoint and returns, relative to it, every file and directory that
+// should be included in a filtered archive export: the filesystem's own lost+found directory is
+// always skipped, since it is filesystem debris rather than data the user exported anything to,
+// then includePatterns and excludePatterns are applied against each entry's path relative to
+// mountPoint. If includePatterns is non-empty, an entry must match at least one of them to be
+// kept; excludePatterns, checked afterwards, drops an entry (and, for a directory, everything
+// beneath it) if it matches any of them.
+func selectTarMembers(mountPoint string, includePatterns, excludePatterns []string) ([]string, error) {
+	var members []string
+	err := filepath.WalkDir(mountPoint, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(mountPoint, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == "lost+found" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if matchesAny(excludePatterns, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if len(includePatterns) > 0 && !matchesAny(includePatterns, rel) {
+			return nil
+		}
+		members = append(members, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// newZstdReader streams diskPath, compressed with zstd at the given level, by running it through
+// the zstd CLI. This is the same subprocess-streaming approach newTarReader uses for archive
+// downloads, since Go has neither a stdlib nor a vendored zstd implementation.
+func newZstdReader(diskPath string, level int) (io.ReadCloser, error) {
+	cmd := exec.Command("/usr/bin/zstd", fmt.Sprintf("-%d", level), "-c", diskPath)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: stderr}, nil
+}
+
+// newTarZstdReader streams mountPoint tarred up and compressed with zstd at the given level, by
+// piping newTarReader's output into the zstd CLI, the same way archiveHandler pipes newTarReader
+// into pipeToGzip for the tar.gz format.
+func newTarZstdReader(mountPoint string, level int, includePatterns, excludePatterns []string) (io.ReadCloser, error) {
+	tarReader, err := newTarReader(mountPoint, includePatterns, excludePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("/usr/bin/zstd", fmt.Sprintf("-%d", level), "-c")
+	cmd.Stdin = tarReader
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		tarReader.Close()
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		tarReader.Close()
+		return nil, err
+	}
+
+	if err = cmd.Start(); err != nil {
+		tarReader.Close()
+		return nil, err
+	}
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: stderr, closer: tarReader}, nil
+}
+
+// nbdkitExport wraps the nbdkit subprocess backing an NBD export, so an execReader's closer can
+// tear it down, and its unix socket's parent directory, so that gets cleaned up too, alongside
+// the qemu-img subprocess the execReader already manages.
+type nbdkitExport struct {
+	cmd      *exec.Cmd
+	sockDir  string
+	sockPath string
+}
+
+func (n *nbdkitExport) Close() error {
+	var err error
+	if n.cmd.Process != nil {
+		err = n.cmd.Process.Kill()
+		_ = n.cmd.Wait()
+	}
+	os.RemoveAll(n.sockDir)
+	return err
+}
+
+// waitForSocket polls for a unix socket to appear at path, up to timeout, so callers don't race
+// nbdkit's own startup before trying to connect to the NBD export it serves.
+func waitForSocket(path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return nil
 		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for nbdkit socket %s", path)
+		}
+		time.Sleep(nbdkitSocketPollInterval)
 	}
-
-	s.handler = mux
 }
 
-func (s *exportServer) getHandlerMap(vi VolumeInfo) map[string]http.Handler {
-	fi, err := os.Stat(vi.Path)
+// newNbdkitExport starts nbdkit serving diskPath read-only over NBD on a fresh unix socket, and
+// waits for that socket to come up. --exit-with-parent has nbdkit shut itself down if this
+// process dies before nbdkitExport.Close does.
+func newNbdkitExport(diskPath string) (*nbdkitExport, error) {
+	sockDir, err := ioutil.TempDir("", "nbdkit")
 	if err != nil {
-		log.Log.Reason(err).Errorf("error statting %s", vi.Path)
-		return nil
+		return nil, err
 	}
+	sockPath := path.Join(sockDir, "nbd.sock")
 
-	var result = make(map[string]http.Handler)
+	cmd := exec.Command("nbdkit", "--exit-with-parent", "--foreground", "--readonly",
+		"--unix", sockPath, "file", "file="+diskPath)
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(sockDir)
+		return nil, err
+	}
 
-	if vi.ArchiveURI != "" {
-		result[vi.ArchiveURI] = s.ArchiveHandler(vi.Path)
+	if err := waitForSocket(sockPath, nbdkitStartupTimeout); err != nil {
+		cmd.Process.Kill()
+		os.RemoveAll(sockDir)
+		return nil, err
 	}
 
-	if vi.DirURI != "" {
-		result[vi.DirURI] = s.DirHandler(vi.DirURI, vi.Path)
+	return &nbdkitExport{cmd: cmd, sockDir: sockDir, sockPath: sockPath}, nil
+}
+
+// nbdPSKIdentity is the fixed identity nbdkit's --tls-psk-file expects each entry to be keyed
+// under. nbdkit only uses it for logging; what actually gates access is knowing the key.
+const nbdPSKIdentity = "kubevirt"
+
+// writeNBDPSKFile writes tokens as a nbdkit TLS-PSK file, so nbdkit only accepts an NBD
+// connection from a client presenting one of the currently valid download tokens as its PSK key,
+// the same tokens the HTTPS download endpoints accept.
+func writeNBDPSKFile(tokens []string) (string, error) {
+	dir, err := ioutil.TempDir("", "nbdkit-psk")
+	if err != nil {
+		return "", err
 	}
+	var buf bytes.Buffer
+	for _, token := range tokens {
+		fmt.Fprintf(&buf, "%s:%s\n", nbdPSKIdentity, hex.EncodeToString([]byte(token)))
+	}
+	pskFile := path.Join(dir, "keys.psk")
+	if err := ioutil.WriteFile(pskFile, buf.Bytes(), 0600); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return pskFile, nil
+}
 
-	p := vi.Path
-	if fi.IsDir() {
-		p = path.Join(p, "disk.img")
+// startNBDExports starts one nbdkit process per volume in s.Volumes, each serving that volume's
+// raw disk over NBD on its own TCP port starting from NBDListenAddr's port, protected by TLS with
+// the currently valid download tokens as pre-shared keys. It returns the started processes so Run
+// can stop them alongside the HTTP server when draining, or nil if NBDListenAddr is not set.
+//
+// Serving every volume from a single nbdkit process would need a scripting plugin to pick the
+// right disk per connection; one process per volume keeps this consistent with how the rest of
+// this file shells out to a subprocess per operation, at the cost of one port per volume.
+func (s *exportServer) startNBDExports() ([]*exec.Cmd, error) {
+	if s.NBDListenAddr == "" {
+		return nil, nil
 	}
 
-	if vi.RawURI != "" {
-		result[vi.RawURI] = s.FileHandler(p)
+	host, portStr, err := net.SplitHostPort(s.NBDListenAddr)
+	if err != nil {
+		return nil, err
+	}
+	basePort, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, err
 	}
 
-	if vi.RawGzURI != "" {
-		result[vi.RawGzURI] = s.GzipHandler(p)
+	tokens, err := s.TokenGetter()
+	if err != nil {
+		return nil, err
+	}
+	pskFile, err := writeNBDPSKFile(tokens)
+	if err != nil {
+		return nil, err
 	}
 
-	return result
-}
+	var cmds []*exec.Cmd
+	for i, vi := range s.Volumes {
+		fi, err := os.Stat(vi.Path)
+		if err != nil {
+			log.Log.Reason(err).Errorf("error statting %s, not exposing it over NBD", vi.Path)
+			continue
+		}
+		diskPath := vi.Path
+		if fi.IsDir() {
+			diskPath = path.Join(diskPath, "disk.img")
+		}
 
-func (s *exportServer) Run() {
-	s.initHandler()
+		port := basePort + i
+		cmd := exec.Command("nbdkit", "--exit-with-parent", "--foreground", "--readonly",
+			"-i", host, "-p", strconv.Itoa(port),
+			"--tls=require", "--tls-psk-file="+pskFile,
+			"file", "file="+diskPath)
+		if err := cmd.Start(); err != nil {
+			return cmds, err
+		}
+		log.Log.Infof("Exposing volume %s over NBD on %s:%d", vi.Name, host, port)
+		cmds = append(cmds, cmd)
+	}
+	return cmds, nil
+}
 
-	srv := &http.Server{
-		Addr:    s.ListenAddr,
-		Handler: s.handler,
+// newQcow2Reader streams diskPath, a raw disk image, converted to qcow2 format. diskPath is
+// served over NBD by nbdkit rather than opened directly, so qemu-img convert reads it through
+// that NBD export and streams the qcow2 result straight to stdout as it goes, the same
+// subprocess-streaming approach newTarReader uses for archive downloads. This means a qcow2
+// download starts producing output immediately, and neither nbdkit nor qemu-img ever need a
+// temporary file or an additional PVC to hold the converted image.
+func newQcow2Reader(diskPath string) (io.ReadCloser, error) {
+	export, err := newNbdkitExport(diskPath)
+	if err != nil {
+		return nil, err
 	}
 
-	ch := make(chan error)
+	cmd := exec.Command("/usr/bin/qemu-img", "convert", "-O", "qcow2",
+		"nbd+unix:///?socket="+export.sockPath, "/dev/stdout")
 
-	go func() {
-		err := srv.ListenAndServeTLS(s.CertFile, s.KeyFile)
-		ch <- err
-	}()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		export.Close()
+		return nil, err
+	}
 
-	if !s.Deadline.IsZero() {
-		log.Log.Infof("Deadline set to %s", s.Deadline)
-		select {
-		case err := <-ch:
-			panic(err)
-		case <-time.After(time.Until(s.Deadline)):
-			log.Log.Info("Deadline exceeded, shutting down")
-			srv.Shutdown(context.TODO())
-		}
-	} else {
-		err := <-ch
-		panic(err)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		export.Close()
+		return nil, err
 	}
-}
 
-func (s *exportServer) AddFlags() {
-	flag.CommandLine.AddGoFlag(goflag.CommandLine.Lookup("v"))
+	if err = cmd.Start(); err != nil {
+		export.Close()
+		return nil, err
+	}
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: stderr, closer: export}, nil
 }
 
-func NewExportServer(config ExportServerConfig) service.Service {
-	es := &exportServer{ExportServerConfig: config}
+// newVmdkReader streams diskPath, a raw disk image, converted to streamOptimized VMDK format, by
+// running it through qemu-img convert, the same subprocess-streaming approach newQcow2Reader uses.
+// The streamOptimized subformat is what vSphere expects when importing a standalone VMDK.
+func newVmdkReader(diskPath string) (io.ReadCloser, error) {
+	cmd := exec.Command("/usr/bin/qemu-img", "convert", "-O", "vmdk", "-o", "subformat=streamOptimized", diskPath, "/dev/stdout")
 
-	if es.ArchiveHandler == nil {
-		es.ArchiveHandler = archiveHandler
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
 	}
 
-	if es.DirHandler == nil {
-		es.DirHandler = dirHandler
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
 	}
 
-	if es.FileHandler == nil {
-		es.FileHandler = fileHandler
+	if err = cmd.Start(); err != nil {
+		return nil, err
 	}
 
-	if es.GzipHandler == nil {
-		es.GzipHandler = gzipHandler
+	return &execReader{cmd: cmd, stdout: stdout, stderr: stderr}, nil
+}
+
+// newVhdReader streams diskPath, a raw disk image, converted to fixed-size VHD format (qemu-img
+// calls this format "vpc"), using the same subprocess-streaming approach as newQcow2Reader.
+func newVhdReader(diskPath string) (io.ReadCloser, error) {
+	cmd := exec.Command("/usr/bin/qemu-img", "convert", "-O", "vpc", diskPath, "/dev/stdout")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
 	}
 
-	if es.TokenGetter == nil {
-		es.TokenGetter = func() (string, error) {
-			return getToken(es.TokenFile)
-		}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
 	}
 
-	return es
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: stderr}, nil
 }
 
-func newTarReader(mountPoint string) (io.ReadCloser, error) {
-	cmd := exec.Command("/usr/bin/tar", "Scv", ".")
-	cmd.Dir = mountPoint
+// newVhdxReader streams diskPath, a raw disk image, converted to VHDX format, using the same
+// subprocess-streaming approach as newQcow2Reader.
+func newVhdxReader(diskPath string) (io.ReadCloser, error) {
+	cmd := exec.Command("/usr/bin/qemu-img", "convert", "-O", "vhdx", diskPath, "/dev/stdout")
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, err
 	}
 
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
 
 	if err = cmd.Start(); err != nil {
 		return nil, err
 	}
 
-	return &execReader{cmd: cmd, stdout: stdout, stderr: ioutil.NopCloser(&stderr)}, nil
+	return &execReader{cmd: cmd, stdout: stdout, stderr: stderr}, nil
 }
 
-func pipeToGzip(reader io.ReadCloser) io.ReadCloser {
+func pipeToGzip(reader io.Reader, level int) io.ReadCloser {
 	pr, pw := io.Pipe()
-	zw := gzip.NewWriter(pw)
+	zw, err := gzip.NewWriterLevel(pw, level)
+	if err != nil {
+		log.Log.Reason(err).Errorf("invalid gzip compression level %d, falling back to the default", level)
+		zw = gzip.NewWriter(pw)
+	}
 
 	go func() {
 		n, err := io.Copy(zw, reader)
@@ -264,39 +1958,79 @@ func getTokenHeader(r *http.Request) (token string) {
 	return
 }
 
-func tokenChecker(tokenGetter TokenGetterFunc, nextHandler http.Handler) http.Handler {
+// tokenChecker authenticates a request against tokenGetter's unrestricted tokens and, if volume
+// is non-empty, also against scopedTokens' per-volume tokens. volume identifies which volume's
+// artifact the wrapped handler serves, or "" for the OVA, manifest, and "all volumes" bundle
+// endpoints, which no scoped token can authenticate.
+func tokenChecker(tokenGetter TokenGetterFunc, scopedTokens []*TokenScope, volume string, nextHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token, err := tokenGetter()
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			nextHandler.ServeHTTP(w, r)
+			return
+		}
+		tokens, err := tokenGetter()
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 		for _, tok := range []string{getTokenQueryParam(r), getTokenHeader(r)} {
-			if tok == token {
-				nextHandler.ServeHTTP(w, r)
-				return
+			for _, validToken := range tokens {
+				if tok == validToken {
+					nextHandler.ServeHTTP(w, r)
+					return
+				}
+			}
+			for _, scope := range scopedTokens {
+				if tok == "" || !scope.allowsVolume(volume) {
+					continue
+				}
+				scopeToken, err := getToken(scope.TokenFile)
+				if err != nil {
+					continue
+				}
+				if tok == scopeToken {
+					if !scope.consumeRead() {
+						w.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+					nextHandler.ServeHTTP(w, r)
+					return
+				}
 			}
 		}
 		w.WriteHeader(http.StatusUnauthorized)
 	})
 }
 
-func archiveHandler(mountPoint string) http.Handler {
+func archiveHandler(mountPoint string, level int, includePatterns, excludePatterns []string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		if req.Method != http.MethodGet {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		tarReader, err := newTarReader(mountPoint)
+		var lastModified time.Time
+		if fi, err := os.Stat(mountPoint); err == nil {
+			lastModified = fi.ModTime()
+			w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+		w.Header().Set("Accept-Ranges", "bytes")
+
+		tarReader, err := newTarReader(mountPoint, includePatterns, excludePatterns)
 		if err != nil {
 			log.Log.Reason(err).Error("error creating tar reader")
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 		defer tarReader.Close()
-		gzipReader := pipeToGzip(tarReader)
+		gzipReader := pipeToGzip(tarReader, level)
 		defer gzipReader.Close()
-		n, err := io.Copy(w, gzipReader)
+
+		var n int64
+		if rr, ok := parseRangeRequest(req, lastModified); ok {
+			n, err = serveRange(w, gzipReader, rr)
+		} else {
+			n, err = io.Copy(w, gzipReader)
+		}
 		if err != nil {
 			log.Log.Reason(err).Error("error writing response body")
 		}
@@ -304,7 +2038,7 @@ func archiveHandler(mountPoint string) http.Handler {
 	})
 }
 
-func gzipHandler(filePath string) http.Handler {
+func gzipHandler(filePath string, level int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		if req.Method != http.MethodGet {
 			w.WriteHeader(http.StatusBadRequest)
@@ -317,7 +2051,99 @@ func gzipHandler(filePath string) http.Handler {
 			return
 		}
 		defer f.Close()
-		gzipReader := pipeToGzip(f)
+		sparse, err := newSparseReader(f)
+		if err != nil {
+			log.Log.Reason(err).Errorf("error setting up sparse reading of %s", filePath)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		gzipReader := pipeToGzip(sparse, level)
+		defer gzipReader.Close()
+		n, err := io.Copy(w, gzipReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
+func zstdHandler(filePath string, level int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		zstdReader, err := newZstdReader(filePath, level)
+		if err != nil {
+			log.Log.Reason(err).Error("error compressing with zstd")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer zstdReader.Close()
+		n, err := io.Copy(w, zstdReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
+func archiveZstdHandler(mountPoint string, level int, includePatterns, excludePatterns []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		zstdReader, err := newTarZstdReader(mountPoint, level, includePatterns, excludePatterns)
+		if err != nil {
+			log.Log.Reason(err).Error("error creating tar+zstd reader")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer zstdReader.Close()
+		n, err := io.Copy(w, zstdReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
+func qcow2Handler(diskPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		qcow2Reader, err := newQcow2Reader(diskPath)
+		if err != nil {
+			log.Log.Reason(err).Error("error converting to qcow2")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer qcow2Reader.Close()
+		n, err := io.Copy(w, qcow2Reader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
+func qcow2GzHandler(diskPath string, level int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		qcow2Reader, err := newQcow2Reader(diskPath)
+		if err != nil {
+			log.Log.Reason(err).Error("error converting to qcow2")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer qcow2Reader.Close()
+		gzipReader := pipeToGzip(qcow2Reader, level)
 		defer gzipReader.Close()
 		n, err := io.Copy(w, gzipReader)
 		if err != nil {
@@ -327,6 +2153,69 @@ func gzipHandler(filePath string) http.Handler {
 	})
 }
 
+func vmdkHandler(diskPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		vmdkReader, err := newVmdkReader(diskPath)
+		if err != nil {
+			log.Log.Reason(err).Error("error converting to vmdk")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer vmdkReader.Close()
+		n, err := io.Copy(w, vmdkReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
+func vhdHandler(diskPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		vhdReader, err := newVhdReader(diskPath)
+		if err != nil {
+			log.Log.Reason(err).Error("error converting to vhd")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer vhdReader.Close()
+		n, err := io.Copy(w, vhdReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
+func vhdxHandler(diskPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		vhdxReader, err := newVhdxReader(diskPath)
+		if err != nil {
+			log.Log.Reason(err).Error("error converting to vhdx")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer vhdxReader.Close()
+		n, err := io.Copy(w, vhdxReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
+
 func dirHandler(uri, mountPoint string) http.Handler {
 	return http.StripPrefix(uri, http.FileServer(http.Dir(mountPoint)))
 }
@@ -339,7 +2228,15 @@ func fileHandler(file string) http.Handler {
 			return
 		}
 		defer f.Close()
-		http.ServeContent(w, r, "disk.img", time.Time{}, f)
+		// A real modtime, rather than the zero time, is needed for http.ServeContent to set
+		// Last-Modified and honor a resuming client's If-Range against it; without it,
+		// ServeContent can't tell whether the disk changed since the client's last attempt and
+		// always falls back to serving the full content instead of the requested range.
+		modTime := time.Time{}
+		if fi, err := f.Stat(); err == nil {
+			modTime = fi.ModTime()
+		}
+		http.ServeContent(w, r, "disk.img", modTime, f)
 	})
 }
 
@@ -351,3 +2248,24 @@ func getToken(tokenFile string) (string, error) {
 
 	return string(content), nil
 }
+
+// getTokens reads each file in tokenFiles and returns the tokens that could be read
+// successfully. A file that does not exist is ignored, since AdditionalTokenFiles
+// stops being mounted once a rotated-out token secret is removed.
+func getTokens(tokenFiles []string) ([]string, error) {
+	var tokens []string
+	for i, tokenFile := range tokenFiles {
+		token, err := getToken(tokenFile)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
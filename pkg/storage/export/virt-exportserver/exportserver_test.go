@@ -20,12 +20,30 @@
 package virtexportserver
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cdiuploadv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/upload/v1beta1"
 )
 
 func successHandler(w http.ResponseWriter, req *http.Request) {
@@ -34,7 +52,10 @@ func successHandler(w http.ResponseWriter, req *http.Request) {
 
 func newTestServer(token string) *exportServer {
 	config := ExportServerConfig{
-		ArchiveHandler: func(string) http.Handler {
+		ArchiveHandler: func(string, int, []string, []string) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		ArchiveZstdHandler: func(string, int, []string, []string) http.Handler {
 			return http.HandlerFunc(successHandler)
 		},
 		DirHandler: func(string, string) http.Handler {
@@ -43,11 +64,35 @@ func newTestServer(token string) *exportServer {
 		FileHandler: func(string) http.Handler {
 			return http.HandlerFunc(successHandler)
 		},
-		GzipHandler: func(string) http.Handler {
+		GzipHandler: func(string, int) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		ZstdHandler: func(string, int) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		Qcow2Handler: func(string) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		Qcow2GzHandler: func(string, int) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		VmdkHandler: func(string) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		VhdHandler: func(string) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		VhdxHandler: func(string) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		OvaHandler: func(OvaConfig) http.Handler {
+			return http.HandlerFunc(successHandler)
+		},
+		AllHandler: func(AllConfig) http.Handler {
 			return http.HandlerFunc(successHandler)
 		},
-		TokenGetter: func() (string, error) {
-			return token, nil
+		TokenGetter: func() ([]string, error) {
+			return []string{token}, nil
 		},
 	}
 	s := NewExportServer(config)
@@ -92,8 +137,539 @@ var _ = Describe("exportserver", func() {
 			VolumeInfo{Path: "/tmp", RawGzURI: "/volume/v1/disk.img.gz"},
 			"/volume/v1/disk.img.gz",
 		),
+		Entry("zstd URI",
+			VolumeInfo{Path: "/tmp", ZstdURI: "/volume/v1/disk.img.zst"},
+			"/volume/v1/disk.img.zst",
+		),
+		Entry("archive zstd URI",
+			VolumeInfo{Path: "/tmp", ArchiveZstdURI: "/volume/v1/disk.tar.zst"},
+			"/volume/v1/disk.tar.zst",
+		),
+		Entry("qcow2 URI",
+			VolumeInfo{Path: "/tmp", Qcow2URI: "/volume/v1/disk.qcow2"},
+			"/volume/v1/disk.qcow2",
+		),
+		Entry("qcow2 gz URI",
+			VolumeInfo{Path: "/tmp", Qcow2GzURI: "/volume/v1/disk.qcow2.gz"},
+			"/volume/v1/disk.qcow2.gz",
+		),
+		Entry("vmdk URI",
+			VolumeInfo{Path: "/tmp", VmdkURI: "/volume/v1/disk.vmdk"},
+			"/volume/v1/disk.vmdk",
+		),
+		Entry("vhd URI",
+			VolumeInfo{Path: "/tmp", VhdURI: "/volume/v1/disk.vhd"},
+			"/volume/v1/disk.vhd",
+		),
+		Entry("vhdx URI",
+			VolumeInfo{Path: "/tmp", VhdxURI: "/volume/v1/disk.vhdx"},
+			"/volume/v1/disk.vhdx",
+		),
 	)
 
+	It("should encrypt served artifacts when an encryption key is configured", func() {
+		dir, err := ioutil.TempDir("", "exportserver-encryption")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		keyFile := filepath.Join(dir, "key")
+		key := make([]byte, 32)
+		for i := range key {
+			key[i] = byte(i)
+		}
+		Expect(ioutil.WriteFile(keyFile, key, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.EncryptionKeyFile = keyFile
+		es.Volumes = []VolumeInfo{{Path: "/tmp", RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		client := http.Client{}
+		req, err := http.NewRequest("GET", httpServer.URL+"/volume/v1/disk.img", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("x-kubevirt-export-token", token)
+		res, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		defer res.Body.Close()
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).ToNot(Equal([]byte("OK")))
+
+		Expect(len(out)).To(BeNumerically(">=", aes.BlockSize))
+		iv := out[:aes.BlockSize]
+		ciphertext := out[aes.BlockSize:]
+		block, err := aes.NewCipher(key)
+		Expect(err).ToNot(HaveOccurred())
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCTR(block, iv).XORKeyStream(plaintext, ciphertext)
+		Expect(string(plaintext)).To(Equal("OK"))
+	})
+
+	It("should still honor a Range request when an encryption key is configured", func() {
+		dir, err := ioutil.TempDir("", "exportserver-encryption-range")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		keyFile := filepath.Join(dir, "key")
+		key := make([]byte, 32)
+		for i := range key {
+			key[i] = byte(i)
+		}
+		Expect(ioutil.WriteFile(keyFile, key, 0644)).To(Succeed())
+
+		Expect(ioutil.WriteFile(filepath.Join(dir, "disk.img"), []byte("hello world"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.EncryptionKeyFile = keyFile
+		es.ArchiveHandler = archiveHandler
+		es.Volumes = []VolumeInfo{{Path: dir, ArchiveURI: "/volume/v1/disk.tar.gz"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		client := http.Client{}
+		req, err := http.NewRequest("GET", httpServer.URL+"/volume/v1/disk.tar.gz", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("x-kubevirt-export-token", token)
+		req.Header.Set("Range", "bytes=4-")
+		res, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+
+		// Before the fix, the IV was written to the raw ResponseWriter before archiveHandler got a
+		// chance to call serveRange's WriteHeader(206) and set Content-Range, which locked in a 200
+		// with no Content-Range regardless of the Range request.
+		Expect(res.StatusCode).To(Equal(http.StatusPartialContent))
+		Expect(res.Header.Get("Content-Range")).ToNot(BeEmpty())
+
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(out)).To(BeNumerically(">=", aes.BlockSize))
+		iv := out[:aes.BlockSize]
+		ciphertext := out[aes.BlockSize:]
+		block, err := aes.NewCipher(key)
+		Expect(err).ToNot(HaveOccurred())
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCTR(block, iv).XORKeyStream(plaintext, ciphertext)
+
+		// Fetch the unencrypted plaintext directly from a bare archiveHandler, rather than a second
+		// request through the encrypted server, so this only exercises the ordering fix under test.
+		plainServer := httptest.NewServer(archiveHandler(dir, gzip.DefaultCompression, nil, nil))
+		defer plainServer.Close()
+		fullRes, err := http.Get(plainServer.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer fullRes.Body.Close()
+		fullPlaintext, err := ioutil.ReadAll(fullRes.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(plaintext).To(Equal(fullPlaintext[4:]))
+	})
+
+	It("should report size and checksum for a volume on the internal metadata endpoint", func() {
+		dir, err := ioutil.TempDir("", "exportserver-metadata")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("this is a test disk image")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		sum := sha256.Sum256(diskContents)
+		expectedChecksum := hex.EncodeToString(sum[:])
+
+		Eventually(func(g Gomega) {
+			res, err := http.Get(httpServer.URL + internalMetadataPath)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+			g.Expect(res.StatusCode).To(Equal(http.StatusOK))
+			var metadata map[string]artifactMetadata
+			g.Expect(json.NewDecoder(res.Body).Decode(&metadata)).To(Succeed())
+			g.Expect(metadata).To(HaveKeyWithValue("/volume/v1/disk.img", artifactMetadata{
+				Size:     int64(len(diskContents)),
+				Checksum: expectedChecksum,
+			}))
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+	})
+
+	It("should serve a volume's checksum at its download URI plus .sha256", func() {
+		dir, err := ioutil.TempDir("", "exportserver-checksum")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("this is a test disk image")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		sum := sha256.Sum256(diskContents)
+		expectedChecksum := hex.EncodeToString(sum[:])
+
+		Eventually(func(g Gomega) {
+			res, err := http.Get(httpServer.URL + "/volume/v1/disk.img.sha256?" + authHeader + "=" + token)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+			g.Expect(res.StatusCode).To(Equal(http.StatusOK))
+			out, err := ioutil.ReadAll(res.Body)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(strings.TrimSpace(string(out))).To(Equal(expectedChecksum))
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+	})
+
+	It("should reject a checksum request without a valid token", func() {
+		dir, err := ioutil.TempDir("", "exportserver-checksum-auth")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("this is a test disk image"), 0644)).To(Succeed())
+
+		es := newTestServer("foo")
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img.sha256")
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should upload a volume's raw artifact to S3 and report its status on the internal s3upload endpoint", func() {
+		dir, err := ioutil.TempDir("", "exportserver-s3upload")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("this is a test disk image")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		accessKeyIDFile := filepath.Join(dir, "accessKeyId")
+		Expect(ioutil.WriteFile(accessKeyIDFile, []byte("test-access-key"), 0644)).To(Succeed())
+		secretAccessKeyFile := filepath.Join(dir, "secretAccessKey")
+		Expect(ioutil.WriteFile(secretAccessKeyFile, []byte("test-secret-key"), 0644)).To(Succeed())
+
+		var uploadedBody []byte
+		s3Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPut))
+			Expect(req.Header.Get("Authorization")).To(ContainSubstring("AWS4-HMAC-SHA256"))
+			var readErr error
+			uploadedBody, readErr = ioutil.ReadAll(req.Body)
+			Expect(readErr).ToNot(HaveOccurred())
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer s3Server.Close()
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.S3Upload = &S3UploadConfig{
+			Endpoint:            s3Server.URL,
+			Bucket:              "test-bucket",
+			AccessKeyIDFile:     accessKeyIDFile,
+			SecretAccessKeyFile: secretAccessKeyFile,
+		}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		Eventually(func(g Gomega) {
+			res, err := http.Get(httpServer.URL + internalS3UploadPath)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+			g.Expect(res.StatusCode).To(Equal(http.StatusOK))
+			var statuses map[string]s3UploadStatus
+			g.Expect(json.NewDecoder(res.Body).Decode(&statuses)).To(Succeed())
+			g.Expect(statuses).To(HaveKeyWithValue("v1", s3UploadStatus{
+				Phase:         s3UploadComplete,
+				BytesUploaded: int64(len(diskContents)),
+				ObjectUrl:     s3Server.URL + "/test-bucket/v1.img",
+			}))
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+
+		Expect(uploadedBody).To(Equal(diskContents))
+	})
+
+	It("should push a volume's raw artifact as a containerDisk image and report its status on the internal registryupload endpoint", func() {
+		dir, err := ioutil.TempDir("", "exportserver-registryupload")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("this is a test disk image")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		usernameFile := filepath.Join(dir, "username")
+		Expect(ioutil.WriteFile(usernameFile, []byte("test-user"), 0644)).To(Succeed())
+		passwordFile := filepath.Join(dir, "password")
+		Expect(ioutil.WriteFile(passwordFile, []byte("test-pass"), 0644)).To(Succeed())
+
+		var pushedBlobDigests []string
+		var pushedManifest registryManifest
+		registryMux := http.NewServeMux()
+		registryMux.HandleFunc("/v2/", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		registryMux.HandleFunc("/v2/exports/v1/blobs/uploads/", func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPost))
+			w.Header().Set("Location", "/v2/exports/v1/blobs/uploads/test-upload-id")
+			w.WriteHeader(http.StatusAccepted)
+		})
+		registryMux.HandleFunc("/v2/exports/v1/blobs/uploads/test-upload-id", func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPut))
+			digest := req.URL.Query().Get("digest")
+			Expect(digest).ToNot(BeEmpty())
+			body, err := ioutil.ReadAll(req.Body)
+			Expect(err).ToNot(HaveOccurred())
+			h := sha256.Sum256(body)
+			Expect(digest).To(Equal("sha256:" + hex.EncodeToString(h[:])))
+			pushedBlobDigests = append(pushedBlobDigests, digest)
+			w.WriteHeader(http.StatusCreated)
+		})
+		registryMux.HandleFunc("/v2/exports/v1/manifests/v1", func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPut))
+			Expect(json.NewDecoder(req.Body).Decode(&pushedManifest)).To(Succeed())
+			w.WriteHeader(http.StatusCreated)
+		})
+		registryServer := httptest.NewTLSServer(registryMux)
+		defer registryServer.Close()
+		registryHost := strings.TrimPrefix(registryServer.URL, "https://")
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.RegistryUpload = &RegistryUploadConfig{
+			Repository:   registryHost + "/exports",
+			Tag:          "v1",
+			UsernameFile: usernameFile,
+			PasswordFile: passwordFile,
+		}
+		es.initHandler()
+
+		// registryClient uses http.DefaultClient, so trust the test server's self-signed cert
+		// for the duration of this test.
+		oldTransport := http.DefaultClient.Transport
+		http.DefaultClient.Transport = registryServer.Client().Transport
+		defer func() { http.DefaultClient.Transport = oldTransport }()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		Eventually(func(g Gomega) {
+			res, err := http.Get(httpServer.URL + internalRegistryUploadPath)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+			g.Expect(res.StatusCode).To(Equal(http.StatusOK))
+			var statuses map[string]registryUploadStatus
+			g.Expect(json.NewDecoder(res.Body).Decode(&statuses)).To(Succeed())
+			g.Expect(statuses).To(HaveKey("v1"))
+			status := statuses["v1"]
+			g.Expect(status.Phase).To(Equal(registryUploadComplete))
+			g.Expect(status.BytesUploaded).To(BeNumerically(">", 0))
+			g.Expect(status.ImageUrl).To(Equal(registryHost + "/exports/v1:v1"))
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+
+		Expect(pushedBlobDigests).To(HaveLen(2))
+		Expect(pushedManifest.SchemaVersion).To(Equal(2))
+		Expect(pushedManifest.Layers).To(HaveLen(1))
+		Expect(pushedManifest.Layers[0].Digest).To(BeElementOf(pushedBlobDigests))
+		Expect(pushedManifest.Config.Digest).To(BeElementOf(pushedBlobDigests))
+	})
+
+	It("should create a DataVolume and stream a volume's raw artifact into it, reporting status on the internal clusterupload endpoint", func() {
+		dir, err := ioutil.TempDir("", "exportserver-clusterupload")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("this is a test disk image")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		var createdDV cdiv1.DataVolume
+		var dvReady bool
+		var gotToken bool
+		var uploadedBody []byte
+		targetClusterMux := http.NewServeMux()
+		targetClusterMux.HandleFunc("/apis/cdi.kubevirt.io/v1beta1/namespaces/target-ns/datavolumes", func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPost))
+			Expect(json.NewDecoder(req.Body).Decode(&createdDV)).To(Succeed())
+			dvReady = true
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(createdDV)
+		})
+		targetClusterMux.HandleFunc("/apis/cdi.kubevirt.io/v1beta1/namespaces/target-ns/datavolumes/v1", func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodGet))
+			dv := createdDV
+			if dvReady {
+				dv.Status.Phase = cdiv1.UploadReady
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(dv)
+		})
+		targetClusterMux.HandleFunc("/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/target-ns/uploadtokenrequests", func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Method).To(Equal(http.MethodPost))
+			var utr cdiuploadv1.UploadTokenRequest
+			Expect(json.NewDecoder(req.Body).Decode(&utr)).To(Succeed())
+			Expect(utr.Spec.PvcName).To(Equal("v1"))
+			gotToken = true
+			utr.Status.Token = "test-upload-token"
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(utr)
+		})
+		targetClusterServer := httptest.NewServer(targetClusterMux)
+		defer targetClusterServer.Close()
+
+		uploadProxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.URL.Path).To(Equal("/v1beta1/upload"))
+			Expect(req.Header.Get("Authorization")).To(Equal("Bearer test-upload-token"))
+			var readErr error
+			uploadedBody, readErr = ioutil.ReadAll(req.Body)
+			Expect(readErr).ToNot(HaveOccurred())
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer uploadProxyServer.Close()
+
+		kubeconfigFile := filepath.Join(dir, "kubeconfig")
+		kubeconfig := "apiVersion: v1\n" +
+			"kind: Config\n" +
+			"clusters:\n" +
+			"- cluster:\n" +
+			"    server: " + targetClusterServer.URL + "\n" +
+			"  name: target\n" +
+			"contexts:\n" +
+			"- context:\n" +
+			"    cluster: target\n" +
+			"    user: target\n" +
+			"  name: target\n" +
+			"current-context: target\n" +
+			"users:\n" +
+			"- name: target\n" +
+			"  user: {}\n"
+		Expect(ioutil.WriteFile(kubeconfigFile, []byte(kubeconfig), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.ClusterUpload = &ClusterUploadConfig{
+			Namespace:         "target-ns",
+			CDIUploadProxyURL: uploadProxyServer.URL,
+			KubeconfigFile:    kubeconfigFile,
+		}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		Eventually(func(g Gomega) {
+			res, err := http.Get(httpServer.URL + internalClusterUploadPath)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+			g.Expect(res.StatusCode).To(Equal(http.StatusOK))
+			var statuses map[string]clusterUploadStatus
+			g.Expect(json.NewDecoder(res.Body).Decode(&statuses)).To(Succeed())
+			g.Expect(statuses).To(HaveKey("v1"))
+			status := statuses["v1"]
+			g.Expect(status.Phase).To(Equal(clusterUploadComplete))
+			g.Expect(status.BytesUploaded).To(Equal(int64(len(diskContents))))
+			g.Expect(status.DataVolumeName).To(Equal("v1"))
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+
+		Expect(gotToken).To(BeTrue())
+		Expect(uploadedBody).To(Equal(diskContents))
+		Expect(createdDV.Spec.Source.Upload).ToNot(BeNil())
+	})
+
+	It("should report bytes transferred for a downloaded volume on the internal progress endpoint", func() {
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: "/tmp", RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		client := http.Client{}
+		req, err := http.NewRequest("GET", httpServer.URL+"/volume/v1/disk.img", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("x-kubevirt-export-token", token)
+		res, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		res.Body.Close()
+
+		progressRes, err := client.Get(httpServer.URL + internalProgressPath)
+		Expect(err).ToNot(HaveOccurred())
+		defer progressRes.Body.Close()
+		Expect(progressRes.StatusCode).To(Equal(http.StatusOK))
+		var progress map[string]volumeActivity
+		Expect(json.NewDecoder(progressRes.Body).Decode(&progress)).To(Succeed())
+		Expect(progress["v1"].BytesTransferred).To(Equal(int64(len(out))))
+	})
+
+	It("should serve a bundle at the configured OVA URI", func() {
+		token := "foo"
+		es := newTestServer(token)
+		es.Ova = &OvaConfig{URI: "/ova", DescriptorFile: "/tmp/descriptor.ovf"}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		client := http.Client{}
+		req, err := http.NewRequest("GET", httpServer.URL+"/ova", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("x-kubevirt-export-token", token)
+		res, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		defer res.Body.Close()
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal("OK"))
+	})
+
+	It("should serve a bundle at the configured combined archive URI", func() {
+		token := "foo"
+		es := newTestServer(token)
+		es.AllURI = "/all"
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		client := http.Client{}
+		req, err := http.NewRequest("GET", httpServer.URL+"/all", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("x-kubevirt-export-token", token)
+		res, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		defer res.Body.Close()
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal("OK"))
+	})
+
 	DescribeTable("should handle (query param version)", func(vi VolumeInfo, uri string) {
 		token := "foo"
 		es := newTestServer(token)
@@ -130,6 +706,34 @@ var _ = Describe("exportserver", func() {
 			VolumeInfo{Path: "/tmp", RawGzURI: "/volume/v1/disk.img.gz"},
 			"/volume/v1/disk.img.gz",
 		),
+		Entry("zstd URI",
+			VolumeInfo{Path: "/tmp", ZstdURI: "/volume/v1/disk.img.zst"},
+			"/volume/v1/disk.img.zst",
+		),
+		Entry("archive zstd URI",
+			VolumeInfo{Path: "/tmp", ArchiveZstdURI: "/volume/v1/disk.tar.zst"},
+			"/volume/v1/disk.tar.zst",
+		),
+		Entry("qcow2 URI",
+			VolumeInfo{Path: "/tmp", Qcow2URI: "/volume/v1/disk.qcow2"},
+			"/volume/v1/disk.qcow2",
+		),
+		Entry("qcow2 gz URI",
+			VolumeInfo{Path: "/tmp", Qcow2GzURI: "/volume/v1/disk.qcow2.gz"},
+			"/volume/v1/disk.qcow2.gz",
+		),
+		Entry("vmdk URI",
+			VolumeInfo{Path: "/tmp", VmdkURI: "/volume/v1/disk.vmdk"},
+			"/volume/v1/disk.vmdk",
+		),
+		Entry("vhd URI",
+			VolumeInfo{Path: "/tmp", VhdURI: "/volume/v1/disk.vhd"},
+			"/volume/v1/disk.vhd",
+		),
+		Entry("vhdx URI",
+			VolumeInfo{Path: "/tmp", VhdxURI: "/volume/v1/disk.vhdx"},
+			"/volume/v1/disk.vhdx",
+		),
 	)
 
 	DescribeTable("should fail bad token", func(vi VolumeInfo, uri string) {
@@ -165,6 +769,34 @@ var _ = Describe("exportserver", func() {
 			VolumeInfo{Path: "/tmp", RawGzURI: "/volume/v1/disk.img.gz"},
 			"/volume/v1/disk.img.gz",
 		),
+		Entry("zstd URI",
+			VolumeInfo{Path: "/tmp", ZstdURI: "/volume/v1/disk.img.zst"},
+			"/volume/v1/disk.img.zst",
+		),
+		Entry("archive zstd URI",
+			VolumeInfo{Path: "/tmp", ArchiveZstdURI: "/volume/v1/disk.tar.zst"},
+			"/volume/v1/disk.tar.zst",
+		),
+		Entry("qcow2 URI",
+			VolumeInfo{Path: "/tmp", Qcow2URI: "/volume/v1/disk.qcow2"},
+			"/volume/v1/disk.qcow2",
+		),
+		Entry("qcow2 gz URI",
+			VolumeInfo{Path: "/tmp", Qcow2GzURI: "/volume/v1/disk.qcow2.gz"},
+			"/volume/v1/disk.qcow2.gz",
+		),
+		Entry("vmdk URI",
+			VolumeInfo{Path: "/tmp", VmdkURI: "/volume/v1/disk.vmdk"},
+			"/volume/v1/disk.vmdk",
+		),
+		Entry("vhd URI",
+			VolumeInfo{Path: "/tmp", VhdURI: "/volume/v1/disk.vhd"},
+			"/volume/v1/disk.vhd",
+		),
+		Entry("vhdx URI",
+			VolumeInfo{Path: "/tmp", VhdxURI: "/volume/v1/disk.vhdx"},
+			"/volume/v1/disk.vhdx",
+		),
 	)
 
 	DescribeTable("should fail bad token (query param version)", func(vi VolumeInfo, uri string) {
@@ -199,6 +831,922 @@ var _ = Describe("exportserver", func() {
 			VolumeInfo{Path: "/tmp", RawGzURI: "/volume/v1/disk.img.gz"},
 			"/volume/v1/disk.img.gz",
 		),
+		Entry("zstd URI",
+			VolumeInfo{Path: "/tmp", ZstdURI: "/volume/v1/disk.img.zst"},
+			"/volume/v1/disk.img.zst",
+		),
+		Entry("archive zstd URI",
+			VolumeInfo{Path: "/tmp", ArchiveZstdURI: "/volume/v1/disk.tar.zst"},
+			"/volume/v1/disk.tar.zst",
+		),
+		Entry("qcow2 URI",
+			VolumeInfo{Path: "/tmp", Qcow2URI: "/volume/v1/disk.qcow2"},
+			"/volume/v1/disk.qcow2",
+		),
+		Entry("qcow2 gz URI",
+			VolumeInfo{Path: "/tmp", Qcow2GzURI: "/volume/v1/disk.qcow2.gz"},
+			"/volume/v1/disk.qcow2.gz",
+		),
+		Entry("vmdk URI",
+			VolumeInfo{Path: "/tmp", VmdkURI: "/volume/v1/disk.vmdk"},
+			"/volume/v1/disk.vmdk",
+		),
+		Entry("vhd URI",
+			VolumeInfo{Path: "/tmp", VhdURI: "/volume/v1/disk.vhd"},
+			"/volume/v1/disk.vhd",
+		),
+		Entry("vhdx URI",
+			VolumeInfo{Path: "/tmp", VhdxURI: "/volume/v1/disk.vhdx"},
+			"/volume/v1/disk.vhdx",
+		),
 	)
 
 })
+
+var _ = Describe("sparseReader", func() {
+	It("should read back the same content as the underlying file", func() {
+		f, err := ioutil.TempFile("", "sparse-reader-test")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		content := make([]byte, 3*4096)
+		copy(content[4096:4096+5], []byte("hello"))
+		copy(content[2*4096:2*4096+5], []byte("world"))
+		Expect(f.Truncate(int64(len(content)))).To(Succeed())
+		_, err = f.WriteAt(content[4096:4096+5], 4096)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = f.WriteAt(content[2*4096:2*4096+5], 2*4096)
+		Expect(err).ToNot(HaveOccurred())
+
+		reader, err := newSparseReader(f)
+		Expect(err).ToNot(HaveOccurred())
+		out, err := ioutil.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal(content))
+	})
+
+	It("should read back an empty file", func() {
+		f, err := ioutil.TempFile("", "sparse-reader-empty-test")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		reader, err := newSparseReader(f)
+		Expect(err).ToNot(HaveOccurred())
+		out, err := ioutil.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(BeEmpty())
+	})
+})
+
+var _ = Describe("range requests", func() {
+	lastModified := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	DescribeTable("parseRangeRequest", func(rangeHeader, ifRangeHeader string, expected rangeRequest, expectedOk bool) {
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		Expect(err).ToNot(HaveOccurred())
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		if ifRangeHeader != "" {
+			req.Header.Set("If-Range", ifRangeHeader)
+		}
+
+		rr, ok := parseRangeRequest(req, lastModified)
+		Expect(ok).To(Equal(expectedOk))
+		if expectedOk {
+			Expect(rr).To(Equal(expected))
+		}
+	},
+		Entry("no Range header", "", "", rangeRequest{}, false),
+		Entry("open-ended range", "bytes=100-", "", rangeRequest{start: 100}, true),
+		Entry("closed range", "bytes=100-199", "", rangeRequest{start: 100, end: 199, hasEnd: true}, true),
+		Entry("suffix range is unsupported", "bytes=-500", "", rangeRequest{}, false),
+		Entry("multiple ranges are unsupported", "bytes=0-99,200-299", "", rangeRequest{}, false),
+		Entry("malformed range", "bytes=abc-", "", rangeRequest{}, false),
+		Entry("end before start", "bytes=199-100", "", rangeRequest{}, false),
+		Entry("matching If-Range is honored", "bytes=100-", lastModified.Format(http.TimeFormat), rangeRequest{start: 100}, true),
+		Entry("stale If-Range falls back to full content", "bytes=100-", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat), rangeRequest{}, false),
+	)
+
+	It("serveRange writes the requested open-ended range with a 206 status", func() {
+		w := httptest.NewRecorder()
+		n, err := serveRange(w, strings.NewReader("0123456789"), rangeRequest{start: 5})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(5))
+		Expect(w.Code).To(Equal(http.StatusPartialContent))
+		Expect(w.Header().Get("Content-Range")).To(Equal("bytes 5-/*"))
+		Expect(w.Body.String()).To(Equal("56789"))
+	})
+
+	It("serveRange writes only the requested closed range", func() {
+		w := httptest.NewRecorder()
+		n, err := serveRange(w, strings.NewReader("0123456789"), rangeRequest{start: 2, end: 4, hasEnd: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(3))
+		Expect(w.Code).To(Equal(http.StatusPartialContent))
+		Expect(w.Header().Get("Content-Range")).To(Equal("bytes 2-4/*"))
+		Expect(w.Body.String()).To(Equal("234"))
+	})
+
+	It("serveRange returns 416 when the range starts past the end of the content", func() {
+		w := httptest.NewRecorder()
+		n, err := serveRange(w, strings.NewReader("0123456789"), rangeRequest{start: 100})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(0))
+		Expect(w.Code).To(Equal(http.StatusRequestedRangeNotSatisfiable))
+	})
+
+	It("fileHandler resumes a download from the requested offset", func() {
+		dir, err := ioutil.TempDir("", "exportserver-range")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("0123456789"), 0644)).To(Succeed())
+
+		httpServer := httptest.NewServer(fileHandler(diskPath))
+		defer httpServer.Close()
+
+		req, err := http.NewRequest("GET", httpServer.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Range", "bytes=5-")
+		res, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusPartialContent))
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal("56789"))
+	})
+
+	It("archiveHandler resumes a download from the requested offset", func() {
+		dir, err := ioutil.TempDir("", "exportserver-range-archive")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		Expect(ioutil.WriteFile(filepath.Join(dir, "disk.img"), []byte("hello world"), 0644)).To(Succeed())
+
+		full := httptest.NewServer(archiveHandler(dir, gzip.DefaultCompression, nil, nil))
+		defer full.Close()
+		fullRes, err := http.Get(full.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer fullRes.Body.Close()
+		fullBody, err := ioutil.ReadAll(fullRes.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		httpServer := httptest.NewServer(archiveHandler(dir, gzip.DefaultCompression, nil, nil))
+		defer httpServer.Close()
+		req, err := http.NewRequest("GET", httpServer.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Range", "bytes=4-")
+		res, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusPartialContent))
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal(fullBody[4:]))
+	})
+})
+
+var _ = Describe("bandwidth limiting", func() {
+	It("should throttle a download to roughly the configured per-connection rate", func() {
+		dir, err := ioutil.TempDir("", "exportserver-bandwidth")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := bytes.Repeat([]byte("x"), 50*1024)
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.FileHandler = fileHandler
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.PerConnectionBandwidthLimit = 10 * 1024
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		start := time.Now()
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		elapsed := time.Since(start)
+
+		Expect(out).To(Equal(diskContents))
+		Expect(elapsed).To(BeNumerically(">=", 3*time.Second))
+	})
+})
+
+var _ = Describe("concurrency limiting", func() {
+	It("should serve downloads up to MaxConcurrentDownloads, and reject further ones with 503 and Retry-After", func() {
+		dir, err := ioutil.TempDir("", "exportserver-concurrency")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("concurrency limiting contents"), 0644)).To(Succeed())
+
+		blockCh := make(chan struct{})
+		token := "foo"
+		es := newTestServer(token)
+		es.FileHandler = func(string) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-blockCh
+				successHandler(w, r)
+			})
+		}
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.MaxConcurrentDownloads = 1
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		doneCh := make(chan *http.Response)
+		go func() {
+			res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+			Expect(err).ToNot(HaveOccurred())
+			doneCh <- res
+		}()
+
+		Eventually(func() int32 {
+			return atomic.LoadInt32(&es.activeDownloads)
+		}, "5s").Should(Equal(int32(1)))
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(res.Header.Get("Retry-After")).ToNot(BeEmpty())
+
+		close(blockCh)
+		firstRes := <-doneCh
+		defer firstRes.Body.Close()
+		Expect(firstRes.StatusCode).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("per-client IP rate limiting", func() {
+	It("should serve requests up to PerClientIPRequestLimit and reject further ones with 429", func() {
+		dir, err := ioutil.TempDir("", "exportserver-ip-rate-limit")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("ip rate limiting contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.FileHandler = fileHandler
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.PerClientIPRequestLimit = 1
+		es.PerClientIPRequestBurst = 1
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		res, err = http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+	})
+})
+
+var _ = Describe("metrics", func() {
+	It("should report bytes served and completed downloads for a volume at /metrics", func() {
+		dir, err := ioutil.TempDir("", "exportserver-metrics")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("hello world")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.FileHandler = fileHandler
+		es.Volumes = []VolumeInfo{{Name: "metrics-volume", Path: diskPath, RawURI: "/volume/metrics-volume/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/metrics-volume/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		_, err = ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		metricsRes, err := http.Get(httpServer.URL + metricsPath)
+		Expect(err).ToNot(HaveOccurred())
+		defer metricsRes.Body.Close()
+		Expect(metricsRes.StatusCode).To(Equal(http.StatusOK))
+		body, err := ioutil.ReadAll(metricsRes.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(string(body)).To(ContainSubstring(`kubevirt_vmexport_server_bytes_served_total{artifact="metrics-volume"}`))
+		Expect(string(body)).To(ContainSubstring(`kubevirt_vmexport_server_downloads_total{artifact="metrics-volume",result="success"} 1`))
+	})
+})
+
+var _ = Describe("access logging", func() {
+	DescribeTable("tokenDisposition", func(statusCode int, expected string) {
+		Expect(tokenDisposition(statusCode)).To(Equal(expected))
+	},
+		Entry("a rejected token", http.StatusUnauthorized, "rejected"),
+		Entry("an accepted token serving a successful download", http.StatusOK, "accepted"),
+		Entry("an accepted token that failed for an unrelated reason", http.StatusInternalServerError, "accepted"),
+	)
+
+	It("should not alter a download's response when logging access to it", func() {
+		dir, err := ioutil.TempDir("", "exportserver-accesslog")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		diskContents := []byte("access logged contents")
+		Expect(ioutil.WriteFile(diskPath, diskContents, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.FileHandler = fileHandler
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		out, err := ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal(diskContents))
+
+		rejected, err := http.Get(httpServer.URL + "/volume/v1/disk.img")
+		Expect(err).ToNot(HaveOccurred())
+		defer rejected.Body.Close()
+		Expect(rejected.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("TLS configuration", func() {
+	It("should default to TLS 1.2 when no minimum version is configured", func() {
+		es := newTestServer("foo")
+		Expect(es.MinTLSVersion).To(Equal(uint16(tls.VersionTLS12)))
+	})
+
+	It("should keep a configured minimum TLS version and cipher suites", func() {
+		config := ExportServerConfig{
+			TokenGetter: func() ([]string, error) {
+				return []string{"foo"}, nil
+			},
+			MinTLSVersion: tls.VersionTLS13,
+			CipherSuites:  []uint16{tls.TLS_AES_128_GCM_SHA256},
+		}
+		s := NewExportServer(config).(*exportServer)
+		Expect(s.MinTLSVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(s.CipherSuites).To(Equal([]uint16{tls.TLS_AES_128_GCM_SHA256}))
+	})
+})
+
+var _ = Describe("client certificate authentication", func() {
+	It("should reject requests with neither a valid token nor a client certificate", func() {
+		handler := tokenChecker(func() ([]string, error) { return []string{"foo"}, nil }, nil, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/volume/v1/disk.img", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should accept a request presenting a client certificate even without a valid token", func() {
+		handler := tokenChecker(func() ([]string, error) { return []string{"foo"}, nil }, nil, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/volume/v1/disk.img", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should leave clientCAPool unset when ClientCAFile is not configured", func() {
+		es := newTestServer("foo")
+		es.initHandler()
+		Expect(es.clientCAPool).To(BeNil())
+	})
+})
+
+var _ = Describe("gzip compression", func() {
+	It("should default GzipCompressionLevel to gzip's own default", func() {
+		es := newTestServer("foo")
+		Expect(es.GzipCompressionLevel).To(Equal(gzip.DefaultCompression))
+	})
+
+	It("should keep a configured gzip compression level", func() {
+		config := ExportServerConfig{
+			TokenGetter: func() ([]string, error) {
+				return []string{"foo"}, nil
+			},
+			GzipCompressionLevel: gzip.BestCompression,
+		}
+		s := NewExportServer(config).(*exportServer)
+		Expect(s.GzipCompressionLevel).To(Equal(gzip.BestCompression))
+	})
+
+	It("should set Content-Length on a gz download once its size has been precomputed", func() {
+		dir, err := ioutil.TempDir("", "exportserver-gzip-contentlength")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("gzip content length contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.GzipHandler = gzipHandler
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawGzURI: "/volume/v1/disk.img.gz"}}
+		es.initHandler()
+
+		Eventually(func() bool {
+			_, ok := es.metadata.snapshot()["/volume/v1/disk.img.gz"]
+			return ok
+		}, "5s").Should(BeTrue())
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img.gz?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.ContentLength).To(BeNumerically(">", 0))
+	})
+})
+
+var _ = Describe("ETag support", func() {
+	It("should set an ETag once the artifact's checksum has been precomputed and honor If-None-Match", func() {
+		dir, err := ioutil.TempDir("", "exportserver-etag")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("etag test contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.FileHandler = fileHandler
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		Eventually(func() bool {
+			_, ok := es.metadata.snapshot()["/volume/v1/disk.img"]
+			return ok
+		}, "5s").Should(BeTrue())
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		res.Body.Close()
+		etag := res.Header.Get("ETag")
+		Expect(etag).ToNot(BeEmpty())
+
+		req, err := http.NewRequest(http.MethodGet, httpServer.URL+"/volume/v1/disk.img?"+authHeader+"="+token, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("If-None-Match", etag)
+		res, err = http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusNotModified))
+	})
+
+	It("should not set an ETag before the artifact's checksum has been precomputed", func() {
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: "/nonexistent", RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.Header.Get("ETag")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("download activity reporting", func() {
+	It("should report no activity for a volume nobody has downloaded yet", func() {
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: "/nonexistent", RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		activity := es.progress.snapshot()["v1"]
+		Expect(activity.LastDownloadStartTimestamp).To(BeNil())
+		Expect(activity.LastDownloadCompletionTimestamp).To(BeNil())
+	})
+
+	It("should record when a download starts and completes", func() {
+		dir, err := ioutil.TempDir("", "exportserver-activity")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("activity reporting contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		before := time.Now()
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		_, err = ioutil.ReadAll(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		after := time.Now()
+
+		activity := es.progress.snapshot()["v1"]
+		Expect(activity.LastDownloadStartTimestamp).ToNot(BeNil())
+		Expect(*activity.LastDownloadStartTimestamp).To(BeTemporally(">=", before))
+		Expect(activity.LastDownloadCompletionTimestamp).ToNot(BeNil())
+		Expect(*activity.LastDownloadCompletionTimestamp).To(BeTemporally("<=", after))
+	})
+})
+
+var _ = Describe("graceful shutdown", func() {
+	It("should default ShutdownGracePeriod to 30 seconds", func() {
+		es := newTestServer("foo")
+		Expect(es.ShutdownGracePeriod).To(Equal(30 * time.Second))
+	})
+
+	It("should keep a configured shutdown grace period", func() {
+		config := ExportServerConfig{
+			TokenGetter: func() ([]string, error) {
+				return []string{"foo"}, nil
+			},
+			ShutdownGracePeriod: 5 * time.Second,
+		}
+		s := NewExportServer(config).(*exportServer)
+		Expect(s.ShutdownGracePeriod).To(Equal(5 * time.Second))
+	})
+
+	It("should refuse new connections once draining but let an in-flight request finish within the grace period", func() {
+		es := newTestServer("foo")
+		es.ShutdownGracePeriod = time.Second
+
+		requestStarted := make(chan struct{})
+		srv := &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(requestStarted)
+				time.Sleep(100 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		go srv.Serve(listener)
+
+		go http.Get("http://" + listener.Addr().String())
+		<-requestStarted
+
+		drained := make(chan struct{})
+		go func() {
+			es.drain(srv)
+			close(drained)
+		}()
+		Eventually(drained, "2s").Should(BeClosed())
+
+		_, err = http.Get("http://" + listener.Addr().String())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("deadline extension", func() {
+	It("should reject non-POST requests", func() {
+		es := newTestServer("foo")
+		es.initHandler()
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		resp, err := http.Get(httpServer.URL + internalDeadlinePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should reject a malformed body", func() {
+		es := newTestServer("foo")
+		es.initHandler()
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		resp, err := http.Post(httpServer.URL+internalDeadlinePath, "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reschedule a running deadline timer", func() {
+		es := newTestServer("foo")
+		es.Deadline = time.Now().Add(time.Hour)
+		es.deadlineTimer = time.NewTimer(time.Hour)
+		es.initHandler()
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		newDeadline := time.Now().Add(50 * time.Millisecond)
+		body, err := json.Marshal(map[string]time.Time{"deadline": newDeadline})
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := http.Post(httpServer.URL+internalDeadlinePath, "application/json", bytes.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		Expect(es.Deadline).To(BeTemporally("~", newDeadline, time.Second))
+		Eventually(es.deadlineTimer.C, "1s").Should(Receive())
+	})
+})
+
+var _ = Describe("pre/post serve hooks", func() {
+	It("should serve artifacts and report success when the pre-serve hook succeeds", func() {
+		dir, err := ioutil.TempDir("", "exportserver-preserve-hook")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("hook test contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Hooks = &HookConfig{PreServeCommand: []string{"true"}}
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		res, err = http.Get(httpServer.URL + internalHooksPath)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		var statusByName map[string]hookStatus
+		Expect(json.NewDecoder(res.Body).Decode(&statusByName)).To(Succeed())
+		Expect(statusByName[preServeHookName].Phase).To(Equal(hookSucceeded))
+	})
+
+	It("should refuse to serve any artifact when the pre-serve hook fails", func() {
+		dir, err := ioutil.TempDir("", "exportserver-preserve-hook-fail")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("hook test contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Hooks = &HookConfig{PreServeCommand: []string{"false"}}
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+
+		res, err = http.Get(httpServer.URL + internalHooksPath)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		var statusByName map[string]hookStatus
+		Expect(json.NewDecoder(res.Body).Decode(&statusByName)).To(Succeed())
+		Expect(statusByName[preServeHookName].Phase).To(Equal(hookFailed))
+	})
+
+	It("should run the post-serve hook while draining", func() {
+		es := newTestServer("foo")
+		es.Hooks = &HookConfig{PostServeCommand: []string{"true"}}
+		es.initHandler()
+
+		srv := &http.Server{Handler: es.handler}
+		es.drain(srv)
+
+		Expect(es.hookStatus.snapshot()[postServeHookName].Phase).To(Equal(hookSucceeded))
+	})
+})
+
+var _ = Describe("scoped download tokens", func() {
+	writeScopedTokenFile := func(dir, token string) string {
+		tokenPath := filepath.Join(dir, "scoped-token")
+		Expect(ioutil.WriteFile(tokenPath, []byte(token), 0644)).To(Succeed())
+		return tokenPath
+	}
+
+	newScopedTestServer := func(dir string, scope *TokenScope) *exportServer {
+		diskPath1 := filepath.Join(dir, "v1-disk.img")
+		diskPath2 := filepath.Join(dir, "v2-disk.img")
+		Expect(ioutil.WriteFile(diskPath1, []byte("v1 contents"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(diskPath2, []byte("v2 contents"), 0644)).To(Succeed())
+
+		es := newTestServer("unrestricted-token")
+		es.ScopedTokens = []*TokenScope{scope}
+		es.Volumes = []VolumeInfo{
+			{Name: "v1", Path: diskPath1, RawURI: "/volume/v1/disk.img"},
+			{Name: "v2", Path: diskPath2, RawURI: "/volume/v2/disk.img"},
+		}
+		es.initHandler()
+		return es
+	}
+
+	It("should accept a scoped token for a volume it is scoped to", func() {
+		dir, err := ioutil.TempDir("", "exportserver-scoped-token")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		tokenPath := writeScopedTokenFile(dir, "scoped")
+
+		es := newScopedTestServer(dir, &TokenScope{TokenFile: tokenPath, Volumes: []string{"v1"}})
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=scoped")
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should reject a scoped token for a volume it is not scoped to", func() {
+		dir, err := ioutil.TempDir("", "exportserver-scoped-token-other-volume")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		tokenPath := writeScopedTokenFile(dir, "scoped")
+
+		es := newScopedTestServer(dir, &TokenScope{TokenFile: tokenPath, Volumes: []string{"v1"}})
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v2/disk.img?" + authHeader + "=scoped")
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject a scoped token for the \"all volumes\" bundle, which is never volume-scoped", func() {
+		dir, err := ioutil.TempDir("", "exportserver-scoped-token-all")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		tokenPath := writeScopedTokenFile(dir, "scoped")
+
+		es := newScopedTestServer(dir, &TokenScope{TokenFile: tokenPath})
+		es.AllURI = "/all"
+		es.initHandler()
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/all?" + authHeader + "=scoped")
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject a scoped token once it has exhausted its MaxReads", func() {
+		dir, err := ioutil.TempDir("", "exportserver-scoped-token-max-reads")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		tokenPath := writeScopedTokenFile(dir, "scoped")
+
+		maxReads := int32(1)
+		es := newScopedTestServer(dir, &TokenScope{TokenFile: tokenPath, Volumes: []string{"v1"}, MaxReads: &maxReads})
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=scoped")
+		Expect(err).ToNot(HaveOccurred())
+		res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		res, err = http.Get(httpServer.URL + "/volume/v1/disk.img?" + authHeader + "=scoped")
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+	})
+})
+
+var _ = Describe("archive member filtering", func() {
+	setUpTree := func() string {
+		dir, err := ioutil.TempDir("", "exportserver-archive-filter")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "logs"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "lost+found"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "keep.txt"), []byte("a"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "skip.tmp"), []byte("b"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "logs", "app.log"), []byte("c"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "lost+found", "orphan"), []byte("d"), 0644)).To(Succeed())
+		return dir
+	}
+
+	It("should include everything except lost+found when no patterns are given", func() {
+		dir := setUpTree()
+		defer os.RemoveAll(dir)
+
+		members, err := selectTarMembers(dir, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(members).To(ConsistOf("keep.txt", "skip.tmp", "logs", filepath.Join("logs", "app.log")))
+	})
+
+	It("should only include files matching an include pattern", func() {
+		dir := setUpTree()
+		defer os.RemoveAll(dir)
+
+		members, err := selectTarMembers(dir, []string{"*.txt"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(members).To(ConsistOf("keep.txt"))
+	})
+
+	It("should drop files matching an exclude pattern", func() {
+		dir := setUpTree()
+		defer os.RemoveAll(dir)
+
+		members, err := selectTarMembers(dir, nil, []string{"*.tmp"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(members).To(ConsistOf("keep.txt", "logs", filepath.Join("logs", "app.log")))
+	})
+})
+
+var _ = Describe("additional disk image discovery", func() {
+	It("should serve an extra disk image found alongside disk.img", func() {
+		dir, err := ioutil.TempDir("", "exportserver-extra-images")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		Expect(ioutil.WriteFile(filepath.Join(dir, "disk.img"), []byte("primary"), 0644)).To(Succeed())
+		extraContents := []byte("hotplugged disk")
+		Expect(ioutil.WriteFile(filepath.Join(dir, "hotplug-disk1.img"), extraContents, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignore me"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: dir, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/hotplug-disk1.img?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		res, err = http.Get(httpServer.URL + "/volume/v1/notes.txt?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("should report size and checksum for an extra disk image on the internal metadata endpoint", func() {
+		dir, err := ioutil.TempDir("", "exportserver-extra-images-metadata")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		Expect(ioutil.WriteFile(filepath.Join(dir, "disk.img"), []byte("primary"), 0644)).To(Succeed())
+		extraContents := []byte("hotplugged disk")
+		Expect(ioutil.WriteFile(filepath.Join(dir, "hotplug-disk1.img"), extraContents, 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: dir, RawURI: "/volume/v1/disk.img"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		sum := sha256.Sum256(extraContents)
+		expectedChecksum := hex.EncodeToString(sum[:])
+
+		Eventually(func(g Gomega) {
+			res, err := http.Get(httpServer.URL + internalMetadataPath)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer res.Body.Close()
+			g.Expect(res.StatusCode).To(Equal(http.StatusOK))
+			var metadata map[string]artifactMetadata
+			g.Expect(json.NewDecoder(res.Body).Decode(&metadata)).To(Succeed())
+			g.Expect(metadata).To(HaveKeyWithValue("/volume/v1/hotplug-disk1.img", artifactMetadata{
+				Size:     int64(len(extraContents)),
+				Checksum: expectedChecksum,
+			}))
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+	})
+})
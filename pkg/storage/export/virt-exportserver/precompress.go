@@ -0,0 +1,159 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"kubevirt.io/client-go/log"
+)
+
+// precomputedArtifactStore tracks, by download URI, the path of an artifact precomputeArtifacts
+// has already compressed into scratch space, so a request for that URI can be served straight
+// from disk instead of recompressing the volume again.
+type precomputedArtifactStore struct {
+	mu    sync.RWMutex
+	byURI map[string]string
+}
+
+func newPrecomputedArtifactStore() *precomputedArtifactStore {
+	return &precomputedArtifactStore{byURI: make(map[string]string)}
+}
+
+func (s *precomputedArtifactStore) set(uri, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byURI[uri] = path
+}
+
+func (s *precomputedArtifactStore) get(uri string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	path, ok := s.byURI[uri]
+	return path, ok
+}
+
+// wrap has requests for uri served from the file precomputeArtifacts already produced for it, if
+// any, falling back to fallback (the usual on-demand compression handler) while that's still in
+// progress or if uri was never configured to be precomputed at all.
+func (s *precomputedArtifactStore) wrap(uri string, fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := s.get(uri)
+		if !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			log.Log.Reason(err).Errorf("error opening precomputed artifact %s, falling back to on-demand compression", path)
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			log.Log.Reason(err).Errorf("error statting precomputed artifact %s, falling back to on-demand compression", path)
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		// http.ServeContent both supports Range requests and lets the kernel do the file-to-socket
+		// copy, instead of streaming every byte through a gzip or zstd process on every request the
+		// way fallback would.
+		http.ServeContent(w, r, "", fi.ModTime(), f)
+	})
+}
+
+// precomputeArtifacts pre-generates the gz and/or zstd raw-volume artifacts PrecomputeFormats
+// asks for, one per configured volume, into scratch space, so the first download of a
+// frequently-requested export doesn't pay the compression cost either. Archive and qcow2 formats
+// aren't covered: they either re-derive their own scratch-space qcow2 conversion already (see
+// newQcow2Reader) or stream directly off the filesystem being exported (see newTarReader), so
+// the "recompress the whole raw disk on every request" cost this targets doesn't apply to them.
+func (s *exportServer) precomputeArtifacts() {
+	precomputeGz := false
+	precomputeZstd := false
+	for _, format := range s.PrecomputeFormats {
+		switch format {
+		case "gz":
+			precomputeGz = true
+		case "zstd":
+			precomputeZstd = true
+		default:
+			log.Log.Warningf("ignoring unknown precompute format %q", format)
+		}
+	}
+
+	for _, vi := range s.Volumes {
+		vi := vi
+		if precomputeGz && vi.RawGzURI != "" {
+			go s.precomputeArtifact(vi.RawGzURI, "precompress-gz-", func(w io.Writer) (int64, error) {
+				f, err := openDiskImage(vi.Path)
+				if err != nil {
+					return 0, err
+				}
+				defer f.Close()
+				sparse, err := newSparseReader(f)
+				if err != nil {
+					return 0, err
+				}
+				gzipReader := pipeToGzip(sparse, s.GzipCompressionLevel)
+				defer gzipReader.Close()
+				return io.Copy(w, gzipReader)
+			})
+		}
+		if precomputeZstd && vi.ZstdURI != "" {
+			go s.precomputeArtifact(vi.ZstdURI, "precompress-zstd-", func(w io.Writer) (int64, error) {
+				zstdReader, err := newZstdReader(vi.Path, s.ZstdCompressionLevel)
+				if err != nil {
+					return 0, err
+				}
+				defer zstdReader.Close()
+				return io.Copy(w, zstdReader)
+			})
+		}
+	}
+}
+
+// precomputeArtifact writes write's output to a fresh file in scratch space (TMPDIR, set by the
+// controller to the exporter pod's scratch emptyDir) and registers it under uri once complete, so
+// a request already in flight against the on-demand handler isn't disrupted by a half-written
+// file appearing partway through.
+func (s *exportServer) precomputeArtifact(uri, tmpFilePrefix string, write func(io.Writer) (int64, error)) {
+	f, err := ioutil.TempFile("", tmpFilePrefix)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error creating scratch file to precompute %s", uri)
+		return
+	}
+	defer f.Close()
+
+	n, err := write(f)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error precomputing %s", uri)
+		os.Remove(f.Name())
+		return
+	}
+
+	s.precomputed.set(uri, f.Name())
+	log.Log.Infof("Precomputed %d bytes for %s at %s", n, uri, f.Name())
+}
@@ -0,0 +1,95 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("precompute formats", func() {
+	It("should precompute a gz artifact and serve it without invoking the on-demand handler", func() {
+		dir, err := ioutil.TempDir("", "exportserver-precompute-gz")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("precompute gz contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.GzipHandler = gzipHandler
+		es.PrecomputeFormats = []string{"gz"}
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawGzURI: "/volume/v1/disk.img.gz"}}
+		es.initHandler()
+
+		Eventually(func() bool {
+			_, ok := es.precomputed.get("/volume/v1/disk.img.gz")
+			return ok
+		}, "5s").Should(BeTrue())
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img.gz?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		gzipReader, err := gzip.NewReader(res.Body)
+		Expect(err).ToNot(HaveOccurred())
+		defer gzipReader.Close()
+		content, err := io.ReadAll(gzipReader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("precompute gz contents"))
+	})
+
+	It("should fall back to the on-demand handler for a format that was not requested", func() {
+		dir, err := ioutil.TempDir("", "exportserver-precompute-fallback")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		diskPath := filepath.Join(dir, "disk.img")
+		Expect(ioutil.WriteFile(diskPath, []byte("precompute fallback contents"), 0644)).To(Succeed())
+
+		token := "foo"
+		es := newTestServer(token)
+		es.GzipHandler = gzipHandler
+		es.PrecomputeFormats = []string{"zstd"}
+		es.Volumes = []VolumeInfo{{Name: "v1", Path: diskPath, RawGzURI: "/volume/v1/disk.img.gz"}}
+		es.initHandler()
+
+		httpServer := httptest.NewServer(es.handler)
+		defer httpServer.Close()
+
+		res, err := http.Get(httpServer.URL + "/volume/v1/disk.img.gz?" + authHeader + "=" + token)
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		_, ok := es.precomputed.get("/volume/v1/disk.img.gz")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,294 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kubevirt.io/client-go/log"
+)
+
+// S3UploadConfig configures the exporter pod to additionally push exported volumes to an
+// S3-compatible object store, alongside still serving them for HTTPS download.
+//
+// Only volumes with a RawURI are uploaded: RawGzURI and ArchiveURI are produced by streaming a
+// gzip or tar pipeline with no fixed length, and a plain SigV4 PUT requires a known
+// Content-Length up front. Supporting those would require the chunked/streaming SigV4 signing
+// flow, which is left for a follow-up if it turns out to be needed.
+type S3UploadConfig struct {
+	Endpoint string
+	Bucket   string
+	Region   string
+
+	AccessKeyIDFile     string
+	SecretAccessKeyFile string
+}
+
+const (
+	s3UploadPending      = "Pending"
+	s3UploadInProgress   = "InProgress"
+	s3UploadComplete     = "Complete"
+	s3UploadFailed       = "Failed"
+	internalS3UploadPath = "/internal/s3upload"
+
+	defaultS3Region = "us-east-1"
+)
+
+// s3UploadStatus mirrors kubevirt.io/api/export/v1alpha1.VirtualMachineExportS3UploadStatus. It
+// is kept as a separate type rather than importing the API package, consistent with this
+// package's existing internal status types (artifactMetadata, volumeProgress), which the
+// controller translates into API types rather than the exporter pod depending on the API.
+type s3UploadStatus struct {
+	Phase         string `json:"phase"`
+	BytesUploaded int64  `json:"bytesUploaded,omitempty"`
+	ObjectUrl     string `json:"objectUrl,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// s3UploadStatusStore tracks s3UploadStatus by volume name.
+type s3UploadStatusStore struct {
+	mu       sync.Mutex
+	byVolume map[string]s3UploadStatus
+}
+
+func newS3UploadStatusStore() *s3UploadStatusStore {
+	return &s3UploadStatusStore{byVolume: make(map[string]s3UploadStatus)}
+}
+
+func (s *s3UploadStatusStore) set(name string, status s3UploadStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byVolume[name] = status
+}
+
+func (s *s3UploadStatusStore) snapshot() map[string]s3UploadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]s3UploadStatus, len(s.byVolume))
+	for name, status := range s.byVolume {
+		result[name] = status
+	}
+	return result
+}
+
+func s3UploadHandler(store *s3UploadStatusStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(store.snapshot()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+// progressReader wraps an io.Reader to report every byte read from it to an s3UploadStatusStore,
+// so that an in-progress upload's BytesUploaded can be polled before it completes.
+type progressReader struct {
+	io.Reader
+	name  string
+	store *s3UploadStatusStore
+	n     int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.n += int64(n)
+		r.store.set(r.name, s3UploadStatus{Phase: s3UploadInProgress, BytesUploaded: r.n})
+	}
+	return n, err
+}
+
+// computeS3Uploads uploads the raw artifact of every volume that has one to config's S3-compatible
+// object store, recording progress and the outcome of each upload in store. It returns once every
+// upload has finished, successfully or not; callers are expected to run it in a goroutine and poll
+// store rather than wait for it.
+func computeS3Uploads(config *S3UploadConfig, volumes []VolumeInfo, store *s3UploadStatusStore) {
+	accessKeyID, err := getToken(config.AccessKeyIDFile)
+	if err != nil {
+		log.Log.Reason(err).Error("error reading S3 access key ID")
+		return
+	}
+	secretAccessKey, err := getToken(config.SecretAccessKeyFile)
+	if err != nil {
+		log.Log.Reason(err).Error("error reading S3 secret access key")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, vi := range volumes {
+		if vi.RawURI == "" {
+			continue
+		}
+		vi := vi
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uploadVolume(config, accessKeyID, secretAccessKey, vi, store)
+		}()
+	}
+	wg.Wait()
+}
+
+func uploadVolume(config *S3UploadConfig, accessKeyID, secretAccessKey string, vi VolumeInfo, store *s3UploadStatusStore) {
+	store.set(vi.Name, s3UploadStatus{Phase: s3UploadInProgress})
+
+	f, err := openDiskImage(vi.Path)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error opening %s to upload volume %s to S3", vi.Path, vi.Name)
+		store.set(vi.Name, s3UploadStatus{Phase: s3UploadFailed, Error: err.Error()})
+		return
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		log.Log.Reason(err).Errorf("error statting %s to upload volume %s to S3", vi.Path, vi.Name)
+		store.set(vi.Name, s3UploadStatus{Phase: s3UploadFailed, Error: err.Error()})
+		return
+	}
+
+	key := vi.Name + ".img"
+	objectUrl, err := putObject(config, accessKeyID, secretAccessKey, key, &progressReader{Reader: f, name: vi.Name, store: store}, fi.Size())
+	if err != nil {
+		log.Log.Reason(err).Errorf("error uploading volume %s to S3", vi.Name)
+		store.set(vi.Name, s3UploadStatus{Phase: s3UploadFailed, Error: err.Error()})
+		return
+	}
+
+	store.set(vi.Name, s3UploadStatus{Phase: s3UploadComplete, BytesUploaded: fi.Size(), ObjectUrl: objectUrl})
+}
+
+// putObject uploads body, of the given size, to key in config's bucket, signing the request with
+// AWS SigV4 using the UNSIGNED-PAYLOAD payload hash so that body never needs to be buffered or
+// hashed up front. It returns the URL of the uploaded object.
+func putObject(config *S3UploadConfig, accessKeyID, secretAccessKey, key string, body io.Reader, size int64) (string, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	region := config.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	objectUrl := fmt.Sprintf("%s://%s/%s/%s", endpoint.Scheme, endpoint.Host, config.Bucket, key)
+	req, err := http.NewRequest(http.MethodPut, objectUrl, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Length", strconv.FormatInt(size, 10))
+
+	if err := signS3Request(req, accessKeyID, secretAccessKey, region, time.Now()); err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("S3 upload of %s failed with status %s", key, resp.Status)
+	}
+
+	return objectUrl, nil
+}
+
+// signS3Request adds the Authorization, X-Amz-Date and X-Amz-Content-Sha256 headers needed to
+// authenticate req as an AWS Signature Version 4 request, using the UNSIGNED-PAYLOAD payload
+// hash. See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func signS3Request(req *http.Request, accessKeyID, secretAccessKey, region string, now time.Time) error {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	dateStamp := now.UTC().Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:UNSIGNED-PAYLOAD\nx-amz-date:%s\n", req.URL.Host, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/s3/aws4_request", dateStamp, region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	signingKey := s3SigningKey(secretAccessKey, dateStamp, region)
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, credentialScope, signedHeaders, signature))
+
+	return nil
+}
+
+func s3SigningKey(secretAccessKey, dateStamp, region string) []byte {
+	kDate := hmacSHA256([]byte("AWS4"+secretAccessKey), dateStamp)
+	kRegion := hmacSHA256(kDate, region)
+	kService := hmacSHA256(kRegion, "s3")
+	return hmacSHA256(kService, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(data string) string {
+	h := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(h[:])
+}
+
+// canonicalURI URI-encodes every segment of path except the separating slashes, as required by
+// the SigV4 canonical request format.
+func canonicalURI(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,74 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import "sync/atomic"
+
+// TokenScope narrows what one additional download token grants access to, letting one export
+// hand out narrowly-scoped credentials to different consumers instead of every additional token
+// being able to download everything the primary token can. Scoping is limited to volume names and
+// a request budget for now: the exporter doesn't track which download format an in-flight request
+// is for at the point tokens are checked, so per-format scoping is left for a follow-up.
+type TokenScope struct {
+	// TokenFile is the path to the file holding the token value, read fresh on every request the
+	// same way TokenFile and AdditionalTokenFiles are, so a scoped token secret can be rotated
+	// without restarting the exporter pod.
+	TokenFile string
+
+	// Volumes, if non-empty, restricts this token to only these volume names. If empty, the
+	// token can download any volume, but, regardless of this field, it can never authenticate a
+	// request for the OVA, the manifest, or the "all volumes" bundle, since those aren't scoped
+	// to a single volume.
+	Volumes []string
+
+	// MaxReads, if set, is how many requests this token may authenticate before being rejected.
+	MaxReads *int32
+
+	// reads counts how many requests this token has already authenticated. It is only ever
+	// mutated through atomic operations, since it is read and updated concurrently by every
+	// matching request.
+	reads int32
+}
+
+// allowsVolume reports whether this scope permits a request for volume, which is "" for the OVA,
+// manifest, and "all volumes" bundle endpoints, none of which any scoped token can authenticate.
+func (s *TokenScope) allowsVolume(volume string) bool {
+	if volume == "" {
+		return false
+	}
+	if len(s.Volumes) == 0 {
+		return true
+	}
+	for _, v := range s.Volumes {
+		if v == volume {
+			return true
+		}
+	}
+	return false
+}
+
+// consumeRead records one more use of this scope's token and reports whether it was still within
+// MaxReads, if set.
+func (s *TokenScope) consumeRead() bool {
+	if s.MaxReads == nil {
+		return true
+	}
+	return atomic.AddInt32(&s.reads, 1) <= *s.MaxReads
+}
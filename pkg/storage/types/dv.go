@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 
+	k8sv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 
@@ -63,6 +64,10 @@ func GetCloneSourceWithInformers(vm *virtv1.VirtualMachine, dvSpec *cdiv1.DataVo
 
 		ds := obj.(*cdiv1.DataSource)
 
+		if !IsDataSourceReady(ds) {
+			return nil, fmt.Errorf("DataSource %s/%s is not ready yet, the golden image import may still be in progress", ns, dvSpec.SourceRef.Name)
+		}
+
 		if ds.Spec.Source.PVC != nil {
 			cloneSource = &CloneSource{
 				Namespace: ds.Spec.Source.PVC.Namespace,
@@ -78,6 +83,18 @@ func GetCloneSourceWithInformers(vm *virtv1.VirtualMachine, dvSpec *cdiv1.DataVo
 	return cloneSource, nil
 }
 
+// IsDataSourceReady reports whether a CDI DataSource's Ready condition is set to true, i.e. its
+// source (for example a golden image PVC kept up to date by a DataImportCron) has finished
+// importing and is safe to clone from.
+func IsDataSourceReady(ds *cdiv1.DataSource) bool {
+	for _, cond := range ds.Status.Conditions {
+		if cond.Type == cdiv1.DataSourceReady {
+			return cond.Status == k8sv1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func GetCloneSource(ctx context.Context, client kubecli.KubevirtClient, vm *virtv1.VirtualMachine, dvSpec *cdiv1.DataVolumeSpec) (*CloneSource, error) {
 	var cloneSource *CloneSource
 	if dvSpec.Source != nil && dvSpec.Source.PVC != nil {
@@ -100,6 +117,10 @@ func GetCloneSource(ctx context.Context, client kubecli.KubevirtClient, vm *virt
 			return nil, err
 		}
 
+		if !IsDataSourceReady(ds) {
+			return nil, fmt.Errorf("DataSource %s/%s is not ready yet, the golden image import may still be in progress", ns, dvSpec.SourceRef.Name)
+		}
+
 		if ds.Spec.Source.PVC != nil {
 			cloneSource = &CloneSource{
 				Namespace: ds.Spec.Source.PVC.Namespace,
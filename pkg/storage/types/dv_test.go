@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/golang/mock/gomock"
+	k8sv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -117,6 +118,14 @@ var _ = Describe("DataVolume utils test", func() {
 						},
 					},
 				},
+				Status: cdiv1.DataSourceStatus{
+					Conditions: []cdiv1.DataSourceCondition{
+						{
+							Type:           cdiv1.DataSourceReady,
+							ConditionState: cdiv1.ConditionState{Status: k8sv1.ConditionTrue},
+						},
+					},
+				},
 			}
 
 			dv := &cdiv1.DataVolumeSpec{
@@ -142,5 +151,34 @@ var _ = Describe("DataVolume utils test", func() {
 			Entry("sourceRef namespace not specified", "", "bar", "bar"),
 			Entry("everything specified", "foo", "bar", "bar"),
 		)
+
+		It("should error if DataSource is not ready yet", func() {
+			sourceRefName := "sourceRef"
+
+			ref := &cdiv1.DataSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: vm.Namespace,
+					Name:      sourceRefName,
+				},
+				Spec: cdiv1.DataSourceSpec{
+					Source: cdiv1.DataSourceSource{
+						PVC: &cdiv1.DataVolumeSourcePVC{
+							Name: "name",
+						},
+					},
+				},
+			}
+
+			dv := &cdiv1.DataVolumeSpec{
+				SourceRef: &cdiv1.DataVolumeSourceRef{
+					Kind: "DataSource",
+					Name: sourceRefName,
+				},
+			}
+
+			cs, err := GetCloneSource(context.TODO(), createClient(ref), vm, dv)
+			Expect(err).To(HaveOccurred())
+			Expect(cs).To(BeNil())
+		})
 	})
 })
@@ -71,6 +71,7 @@ const (
 	pvcAccessModeErr             = "pvc access mode can't be read only"
 	pvcSizeErrFmt                = "pvc size [%s] should be bigger then [%s]"
 	memoryDumpNameConflictErr    = "can't request memory dump for pvc [%s] while pvc [%s] is still associated as the memory dump pvc"
+	backupCheckpointVolumeErrFmt = "volume [%s] not found in the VirtualMachineInstance"
 	defaultProfilerComponentPort = 8443
 
 	configName         = "config"
@@ -1418,7 +1419,7 @@ func (app *SubresourceAPIApp) validateMemoryDumpClaim(vmi *v1.VirtualMachineInst
 		log.Log.Object(vmi).V(3).Infof(fsOverheadMsg)
 		expectedPvcSize, overheadErr = storagetypes.GetSizeIncludingGivenOverhead(expectedMemoryDumpSize, filesystemOverhead)
 	} else {
-		expectedPvcSize, overheadErr = storagetypes.GetSizeIncludingFSOverhead(expectedMemoryDumpSize, pvc.Spec.StorageClassName, pvc.Spec.VolumeMode, cdiConfig)
+		expectedPvcSize, overheadErr = storagetypes.GetSizeIncludingFSOverhead(expectedMemoryDumpSize, pvc.Spec.StorageClassName, pvc.Spec.VolumeMode, cdiConfig, app.clusterConfig.GetConfig().FilesystemOverhead)
 	}
 	if overheadErr != nil {
 		return errors.NewInternalError(overheadErr)
@@ -1539,3 +1540,123 @@ func (app *SubresourceAPIApp) RemoveMemoryDumpVMRequestHandler(request *restful.
 
 	response.WriteHeader(http.StatusAccepted)
 }
+
+func (app *SubresourceAPIApp) validateBackupCheckpointRequest(vmi *v1.VirtualMachineInstance, checkpointOpts *v1.BackupCheckpointOptions) *errors.StatusError {
+	if !vmi.IsRunning() {
+		return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf(vmiNotRunning))
+	}
+
+	for _, volume := range vmi.Spec.Volumes {
+		if volume.Name == checkpointOpts.VolumeName {
+			return nil
+		}
+	}
+
+	return errors.NewBadRequest(fmt.Sprintf(backupCheckpointVolumeErrFmt, checkpointOpts.VolumeName))
+}
+
+func generateVMBackupCheckpointPatch(vm *v1.VirtualMachine, checkpointOpts *v1.BackupCheckpointOptions) (string, error) {
+	now := k8smetav1.Now()
+	checkpoint := v1.VirtualMachineVolumeBackupCheckpoint{
+		VolumeName:     checkpointOpts.VolumeName,
+		CheckpointName: checkpointOpts.CheckpointName,
+		CreationTime:   &now,
+	}
+
+	vmCopy := vm.DeepCopy()
+	found := false
+	for i, c := range vmCopy.Status.VolumeBackupCheckpoints {
+		if c.VolumeName == checkpoint.VolumeName {
+			vmCopy.Status.VolumeBackupCheckpoints[i] = checkpoint
+			found = true
+			break
+		}
+	}
+	if !found {
+		vmCopy.Status.VolumeBackupCheckpoints = append(vmCopy.Status.VolumeBackupCheckpoints, checkpoint)
+	}
+
+	oldJson, err := json.Marshal(vm.Status.VolumeBackupCheckpoints)
+	if err != nil {
+		return "", err
+	}
+	newJson, err := json.Marshal(vmCopy.Status.VolumeBackupCheckpoints)
+	if err != nil {
+		return "", err
+	}
+
+	test := fmt.Sprintf(`{ "op": "test", "path": "/status/volumeBackupCheckpoints", "value": %s}`, string(oldJson))
+	update := fmt.Sprintf(`{ "op": "replace", "path": "/status/volumeBackupCheckpoints", "value": %s}`, string(newJson))
+	patch := fmt.Sprintf("[%s, %s]", test, update)
+
+	return patch, nil
+}
+
+// BackupCheckpointVMRequestHandler records a backup checkpoint against one of the
+// VirtualMachine's volumes. It is a bookkeeping primitive for backup vendors: KubeVirt already
+// lets a vendor quiesce the guest via the freeze/unfreeze subresources, and this subresource
+// remembers the checkpoint name the vendor took the backup against so that the vendor's next
+// incremental backup can be correlated with it. It does not track which blocks changed between
+// checkpoints; vendors that need changed-block tracking must still compute it themselves.
+func (app *SubresourceAPIApp) BackupCheckpointVMRequestHandler(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+	namespace := request.PathParameter("namespace")
+
+	checkpointOpts := &v1.BackupCheckpointOptions{}
+	if request.Request.Body == nil {
+		writeError(errors.NewBadRequest("Request with no body"), response)
+		return
+	}
+	defer request.Request.Body.Close()
+	err := yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(checkpointOpts)
+	switch err {
+	case io.EOF, nil:
+		break
+	default:
+		writeError(errors.NewBadRequest(fmt.Sprintf(unmarshalRequestErrFmt, err)), response)
+		return
+	}
+
+	if checkpointOpts.VolumeName == "" || checkpointOpts.CheckpointName == "" {
+		writeError(errors.NewBadRequest("Backup checkpoint requires volumeName and checkpointName to be set"), response)
+		return
+	}
+
+	vm, statErr := app.fetchVirtualMachine(name, namespace)
+	if statErr != nil {
+		writeError(statErr, response)
+		return
+	}
+
+	vmi, statErr := app.FetchVirtualMachineInstance(namespace, name)
+	if statErr != nil {
+		writeError(statErr, response)
+		return
+	}
+
+	if statErr := app.validateBackupCheckpointRequest(vmi, checkpointOpts); statErr != nil {
+		writeError(statErr, response)
+		return
+	}
+
+	patch, err := generateVMBackupCheckpointPatch(vm, checkpointOpts)
+	if err != nil {
+		writeError(errors.NewConflict(v1.Resource("virtualmachine"), name, err), response)
+		return
+	}
+
+	log.Log.Object(vm).V(4).Infof(patchingVMStatusFmt, patch)
+	if err := app.statusUpdater.PatchStatus(vm, types.JSONPatchType, []byte(patch), &k8smetav1.PatchOptions{DryRun: checkpointOpts.DryRun}); err != nil {
+		log.Log.Object(vm).V(1).Errorf("unable to patch vm status: %v", err)
+		if errors.IsInvalid(err) {
+			if statErr, ok := err.(*errors.StatusError); ok {
+				writeError(statErr, response)
+				return
+			}
+		}
+		writeError(errors.NewInternalError(fmt.Errorf("unable to patch vm status: %v", err)), response)
+		return
+	}
+
+	response.WriteHeader(http.StatusAccepted)
+}
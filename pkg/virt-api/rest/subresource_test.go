@@ -1345,6 +1345,81 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 		)
 	})
 
+	Context("Backup checkpoint Subresource api", func() {
+		newBackupCheckpointBody := func(opts *v1.BackupCheckpointOptions) io.ReadCloser {
+			reqJson, _ := json.Marshal(opts)
+			return &readCloserWrapper{bytes.NewReader(reqJson)}
+		}
+
+		BeforeEach(func() {
+			request.PathParameters()["name"] = testVMName
+			request.PathParameters()["namespace"] = k8smetav1.NamespaceDefault
+		})
+
+		DescribeTable("With backup checkpoint request", func(checkpointOpts *v1.BackupCheckpointOptions, statusCode int, vmiRunning bool) {
+			request.Request.Body = newBackupCheckpointBody(checkpointOpts)
+
+			vm := newMinimalVM(request.PathParameter("name"))
+			vm.Namespace = k8smetav1.NamespaceDefault
+
+			vmi := api.NewMinimalVMI(testVMIName)
+			vmi.Spec.Volumes = []v1.Volume{{Name: "vol1"}}
+			if vmiRunning {
+				vmi.Status.Phase = v1.Running
+			}
+
+			vmClient.EXPECT().Get(vm.Name, &k8smetav1.GetOptions{}).Return(vm, nil).AnyTimes()
+			vmiClient.EXPECT().Get(vm.Name, &k8smetav1.GetOptions{}).Return(vmi, nil).AnyTimes()
+			vmClient.EXPECT().PatchStatus(vm.Name, types.JSONPatchType, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(name string, patchType types.PatchType, body interface{}, opts *k8smetav1.PatchOptions) (interface{}, interface{}) {
+					return vm, nil
+				}).AnyTimes()
+
+			app.BackupCheckpointVMRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(statusCode))
+		},
+			Entry("VM with a valid backup checkpoint request should succeed", &v1.BackupCheckpointOptions{
+				VolumeName:     "vol1",
+				CheckpointName: "checkpoint1",
+			}, http.StatusAccepted, true),
+			Entry("VM with a backup checkpoint request for a non existing volume should fail", &v1.BackupCheckpointOptions{
+				VolumeName:     "doesnotexist",
+				CheckpointName: "checkpoint1",
+			}, http.StatusBadRequest, true),
+			Entry("VM with a backup checkpoint request while vmi not running should fail", &v1.BackupCheckpointOptions{
+				VolumeName:     "vol1",
+				CheckpointName: "checkpoint1",
+			}, http.StatusConflict, false),
+			Entry("VM with a backup checkpoint request missing checkpointName should fail", &v1.BackupCheckpointOptions{
+				VolumeName: "vol1",
+			}, http.StatusBadRequest, true),
+		)
+
+		DescribeTable("Should generate expected vm patch", func(checkpointOpts *v1.BackupCheckpointOptions, existing []v1.VirtualMachineVolumeBackupCheckpoint, expectedLen int) {
+			vm := newMinimalVM(request.PathParameter("name"))
+			vm.Namespace = k8smetav1.NamespaceDefault
+			vm.Status.VolumeBackupCheckpoints = existing
+
+			patch, err := generateVMBackupCheckpointPatch(vm, checkpointOpts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(patch).To(ContainSubstring(checkpointOpts.CheckpointName))
+
+			var patched []map[string]interface{}
+			Expect(json.Unmarshal([]byte(patch), &patched)).To(Succeed())
+			Expect(patched).To(HaveLen(2))
+		},
+			Entry("add a checkpoint for a volume with no existing checkpoint",
+				&v1.BackupCheckpointOptions{VolumeName: "vol1", CheckpointName: "checkpoint1"},
+				nil,
+				1),
+			Entry("replace the checkpoint for a volume that already has one",
+				&v1.BackupCheckpointOptions{VolumeName: "vol1", CheckpointName: "checkpoint2"},
+				[]v1.VirtualMachineVolumeBackupCheckpoint{{VolumeName: "vol1", CheckpointName: "checkpoint1"}},
+				1),
+		)
+	})
+
 	Context("Subresource api - error handling for StartVMRequestHandler", func() {
 		BeforeEach(func() {
 			request.PathParameters()["name"] = testVMName
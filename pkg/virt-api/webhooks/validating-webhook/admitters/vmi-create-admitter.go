@@ -42,8 +42,14 @@ import (
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/pkg/virt-handler/seccomp"
 )
 
+// seccompLocalhostProfilePrefix restricts seccompProfile.localhostProfile to profiles that
+// virt-handler itself installed onto the node, so a VMI can't point qemu at an arbitrary,
+// attacker-controlled file under the kubelet's seccomp profile root.
+const seccompLocalhostProfilePrefix = seccomp.ProfilesDir + "/"
+
 const requiredFieldFmt = "%s is a required field"
 
 const (
@@ -114,9 +120,48 @@ func (admitter *VMICreateAdmitter) Admit(ar *admissionv1.AdmissionReview) *admis
 
 	reviewResponse := admissionv1.AdmissionResponse{}
 	reviewResponse.Allowed = true
+	if admitter.ClusterConfig.NonRootEnabled() {
+		reviewResponse.Warnings = warnAboutRootRequiredFeatures(&vmi.Spec)
+	}
 	return &reviewResponse
 }
 
+// warnAboutRootRequiredFeatures returns admission warnings for VirtualMachineInstance
+// features that still force virt-launcher to run as root even when the NonRoot
+// feature gate is enabled, so users are aware their non-root request is only
+// partially honored.
+func warnAboutRootRequiredFeatures(spec *v1.VirtualMachineInstanceSpec) []string {
+	var warnings []string
+
+	for _, volume := range spec.Volumes {
+		if (volume.DataVolume != nil && volume.DataVolume.Hotpluggable) ||
+			(volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.Hotpluggable) {
+			warnings = append(warnings, "hotplug volumes still require virt-launcher to run as root")
+			break
+		}
+	}
+
+	for _, iface := range spec.Domain.Devices.Interfaces {
+		if iface.SRIOV != nil {
+			warnings = append(warnings, "SR-IOV interfaces still require virt-launcher to run as root")
+			break
+		}
+	}
+
+	for _, fs := range spec.Domain.Devices.Filesystems {
+		if fs.Virtiofs != nil {
+			warnings = append(warnings, "virtiofs filesystems still require virt-launcher to run as root")
+			break
+		}
+	}
+
+	if len(spec.Domain.Devices.HostDevices) > 0 || len(spec.Domain.Devices.GPUs) > 0 {
+		warnings = append(warnings, "host devices and GPUs still require virt-launcher to run as root")
+	}
+
+	return warnings
+}
+
 func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
 	var causes []metav1.StatusCause
 	volumeNameMap := make(map[string]*v1.Volume)
@@ -206,6 +251,8 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 	}
 	causes = append(causes, validatePodDNSConfig(spec.DNSConfig, &spec.DNSPolicy, field.Child("dnsConfig"))...)
 	causes = append(causes, validateLiveMigration(field, spec, config)...)
+	causes = append(causes, validateSelinuxContext(field, spec, config)...)
+	causes = append(causes, validateSeccompProfile(field, spec)...)
 	causes = append(causes, validateGPUsWithPassthroughEnabled(field, spec, config)...)
 	causes = append(causes, validateFilesystemsWithVirtIOFSEnabled(field, spec, config)...)
 	causes = append(causes, validateHostDevicesWithPassthroughEnabled(field, spec, config)...)
@@ -794,6 +841,44 @@ func validateGPUsWithPassthroughEnabled(field *k8sfield.Path, spec *v1.VirtualMa
 	return causes
 }
 
+func validateSeccompProfile(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec) (causes []metav1.StatusCause) {
+	seccomp := spec.SeccompProfile
+	if seccomp == nil || seccomp.Type != k8sv1.SeccompProfileTypeLocalhost {
+		return causes
+	}
+
+	profileField := field.Child("seccompProfile", "localhostProfile")
+	if seccomp.LocalhostProfile == nil || *seccomp.LocalhostProfile == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s is required when seccompProfile type is Localhost", profileField.String()),
+			Field:   profileField.String(),
+		})
+		return causes
+	}
+
+	if !strings.HasPrefix(*seccomp.LocalhostProfile, seccompLocalhostProfilePrefix) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must reference a profile installed by KubeVirt, i.e. start with %q", profileField.String(), seccompLocalhostProfilePrefix),
+			Field:   profileField.String(),
+		})
+	}
+
+	return causes
+}
+
+func validateSelinuxContext(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) (causes []metav1.StatusCause) {
+	if spec.SelinuxContext != "" && !config.SELinuxCustomTypeEnabled() {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("SELinuxCustomType feature gate is not enabled in kubevirt-config"),
+			Field:   field.Child("selinuxContext").String(),
+		})
+	}
+	return causes
+}
+
 func validateFilesystemsWithVirtIOFSEnabled(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) (causes []metav1.StatusCause) {
 	if spec.Domain.Devices.Filesystems != nil && !config.VirtiofsEnabled() {
 		causes = append(causes, metav1.StatusCause{
@@ -2370,15 +2455,25 @@ func validateDisks(field *k8sfield.Path, disks []v1.Disk) []metav1.StatusCause {
 			})
 		}
 
-		if disk.IO != "" && disk.IO != v1.IODefault && disk.IO != v1.IONative && disk.IO != v1.IOThreads {
+		if disk.IO != "" && disk.IO != v1.IODefault && disk.IO != v1.IONative && disk.IO != v1.IOThreads && disk.IO != v1.IOUring {
 			field := field.Child("domain", "devices", "disks").Index(idx).Child("io").String()
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueNotSupported,
-				Message: fmt.Sprintf("Disk IO mode for %s is not supported. Supported modes are: native, threads, default.", field),
+				Message: fmt.Sprintf("Disk IO mode for %s is not supported. Supported modes are: native, threads, default, io_uring.", field),
 				Field:   field,
 			})
 		}
 
+		// A shareable disk is expected to be attached concurrently to multiple VMIs, so host page
+		// caching must stay off to avoid each VMI seeing a stale, locally cached copy of the data.
+		if disk.Shareable != nil && *disk.Shareable && disk.Cache == v1.CacheWriteBack {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is shareable and therefore must not use the writeback cache mode", field.Index(idx).String()),
+				Field:   field.Index(idx).Child("cache").String(),
+			})
+		}
+
 		// Verify disk and volume name can be a valid container name since disk
 		// name can become a container name which will fail to schedule if invalid
 		errs := validation.IsDNS1123Label(disk.Name)
@@ -1191,6 +1191,14 @@ var _ = Describe("Validating VM Admitter", func() {
 						},
 					},
 				},
+				Status: cdiv1.DataSourceStatus{
+					Conditions: []cdiv1.DataSourceCondition{
+						{
+							Type:           cdiv1.DataSourceReady,
+							ConditionState: cdiv1.ConditionState{Status: k8sv1.ConditionTrue},
+						},
+					},
+				},
 			}
 
 			vm := &v1.VirtualMachine{
@@ -28,6 +28,7 @@ import (
 
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "kubevirt.io/api/core/v1"
 )
@@ -117,6 +118,181 @@ func (c *ClusterConfig) GetMigrationConfiguration() *v1.MigrationConfiguration {
 	return c.GetConfig().MigrationConfiguration
 }
 
+func (c *ClusterConfig) GetVMExportDeadline() *metav1.Duration {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.Deadline
+}
+
+func (c *ClusterConfig) GetVMExportNamespaceQuota() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.MaxConcurrentNamespaceExports
+}
+
+func (c *ClusterConfig) GetVMExportTTL() *metav1.Duration {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.TTLDuration
+}
+
+func (c *ClusterConfig) GetVMExportPodResourceRequirements() *k8sv1.ResourceRequirements {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PodResourceRequirements
+}
+
+func (c *ClusterConfig) GetVMExportIngressSelector() *metav1.LabelSelector {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.IngressSelector
+}
+
+func (c *ClusterConfig) GetVMExportRequeueInterval() *metav1.Duration {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.RequeueInterval
+}
+
+func (c *ClusterConfig) GetVMExportPodTTLAfterFinished() *metav1.Duration {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PodTTLAfterFinished
+}
+
+func (c *ClusterConfig) GetVMExportReplicas() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.Replicas
+}
+
+func (c *ClusterConfig) GetVMExportPodRunAsUser() *int64 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PodRunAsUser
+}
+
+func (c *ClusterConfig) GetVMExportPodFSGroup() *int64 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PodFSGroup
+}
+
+func (c *ClusterConfig) GetVMExportZstdCompressionLevel() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.ZstdCompressionLevel
+}
+
+func (c *ClusterConfig) GetVMExportGzipCompressionLevel() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.GzipCompressionLevel
+}
+
+func (c *ClusterConfig) GetVMExportShutdownGracePeriod() *metav1.Duration {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.ShutdownGracePeriod
+}
+
+func (c *ClusterConfig) GetVMExportPerConnectionBandwidthLimit() *resource.Quantity {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PerConnectionBandwidthLimit
+}
+
+func (c *ClusterConfig) GetVMExportTotalBandwidthLimit() *resource.Quantity {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.TotalBandwidthLimit
+}
+
+func (c *ClusterConfig) GetVMExportMaxConcurrentDownloads() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.MaxConcurrentDownloads
+}
+
+func (c *ClusterConfig) GetVMExportPerClientIPRequestLimit() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PerClientIPRequestLimit
+}
+
+func (c *ClusterConfig) GetVMExportPerClientIPRequestBurst() *int32 {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PerClientIPRequestBurst
+}
+
+func (c *ClusterConfig) GetVMExportPrecomputeFormats() []string {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.PrecomputeFormats
+}
+
+func (c *ClusterConfig) GetVMExportEnableNBD() *bool {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.EnableNBD
+}
+
+func (c *ClusterConfig) GetVMExportScratchSpaceSize() *resource.Quantity {
+	exportConfiguration := c.GetConfig().ExportConfiguration
+	if exportConfiguration == nil {
+		return nil
+	}
+	return exportConfiguration.ScratchSpaceSize
+}
+
+// GetTLSConfiguration returns the cluster-wide TLS crypto policy, or nil if the KubeVirt CR does
+// not set one, meaning callers should fall back to their own default minimum TLS version and
+// cipher suite selection.
+func (c *ClusterConfig) GetTLSConfiguration() *v1.TLSConfiguration {
+	return c.GetConfig().TLSConfiguration
+}
+
 func (c *ClusterConfig) GetImagePullPolicy() (policy k8sv1.PullPolicy) {
 	return c.GetConfig().ImagePullPolicy
 }
@@ -308,17 +484,17 @@ func (c *ClusterConfig) GetVirtLauncherVerbosity() uint {
 	return logConf.VirtLauncher
 }
 
-//GetMinCPUModel return minimal cpu which is used in node-labeller
+// GetMinCPUModel return minimal cpu which is used in node-labeller
 func (c *ClusterConfig) GetMinCPUModel() string {
 	return c.GetConfig().MinCPUModel
 }
 
-//GetObsoleteCPUModels return slice of obsolete cpus which are used in node-labeller
+// GetObsoleteCPUModels return slice of obsolete cpus which are used in node-labeller
 func (c *ClusterConfig) GetObsoleteCPUModels() map[string]bool {
 	return c.GetConfig().ObsoleteCPUModels
 }
 
-//GetClusterCPUArch return the CPU architecture in ClusterConfig
+// GetClusterCPUArch return the CPU architecture in ClusterConfig
 func (c *ClusterConfig) GetClusterCPUArch() string {
 	return c.cpuArch
 }
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 
 	"kubevirt.io/kubevirt/pkg/virt-controller/watch/topology"
@@ -89,8 +90,8 @@ const (
 // Libvirt needs roughly 10 seconds to start.
 const LibvirtStartupDelay = 10
 
-//These perfixes for node feature discovery, are used in a NodeSelector on the pod
-//to match a VirtualMachineInstance CPU model(Family) and/or features to nodes that support them.
+// These perfixes for node feature discovery, are used in a NodeSelector on the pod
+// to match a VirtualMachineInstance CPU model(Family) and/or features to nodes that support them.
 const NFD_CPU_MODEL_PREFIX = "cpu-model.node.kubevirt.io/"
 const NFD_CPU_FEATURE_PREFIX = "cpu-feature.node.kubevirt.io/"
 const NFD_KVM_INFO_PREFIX = "hyperv.node.kubevirt.io/"
@@ -348,6 +349,16 @@ func sysprepVolumeSource(sysprepVolume v1.SysprepSource) (k8sv1.VolumeSource, er
 	return k8sv1.VolumeSource{}, fmt.Errorf(errorStr)
 }
 
+// selinuxLauncherType returns the SELinux type to use for the VMI's compute container, preferring
+// the VMI's own spec.SelinuxContext override over the cluster-wide default when the
+// SELinuxCustomType feature gate is enabled.
+func (t *templateService) selinuxLauncherType(vmi *v1.VirtualMachineInstance) string {
+	if vmi.Spec.SelinuxContext != "" && t.clusterConfig.SELinuxCustomTypeEnabled() {
+		return vmi.Spec.SelinuxContext
+	}
+	return t.clusterConfig.GetSELinuxLauncherType()
+}
+
 func (t *templateService) GetLauncherImage() string {
 	return t.launcherImage
 }
@@ -523,10 +534,10 @@ func (t *templateService) renderLaunchManifest(vmi *v1.VirtualMachineInstance, i
 	// Make sure the compute container is always the first since the mutating webhook shipped with the sriov operator
 	// for adding the requested resources to the pod will add them to the first container of the list
 	containers := []k8sv1.Container{compute}
-	containersDisks := containerdisk.GenerateContainers(vmi, imageIDs, containerDisks, virtBinDir)
+	containersDisks := containerdisk.GenerateContainers(vmi, imageIDs, containerDisks, virtBinDir, t.clusterConfig.ContainerDiskCacheEnabled())
 	containers = append(containers, containersDisks...)
 
-	kernelBootContainer := containerdisk.GenerateKernelBootContainer(vmi, imageIDs, containerDisks, virtBinDir)
+	kernelBootContainer := containerdisk.GenerateKernelBootContainer(vmi, imageIDs, containerDisks, virtBinDir, t.clusterConfig.ContainerDiskCacheEnabled())
 	if kernelBootContainer != nil {
 		log.Log.Object(vmi).Infof("kernel boot container generated")
 		containers = append(containers, *kernelBootContainer)
@@ -596,9 +607,9 @@ func (t *templateService) renderLaunchManifest(vmi *v1.VirtualMachineInstance, i
 				userId).Render(initContainerCommand))
 
 		// this causes containerDisks to be pre-pulled before virt-launcher starts.
-		initContainers = append(initContainers, containerdisk.GenerateInitContainers(vmi, imageIDs, containerDisks, virtBinDir)...)
+		initContainers = append(initContainers, containerdisk.GenerateInitContainers(vmi, imageIDs, containerDisks, virtBinDir, t.clusterConfig.ContainerDiskCacheEnabled())...)
 
-		kernelBootInitContainer := containerdisk.GenerateKernelBootInitContainer(vmi, imageIDs, containerDisks, virtBinDir)
+		kernelBootInitContainer := containerdisk.GenerateKernelBootInitContainer(vmi, imageIDs, containerDisks, virtBinDir, t.clusterConfig.ContainerDiskCacheEnabled())
 		if kernelBootInitContainer != nil {
 			initContainers = append(initContainers, *kernelBootInitContainer)
 		}
@@ -647,7 +658,7 @@ func (t *templateService) renderLaunchManifest(vmi *v1.VirtualMachineInstance, i
 	}
 
 	// If an SELinux type was specified, use that--otherwise don't set an SELinux type
-	selinuxType := t.clusterConfig.GetSELinuxLauncherType()
+	selinuxType := t.selinuxLauncherType(vmi)
 	if selinuxType != "" {
 		alignPodMultiCategorySecurity(&pod, selinuxType)
 	}
@@ -747,6 +758,10 @@ func (t *templateService) newContainerSpecRenderer(vmi *v1.VirtualMachineInstanc
 		computeContainerOpts = append(computeContainerOpts, WithLivelinessProbe(vmi))
 	}
 
+	if vmi.Spec.SeccompProfile != nil {
+		computeContainerOpts = append(computeContainerOpts, WithSeccompProfile(vmi.Spec.SeccompProfile))
+	}
+
 	const computeContainerName = "compute"
 	containerRenderer := NewContainerSpecRenderer(
 		computeContainerName, t.launcherImage, t.clusterConfig.GetImagePullPolicy(), computeContainerOpts...)
@@ -860,7 +875,7 @@ func (t *templateService) RenderHotplugAttachmentPodTemplate(volumes []*v1.Volum
 					SecurityContext: &k8sv1.SecurityContext{
 						SELinuxOptions: &k8sv1.SELinuxOptions{
 							Level: "s0",
-							Type:  t.clusterConfig.GetSELinuxLauncherType(),
+							Type:  t.selinuxLauncherType(vmi),
 						},
 					},
 					VolumeMounts: []k8sv1.VolumeMount{
@@ -941,7 +956,7 @@ func (t *templateService) RenderHotplugAttachmentPodTemplate(volumes []*v1.Volum
 	return pod, nil
 }
 
-func (t *templateService) RenderHotplugAttachmentTriggerPodTemplate(volume *v1.Volume, ownerPod *k8sv1.Pod, _ *v1.VirtualMachineInstance, pvcName string, isBlock bool, tempPod bool) (*k8sv1.Pod, error) {
+func (t *templateService) RenderHotplugAttachmentTriggerPodTemplate(volume *v1.Volume, ownerPod *k8sv1.Pod, vmi *v1.VirtualMachineInstance, pvcName string, isBlock bool, tempPod bool) (*k8sv1.Pod, error) {
 	zero := int64(0)
 	sharedMount := k8sv1.MountPropagationHostToContainer
 	var command []string
@@ -993,7 +1008,7 @@ func (t *templateService) RenderHotplugAttachmentTriggerPodTemplate(volume *v1.V
 					SecurityContext: &k8sv1.SecurityContext{
 						SELinuxOptions: &k8sv1.SELinuxOptions{
 							Level: "s0",
-							Type:  t.clusterConfig.GetSELinuxLauncherType(),
+							Type:  t.selinuxLauncherType(vmi),
 						},
 					},
 					VolumeMounts: []k8sv1.VolumeMount{
@@ -1052,7 +1067,25 @@ func (t *templateService) RenderHotplugAttachmentTriggerPodTemplate(volume *v1.V
 	return pod, nil
 }
 
+// exporterTerminationGracePeriodSeconds gives an in-flight download enough time to finish
+// draining before the exporter pod is killed, instead of cutting connections off immediately.
+const exporterTerminationGracePeriodSeconds int64 = 130
+
+func exporterProbeHandler() k8sv1.ProbeHandler {
+	return k8sv1.ProbeHandler{
+		HTTPGet: &k8sv1.HTTPGetAction{
+			Scheme: k8sv1.URISchemeHTTPS,
+			Port: intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 8443,
+			},
+			Path: "/internal/progress",
+		},
+	}
+}
+
 func (t *templateService) RenderExporterManifest(vmExport *exportv1.VirtualMachineExport, namePrefix string) *k8sv1.Pod {
+	gracePeriod := exporterTerminationGracePeriodSeconds
 	exporterPod := &k8sv1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-%s", namePrefix, vmExport.Name),
@@ -1069,7 +1102,8 @@ func (t *templateService) RenderExporterManifest(vmExport *exportv1.VirtualMachi
 			},
 		},
 		Spec: k8sv1.PodSpec{
-			RestartPolicy: k8sv1.RestartPolicyNever,
+			RestartPolicy:                 k8sv1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &gracePeriod,
 			Containers: []k8sv1.Container{
 				{
 					Name:            vmExport.Name,
@@ -1085,6 +1119,17 @@ func (t *templateService) RenderExporterManifest(vmExport *exportv1.VirtualMachi
 							},
 						},
 					},
+					ReadinessProbe: &k8sv1.Probe{
+						ProbeHandler:        exporterProbeHandler(),
+						InitialDelaySeconds: 5,
+						PeriodSeconds:       10,
+					},
+					LivenessProbe: &k8sv1.Probe{
+						ProbeHandler:        exporterProbeHandler(),
+						InitialDelaySeconds: 15,
+						PeriodSeconds:       10,
+						FailureThreshold:    5,
+					},
 				},
 			},
 		},
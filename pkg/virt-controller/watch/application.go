@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"kubevirt.io/kubevirt/pkg/monitoring/migration"
+	"kubevirt.io/kubevirt/pkg/monitoring/vmexport"
 
 	clonev1alpha1 "kubevirt.io/api/clone/v1alpha1"
 
@@ -159,6 +160,7 @@ type VirtControllerApp struct {
 
 	persistentVolumeClaimCache    cache.Store
 	persistentVolumeClaimInformer cache.SharedIndexInformer
+	persistentVolumeInformer      cache.SharedIndexInformer
 
 	rsController *VMIReplicaSet
 	rsInformer   cache.SharedIndexInformer
@@ -174,6 +176,7 @@ type VirtControllerApp struct {
 	dataVolumeInformer cache.SharedIndexInformer
 	cdiInformer        cache.SharedIndexInformer
 	cdiConfigInformer  cache.SharedIndexInformer
+	dataSourceInformer cache.SharedIndexInformer
 
 	migrationController *MigrationController
 	migrationInformer   cache.SharedIndexInformer
@@ -224,6 +227,8 @@ type VirtControllerApp struct {
 
 	// indicates if controllers were started with or without CDI/DataVolume support
 	hasCDI bool
+	// indicates if controllers were started with or without CDI/DataSource support
+	hasCDIDataSource bool
 	// the channel used to trigger re-initialization.
 	reInitChan chan string
 
@@ -324,6 +329,7 @@ func Execute() {
 
 	app.reInitChan = make(chan string, 10)
 	app.hasCDI = app.clusterConfig.HasDataVolumeAPI()
+	app.hasCDIDataSource = app.clusterConfig.HasDataSourceAPI()
 	app.clusterConfig.SetConfigModifiedCallback(app.configModificationCallback)
 	app.clusterConfig.SetConfigModifiedCallback(app.shouldChangeLogVerbosity)
 	app.clusterConfig.SetConfigModifiedCallback(app.shouldChangeRateLimiter)
@@ -353,6 +359,8 @@ func Execute() {
 	app.persistentVolumeClaimInformer = app.informerFactory.PersistentVolumeClaim()
 	app.persistentVolumeClaimCache = app.persistentVolumeClaimInformer.GetStore()
 
+	app.persistentVolumeInformer = app.informerFactory.PersistentVolume()
+
 	app.pdbInformer = app.informerFactory.K8SInformerFactory().Policy().V1().PodDisruptionBudgets().Informer()
 
 	app.vmInformer = app.informerFactory.VirtualMachine()
@@ -387,6 +395,17 @@ func Execute() {
 		log.Log.Infof("CDI not detected, DataVolume integration disabled")
 	}
 
+	if app.hasCDIDataSource {
+		app.dataSourceInformer = app.informerFactory.DataSource()
+		log.Log.Infof("CDI detected, DataSource integration enabled")
+	} else {
+		// Add a dummy DataSource informer in the event datasource support
+		// is disabled. This lets the controller continue to work without
+		// requiring a separate branching code path.
+		app.dataSourceInformer = app.informerFactory.DummyDataSource()
+		log.Log.Infof("CDI not detected, DataSource integration disabled")
+	}
+
 	onOpenShift, err := clusterutil.IsOnOpenShift(app.clientSet)
 	if err != nil {
 		golog.Fatalf("Error determining cluster type: %v", err)
@@ -431,6 +450,17 @@ func (vca *VirtControllerApp) configModificationCallback() {
 			log.Log.Infof("Reinitialize virt-controller, cdi api has been removed")
 		}
 		vca.reInitChan <- "reinit"
+		return
+	}
+
+	newHasCDIDataSource := vca.clusterConfig.HasDataSourceAPI()
+	if newHasCDIDataSource != vca.hasCDIDataSource {
+		if newHasCDIDataSource {
+			log.Log.Infof("Reinitialize virt-controller, cdi datasource api has been introduced")
+		} else {
+			log.Log.Infof("Reinitialize virt-controller, cdi datasource api has been removed")
+		}
+		vca.reInitChan <- "reinit"
 	}
 }
 
@@ -490,10 +520,10 @@ func (vca *VirtControllerApp) onStartedLeading() func(ctx context.Context) {
 		vca.informerFactory.Start(stop)
 
 		golog.Printf("STARTING controllers with following threads : "+
-			"node %d, vmi %d, replicaset %d, vm %d, migration %d, evacuation %d, disruptionBudget %d",
+			"node %d, vmi %d, replicaset %d, vm %d, migration %d, evacuation %d, disruptionBudget %d, export %d",
 			vca.nodeControllerThreads, vca.vmiControllerThreads, vca.rsControllerThreads,
 			vca.vmControllerThreads, vca.migrationControllerThreads, vca.evacuationControllerThreads,
-			vca.disruptionBudgetControllerThreads)
+			vca.disruptionBudgetControllerThreads, vca.exportControllerThreads)
 
 		vmiprom.SetupVMICollector(vca.vmiInformer, vca.clusterConfig)
 		perfscale.RegisterPerfScaleMetrics(vca.vmiInformer)
@@ -502,6 +532,7 @@ func (vca *VirtControllerApp) onStartedLeading() func(ctx context.Context) {
 		}
 		golog.Printf("\nvca.migrationInformer :%v\n", vca.migrationInformer)
 		migration.RegisterMigrationMetrics(vca.migrationInformer)
+		vmexport.RegisterMetrics(vca.vmExportInformer)
 
 		go vca.evacuationController.Run(vca.evacuationControllerThreads, stop)
 		go vca.disruptionBudgetController.Run(vca.disruptionBudgetControllerThreads, stop)
@@ -723,8 +754,10 @@ func (vca *VirtControllerApp) initExportController() {
 		Client:                    vca.clientSet,
 		VMExportInformer:          vca.vmExportInformer,
 		PVCInformer:               vca.persistentVolumeClaimInformer,
+		PVInformer:                vca.persistentVolumeInformer,
 		PodInformer:               vca.allPodInformer,
 		DataVolumeInformer:        vca.dataVolumeInformer,
+		DataSourceInformer:        vca.dataSourceInformer,
 		ServiceInformer:           vca.exportServiceInformer,
 		Recorder:                  recorder,
 		ResyncPeriod:              vca.snapshotControllerResyncPeriod,
@@ -739,6 +772,8 @@ func (vca *VirtControllerApp) initExportController() {
 		VMSnapshotContentInformer: vca.vmSnapshotContentInformer,
 		VMInformer:                vca.vmInformer,
 		VMIInformer:               vca.vmiInformer,
+		ClusterConfig:             vca.clusterConfig,
+		InstancetypeMethods:       instancetype.NewMethods(vca.clientSet),
 	}
 	vca.exportController.Init()
 }
@@ -203,6 +203,7 @@ var _ = Describe("Application", func() {
 			VMSnapshotContentInformer: vmSnapshotContentInformer,
 			VMInformer:                vmInformer,
 			VMIInformer:               vmiInformer,
+			ClusterConfig:             config,
 		}
 		app.exportController.Init()
 		app.persistentVolumeClaimInformer = pvcInformer
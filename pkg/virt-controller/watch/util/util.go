@@ -36,6 +36,7 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	typesutil "kubevirt.io/kubevirt/pkg/storage/types"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 func ProcessWorkItem(queue workqueue.RateLimitingInterface, handler func(string) (time.Duration, error)) bool {
@@ -106,7 +107,19 @@ func PodsUsingPVCs(podInformer cache.SharedIndexInformer, namespace string, pvcN
 	return pods, nil
 }
 
-func CreateDataVolumeManifest(clientset kubecli.KubevirtClient, dataVolumeTemplate virtv1.DataVolumeTemplateSpec, vm *virtv1.VirtualMachine) (*cdiv1.DataVolume, error) {
+// RetainDataVolumes returns true if DataVolumes created from vm's dataVolumeTemplates should survive the deletion
+// of the VirtualMachine, so they can later be adopted by a new VirtualMachine with a matching dataVolumeTemplate.
+// The per-VM DataVolumeRetentionPolicy takes precedence over the cluster-wide default; a nil clusterConfig is
+// treated as "no cluster-wide default available" and falls back to the regular delete behavior.
+func RetainDataVolumes(vm *virtv1.VirtualMachine, clusterConfig *virtconfig.ClusterConfig) bool {
+	policy := vm.Spec.DataVolumeRetentionPolicy
+	if policy == nil && clusterConfig != nil {
+		policy = clusterConfig.GetConfig().DataVolumeRetentionPolicy
+	}
+	return policy != nil && *policy == virtv1.DataVolumeRetentionPolicyRetain
+}
+
+func CreateDataVolumeManifest(clientset kubecli.KubevirtClient, clusterConfig *virtconfig.ClusterConfig, dataVolumeTemplate virtv1.DataVolumeTemplateSpec, vm *virtv1.VirtualMachine) (*cdiv1.DataVolume, error) {
 	newDataVolume := &cdiv1.DataVolume{}
 	newDataVolume.Spec = *dataVolumeTemplate.Spec.DeepCopy()
 	newDataVolume.ObjectMeta = *dataVolumeTemplate.ObjectMeta.DeepCopy()
@@ -124,8 +137,10 @@ func CreateDataVolumeManifest(clientset kubecli.KubevirtClient, dataVolumeTempla
 	}
 	newDataVolume.ObjectMeta.Annotations = annotations
 
-	newDataVolume.ObjectMeta.OwnerReferences = []v1.OwnerReference{
-		*v1.NewControllerRef(vm, virtv1.VirtualMachineGroupVersionKind),
+	if !RetainDataVolumes(vm, clusterConfig) {
+		newDataVolume.ObjectMeta.OwnerReferences = []v1.OwnerReference{
+			*v1.NewControllerRef(vm, virtv1.VirtualMachineGroupVersionKind),
+		}
 	}
 
 	if newDataVolume.Spec.PriorityClassName == "" && vm.Spec.Template.Spec.PriorityClassName != "" {
@@ -446,7 +446,7 @@ func (c *VMController) handleDataVolumes(vm *virtv1.VirtualMachine, dataVolumes
 
 			// ready = false because encountered DataVolume that is not created yet
 			ready = false
-			newDataVolume, err := watchutil.CreateDataVolumeManifest(c.clientset, template, vm)
+			newDataVolume, err := watchutil.CreateDataVolumeManifest(c.clientset, c.clusterConfig, template, vm)
 			if err != nil {
 				return ready, fmt.Errorf("unable to create DataVolume manifest: %v", err)
 			}
@@ -2042,6 +2042,7 @@ func (c *VMIController) updateVolumeStatus(vmi *virtv1.VirtualMachineInstance, v
 		return err
 	}
 	newStatus := make([]virtv1.VolumeStatus, 0)
+	volumeExpansionInProgress := false
 	for i, volume := range vmi.Spec.Volumes {
 		status := virtv1.VolumeStatus{}
 		if _, ok := oldStatusMap[volume.Name]; ok {
@@ -2076,7 +2077,12 @@ func (c *VMIController) updateVolumeStatus(vmi *virtv1.VirtualMachineInstance, v
 				if len(attachmentPod.Status.ContainerStatuses) == 1 && attachmentPod.Status.ContainerStatuses[0].Ready {
 					status.HotplugVolume.AttachPodUID = attachmentPod.UID
 				}
-				if c.canMoveToAttachedPhase(status.Phase) {
+				if attachmentPod.Status.Phase == k8sv1.PodFailed {
+					status.Phase = virtv1.HotplugVolumeAttachFailed
+					status.Message = fmt.Sprintf("Attachment pod %s, for volume %s failed", attachmentPod.Name, volume.Name)
+					status.Reason = FailedCreatePodReason
+					c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, status.Reason, status.Message)
+				} else if c.canMoveToAttachedPhase(status.Phase) {
 					status.Phase = virtv1.HotplugVolumeAttachedToNode
 					status.Message = fmt.Sprintf("Created hotplug attachment pod %s, for volume %s", attachmentPod.Name, volume.Name)
 					status.Reason = SuccessfulCreatePodReason
@@ -2092,6 +2098,13 @@ func (c *VMIController) updateVolumeStatus(vmi *virtv1.VirtualMachineInstance, v
 			pvcInterface, pvcExists, _ := c.pvcInformer.GetStore().GetByKey(fmt.Sprintf("%s/%s", vmi.Namespace, pvcName))
 			if pvcExists {
 				pvc := pvcInterface.(*k8sv1.PersistentVolumeClaim)
+				var previousCapacity k8sv1.ResourceList
+				if status.PersistentVolumeClaimInfo != nil {
+					previousCapacity = status.PersistentVolumeClaimInfo.Capacity
+				}
+				if wasPVCExpanded(previousCapacity, pvc.Status.Capacity) {
+					volumeExpansionInProgress = true
+				}
 				status.PersistentVolumeClaimInfo = &virtv1.PersistentVolumeClaimInfo{
 					AccessModes:  pvc.Spec.AccessModes,
 					VolumeMode:   pvc.Spec.VolumeMode,
@@ -2135,9 +2148,50 @@ func (c *VMIController) updateVolumeStatus(vmi *virtv1.VirtualMachineInstance, v
 		return strings.Compare(newStatus[i].Name, newStatus[j].Name) == -1
 	})
 	vmi.Status.VolumeStatus = newStatus
+	c.updateVolumesChangeCondition(vmi, volumeExpansionInProgress)
 	return nil
 }
 
+// wasPVCExpanded returns true if newCapacity's storage request is larger than previousCapacity's, which indicates
+// the backing PVC was just expanded and the new size still needs to be propagated to the running guest.
+func wasPVCExpanded(previousCapacity, newCapacity k8sv1.ResourceList) bool {
+	if previousCapacity == nil {
+		return false
+	}
+	previousStorage, ok := previousCapacity[k8sv1.ResourceStorage]
+	if !ok {
+		return false
+	}
+	newStorage, ok := newCapacity[k8sv1.ResourceStorage]
+	if !ok {
+		return false
+	}
+	return newStorage.Cmp(previousStorage) > 0
+}
+
+// updateVolumesChangeCondition keeps the VolumesChange condition in sync with whether a PVC expansion was just
+// detected for one of the VMI's volumes. Once a reconcile passes without detecting a new expansion, the condition
+// is flipped to true, on the assumption that virt-handler has had the chance to propagate the previous one to the guest.
+func (c *VMIController) updateVolumesChangeCondition(vmi *virtv1.VirtualMachineInstance, volumeExpansionInProgress bool) {
+	condManager := controller.NewVirtualMachineInstanceConditionManager()
+	condition := virtv1.VirtualMachineInstanceCondition{
+		Type:               virtv1.VirtualMachineInstanceVolumesChange,
+		LastTransitionTime: v1.Now(),
+	}
+	if volumeExpansionInProgress {
+		condition.Status = k8sv1.ConditionFalse
+		condition.Reason = "PVCExpansionDetected"
+		condition.Message = "waiting for a PVC expansion to be propagated to the guest"
+	} else if !condManager.HasCondition(vmi, virtv1.VirtualMachineInstanceVolumesChange) {
+		return
+	} else {
+		condition.Status = k8sv1.ConditionTrue
+		condition.Reason = "VolumesInSync"
+		condition.Message = "all volumes are propagated to the guest"
+	}
+	condManager.UpdateCondition(vmi, &condition)
+}
+
 func (c *VMIController) getFilesystemOverhead(pvc *k8sv1.PersistentVolumeClaim) (cdiv1.Percent, error) {
 	// To avoid conflicts, we only allow having one CDI instance
 	if cdiInstances := len(c.cdiInformer.GetStore().List()); cdiInstances != 1 {
@@ -2156,12 +2210,12 @@ func (c *VMIController) getFilesystemOverhead(pvc *k8sv1.PersistentVolumeClaim)
 		return "0", fmt.Errorf("Failed to convert CDIConfig object %v to type CDIConfig", cdiConfigInterface)
 	}
 
-	return kubevirttypes.GetFilesystemOverhead(pvc.Spec.VolumeMode, pvc.Spec.StorageClassName, cdiConfig), nil
+	return kubevirttypes.GetFilesystemOverhead(pvc.Spec.VolumeMode, pvc.Spec.StorageClassName, cdiConfig, c.clusterConfig.GetConfig().FilesystemOverhead), nil
 }
 
 func (c *VMIController) canMoveToAttachedPhase(currentPhase virtv1.VolumePhase) bool {
 	return currentPhase == "" || currentPhase == virtv1.VolumeBound || currentPhase == virtv1.VolumePending ||
-		currentPhase == virtv1.HotplugVolumeAttachedToNode
+		currentPhase == virtv1.HotplugVolumeAttachedToNode || currentPhase == virtv1.HotplugVolumeAttachFailed
 }
 
 func (c *VMIController) findAttachmentPodByVolumeName(volumeName string, attachmentPods []*k8sv1.Pod) *k8sv1.Pod {
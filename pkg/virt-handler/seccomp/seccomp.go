@@ -0,0 +1,79 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package seccomp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// ProfilesDir is where virt-handler installs KubeVirt's seccomp profiles, relative to the
+// kubelet root directory passed to InstallPolicy. Pods reference an installed profile with
+// a SeccompProfile of type Localhost and a LocalhostProfile of "kubevirt/<ProfileName>.json".
+const ProfilesDir = "kubevirt"
+
+// DefaultProfile is the name of the seccomp profile virt-handler installs for virt-launcher/qemu.
+// A VirtualMachineInstance uses it by setting spec.seccompProfile to type Localhost with
+// localhostProfile "kubevirt/virt-launcher-default.json"; a VirtualMachineInstance without
+// spec.seccompProfile gets no seccomp profile set on its compute container. It tightens syscall exposure beyond
+// the container runtime's RuntimeDefault profile while still allowing the syscalls qemu needs
+// to service device hotplug (e.g. mount/umount2 for hotplugged volumes, and the memory
+// management calls used when hot-adding guest RAM).
+const DefaultProfile = "virt-launcher-default"
+
+type copyPolicy = func(profileName string, dir string) error
+
+type SeccompProfileInstaller struct {
+	copyProfileFunc copyPolicy
+}
+
+func NewSeccompProfileInstaller() *SeccompProfileInstaller {
+	return &SeccompProfileInstaller{
+		copyProfileFunc: defaultCopyProfileFunc,
+	}
+}
+
+// InstallPolicy copies KubeVirt's built-in seccomp profiles, baked into the virt-handler
+// image, into dir/ProfilesDir where the kubelet's seccomp profile root can see them.
+func (i *SeccompProfileInstaller) InstallPolicy(dir string) error {
+	destDir := filepath.Join(dir, ProfilesDir)
+	for _, profileName := range []string{DefaultProfile} {
+		if err := i.copyProfileFunc(profileName, destDir); err != nil {
+			return fmt.Errorf("failed to install seccomp profile %v: %v", profileName, err)
+		}
+	}
+	return nil
+}
+
+func defaultCopyProfileFunc(profileName string, dir string) error {
+	sourceFile := filepath.Join("/", profileName+".json")
+	// #nosec No risk for path injection. Using static string path
+	input, err := ioutil.ReadFile(sourceFile)
+	if err != nil {
+		return fmt.Errorf("failed to read seccomp profile %v: %v", sourceFile, err)
+	}
+
+	destinationFile := filepath.Join(dir, profileName+".json")
+	if err := ioutil.WriteFile(destinationFile, input, 0644); err != nil {
+		return fmt.Errorf("failed to create seccomp profile %v: %v", destinationFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package seccomp_test
+
+import (
+	"testing"
+
+	"kubevirt.io/client-go/testutils"
+)
+
+func TestSeccomp(t *testing.T) {
+	testutils.KubeVirtTestSuiteSetup(t)
+}
@@ -0,0 +1,44 @@
+package seccomp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("seccomp", func() {
+
+	var tempDir string
+	var installer *SeccompProfileInstaller
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "kubevirt")
+		Expect(err).ToNot(HaveOccurred())
+		installer = &SeccompProfileInstaller{}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should install the default profile into the profiles subdirectory", func() {
+		installer.copyProfileFunc = func(profileName string, dir string) error {
+			Expect(profileName).To(Equal(DefaultProfile))
+			Expect(dir).To(Equal(filepath.Join(tempDir, ProfilesDir)))
+			return nil
+		}
+		Expect(installer.InstallPolicy(tempDir)).To(Succeed())
+	})
+
+	It("should fail if copying a profile fails", func() {
+		installer.copyProfileFunc = func(profileName string, dir string) error {
+			return fmt.Errorf("something went wrong")
+		}
+		Expect(installer.InstallPolicy(tempDir)).ToNot(Succeed())
+	})
+})
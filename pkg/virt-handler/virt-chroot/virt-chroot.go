@@ -44,6 +44,10 @@ func GetChrootMountNamespace() string {
 	return mountNamespace
 }
 
+// MountChroot bind-mounts sourcePath onto targetPath inside the host mount namespace.
+// TODO: once virt-launcher pods can run in a user namespace (virtconfig.UserNamespacesGate),
+// this will need an idmapped-mount variant so volumes keep their host ownership instead of
+// appearing as the namespace's overflow uid/gid.
 func MountChroot(sourcePath, targetPath *safepath.Path, ro bool) *exec.Cmd {
 	return UnsafeMountChroot(trimProcPrefix(sourcePath), trimProcPrefix(targetPath), ro)
 }
@@ -58,6 +58,7 @@ type Connection interface {
 	SetReconnectChan(reconnect chan bool)
 	QemuAgentCommand(command string, domainName string) (string, error)
 	GetAllDomainStats(statsTypes libvirt.DomainStatsTypes, flags libvirt.ConnectGetAllDomainStatsFlags) ([]libvirt.DomainStats, error)
+	DomainRestoreFlags(srcFile string, xmlConf string, flags libvirt.DomainSaveRestoreFlags) error
 	// helper method, not found in libvirt
 	// We add this helper to
 	// 1. avoid to expose to the client code the libvirt-specific return type, see docs in stats/ subpackage
@@ -235,6 +236,16 @@ func (l *LibvirtConnection) ListAllDomains(flags libvirt.ConnectListAllDomainsFl
 	return doms, nil
 }
 
+func (l *LibvirtConnection) DomainRestoreFlags(srcFile string, xmlConf string, flags libvirt.DomainSaveRestoreFlags) error {
+	if err := l.reconnectIfNecessary(); err != nil {
+		return err
+	}
+
+	err := l.Connect.DomainRestoreFlags(srcFile, xmlConf, flags)
+	l.checkConnectionLost(err)
+	return err
+}
+
 // Execute a command on the Qemu guest agent
 // command - the qemu command, for example this gets the interfaces: {"execute":"guest-network-get-interfaces"}
 // domainName -  the qemu domain name
@@ -488,6 +499,7 @@ type VirDomain interface {
 	AbortJob() error
 	Free() error
 	CoreDumpWithFormat(to string, format libvirt.DomainCoreDumpFormat, flags libvirt.DomainCoreDumpFlags) error
+	SaveFlags(destFile string, destXml string, flags libvirt.DomainSaveRestoreFlags) error
 }
 
 func NewConnection(uri string, user string, pass string, checkInterval time.Duration) (Connection, error) {
@@ -2499,6 +2499,46 @@ var _ = Describe("Manager helper functions", func() {
 
 	})
 
+	Context("hasMultipathHolder", func() {
+
+		var tmpDir string
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "sysblockdir")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(tmpDir)
+		})
+
+		It("should return false when the device has no holders directory", func() {
+			claimed, err := hasMultipathHolder(tmpDir, 8, 16)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+		})
+
+		It("should return false when the device has holders but none are dm devices", func() {
+			holdersDir := filepath.Join(tmpDir, "8:16", "holders")
+			Expect(os.MkdirAll(filepath.Join(holdersDir, "sdc"), 0755)).To(Succeed())
+
+			claimed, err := hasMultipathHolder(tmpDir, 8, 16)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+		})
+
+		It("should return true when a dm-multipath device holds the block device", func() {
+			holdersDir := filepath.Join(tmpDir, "8:16", "holders")
+			Expect(os.MkdirAll(filepath.Join(holdersDir, "dm-3"), 0755)).To(Succeed())
+
+			claimed, err := hasMultipathHolder(tmpDir, 8, 16)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claimed).To(BeTrue())
+		})
+
+	})
+
 	Context("possibleGuestSize", func() {
 
 		var properDisk api.Disk
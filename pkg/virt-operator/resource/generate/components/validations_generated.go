@@ -4950,7 +4950,7 @@ var CRDsValidation map[string]string = map[string]string{
                               io:
                                 description: 'IO specifies which QEMU disk IO mode
                                   should be used. Supported values are: native, default,
-                                  threads.'
+                                  threads, io_uring.'
                                 type: string
                               lun:
                                 description: Attach a volume as a LUN to the vmi.
@@ -5962,6 +5962,13 @@ var CRDsValidation map[string]string = map[string]string{
                     scheduler. If not specified, the VMI will be dispatched by default
                     scheduler.
                   type: string
+                selinuxContext:
+                  description: If specified, overrides the cluster-wide SELinux
+                    type configured for virt-launcher with a custom SELinux type
+                    for this VirtualMachineInstance's compute container. Setting
+                    this field requires the SELinuxCustomType feature gate to be
+                    enabled.
+                  type: string
                 startStrategy:
                   description: StartStrategy can be set to "Paused" if Virtual Machine
                     should be started in paused state.
@@ -6752,7 +6759,8 @@ var CRDsValidation map[string]string = map[string]string{
                         type: object
                       io:
                         description: 'IO specifies which QEMU disk IO mode should
-                          be used. Supported values are: native, default, threads.'
+                          be used. Supported values are: native, default, threads,
+                          io_uring.'
                         type: string
                       lun:
                         description: Attach a volume as a LUN to the vmi.
@@ -7719,6 +7727,12 @@ var CRDsValidation map[string]string = map[string]string{
           description: TokenSecretRef is the name of the secret that contains the
             token used by the export server pod
           type: string
+        ttlDuration:
+          description: TTLDuration limits the lifetime of an export. If this field
+            is set, after this duration has passed from deployment, the export is
+            automatically deleted. If this field is not set, the export will not
+            get cleaned up.
+          type: string
       required:
       - source
       - tokenSecretRef
@@ -9102,7 +9116,8 @@ var CRDsValidation map[string]string = map[string]string{
                         type: object
                       io:
                         description: 'IO specifies which QEMU disk IO mode should
-                          be used. Supported values are: native, default, threads.'
+                          be used. Supported values are: native, default, threads,
+                          io_uring.'
                         type: string
                       lun:
                         description: Attach a volume as a LUN to the vmi.
@@ -10054,6 +10069,12 @@ var CRDsValidation map[string]string = map[string]string{
           description: If specified, the VMI will be dispatched by specified scheduler.
             If not specified, the VMI will be dispatched by default scheduler.
           type: string
+        selinuxContext:
+          description: If specified, overrides the cluster-wide SELinux type
+            configured for virt-launcher with a custom SELinux type for this
+            VirtualMachineInstance's compute container. Setting this field
+            requires the SELinuxCustomType feature gate to be enabled.
+          type: string
         startStrategy:
           description: StartStrategy can be set to "Paused" if Virtual Machine should
             be started in paused state.
@@ -11401,7 +11422,8 @@ var CRDsValidation map[string]string = map[string]string{
                         type: object
                       io:
                         description: 'IO specifies which QEMU disk IO mode should
-                          be used. Supported values are: native, default, threads.'
+                          be used. Supported values are: native, default, threads,
+                          io_uring.'
                         type: string
                       lun:
                         description: Attach a volume as a LUN to the vmi.
@@ -13484,7 +13506,7 @@ var CRDsValidation map[string]string = map[string]string{
                               io:
                                 description: 'IO specifies which QEMU disk IO mode
                                   should be used. Supported values are: native, default,
-                                  threads.'
+                                  threads, io_uring.'
                                 type: string
                               lun:
                                 description: Attach a volume as a LUN to the vmi.
@@ -14496,6 +14518,13 @@ var CRDsValidation map[string]string = map[string]string{
                     scheduler. If not specified, the VMI will be dispatched by default
                     scheduler.
                   type: string
+                selinuxContext:
+                  description: If specified, overrides the cluster-wide SELinux
+                    type configured for virt-launcher with a custom SELinux type
+                    for this VirtualMachineInstance's compute container. Setting
+                    this field requires the SELinuxCustomType feature gate to be
+                    enabled.
+                  type: string
                 startStrategy:
                   description: StartStrategy can be set to "Paused" if Virtual Machine
                     should be started in paused state.
@@ -18494,6 +18523,13 @@ var CRDsValidation map[string]string = map[string]string{
                             specified scheduler. If not specified, the VMI will be
                             dispatched by default scheduler.
                           type: string
+                        selinuxContext:
+                          description: If specified, overrides the cluster-wide
+                            SELinux type configured for virt-launcher with a
+                            custom SELinux type for this VirtualMachineInstance's
+                            compute container. Setting this field requires the
+                            SELinuxCustomType feature gate to be enabled.
+                          type: string
                         startStrategy:
                           description: StartStrategy can be set to "Paused" if Virtual
                             Machine should be started in paused state.
@@ -23175,6 +23211,14 @@ var CRDsValidation map[string]string = map[string]string{
                                 by specified scheduler. If not specified, the VMI
                                 will be dispatched by default scheduler.
                               type: string
+                            selinuxContext:
+                              description: If specified, overrides the
+                                cluster-wide SELinux type configured for
+                                virt-launcher with a custom SELinux type for this
+                                VirtualMachineInstance's compute container.
+                                Setting this field requires the SELinuxCustomType
+                                feature gate to be enabled.
+                              type: string
                             startStrategy:
                               description: StartStrategy can be set to "Paused" if
                                 Virtual Machine should be started in paused state.
@@ -458,10 +458,10 @@ func calcPVCNeededSize(memoryDumpExpectedSize *resource.Quantity, storageClass *
 		storageClass = nil
 	}
 
-	return storagetypes.GetSizeIncludingFSOverhead(memoryDumpExpectedSize, storageClass, &fsVolumeMode, cdiConfig)
+	return storagetypes.GetSizeIncludingFSOverhead(memoryDumpExpectedSize, storageClass, &fsVolumeMode, cdiConfig, nil)
 }
 
-func generatePVC(size *resource.Quantity, claimName, namespace, storageClass, accessMode string) (*k8sv1.PersistentVolumeClaim, error) {
+func generatePVC(size *resource.Quantity, claimName, namespace, storageClass, accessMode string, virtClient kubecli.KubevirtClient) (*k8sv1.PersistentVolumeClaim, error) {
 	pvc := &k8sv1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      claimName,
@@ -486,12 +486,28 @@ func generatePVC(size *resource.Quantity, claimName, namespace, storageClass, ac
 	if accessMode != "" {
 		pvc.Spec.AccessModes = []k8sv1.PersistentVolumeAccessMode{k8sv1.PersistentVolumeAccessMode(accessMode)}
 	} else {
-		pvc.Spec.AccessModes = []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteOnce}
+		pvc.Spec.AccessModes = []k8sv1.PersistentVolumeAccessMode{defaultAccessModeForStorageClass(storageClass, virtClient)}
 	}
 
 	return pvc, nil
 }
 
+// defaultAccessModeForStorageClass consults the storage class's StorageProfile for its
+// recommended access mode, so users don't have to hand-specify --access-mode for every storage
+// backend. It falls back to ReadWriteOnce if the StorageProfile can't be retrieved or has no
+// recommendation yet.
+func defaultAccessModeForStorageClass(storageClass string, virtClient kubecli.KubevirtClient) k8sv1.PersistentVolumeAccessMode {
+	if storageClass == "" {
+		return k8sv1.ReadWriteOnce
+	}
+	storageProfile, err := virtClient.CdiClient().CdiV1beta1().StorageProfiles().Get(context.Background(), storageClass, metav1.GetOptions{})
+	if err != nil {
+		return k8sv1.ReadWriteOnce
+	}
+	_, defaultAccessMode := storagetypes.GetDefaultVolumeAndAccessMode(storageProfile)
+	return defaultAccessMode
+}
+
 func createPVCforMemoryDump(namespace, vmName, claimName string, virtClient kubecli.KubevirtClient) error {
 	_, err := virtClient.CoreV1().PersistentVolumeClaims(namespace).Get(context.Background(), claimName, metav1.GetOptions{})
 	if err == nil {
@@ -511,7 +527,7 @@ func createPVCforMemoryDump(namespace, vmName, claimName string, virtClient kube
 		return err
 	}
 
-	pvc, err := generatePVC(neededSize, claimName, namespace, storageClass, accessMode)
+	pvc, err := generatePVC(neededSize, claimName, namespace, storageClass, accessMode, virtClient)
 	if err != nil {
 		return err
 	}
@@ -118,6 +118,28 @@ func (in *AuthorizedKeysFile) DeepCopy() *AuthorizedKeysFile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCheckpointOptions) DeepCopyInto(out *BackupCheckpointOptions) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCheckpointOptions.
+func (in *BackupCheckpointOptions) DeepCopy() *BackupCheckpointOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCheckpointOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BIOS) DeepCopyInto(out *BIOS) {
 	*out = *in
@@ -1165,6 +1187,127 @@ func (in *EphemeralVolumeSource) DeepCopy() *EphemeralVolumeSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportConfiguration) DeepCopyInto(out *ExportConfiguration) {
+	*out = *in
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrentNamespaceExports != nil {
+		in, out := &in.MaxConcurrentNamespaceExports, &out.MaxConcurrentNamespaceExports
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLDuration != nil {
+		in, out := &in.TTLDuration, &out.TTLDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodResourceRequirements != nil {
+		in, out := &in.PodResourceRequirements, &out.PodResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngressSelector != nil {
+		in, out := &in.IngressSelector, &out.IngressSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequeueInterval != nil {
+		in, out := &in.RequeueInterval, &out.RequeueInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodTTLAfterFinished != nil {
+		in, out := &in.PodTTLAfterFinished, &out.PodTTLAfterFinished
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodRunAsUser != nil {
+		in, out := &in.PodRunAsUser, &out.PodRunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PodFSGroup != nil {
+		in, out := &in.PodFSGroup, &out.PodFSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ZstdCompressionLevel != nil {
+		in, out := &in.ZstdCompressionLevel, &out.ZstdCompressionLevel
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GzipCompressionLevel != nil {
+		in, out := &in.GzipCompressionLevel, &out.GzipCompressionLevel
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ShutdownGracePeriod != nil {
+		in, out := &in.ShutdownGracePeriod, &out.ShutdownGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PerConnectionBandwidthLimit != nil {
+		in, out := &in.PerConnectionBandwidthLimit, &out.PerConnectionBandwidthLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TotalBandwidthLimit != nil {
+		in, out := &in.TotalBandwidthLimit, &out.TotalBandwidthLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxConcurrentDownloads != nil {
+		in, out := &in.MaxConcurrentDownloads, &out.MaxConcurrentDownloads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PerClientIPRequestLimit != nil {
+		in, out := &in.PerClientIPRequestLimit, &out.PerClientIPRequestLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PerClientIPRequestBurst != nil {
+		in, out := &in.PerClientIPRequestBurst, &out.PerClientIPRequestBurst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnableNBD != nil {
+		in, out := &in.EnableNBD, &out.EnableNBD
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScratchSpaceSize != nil {
+		in, out := &in.ScratchSpaceSize, &out.ScratchSpaceSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PrecomputeFormats != nil {
+		in, out := &in.PrecomputeFormats, &out.PrecomputeFormats
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportConfiguration.
+func (in *ExportConfiguration) DeepCopy() *ExportConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeatureAPIC) DeepCopyInto(out *FeatureAPIC) {
 	*out = *in
@@ -1670,6 +1813,11 @@ func (in *HostDisk) DeepCopyInto(out *HostDisk) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Qcow2 != nil {
+		in, out := &in.Qcow2, &out.Qcow2
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -2142,11 +2290,31 @@ func (in *KubeVirtConfiguration) DeepCopyInto(out *KubeVirtConfiguration) {
 		*out = new(SMBiosConfiguration)
 		**out = **in
 	}
+	if in.DataVolumeRetentionPolicy != nil {
+		in, out := &in.DataVolumeRetentionPolicy, &out.DataVolumeRetentionPolicy
+		*out = new(DataVolumeRetentionPolicy)
+		**out = **in
+	}
+	if in.FilesystemOverhead != nil {
+		in, out := &in.FilesystemOverhead, &out.FilesystemOverhead
+		*out = new(v1beta1.FilesystemOverhead)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EvictionStrategy != nil {
 		in, out := &in.EvictionStrategy, &out.EvictionStrategy
 		*out = new(EvictionStrategy)
 		**out = **in
 	}
+	if in.ExportConfiguration != nil {
+		in, out := &in.ExportConfiguration, &out.ExportConfiguration
+		*out = new(ExportConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSConfiguration != nil {
+		in, out := &in.TLSConfiguration, &out.TLSConfiguration
+		*out = new(TLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SupportedGuestAgentVersions != nil {
 		in, out := &in.SupportedGuestAgentVersions, &out.SupportedGuestAgentVersions
 		*out = make([]string, len(*in))
@@ -3551,6 +3719,27 @@ func (in *SysprepSource) DeepCopy() *SysprepSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfiguration) DeepCopyInto(out *TLSConfiguration) {
+	*out = *in
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfiguration.
+func (in *TLSConfiguration) DeepCopy() *TLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TPMDevice) DeepCopyInto(out *TPMDevice) {
 	*out = *in
@@ -4540,6 +4729,11 @@ func (in *VirtualMachineInstanceSpec) DeepCopyInto(out *VirtualMachineInstanceSp
 		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(corev1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -4800,6 +4994,11 @@ func (in *VirtualMachineSpec) DeepCopyInto(out *VirtualMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DataVolumeRetentionPolicy != nil {
+		in, out := &in.DataVolumeRetentionPolicy, &out.DataVolumeRetentionPolicy
+		*out = new(DataVolumeRetentionPolicy)
+		**out = **in
+	}
 	return
 }
 
@@ -4910,6 +5109,13 @@ func (in *VirtualMachineStatus) DeepCopyInto(out *VirtualMachineStatus) {
 		*out = new(VirtualMachineMemoryDumpRequest)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeBackupCheckpoints != nil {
+		in, out := &in.VolumeBackupCheckpoints, &out.VolumeBackupCheckpoints
+		*out = make([]VirtualMachineVolumeBackupCheckpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -4923,6 +5129,26 @@ func (in *VirtualMachineStatus) DeepCopy() *VirtualMachineStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineVolumeBackupCheckpoint) DeepCopyInto(out *VirtualMachineVolumeBackupCheckpoint) {
+	*out = *in
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeBackupCheckpoint.
+func (in *VirtualMachineVolumeBackupCheckpoint) DeepCopy() *VirtualMachineVolumeBackupCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineVolumeBackupCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineVolumeRequest) DeepCopyInto(out *VirtualMachineVolumeRequest) {
 	*out = *in
@@ -4,11 +4,13 @@ package v1
 
 func (HostDisk) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":         "Represents a disk created on the cluster level",
-		"path":     "The path to HostDisk image located on the cluster",
-		"type":     "Contains information if disk.img exists or should be created\nallowed options are 'Disk' and 'DiskOrCreate'",
-		"capacity": "Capacity of the sparse disk\n+optional",
-		"shared":   "Shared indicate whether the path is shared between nodes",
+		"":              "Represents a disk created on the cluster level",
+		"path":          "The path to HostDisk image located on the cluster",
+		"type":          "Contains information if disk.img exists or should be created\nallowed options are 'Disk' and 'DiskOrCreate'",
+		"capacity":      "Capacity of the sparse disk\n+optional",
+		"shared":        "Shared indicate whether the path is shared between nodes",
+		"qcow2":         "If set, a newly created HostDisk image is backed by a sparse qcow2 file instead of a raw\nfile, which typically uses significantly less space on disk for mostly-empty images. Has no\neffect if the image already exists. Defaults to false (raw).",
+		"preallocation": "Preallocation applied when creating a qcow2-backed HostDisk image. Only valid when Qcow2 is\nset. If empty, no preallocation is requested and the image stays fully sparse.",
 	}
 }
 
@@ -319,7 +321,7 @@ func (Disk) SwaggerDoc() map[string]string {
 		"serial":            "Serial provides the ability to specify a serial number for the disk device.\n+optional",
 		"dedicatedIOThread": "dedicatedIOThread indicates this disk should have an exclusive IO Thread.\nEnabling this implies useIOThreads = true.\nDefaults to false.\n+optional",
 		"cache":             "Cache specifies which kvm disk cache mode should be used.\nSupported values are: CacheNone, CacheWriteThrough.\n+optional",
-		"io":                "IO specifies which QEMU disk IO mode should be used.\nSupported values are: native, default, threads.\n+optional",
+		"io":                "IO specifies which QEMU disk IO mode should be used.\nSupported values are: native, default, threads, io_uring.\n+optional",
 		"tag":               "If specified, disk address and its tag will be provided to the guest via config drive metadata\n+optional",
 		"blockSize":         "If specified, the virtual disk will be presented with the given block sizes.\n+optional",
 		"shareable":         "If specified the disk is made sharable and multiple write from different VMs are permitted\n+optional",
@@ -442,8 +444,9 @@ func (EphemeralVolumeSource) SwaggerDoc() map[string]string {
 
 func (EmptyDiskSource) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":         "EmptyDisk represents a temporary disk which shares the vmis lifecycle.",
-		"capacity": "Capacity of the sparse disk.",
+		"":              "EmptyDisk represents a temporary disk which shares the vmis lifecycle.",
+		"capacity":      "Capacity of the sparse disk.",
+		"preallocation": "Preallocation applied when creating the backing qcow2 image for this EmptyDisk. If empty, no\npreallocation is requested and the image stays fully sparse. Use this for latency-sensitive\nworkloads that cannot tolerate the write-time allocation stalls of a sparse image.",
 	}
 }
 
@@ -85,6 +85,17 @@ type VirtualMachineInstanceSpec struct {
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// If specified, overrides the cluster-wide SELinux type configured for virt-launcher
+	// with a custom SELinux type for this VirtualMachineInstance's compute container.
+	// Setting this field requires the SELinuxCustomType feature gate to be enabled.
+	// +optional
+	SelinuxContext string `json:"selinuxContext,omitempty"`
+
+	// If specified, the seccomp profile for the virt-launcher compute container, which runs qemu.
+	// If not specified, the container runtime's default profile is used, same as for any other container.
+	// +optional
+	SeccompProfile *k8sv1.SeccompProfile `json:"seccompProfile,omitempty"`
+
 	// Specification of the desired behavior of the VirtualMachineInstance on the host.
 	Domain DomainSpec `json:"domain"`
 	// NodeSelector is a selector which must be true for the vmi to fit on a node.
@@ -351,6 +362,8 @@ const (
 	VolumeReady VolumePhase = "Ready"
 	// HotplugVolumeDetaching means the volume is being detached from the node, and the attachment pod is being removed.
 	HotplugVolumeDetaching VolumePhase = "Detaching"
+	// HotplugVolumeAttachFailed means the attachment pod for the volume failed, and the volume could not be attached to the node.
+	HotplugVolumeAttachFailed VolumePhase = "AttachFailed"
 	// HotplugVolumeUnMounted means the volume has been unmounted from the virt-launcer pod.
 	HotplugVolumeUnMounted VolumePhase = "UnMountedFromPod"
 	// MemoryDumpVolumeCompleted means that the requested memory dump was completed and the dump is ready in the volume
@@ -459,6 +472,10 @@ const (
 
 	// Indicates whether the VMI is live migratable
 	VirtualMachineInstanceIsMigratable VirtualMachineInstanceConditionType = "LiveMigratable"
+
+	// VirtualMachineInstanceVolumesChange indicates whether the volumes have changed, e.g. because an underlying
+	// PVC was expanded, and whether that change has already been propagated to the guest.
+	VirtualMachineInstanceVolumesChange VirtualMachineInstanceConditionType = "VolumesChange"
 	// Reason means that VMI is not live migratioable because of it's disks collection
 	VirtualMachineInstanceReasonDisksNotMigratable = "DisksNotLiveMigratable"
 	// Reason means that VMI is not live migratioable because of it's network interfaces collection
@@ -1255,8 +1272,9 @@ type VirtualMachine struct {
 
 // Return the current runStrategy for the VirtualMachine
 // if vm.spec.running is set, that will be mapped to runStrategy:
-//   false: RunStrategyHalted
-//   true: RunStrategyAlways
+//
+//	false: RunStrategyHalted
+//	true: RunStrategyAlways
 func (vm *VirtualMachine) RunStrategy() (VirtualMachineRunStrategy, error) {
 	if vm.Spec.Running != nil && vm.Spec.RunStrategy != nil {
 		return RunStrategyUnknown, fmt.Errorf("running and runstrategy are mutually exclusive")
@@ -1325,8 +1343,27 @@ type VirtualMachineSpec struct {
 	// dataVolumeTemplates is a list of dataVolumes that the VirtualMachineInstance template can reference.
 	// DataVolumes in this list are dynamically created for the VirtualMachine and are tied to the VirtualMachine's life-cycle.
 	DataVolumeTemplates []DataVolumeTemplateSpec `json:"dataVolumeTemplates,omitempty"`
+
+	// DataVolumeRetentionPolicy specifies whether the DataVolumes created from dataVolumeTemplates should be
+	// deleted, retained, or adopted when the VirtualMachine they belong to is deleted. If unset, the
+	// cluster-wide default configured in KubeVirtConfiguration is used, which itself defaults to deleting them.
+	// +optional
+	DataVolumeRetentionPolicy *DataVolumeRetentionPolicy `json:"dataVolumeRetentionPolicy,omitempty" optional:"true"`
 }
 
+// DataVolumeRetentionPolicy specifies what should happen to DataVolumes created from dataVolumeTemplates once the
+// owning VirtualMachine is deleted.
+type DataVolumeRetentionPolicy string
+
+const (
+	// DataVolumeRetentionPolicyDelete means the DataVolumes are owned by the VirtualMachine and are garbage
+	// collected along with it. This is the default behavior.
+	DataVolumeRetentionPolicyDelete DataVolumeRetentionPolicy = "Delete"
+	// DataVolumeRetentionPolicyRetain means the DataVolumes are not owned by the VirtualMachine and survive its
+	// deletion, so they can be adopted by a new VirtualMachine with a matching dataVolumeTemplate.
+	DataVolumeRetentionPolicyRetain DataVolumeRetentionPolicy = "Retain"
+)
+
 // StateChangeRequestType represents the existing state change requests that are possible
 type StateChangeRequestAction string
 
@@ -1430,6 +1467,16 @@ type VirtualMachineStatus struct {
 	// +nullable
 	// +optional
 	MemoryDumpRequest *VirtualMachineMemoryDumpRequest `json:"memoryDumpRequest,omitempty" optional:"true"`
+
+	// VolumeBackupCheckpoints tracks the most recent backup checkpoint recorded against each
+	// volume. It is populated through the backupcheckpoint subresource and lets an external
+	// backup vendor, after quiescing the guest with the freeze/unfreeze subresources,
+	// correlate its next incremental backup with the checkpoint it follows. KubeVirt does not
+	// track which blocks changed between checkpoints itself.
+	// +optional
+	// +listType=map
+	// +listMapKey=volumeName
+	VolumeBackupCheckpoints []VirtualMachineVolumeBackupCheckpoint `json:"volumeBackupCheckpoints,omitempty" optional:"true"`
 }
 
 type VolumeSnapshotStatus struct {
@@ -1531,6 +1578,9 @@ const (
 	// IODefault - Fallback to the default value from the kernel. With recent Kernel versions (for example RHEL-7) the
 	// default is AIO.
 	IODefault DriverIO = "default"
+	// IOUring - Use the Linux io_uring API for I/O submission, offering lower overhead than native AIO on
+	// kernels that support it. Must be requested explicitly since support cannot be reliably detected.
+	IOUring DriverIO = "io_uring"
 )
 
 // Handler defines a specific action that should be taken
@@ -1652,7 +1702,6 @@ const (
 	WorkloadUpdateMethodEvict WorkloadUpdateMethod = "Evict"
 )
 
-//
 // KubeVirtWorkloadUpdateStrategy defines options related to updating a KubeVirt install
 type KubeVirtWorkloadUpdateStrategy struct {
 	// WorkloadUpdateMethods defines the methods that can be used to disrupt workloads
@@ -2132,6 +2181,38 @@ type RemoveVolumeOptions struct {
 	DryRun []string `json:"dryRun,omitempty"`
 }
 
+// VirtualMachineVolumeBackupCheckpoint records a backup checkpoint a backup vendor has taken
+// against one of the VirtualMachine's volumes.
+type VirtualMachineVolumeBackupCheckpoint struct {
+	// VolumeName is the name of the volume, as listed among the VirtualMachine's volumes,
+	// that this checkpoint was taken against.
+	VolumeName string `json:"volumeName"`
+	// CheckpointName is the vendor-supplied identifier for this checkpoint.
+	CheckpointName string `json:"checkpointName"`
+	// CreationTime is the time the checkpoint was recorded.
+	// +nullable
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty" optional:"true"`
+}
+
+// BackupCheckpointOptions are the options to record a new backup checkpoint against a volume.
+type BackupCheckpointOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// VolumeName is the name of the volume this checkpoint is taken against.
+	VolumeName string `json:"volumeName"`
+	// CheckpointName is the vendor-supplied identifier for this checkpoint.
+	CheckpointName string `json:"checkpointName"`
+	// When present, indicates that modifications should not be
+	// persisted. An invalid or unrecognized dryRun directive will
+	// result in an error response and no further processing of the
+	// request. Valid values are:
+	// - All: all dry run stages will be processed
+	// +optional
+	// +listType=atomic
+	DryRun []string `json:"dryRun,omitempty"`
+}
+
 type TokenBucketRateLimiter struct {
 	// QPS indicates the maximum QPS to the apiserver from this client.
 	// If it's zero, the component default will be used
@@ -2174,11 +2255,35 @@ type KubeVirtConfiguration struct {
 	DefaultRuntimeClass    string                  `json:"defaultRuntimeClass,omitempty"`
 	SMBIOSConfig           *SMBiosConfiguration    `json:"smbios,omitempty"`
 
+	// DataVolumeRetentionPolicy specifies the cluster-wide default for whether DataVolumes created from
+	// dataVolumeTemplates are deleted, retained, or adopted when their owning VirtualMachine is deleted. This can
+	// be overridden per VirtualMachine via VirtualMachineSpec.DataVolumeRetentionPolicy. Defaults to Delete.
+	// +optional
+	DataVolumeRetentionPolicy *DataVolumeRetentionPolicy `json:"dataVolumeRetentionPolicy,omitempty"`
+
+	// FilesystemOverhead is a percentage of filesystem's size to be reserved when resizing the PVC, with an optional
+	// per-StorageClass override. This takes precedence over the overhead reported by CDI, which is useful when a
+	// StorageClass's filesystem doesn't match CDI's assumption of a single global default.
+	// +optional
+	FilesystemOverhead *cdiv1.FilesystemOverhead `json:"filesystemOverhead,omitempty"`
+
 	// EvictionStrategy defines at the cluster level if the VirtualMachineInstance should be
 	// migrated instead of shut-off in case of a node drain. If the VirtualMachineInstance specific
 	// field is set it overrides the cluster level one.
 	EvictionStrategy *EvictionStrategy `json:"evictionStrategy,omitempty"`
 
+	// ExportConfiguration contains the cluster-wide defaults for exports created with
+	// VirtualMachineExport.
+	// +optional
+	ExportConfiguration *ExportConfiguration `json:"vmExport,omitempty"`
+
+	// TLSConfiguration sets the cluster-wide TLS minimum version and cipher suites accepted by
+	// KubeVirt's HTTPS endpoints, for example virt-api, virt-handler, and the VM export data
+	// path. If this is not set, TLS 1.2 is the minimum accepted version and Go's default cipher
+	// suite selection is used.
+	// +optional
+	TLSConfiguration *TLSConfiguration `json:"tlsConfiguration,omitempty"`
+
 	// deprecated
 	SupportedGuestAgentVersions    []string                          `json:"supportedGuestAgentVersions,omitempty"`
 	MemBalloonStatsPeriod          *uint32                           `json:"memBalloonStatsPeriod,omitempty"`
@@ -2193,6 +2298,180 @@ type KubeVirtConfiguration struct {
 	HandlerConfiguration           *ReloadableComponentConfiguration `json:"handlerConfiguration,omitempty"`
 }
 
+// ExportConfiguration holds the cluster-wide defaults for VirtualMachineExport
+type ExportConfiguration struct {
+	// Deadline specifies how long an export server is allowed to run, starting from when the
+	// exporter pod is created, before the export is automatically stopped. This can be overridden
+	// per export with VirtualMachineExportSpec.Deadline. If neither is set, the export defaults to
+	// 24 hours.
+	// +optional
+	Deadline *metav1.Duration `json:"deadline,omitempty"`
+
+	// MaxConcurrentNamespaceExports limits the number of VirtualMachineExports that can be in the
+	// Ready phase at the same time within a single namespace. Additional exports are kept in the
+	// Pending phase, with a QuotaExceeded condition, until one of the Ready exports is no longer
+	// using an exporter pod. If this is not set, there is no limit.
+	// +optional
+	MaxConcurrentNamespaceExports *int32 `json:"maxConcurrentNamespaceExports,omitempty"`
+
+	// TTLDuration limits the lifetime of an export that does not specify its own
+	// VirtualMachineExportSpec.TTLDuration. If neither is set, the export is not cleaned up
+	// automatically.
+	// +optional
+	TTLDuration *metav1.Duration `json:"ttlDuration,omitempty"`
+
+	// PodResourceRequirements specifies the resource requirements applied to the exporter pod's
+	// container, for every export in the cluster. If this is not set, the exporter pod's
+	// container has no resource requests or limits.
+	// +optional
+	PodResourceRequirements *k8sv1.ResourceRequirements `json:"podResourceRequirements,omitempty"`
+
+	// IngressSelector, if set, restricts external link auto-detection to Ingresses and Routes
+	// pointing at the export proxy service whose labels match this selector, instead of using the
+	// first one found. This is useful on multi-ingress clusters to control which entry point
+	// exports are published on. This can be overridden per export with
+	// VirtualMachineExportSpec.ExternalHostname. If neither is set, every Ingress and Route
+	// pointing at the export proxy service is considered, matching the behavior as before this
+	// field was introduced.
+	// +optional
+	IngressSelector *metav1.LabelSelector `json:"ingressSelector,omitempty"`
+
+	// RequeueInterval controls how often the export controller polls a VirtualMachineExport whose
+	// source is not yet available, for example a PVC that is not yet bound. If this is not set, it
+	// defaults to 3 seconds. This does not affect exports waiting on a snapshot restore, which
+	// already poll less frequently since a restore takes noticeably longer.
+	// +optional
+	RequeueInterval *metav1.Duration `json:"requeueInterval,omitempty"`
+
+	// PodTTLAfterFinished controls how long a Succeeded or Failed exporter pod is kept around
+	// before being deleted, so its logs remain available to diagnose a failed download. If this
+	// is not set, the exporter pod is deleted as soon as it finishes.
+	// +optional
+	PodTTLAfterFinished *metav1.Duration `json:"podTTLAfterFinished,omitempty"`
+
+	// Replicas controls how many exporter pods are started for every VirtualMachineExport whose
+	// source has been claimed, all sharing the same labels so the export Service load-balances
+	// across them. This spreads download traffic for a volume across more than one pod's
+	// network and CPU. Only the first exporter pod is tracked in VirtualMachineExportStatus; the
+	// others are created and torn down alongside it. If this is not set, it defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// PodRunAsUser overrides the user ID the exporter pod's container runs as. If this is not
+	// set, the container runs as whatever user its image defaults to.
+	// +optional
+	PodRunAsUser *int64 `json:"podRunAsUser,omitempty"`
+
+	// PodFSGroup overrides the group ID the exporter pod's volumes are made accessible to. This
+	// is useful for storage backends that only grant access to a specific group ID. If this is
+	// not set, it defaults to the kvm group, matching the behavior as before this field was
+	// introduced.
+	// +optional
+	PodFSGroup *int64 `json:"podFSGroup,omitempty"`
+
+	// ZstdCompressionLevel controls the compression level used for the zstd and tar.zst export
+	// formats, from 1 (fastest) to 19 (smallest). If this is not set, it defaults to 3, which is
+	// zstd's own default.
+	// +optional
+	ZstdCompressionLevel *int32 `json:"zstdCompressionLevel,omitempty"`
+
+	// GzipCompressionLevel controls the compression level used for the gz and tar.gz export
+	// formats, from 1 (fastest) to 9 (smallest). If this is not set, it defaults to compress/gzip's
+	// own default.
+	// +optional
+	GzipCompressionLevel *int32 `json:"gzipCompressionLevel,omitempty"`
+
+	// ShutdownGracePeriod controls how long an exporter pod waits for in-flight downloads to
+	// finish once it starts shutting down, whether because its Deadline was reached or because
+	// the VirtualMachineExport was deleted, before forcibly closing remaining connections. If
+	// this is not set, it defaults to 30 seconds.
+	// +optional
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+
+	// PerConnectionBandwidthLimit caps the egress bandwidth of each individual download
+	// connection an exporter pod serves. If this is not set, individual connections are
+	// unlimited.
+	// +optional
+	PerConnectionBandwidthLimit *resource.Quantity `json:"perConnectionBandwidthLimit,omitempty"`
+
+	// TotalBandwidthLimit caps the combined egress bandwidth an exporter pod serves across every
+	// connection it currently has open. This bounds how much of the node's shared NIC a bulk
+	// export can consume, which matters most when many connections are downloading the same
+	// export at once. If this is not set, an exporter pod's total bandwidth is unlimited.
+	// +optional
+	TotalBandwidthLimit *resource.Quantity `json:"totalBandwidthLimit,omitempty"`
+
+	// MaxConcurrentDownloads caps how many downloads an exporter pod serves at once, across
+	// every volume and format it exposes combined. Requests beyond the limit get a 503 with a
+	// Retry-After header instead of being served, so a single export can't be used to exhaust
+	// the pod's resources. If this is not set, the number of concurrent downloads is unlimited.
+	// +optional
+	MaxConcurrentDownloads *int32 `json:"maxConcurrentDownloads,omitempty"`
+
+	// PerClientIPRequestLimit caps how many requests per second a single client IP may make
+	// against an exporter pod, to mitigate an abusive or runaway client hammering a download
+	// endpoint. A client that exceeds it immediately gets a 429 instead of queuing. If this is
+	// not set, per-client IP request rate limiting is disabled.
+	// +optional
+	PerClientIPRequestLimit *int32 `json:"perClientIPRequestLimit,omitempty"`
+
+	// PerClientIPRequestBurst is how many requests a client IP may make in a single burst
+	// before PerClientIPRequestLimit applies. If this is not set, it defaults to 1.
+	// +optional
+	PerClientIPRequestBurst *int32 `json:"perClientIPRequestBurst,omitempty"`
+
+	// EnableNBD additionally has an exporter pod expose each of its volumes over NBD (Network
+	// Block Device), so tools like qemu-img convert or backup software can read a disk with
+	// random access instead of downloading the whole image. NBD connections are authenticated
+	// with TLS-PSK, using the same tokens accepted by the HTTPS download endpoints as the
+	// pre-shared keys. If this is not set, NBD is not exposed.
+	// +optional
+	EnableNBD *bool `json:"enableNBD,omitempty"`
+
+	// ScratchSpaceSize sets the size of the emptyDir mounted into every exporter pod's container
+	// for temporary compression and format conversion state, backing its writable /var/run/kubevirt
+	// scratch directory now that the container's root filesystem is read-only. This can be
+	// overridden per export with VirtualMachineExportSpec.ScratchSpaceSize. If neither is set, it
+	// defaults to 1Gi.
+	// +optional
+	ScratchSpaceSize *resource.Quantity `json:"scratchSpaceSize,omitempty"`
+
+	// PrecomputeFormats lists raw-volume export formats an exporter pod should compress into its
+	// scratch space right after startup, instead of on the first request for them, so a
+	// frequently-downloaded export doesn't pay the compression cost on its first download either.
+	// Accepted values are "gz" and "zstd". This can be overridden per export with
+	// VirtualMachineExportSpec.PrecomputeFormats. If neither is set, every download is compressed
+	// on demand as before.
+	// +optional
+	// +listType=set
+	PrecomputeFormats []string `json:"precomputeFormats,omitempty"`
+}
+
+// TLSProtocolVersion represents a TLS protocol version.
+type TLSProtocolVersion string
+
+const (
+	VersionTLS10 TLSProtocolVersion = "VersionTLS10"
+	VersionTLS11 TLSProtocolVersion = "VersionTLS11"
+	VersionTLS12 TLSProtocolVersion = "VersionTLS12"
+	VersionTLS13 TLSProtocolVersion = "VersionTLS13"
+)
+
+// TLSConfiguration holds the cluster-wide TLS crypto policy
+type TLSConfiguration struct {
+	// MinTLSVersion is the minimum TLS version accepted by KubeVirt's HTTPS endpoints. If this
+	// is not set, it defaults to VersionTLS12.
+	// +optional
+	MinTLSVersion TLSProtocolVersion `json:"minTLSVersion,omitempty"`
+
+	// Ciphers is the list of accepted TLS cipher suite names, as reported by
+	// crypto/tls.CipherSuites and crypto/tls.InsecureCipherSuites. If this is not set, or none
+	// of the named ciphers are recognized, Go's default cipher suite selection is used.
+	// +optional
+	// +listType=atomic
+	Ciphers []string `json:"ciphers,omitempty"`
+}
+
 type SMBiosConfiguration struct {
 	Manufacturer string `json:"manufacturer,omitempty"`
 	Product      string `json:"product,omitempty"`
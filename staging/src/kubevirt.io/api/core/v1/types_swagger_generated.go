@@ -20,6 +20,8 @@ func (VirtualMachineInstanceSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                              "VirtualMachineInstanceSpec is a description of a VirtualMachineInstance.",
 		"priorityClassName":             "If specified, indicates the pod's priority.\nIf not specified, the pod priority will be default or zero if there is no\ndefault.\n+optional",
+		"selinuxContext":                "If specified, overrides the cluster-wide SELinux type configured for virt-launcher\nwith a custom SELinux type for this VirtualMachineInstance's compute container.\nSetting this field requires the SELinuxCustomType feature gate to be enabled.\n+optional",
+		"seccompProfile":                "If specified, the seccomp profile for the virt-launcher compute container, which runs qemu.\nIf not specified, the container runtime's default profile is used, same as for any other container.\n+optional",
 		"domain":                        "Specification of the desired behavior of the VirtualMachineInstance on the host.",
 		"nodeSelector":                  "NodeSelector is a selector which must be true for the vmi to fit on a node.\nSelector which must match a node's labels for the vmi to be scheduled on that node.\nMore info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/\n+optional",
 		"affinity":                      "If affinity is specifies, obey all the affinity rules",
@@ -318,13 +320,14 @@ func (VirtualMachineList) SwaggerDoc() map[string]string {
 
 func (VirtualMachineSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                    "VirtualMachineSpec describes how the proper VirtualMachine\nshould look like",
-		"running":             "Running controls whether the associatied VirtualMachineInstance is created or not\nMutually exclusive with RunStrategy",
-		"runStrategy":         "Running state indicates the requested running state of the VirtualMachineInstance\nmutually exclusive with Running",
-		"instancetype":        "InstancetypeMatcher references a instancetype that is used to fill fields in Template",
-		"preference":          "PreferenceMatcher references a set of preference that is used to fill fields in Template",
-		"template":            "Template is the direct specification of VirtualMachineInstance",
-		"dataVolumeTemplates": "dataVolumeTemplates is a list of dataVolumes that the VirtualMachineInstance template can reference.\nDataVolumes in this list are dynamically created for the VirtualMachine and are tied to the VirtualMachine's life-cycle.",
+		"":                          "VirtualMachineSpec describes how the proper VirtualMachine\nshould look like",
+		"running":                   "Running controls whether the associatied VirtualMachineInstance is created or not\nMutually exclusive with RunStrategy",
+		"runStrategy":               "Running state indicates the requested running state of the VirtualMachineInstance\nmutually exclusive with Running",
+		"instancetype":              "InstancetypeMatcher references a instancetype that is used to fill fields in Template",
+		"preference":                "PreferenceMatcher references a set of preference that is used to fill fields in Template",
+		"template":                  "Template is the direct specification of VirtualMachineInstance",
+		"dataVolumeTemplates":       "dataVolumeTemplates is a list of dataVolumes that the VirtualMachineInstance template can reference.\nDataVolumes in this list are dynamically created for the VirtualMachine and are tied to the VirtualMachine's life-cycle.",
+		"dataVolumeRetentionPolicy": "DataVolumeRetentionPolicy specifies whether the DataVolumes created from dataVolumeTemplates should be\ndeleted, retained, or adopted when the VirtualMachine they belong to is deleted. If unset, the\ncluster-wide default configured in KubeVirtConfiguration is used, which itself defaults to deleting them.",
 	}
 }
 
@@ -336,18 +339,19 @@ func (VirtualMachineStartFailure) SwaggerDoc() map[string]string {
 
 func (VirtualMachineStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                       "VirtualMachineStatus represents the status returned by the\ncontroller to describe how the VirtualMachine is doing",
-		"snapshotInProgress":     "SnapshotInProgress is the name of the VirtualMachineSnapshot currently executing",
-		"restoreInProgress":      "RestoreInProgress is the name of the VirtualMachineRestore currently executing",
-		"created":                "Created indicates if the virtual machine is created in the cluster",
-		"ready":                  "Ready indicates if the virtual machine is running and ready",
-		"printableStatus":        "PrintableStatus is a human readable, high-level representation of the status of the virtual machine",
-		"conditions":             "Hold the state information of the VirtualMachine and its VirtualMachineInstance",
-		"stateChangeRequests":    "StateChangeRequests indicates a list of actions that should be taken on a VMI\ne.g. stop a specific VMI then start a new one.",
-		"volumeRequests":         "VolumeRequests indicates a list of volumes add or remove from the VMI template and\nhotplug on an active running VMI.\n+listType=atomic",
-		"volumeSnapshotStatuses": "VolumeSnapshotStatuses indicates a list of statuses whether snapshotting is\nsupported by each volume.",
-		"startFailure":           "StartFailure tracks consecutive VMI startup failures for the purposes of\ncrash loop backoffs\n+nullable\n+optional",
-		"memoryDumpRequest":      "MemoryDumpRequest tracks memory dump request phase and info of getting a memory\ndump to the given pvc\n+nullable\n+optional",
+		"":                        "VirtualMachineStatus represents the status returned by the\ncontroller to describe how the VirtualMachine is doing",
+		"snapshotInProgress":      "SnapshotInProgress is the name of the VirtualMachineSnapshot currently executing",
+		"restoreInProgress":       "RestoreInProgress is the name of the VirtualMachineRestore currently executing",
+		"created":                 "Created indicates if the virtual machine is created in the cluster",
+		"ready":                   "Ready indicates if the virtual machine is running and ready",
+		"printableStatus":         "PrintableStatus is a human readable, high-level representation of the status of the virtual machine",
+		"conditions":              "Hold the state information of the VirtualMachine and its VirtualMachineInstance",
+		"stateChangeRequests":     "StateChangeRequests indicates a list of actions that should be taken on a VMI\ne.g. stop a specific VMI then start a new one.",
+		"volumeRequests":          "VolumeRequests indicates a list of volumes add or remove from the VMI template and\nhotplug on an active running VMI.\n+listType=atomic",
+		"volumeSnapshotStatuses":  "VolumeSnapshotStatuses indicates a list of statuses whether snapshotting is\nsupported by each volume.",
+		"startFailure":            "StartFailure tracks consecutive VMI startup failures for the purposes of\ncrash loop backoffs\n+nullable\n+optional",
+		"memoryDumpRequest":       "MemoryDumpRequest tracks memory dump request phase and info of getting a memory\ndump to the given pvc\n+nullable\n+optional",
+		"volumeBackupCheckpoints": "VolumeBackupCheckpoints tracks the most recent backup checkpoint recorded against each\nvolume. It is populated through the backupcheckpoint subresource and lets an external\nbackup vendor, after quiescing the guest with the freeze/unfreeze subresources,\ncorrelate its next incremental backup with the checkpoint it follows. KubeVirt does not\ntrack which blocks changed between checkpoints itself.\n+optional\n+listType=map\n+listMapKey=volumeName",
 	}
 }
 
@@ -644,6 +648,24 @@ func (RemoveVolumeOptions) SwaggerDoc() map[string]string {
 	}
 }
 
+func (VirtualMachineVolumeBackupCheckpoint) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "VirtualMachineVolumeBackupCheckpoint records a backup checkpoint a backup vendor has taken\nagainst one of the VirtualMachine's volumes.",
+		"volumeName":     "VolumeName is the name of the volume, as listed among the VirtualMachine's volumes,\nthat this checkpoint was taken against.",
+		"checkpointName": "CheckpointName is the vendor-supplied identifier for this checkpoint.",
+		"creationTime":   "CreationTime is the time the checkpoint was recorded.\n+nullable\n+optional",
+	}
+}
+
+func (BackupCheckpointOptions) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "BackupCheckpointOptions are the options to record a new backup checkpoint against a volume.",
+		"volumeName":     "VolumeName is the name of the volume this checkpoint is taken against.",
+		"checkpointName": "CheckpointName is the vendor-supplied identifier for this checkpoint.",
+		"dryRun":         "When present, indicates that modifications should not be\npersisted. An invalid or unrecognized dryRun directive will\nresult in an error response and no further processing of the\nrequest. Valid values are:\n- All: all dry run stages will be processed\n+optional\n+listType=atomic",
+	}
+}
+
 func (TokenBucketRateLimiter) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"qps":   "QPS indicates the maximum QPS to the apiserver from this client.\nIf it's zero, the component default will be used",
@@ -672,8 +694,30 @@ func (ReloadableComponentConfiguration) SwaggerDoc() map[string]string {
 func (KubeVirtConfiguration) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                            "KubeVirtConfiguration holds all kubevirt configurations",
+		"dataVolumeRetentionPolicy":   "DataVolumeRetentionPolicy specifies the cluster-wide default for whether DataVolumes created from\ndataVolumeTemplates are deleted, retained, or adopted when their owning VirtualMachine is deleted. This can\nbe overridden per VirtualMachine via VirtualMachineSpec.DataVolumeRetentionPolicy. Defaults to Delete.",
 		"evictionStrategy":            "EvictionStrategy defines at the cluster level if the VirtualMachineInstance should be\nmigrated instead of shut-off in case of a node drain. If the VirtualMachineInstance specific\nfield is set it overrides the cluster level one.",
+		"vmExport":                    "ExportConfiguration contains the cluster-wide defaults for exports created with\nVirtualMachineExport.\n+optional",
 		"supportedGuestAgentVersions": "deprecated",
+		"filesystemOverhead":          "FilesystemOverhead is a percentage of filesystem's size to be reserved when resizing the PVC, with an optional\nper-StorageClass override. This takes precedence over the overhead reported by CDI, which is useful when a\nStorageClass's filesystem doesn't match CDI's assumption of a single global default.",
+	}
+}
+
+func (ExportConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                              "ExportConfiguration holds the cluster-wide defaults for VirtualMachineExport",
+		"deadline":                      "Deadline specifies how long an export server is allowed to run, starting from when the\nexporter pod is created, before the export is automatically stopped. This can be overridden\nper export with VirtualMachineExportSpec.Deadline. If neither is set, the export defaults to\n24 hours.\n+optional",
+		"maxConcurrentNamespaceExports": "MaxConcurrentNamespaceExports limits the number of VirtualMachineExports that can be in the\nReady phase at the same time within a single namespace. Additional exports are kept in the\nPending phase, with a QuotaExceeded condition, until one of the Ready exports is no longer\nusing an exporter pod. If this is not set, there is no limit.\n+optional",
+		"ttlDuration":                   "TTLDuration limits the lifetime of an export that does not specify its own\nVirtualMachineExportSpec.TTLDuration. If neither is set, the export is not cleaned up\nautomatically.\n+optional",
+		"podResourceRequirements":       "PodResourceRequirements specifies the resource requirements applied to the exporter pod's\ncontainer, for every export in the cluster. If this is not set, the exporter pod's\ncontainer has no resource requests or limits.\n+optional",
+		"ingressSelector":               "IngressSelector, if set, restricts external link auto-detection to Ingresses and Routes\npointing at the export proxy service whose labels match this selector, instead of using the\nfirst one found. This is useful on multi-ingress clusters to control which entry point\nexports are published on. This can be overridden per export with\nVirtualMachineExportSpec.ExternalHostname. If neither is set, every Ingress and Route\npointing at the export proxy service is considered, matching the behavior as before this\nfield was introduced.\n+optional",
+		"requeueInterval":               "RequeueInterval controls how often the export controller polls a VirtualMachineExport whose\nsource is not yet available, for example a PVC that is not yet bound. If this is not set, it\ndefaults to 3 seconds. This does not affect exports waiting on a snapshot restore, which\nalready poll less frequently since a restore takes noticeably longer.\n+optional",
+		"podTTLAfterFinished":           "PodTTLAfterFinished controls how long a Succeeded or Failed exporter pod is kept around\nbefore being deleted, so its logs remain available to diagnose a failed download. If this\nis not set, the exporter pod is deleted as soon as it finishes.\n+optional",
+		"replicas":                      "Replicas controls how many exporter pods are started for every VirtualMachineExport whose\nsource has been claimed, all sharing the same labels so the export Service load-balances\nacross them. This spreads download traffic for a volume across more than one pod's\nnetwork and CPU. Only the first exporter pod is tracked in VirtualMachineExportStatus; the\nothers are created and torn down alongside it. If this is not set, it defaults to 1.\n+optional",
+		"podRunAsUser":                  "PodRunAsUser overrides the user ID the exporter pod's container runs as. If this is not\nset, the container runs as whatever user its image defaults to.\n+optional",
+		"podFSGroup":                    "PodFSGroup overrides the group ID the exporter pod's volumes are made accessible to. This\nis useful for storage backends that only grant access to a specific group ID. If this is\nnot set, it defaults to the kvm group, matching the behavior as before this field was\nintroduced.\n+optional",
+		"zstdCompressionLevel":          "ZstdCompressionLevel controls the compression level used for the zstd and tar.zst export\nformats, from 1 (fastest) to 19 (smallest). If this is not set, it defaults to 3, which is\nzstd's own default.\n+optional",
+		"perConnectionBandwidthLimit":   "PerConnectionBandwidthLimit caps the egress bandwidth of each individual download\nconnection an exporter pod serves. If this is not set, individual connections are\nunlimited.\n+optional",
+		"totalBandwidthLimit":           "TotalBandwidthLimit caps the combined egress bandwidth an exporter pod serves across every\nconnection it currently has open. This bounds how much of the node's shared NIC a bulk\nexport can consume, which matters most when many connections are downloading the same\nexport at once. If this is not set, an exporter pod's total bandwidth is unlimited.\n+optional",
 	}
 }
 
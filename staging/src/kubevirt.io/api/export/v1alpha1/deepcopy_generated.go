@@ -22,6 +22,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -75,6 +77,55 @@ func (in *VirtualMachineExport) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportClusterUpload) DeepCopyInto(out *VirtualMachineExportClusterUpload) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportClusterUpload.
+func (in *VirtualMachineExportClusterUpload) DeepCopy() *VirtualMachineExportClusterUpload {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportClusterUpload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportClusterUploadStatus) DeepCopyInto(out *VirtualMachineExportClusterUploadStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportClusterUploadStatus.
+func (in *VirtualMachineExportClusterUploadStatus) DeepCopy() *VirtualMachineExportClusterUploadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportClusterUploadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportConditionTransition) DeepCopyInto(out *VirtualMachineExportConditionTransition) {
+	*out = *in
+	in.TransitionTimestamp.DeepCopyInto(&out.TransitionTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportConditionTransition.
+func (in *VirtualMachineExportConditionTransition) DeepCopy() *VirtualMachineExportConditionTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportConditionTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineExportLink) DeepCopyInto(out *VirtualMachineExportLink) {
 	*out = *in
@@ -85,6 +136,25 @@ func (in *VirtualMachineExportLink) DeepCopyInto(out *VirtualMachineExportLink)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Ova != nil {
+		in, out := &in.Ova, &out.Ova
+		*out = new(VirtualMachineExportVolumeFormat)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.All != nil {
+		in, out := &in.All, &out.All
+		*out = new(VirtualMachineExportVolumeFormat)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]VirtualMachineExportManifest, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationTimestamp != nil {
+		in, out := &in.ExpirationTimestamp, &out.ExpirationTimestamp
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -111,6 +181,13 @@ func (in *VirtualMachineExportLinks) DeepCopyInto(out *VirtualMachineExportLinks
 		*out = new(VirtualMachineExportLink)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExternalLinks != nil {
+		in, out := &in.ExternalLinks, &out.ExternalLinks
+		*out = make([]VirtualMachineExportLink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -157,10 +234,274 @@ func (in *VirtualMachineExportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportManifest) DeepCopyInto(out *VirtualMachineExportManifest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportManifest.
+func (in *VirtualMachineExportManifest) DeepCopy() *VirtualMachineExportManifest {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportManifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportHooks) DeepCopyInto(out *VirtualMachineExportHooks) {
+	*out = *in
+	if in.PreServeCommand != nil {
+		in, out := &in.PreServeCommand, &out.PreServeCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostServeCommand != nil {
+		in, out := &in.PostServeCommand, &out.PostServeCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportHooks.
+func (in *VirtualMachineExportHooks) DeepCopy() *VirtualMachineExportHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportRegistryUpload) DeepCopyInto(out *VirtualMachineExportRegistryUpload) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportRegistryUpload.
+func (in *VirtualMachineExportRegistryUpload) DeepCopy() *VirtualMachineExportRegistryUpload {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportRegistryUpload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportRegistryUploadStatus) DeepCopyInto(out *VirtualMachineExportRegistryUploadStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportRegistryUploadStatus.
+func (in *VirtualMachineExportRegistryUploadStatus) DeepCopy() *VirtualMachineExportRegistryUploadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportRegistryUploadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportS3Upload) DeepCopyInto(out *VirtualMachineExportS3Upload) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportS3Upload.
+func (in *VirtualMachineExportS3Upload) DeepCopy() *VirtualMachineExportS3Upload {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportS3Upload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportS3UploadStatus) DeepCopyInto(out *VirtualMachineExportS3UploadStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportS3UploadStatus.
+func (in *VirtualMachineExportS3UploadStatus) DeepCopy() *VirtualMachineExportS3UploadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportS3UploadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportScopedTokenSecretRef) DeepCopyInto(out *VirtualMachineExportScopedTokenSecretRef) {
+	*out = *in
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxReads != nil {
+		in, out := &in.MaxReads, &out.MaxReads
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportScopedTokenSecretRef.
+func (in *VirtualMachineExportScopedTokenSecretRef) DeepCopy() *VirtualMachineExportScopedTokenSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportScopedTokenSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineExportSpec) DeepCopyInto(out *VirtualMachineExportSpec) {
 	*out = *in
 	in.Source.DeepCopyInto(&out.Source)
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdditionalTokenSecretRefs != nil {
+		in, out := &in.AdditionalTokenSecretRefs, &out.AdditionalTokenSecretRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScopedTokenSecretRefs != nil {
+		in, out := &in.ScopedTokenSecretRefs, &out.ScopedTokenSecretRefs
+		*out = make([]VirtualMachineExportScopedTokenSecretRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TTLDuration != nil {
+		in, out := &in.TTLDuration, &out.TTLDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.VolumeNames != nil {
+		in, out := &in.VolumeNames, &out.VolumeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quiesce != nil {
+		in, out := &in.Quiesce, &out.Quiesce
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(VirtualMachineExportDeletionPolicy)
+		**out = **in
+	}
+	if in.S3Upload != nil {
+		in, out := &in.S3Upload, &out.S3Upload
+		*out = new(VirtualMachineExportS3Upload)
+		**out = **in
+	}
+	if in.RegistryUpload != nil {
+		in, out := &in.RegistryUpload, &out.RegistryUpload
+		*out = new(VirtualMachineExportRegistryUpload)
+		**out = **in
+	}
+	if in.ClusterUpload != nil {
+		in, out := &in.ClusterUpload, &out.ClusterUpload
+		*out = new(VirtualMachineExportClusterUpload)
+		**out = **in
+	}
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]ExportVolumeFormat, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodResourceRequirements != nil {
+		in, out := &in.PodResourceRequirements, &out.PodResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OnDemand != nil {
+		in, out := &in.OnDemand, &out.OnDemand
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PerVolumePods != nil {
+		in, out := &in.PerVolumePods, &out.PerVolumePods
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExternalHostname != nil {
+		in, out := &in.ExternalHostname, &out.ExternalHostname
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExternalTLSSecretRef != nil {
+		in, out := &in.ExternalTLSSecretRef, &out.ExternalTLSSecretRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.EncryptionSecretRef != nil {
+		in, out := &in.EncryptionSecretRef, &out.EncryptionSecretRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientCertificateAuthorityRef != nil {
+		in, out := &in.ClientCertificateAuthorityRef, &out.ClientCertificateAuthorityRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.RestoreStorageClassName != nil {
+		in, out := &in.RestoreStorageClassName, &out.RestoreStorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScratchSpaceSize != nil {
+		in, out := &in.ScratchSpaceSize, &out.ScratchSpaceSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ArchiveIncludePatterns != nil {
+		in, out := &in.ArchiveIncludePatterns, &out.ArchiveIncludePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ArchiveExcludePatterns != nil {
+		in, out := &in.ArchiveExcludePatterns, &out.ArchiveExcludePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(VirtualMachineExportHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrecomputeFormats != nil {
+		in, out := &in.PrecomputeFormats, &out.PrecomputeFormats
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -182,6 +523,11 @@ func (in *VirtualMachineExportStatus) DeepCopyInto(out *VirtualMachineExportStat
 		*out = new(VirtualMachineExportLinks)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(string)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]Condition, len(*in))
@@ -189,6 +535,20 @@ func (in *VirtualMachineExportStatus) DeepCopyInto(out *VirtualMachineExportStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VolumeStatuses != nil {
+		in, out := &in.VolumeStatuses, &out.VolumeStatuses
+		*out = make([]VirtualMachineExportVolumeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConditionTransitions != nil {
+		in, out := &in.ConditionTransitions, &out.ConditionTransitions
+		*out = make([]VirtualMachineExportConditionTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -208,7 +568,9 @@ func (in *VirtualMachineExportVolume) DeepCopyInto(out *VirtualMachineExportVolu
 	if in.Formats != nil {
 		in, out := &in.Formats, &out.Formats
 		*out = make([]VirtualMachineExportVolumeFormat, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -226,6 +588,11 @@ func (in *VirtualMachineExportVolume) DeepCopy() *VirtualMachineExportVolume {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineExportVolumeFormat) DeepCopyInto(out *VirtualMachineExportVolumeFormat) {
 	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -238,3 +605,68 @@ func (in *VirtualMachineExportVolumeFormat) DeepCopy() *VirtualMachineExportVolu
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportVolumeProgress) DeepCopyInto(out *VirtualMachineExportVolumeProgress) {
+	*out = *in
+	if in.PercentComplete != nil {
+		in, out := &in.PercentComplete, &out.PercentComplete
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportVolumeProgress.
+func (in *VirtualMachineExportVolumeProgress) DeepCopy() *VirtualMachineExportVolumeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportVolumeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportVolumeStatus) DeepCopyInto(out *VirtualMachineExportVolumeStatus) {
+	*out = *in
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(VirtualMachineExportVolumeProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3Upload != nil {
+		in, out := &in.S3Upload, &out.S3Upload
+		*out = new(VirtualMachineExportS3UploadStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryUpload != nil {
+		in, out := &in.RegistryUpload, &out.RegistryUpload
+		*out = new(VirtualMachineExportRegistryUploadStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterUpload != nil {
+		in, out := &in.ClusterUpload, &out.ClusterUpload
+		*out = new(VirtualMachineExportClusterUploadStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastDownloadStartTimestamp != nil {
+		in, out := &in.LastDownloadStartTimestamp, &out.LastDownloadStartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDownloadCompletionTimestamp != nil {
+		in, out := &in.LastDownloadCompletionTimestamp, &out.LastDownloadCompletionTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportVolumeStatus.
+func (in *VirtualMachineExportVolumeStatus) DeepCopy() *VirtualMachineExportVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -21,11 +21,17 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	App = "virt-exporter"
+
+	// AnnotationExportClaimed is set on a VirtualMachineExport with spec.onDemand set, to trigger
+	// creation of its exporter pod. It is meant to be set by whatever fronts the download, such as
+	// the export proxy handling the first request for it, once spec.onDemand is in use.
+	AnnotationExportClaimed = "export.kubevirt.io/claimed"
 )
 
 // VirtualMachineExport defines the operation of exporting a VM source
@@ -54,10 +60,335 @@ type VirtualMachineExportList struct {
 type VirtualMachineExportSpec struct {
 	Source corev1.TypedLocalObjectReference `json:"source"`
 
-	// TokenSecretRef is the name of the secret that contains the token used by the export server pod
-	TokenSecretRef string `json:"tokenSecretRef"`
+	// TokenSecretRef is the name of the secret that contains the token used by the export server pod.
+	// If this is not set, a secret containing a randomly generated token is created and owned by the
+	// VirtualMachineExport, and its name is published in status.tokenSecretRef.
+	// +optional
+	TokenSecretRef *string `json:"tokenSecretRef,omitempty"`
+
+	// AdditionalTokenSecretRefs is a list of names of secrets that also contain a token accepted by
+	// the export server pod, in addition to the one in tokenSecretRef. This allows rotating the
+	// token in tokenSecretRef to a new secret while still accepting downloads that were started
+	// with the previous token, until it is removed from this list.
+	// +optional
+	// +listType=set
+	AdditionalTokenSecretRefs []string `json:"additionalTokenSecretRefs,omitempty"`
+
+	// ScopedTokenSecretRefs is a list of secrets that also contain a token accepted by the export
+	// server pod, each narrowed by its own scope. Unlike additionalTokenSecretRefs, a scoped
+	// token only grants access to the volumes (and up to the number of reads) its scope allows,
+	// so one export can hand out narrowly-scoped download credentials to different consumers.
+	// +optional
+	// +listType=atomic
+	ScopedTokenSecretRefs []VirtualMachineExportScopedTokenSecretRef `json:"scopedTokenSecretRefs,omitempty"`
+
+	// TTLDuration limits the lifetime of an export.
+	// If this field is set, after this duration has passed from deployment,
+	// the export is automatically deleted. If this field is not set, the
+	// export will not get cleaned up.
+	// +optional
+	TTLDuration *metav1.Duration `json:"ttlDuration,omitempty"`
+
+	// VolumeNames is a list of the volumes to export from the source. If this
+	// list is empty, all volumes are exported. This only applies to sources
+	// that resolve to more than one volume, such as a VirtualMachine.
+	// +optional
+	// +listType=set
+	VolumeNames []string `json:"volumeNames,omitempty"`
+
+	// Quiesce requests that, if the Virtual Machine source is running and the
+	// guest agent is available, the controller freeze the guest file systems
+	// before exposing the export, and thaw them again once the export is
+	// ready. This allows an export to be taken from a running Virtual Machine
+	// without having to stop it first. If the guest agent is not available,
+	// this field has no effect and an export of a source that is in use
+	// continues to be blocked until the source becomes available.
+	// +optional
+	Quiesce *bool `json:"quiesce,omitempty"`
+
+	// Deadline specifies how long the export server is allowed to run, starting from when the
+	// exporter pod is created, before the export is automatically stopped. If this field is not
+	// set, the cluster default configured in KubeVirtConfiguration is used, or 24 hours if that
+	// is also not set.
+	// +optional
+	Deadline *metav1.Duration `json:"deadline,omitempty"`
+
+	// DeletionPolicy controls what happens to the secret holding the export token that
+	// TokenSecretRef refers to when the VirtualMachineExport is deleted, if that secret was
+	// auto-generated rather than provided by the user in spec.tokenSecretRef. If this is not
+	// set, Delete is used.
+	// +optional
+	DeletionPolicy *VirtualMachineExportDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// S3Upload, if set, additionally has the exporter pod push each exported volume's primary
+	// artifact to an S3-compatible object store, alongside still serving it for HTTPS download.
+	// +optional
+	S3Upload *VirtualMachineExportS3Upload `json:"s3Upload,omitempty"`
+
+	// RegistryUpload, if set, additionally has the exporter pod build each exported volume's raw
+	// artifact into a containerDisk image and push it to an OCI registry, alongside still serving
+	// it for HTTPS download.
+	// +optional
+	RegistryUpload *VirtualMachineExportRegistryUpload `json:"registryUpload,omitempty"`
+
+	// ClusterUpload, if set, additionally has the exporter pod create a DataVolume for each
+	// exported volume's raw artifact on another cluster and stream the artifact straight into
+	// it, alongside still serving it for HTTPS download.
+	// +optional
+	ClusterUpload *VirtualMachineExportClusterUpload `json:"clusterUpload,omitempty"`
+
+	// Formats restricts which of the volume formats that would otherwise be available are
+	// converted and advertised in status.links. This is useful to skip the cost of converting
+	// to formats that will not be used. If this list is empty, every format applicable to the
+	// source is made available, which is the same behavior as before this field was introduced.
+	// +optional
+	// +listType=set
+	Formats []ExportVolumeFormat `json:"formats,omitempty"`
+
+	// PodResourceRequirements specifies the resource requirements applied to this export's
+	// exporter pod container. If this is not set, the cluster default configured in
+	// KubeVirtConfiguration is used, or the exporter pod's container has no resource requests
+	// or limits if that is also not set.
+	// +optional
+	PodResourceRequirements *corev1.ResourceRequirements `json:"podResourceRequirements,omitempty"`
+
+	// Paused, if true, tears down the exporter pod without deleting the VirtualMachineExport,
+	// its service, or its token secret. Setting this back to false, or unsetting it, resumes
+	// the export, recreating the exporter pod. This allows operators to temporarily stop
+	// serving data, for example during a maintenance window, without losing the export's
+	// configuration or having consumers need a new token or URL once it resumes.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
+	// OnDemand, if true, holds off creating the exporter pod until the export is claimed, by
+	// annotating the VirtualMachineExport with export.kubevirt.io/claimed, instead of creating it
+	// as soon as the source volumes become available. This lets many exports be pre-created
+	// without each of them consuming node resources while idle, at the cost of the first download
+	// having to wait for the exporter pod to start.
+	// +optional
+	OnDemand *bool `json:"onDemand,omitempty"`
+
+	// PerVolumePods, if true and the source resolves to more than one volume, has the controller
+	// create one exporter pod and one Service per additional volume, instead of bundling every
+	// volume into the single exporter pod normally used. This lets conversions and downloads of
+	// different volumes run in parallel, on different nodes, instead of serializing through one
+	// pod. The first volume is still served by the export's regular exporter pod and Service,
+	// published in status.links as before; the per-volume pods and Services this creates for the
+	// remaining volumes are not yet published there.
+	// +optional
+	PerVolumePods *bool `json:"perVolumePods,omitempty"`
+
+	// ServiceType controls the type of the Service created to front the exporter pod. Valid
+	// values are NodePort and LoadBalancer. If this is not set, a ClusterIP service is created,
+	// matching the behavior as before this field was introduced. This is useful on clusters that
+	// have no Ingress or Route available to reach the export externally: setting it to NodePort
+	// or LoadBalancer causes the resulting external address to be published in
+	// status.links.external, the same as would happen with an Ingress or Route host.
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ExternalHostname overrides the hostname published in status.links.external and used as the
+	// exporter pod's serving certificate's Subject Alternative Name. If this is not set, the
+	// hostname is auto-detected from the first Ingress or Route pointing at the export, or, if
+	// spec.serviceType requested a NodePort or LoadBalancer service, from that service's address,
+	// matching the behavior as before this field was introduced.
+	// +optional
+	ExternalHostname *string `json:"externalHostname,omitempty"`
+
+	// ExternalTLSSecretRef is the name of a secret of type kubernetes.io/tls, in the same
+	// namespace as the VirtualMachineExport, whose certificate is published as the external
+	// link's cert instead of the one auto-detected from the matching Ingress or Route's TLS
+	// configuration. This is only used together with externalHostname, since without it there is
+	// no external link to attach the certificate to.
+	// +optional
+	ExternalTLSSecretRef *string `json:"externalTLSSecretRef,omitempty"`
+
+	// EncryptionSecretRef is the name of a secret, in the same namespace as the
+	// VirtualMachineExport, containing a 32-byte AES-256 key under the key "key". If this is
+	// set, the exporter pod encrypts every artifact it serves with that key before sending it,
+	// so the client must decrypt what it downloads with the same key. This adds a layer of
+	// protection for artifacts that traverse shared ingress infrastructure, on top of what TLS
+	// and the download token already provide. If this is not set, artifacts are served
+	// unencrypted, matching the behavior as before this field was introduced.
+	// +optional
+	EncryptionSecretRef *string `json:"encryptionSecretRef,omitempty"`
+
+	// ClientCertificateAuthorityRef is the name of a secret, in the same namespace as the
+	// VirtualMachineExport, containing a CA bundle under the key "ca.crt". If this is set, the
+	// exporter pod additionally accepts connections from clients that present a certificate
+	// signed by that CA, as an alternative to the download token, so exports can be consumed by
+	// automation that already has an mTLS identity. This does not disable token authentication;
+	// either is accepted. If this is not set, only the download token is accepted, matching the
+	// behavior as before this field was introduced.
+	// +optional
+	ClientCertificateAuthorityRef *string `json:"clientCertificateAuthorityRef,omitempty"`
+
+	// RestoreStorageClassName overrides the StorageClass used for the temporary PVCs created to
+	// restore a VirtualMachineSnapshot source's volumes for the duration of the export. If this
+	// is not set, each restore PVC reuses the StorageClass of the volume it was snapshotted from,
+	// matching the behavior as before this field was introduced. This is only used when the
+	// source is a VirtualMachineSnapshot; it has no effect on other source types.
+	// +optional
+	RestoreStorageClassName *string `json:"restoreStorageClassName,omitempty"`
+
+	// ScratchSpaceSize sets the size of the emptyDir mounted into the exporter pod for temporary
+	// compression and format conversion state. If this is not set, the cluster default configured
+	// in KubeVirtConfiguration is used, or 1Gi if that is also not set.
+	// +optional
+	ScratchSpaceSize *resource.Quantity `json:"scratchSpaceSize,omitempty"`
+
+	// ArchiveIncludePatterns restricts a dir or tar.gz/tar.zst export of a filesystem volume with
+	// non-kubevirt content to files whose path relative to the volume's root matches at least one
+	// of these glob patterns. If this is not set, every file is included, matching the behavior
+	// as before this field was introduced. This has no effect on a volume holding a kubevirt disk
+	// image, since that is always exported as a single disk image rather than an archive.
+	// +optional
+	// +listType=set
+	ArchiveIncludePatterns []string `json:"archiveIncludePatterns,omitempty"`
+
+	// ArchiveExcludePatterns drops files whose path relative to the volume's root matches any of
+	// these glob patterns from a tar.gz/tar.zst export of a filesystem volume with non-kubevirt
+	// content, applied after ArchiveIncludePatterns. The filesystem's own lost+found directory is
+	// always excluded, regardless of this field.
+	// +optional
+	// +listType=set
+	ArchiveExcludePatterns []string `json:"archiveExcludePatterns,omitempty"`
+
+	// Hooks, if set, has the exporter pod run external commands around serving this export's
+	// artifacts, for example to run a malware scan or apply a signature before anything becomes
+	// downloadable. Both commands must already exist in the exporter image; this field cannot
+	// supply arbitrary scripts of its own.
+	// +optional
+	Hooks *VirtualMachineExportHooks `json:"hooks,omitempty"`
+
+	// PrecomputeFormats lists raw-volume export formats the exporter pod should compress into its
+	// scratch space right after startup, instead of on the first request for them, so a
+	// frequently-downloaded export doesn't pay the compression cost on its first download either.
+	// Accepted values are "gz" and "zstd". If this is not set, the cluster default configured in
+	// KubeVirtConfiguration is used, or every download is compressed on demand if that is also not
+	// set, matching the behavior as before this field was introduced.
+	// +optional
+	// +listType=set
+	PrecomputeFormats []string `json:"precomputeFormats,omitempty"`
+}
+
+// VirtualMachineExportS3Upload configures the exporter pod to push exported volumes to an
+// S3-compatible object store, in addition to serving them for HTTPS download.
+type VirtualMachineExportS3Upload struct {
+	// Endpoint is the base URL of the S3-compatible service to upload to, for example
+	// https://s3.us-east-1.amazonaws.com or https://minio.example.com.
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket that exported volumes are uploaded into.
+	Bucket string `json:"bucket"`
+
+	// Region is the region to sign upload requests for. If this is not set, requests are signed
+	// for the us-east-1 region, which most S3-compatible services accept regardless of where
+	// they are actually hosted.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// CredentialsSecretRef is the name of a secret, in the same namespace as the
+	// VirtualMachineExport, containing the accessKeyId and secretAccessKey used to authenticate
+	// to Endpoint.
+	CredentialsSecretRef string `json:"credentialsSecretRef"`
+}
+
+// VirtualMachineExportRegistryUpload configures the exporter pod to build exported volumes into
+// containerDisk images and push them to an OCI registry, in addition to serving them for HTTPS
+// download. Only a volume's raw artifact is built into a containerDisk; volumes that only have a
+// dir or archive artifact are not uploaded.
+type VirtualMachineExportRegistryUpload struct {
+	// Repository is the registry repository to push exported volumes to, for example
+	// registry.example.com/exports/my-vm. Each volume is pushed as its own image, named
+	// <repository>/<volume name>.
+	Repository string `json:"repository"`
+
+	// Tag is the tag applied to each pushed image. If this is not set, latest is used.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// CredentialsSecretRef is the name of a secret, in the same namespace as the
+	// VirtualMachineExport, containing the username and password used to authenticate to
+	// Repository.
+	CredentialsSecretRef string `json:"credentialsSecretRef"`
+}
+
+// VirtualMachineExportClusterUpload configures the exporter pod to create a DataVolume for each
+// exported volume's raw artifact on another cluster and stream the artifact straight into it,
+// via that cluster's cdi-uploadproxy, in addition to serving it for HTTPS download. Only a
+// volume's raw artifact is transferred this way; volumes that only have a dir or archive
+// artifact are not uploaded.
+type VirtualMachineExportClusterUpload struct {
+	// Namespace is the namespace on the target cluster to create DataVolumes in.
+	Namespace string `json:"namespace"`
+
+	// CDIUploadProxyURL is the base URL of the target cluster's cdi-uploadproxy service, for
+	// example https://cdi-uploadproxy.target-cluster.example.com.
+	CDIUploadProxyURL string `json:"cdiUploadProxyUrl"`
+
+	// KubeconfigSecretRef is the name of a secret, in the same namespace as the
+	// VirtualMachineExport, containing a kubeconfig, under the key kubeconfig, used to
+	// authenticate to the target cluster's API server in order to create the target
+	// DataVolumes and request upload tokens for them.
+	KubeconfigSecretRef string `json:"kubeconfigSecretRef"`
+}
+
+// VirtualMachineExportHooks configures commands the exporter pod runs around serving an export's
+// artifacts, for example to run a malware scan or apply a signature before anything is made
+// downloadable. Both commands must already exist in the exporter image; this cannot be used to
+// supply arbitrary scripts of the user's own.
+type VirtualMachineExportHooks struct {
+	// PreServeCommand, if set, is run once when the exporter pod starts, before any artifact is
+	// made downloadable. If it exits non-zero, the exporter pod does not serve any artifact for
+	// the rest of its lifetime, and the failure is reported in the HooksReady condition.
+	// +optional
+	// +listType=atomic
+	PreServeCommand []string `json:"preServeCommand,omitempty"`
+
+	// PostServeCommand, if set, is run once the exporter pod stops accepting new downloads,
+	// whether because spec.deadline was reached or the VirtualMachineExport was deleted. If it
+	// exits non-zero, the failure is reported in the HooksReady condition.
+	// +optional
+	// +listType=atomic
+	PostServeCommand []string `json:"postServeCommand,omitempty"`
+}
+
+// VirtualMachineExportScopedTokenSecretRef references a secret holding an additional download
+// token, together with the scope that narrows what it grants access to, so it can be handed to a
+// consumer that should not have the same access as the token in tokenSecretRef.
+type VirtualMachineExportScopedTokenSecretRef struct {
+	// SecretRef is the name of a secret, in the same namespace as the VirtualMachineExport,
+	// containing the token under the same key tokenSecretRef's secret uses.
+	SecretRef string `json:"secretRef"`
+
+	// Volumes, if non-empty, restricts this token to only these volume names. If empty, the
+	// token can download any volume, but, regardless of this field, it can never download the
+	// OVA, the manifest, or the "all volumes" bundle, since those aren't scoped to a single
+	// volume.
+	// +optional
+	// +listType=set
+	Volumes []string `json:"volumes,omitempty"`
+
+	// MaxReads, if set, is how many requests this token may authenticate before being rejected.
+	// It is counted by the exporter pod in memory only, so it resets if the pod restarts.
+	// +optional
+	MaxReads *int32 `json:"maxReads,omitempty"`
 }
 
+// VirtualMachineExportDeletionPolicy defines that is done with an auto-generated token secret
+// when the owning VirtualMachineExport is deleted.
+type VirtualMachineExportDeletionPolicy string
+
+const (
+	// VirtualMachineExportDeletionPolicyDelete deletes the auto-generated token secret along
+	// with the VirtualMachineExport.
+	VirtualMachineExportDeletionPolicyDelete VirtualMachineExportDeletionPolicy = "Delete"
+	// VirtualMachineExportDeletionPolicyRetain leaves the auto-generated token secret in place
+	// after the VirtualMachineExport it was created for is deleted.
+	VirtualMachineExportDeletionPolicyRetain VirtualMachineExportDeletionPolicy = "Retain"
+)
+
 // VirtualMachineExportPhase is the current phase of the VirtualMachineExport
 type VirtualMachineExportPhase string
 
@@ -70,6 +401,8 @@ const (
 	Terminated VirtualMachineExportPhase = "Terminated"
 	// Skipped means the export is invalid in a way so the exporter pod cannot start, and we are skipping creating the exporter server pod.
 	Skipped VirtualMachineExportPhase = "Skipped"
+	// Paused means spec.paused is set, and the exporter pod has been torn down until it is unset.
+	Paused VirtualMachineExportPhase = "Paused"
 )
 
 // VirtualMachineExportStatus is the status for a VirtualMachineExport resource
@@ -85,17 +418,210 @@ type VirtualMachineExportStatus struct {
 	// create the internal URLs for downloading the images
 	ServiceName string `json:"serviceName,omitempty"`
 
+	// +optional
+	// TokenSecretRef is the name of the secret that contains the token used by the export server pod.
+	// This is either the value of spec.tokenSecretRef, or the name of the secret automatically
+	// generated for this export if that field was not set.
+	TokenSecretRef *string `json:"tokenSecretRef,omitempty"`
+
 	// +optional
 	// +listType=atomic
 	Conditions []Condition `json:"conditions,omitempty"`
+
+	// VolumeStatuses reports the transfer progress of each exported volume while the exporter pod
+	// is running. It is not populated before the export is Ready, and a volume stops being updated
+	// once the exporter pod has terminated.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	VolumeStatuses []VirtualMachineExportVolumeStatus `json:"volumeStatuses,omitempty"`
+
+	// ConditionTransitions records the most recent reasons the Ready condition has moved through,
+	// for example InUse, PodPending, PodReady, PodCompleted, oldest first. It is bounded to a fixed
+	// number of entries, so support can reconstruct why an export bounced between states without
+	// digging through events.
+	// +optional
+	// +listType=atomic
+	ConditionTransitions []VirtualMachineExportConditionTransition `json:"conditionTransitions,omitempty"`
+}
+
+// VirtualMachineExportConditionTransition records a single point at which the Ready condition's
+// reason changed.
+type VirtualMachineExportConditionTransition struct {
+	// Reason is the Ready condition's reason as of this transition.
+	Reason string `json:"reason,omitempty"`
+
+	// TransitionTimestamp is when this transition occurred.
+	TransitionTimestamp metav1.Time `json:"transitionTimestamp,omitempty"`
+}
+
+// VirtualMachineExportVolumeStatus reports the transfer progress of a single exported volume.
+type VirtualMachineExportVolumeStatus struct {
+	// Name is the name of the volume this status applies to.
+	Name string `json:"name"`
+
+	// Progress reports how much of the volume has been transferred so far.
+	// +optional
+	Progress *VirtualMachineExportVolumeProgress `json:"progress,omitempty"`
+
+	// S3Upload reports the progress of this volume's upload to Spec.S3Upload, if that is set.
+	// +optional
+	S3Upload *VirtualMachineExportS3UploadStatus `json:"s3Upload,omitempty"`
+
+	// RegistryUpload reports the progress of this volume's upload to Spec.RegistryUpload, if
+	// that is set.
+	// +optional
+	RegistryUpload *VirtualMachineExportRegistryUploadStatus `json:"registryUpload,omitempty"`
+
+	// ClusterUpload reports the progress of this volume's upload to Spec.ClusterUpload, if
+	// that is set.
+	// +optional
+	ClusterUpload *VirtualMachineExportClusterUploadStatus `json:"clusterUpload,omitempty"`
+
+	// LastDownloadStartTimestamp is when this volume's most recently started download began. It
+	// is not cleared once that download completes, so it stays set for a volume that has been
+	// downloaded before, even while no download of it is in progress right now.
+	// +optional
+	LastDownloadStartTimestamp *metav1.Time `json:"lastDownloadStartTimestamp,omitempty"`
+
+	// LastDownloadCompletionTimestamp is when this volume's most recently started download
+	// finished being served. Combined with LastDownloadStartTimestamp, this lets a controller
+	// distinguish a volume nobody has downloaded yet from one that has already been consumed.
+	// +optional
+	LastDownloadCompletionTimestamp *metav1.Time `json:"lastDownloadCompletionTimestamp,omitempty"`
+}
+
+// VirtualMachineExportVolumeProgress reports the number of bytes transferred for a volume, and,
+// when the volume's total size is known, what percentage of it that represents.
+type VirtualMachineExportVolumeProgress struct {
+	// BytesTransferred is the total number of bytes transferred for this volume so far, summed
+	// across every download request made for it.
+	BytesTransferred int64 `json:"bytesTransferred"`
+
+	// PercentComplete is BytesTransferred expressed as a percentage of the volume's total size.
+	// This is omitted if the volume's total size could not be determined.
+	// +optional
+	PercentComplete *int32 `json:"percentComplete,omitempty"`
+}
+
+// VirtualMachineExportS3UploadPhase is the state of a volume's upload to Spec.S3Upload.
+type VirtualMachineExportS3UploadPhase string
+
+const (
+	// S3UploadPending means the exporter pod has not started uploading this volume yet.
+	S3UploadPending VirtualMachineExportS3UploadPhase = "Pending"
+	// S3UploadInProgress means the exporter pod is currently uploading this volume.
+	S3UploadInProgress VirtualMachineExportS3UploadPhase = "InProgress"
+	// S3UploadComplete means the exporter pod finished uploading this volume successfully.
+	S3UploadComplete VirtualMachineExportS3UploadPhase = "Complete"
+	// S3UploadFailed means the exporter pod's upload of this volume did not succeed.
+	S3UploadFailed VirtualMachineExportS3UploadPhase = "Failed"
+)
+
+// VirtualMachineExportS3UploadStatus reports the progress of a single volume's upload to an
+// S3-compatible object store configured in Spec.S3Upload.
+type VirtualMachineExportS3UploadStatus struct {
+	// Phase is the current state of the upload.
+	Phase VirtualMachineExportS3UploadPhase `json:"phase"`
+
+	// BytesUploaded is the number of bytes of the volume's artifact uploaded so far.
+	// +optional
+	BytesUploaded int64 `json:"bytesUploaded,omitempty"`
+
+	// ObjectUrl is the URL of the uploaded object, once Phase is Complete.
+	// +optional
+	ObjectUrl string `json:"objectUrl,omitempty"`
+
+	// Error is the error message from the most recent failed upload attempt, if Phase is Failed.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// VirtualMachineExportRegistryUploadPhase is the state of a volume's upload to
+// Spec.RegistryUpload.
+type VirtualMachineExportRegistryUploadPhase string
+
+const (
+	// RegistryUploadPending means the exporter pod has not started uploading this volume yet.
+	RegistryUploadPending VirtualMachineExportRegistryUploadPhase = "Pending"
+	// RegistryUploadInProgress means the exporter pod is currently uploading this volume.
+	RegistryUploadInProgress VirtualMachineExportRegistryUploadPhase = "InProgress"
+	// RegistryUploadComplete means the exporter pod finished uploading this volume successfully.
+	RegistryUploadComplete VirtualMachineExportRegistryUploadPhase = "Complete"
+	// RegistryUploadFailed means the exporter pod's upload of this volume did not succeed.
+	RegistryUploadFailed VirtualMachineExportRegistryUploadPhase = "Failed"
+)
+
+// VirtualMachineExportRegistryUploadStatus reports the progress of a single volume's upload to
+// an OCI registry configured in Spec.RegistryUpload.
+type VirtualMachineExportRegistryUploadStatus struct {
+	// Phase is the current state of the upload.
+	Phase VirtualMachineExportRegistryUploadPhase `json:"phase"`
+
+	// BytesUploaded is the number of bytes of the volume's containerDisk image uploaded so far.
+	// +optional
+	BytesUploaded int64 `json:"bytesUploaded,omitempty"`
+
+	// ImageUrl is the repository and tag of the uploaded containerDisk image, once Phase is
+	// Complete.
+	// +optional
+	ImageUrl string `json:"imageUrl,omitempty"`
+
+	// Error is the error message from the most recent failed upload attempt, if Phase is Failed.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// VirtualMachineExportClusterUploadPhase is the state of a volume's upload to
+// Spec.ClusterUpload.
+type VirtualMachineExportClusterUploadPhase string
+
+const (
+	// ClusterUploadPending means the exporter pod has not started uploading this volume yet.
+	ClusterUploadPending VirtualMachineExportClusterUploadPhase = "Pending"
+	// ClusterUploadInProgress means the exporter pod is currently uploading this volume.
+	ClusterUploadInProgress VirtualMachineExportClusterUploadPhase = "InProgress"
+	// ClusterUploadComplete means the exporter pod finished uploading this volume successfully.
+	ClusterUploadComplete VirtualMachineExportClusterUploadPhase = "Complete"
+	// ClusterUploadFailed means the exporter pod's upload of this volume did not succeed.
+	ClusterUploadFailed VirtualMachineExportClusterUploadPhase = "Failed"
+)
+
+// VirtualMachineExportClusterUploadStatus reports the progress of a single volume's upload to
+// another cluster configured in Spec.ClusterUpload.
+type VirtualMachineExportClusterUploadStatus struct {
+	// Phase is the current state of the upload.
+	Phase VirtualMachineExportClusterUploadPhase `json:"phase"`
+
+	// BytesUploaded is the number of bytes of the volume's artifact uploaded so far.
+	// +optional
+	BytesUploaded int64 `json:"bytesUploaded,omitempty"`
+
+	// DataVolumeName is the name of the DataVolume created on the target cluster, once it has
+	// been created.
+	// +optional
+	DataVolumeName string `json:"dataVolumeName,omitempty"`
+
+	// Error is the error message from the most recent failed upload attempt, if Phase is Failed.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // VirtualMachineExportLinks contains the links that point the exported VM resources
 type VirtualMachineExportLinks struct {
 	// +optional
 	Internal *VirtualMachineExportLink `json:"internal,omitempty"`
+	// External is the first entry of ExternalLinks, kept for consumers that only look at a
+	// single external link. If ExternalLinks is empty, this is nil.
 	// +optional
 	External *VirtualMachineExportLink `json:"external,omitempty"`
+	// ExternalLinks lists every externally reachable VirtualMachineExportLink for this export,
+	// one per matching Ingress, Route, or NodePort/LoadBalancer Service entry point, so that
+	// clients on different network paths can pick the one they can reach. If spec.externalHostname
+	// is set, or the cluster has no matching Ingress or Route, this has at most one entry.
+	// +optional
+	// +listType=atomic
+	ExternalLinks []VirtualMachineExportLink `json:"externalLinks,omitempty"`
 }
 
 // VirtualMachineExportLink contains a list of volumes available for export, as well as the URLs to obtain these volumes
@@ -108,8 +634,60 @@ type VirtualMachineExportLink struct {
 	// +listMapKey=name
 	// +optional
 	Volumes []VirtualMachineExportVolume `json:"volumes"`
+
+	// Ova is the URL to download every volume plus a generated OVF descriptor bundled into a
+	// single OVA archive. It is only populated when the export source is a VirtualMachine.
+	// +optional
+	Ova *VirtualMachineExportVolumeFormat `json:"ova,omitempty"`
+
+	// All is the URL to download every exported volume, plus the generated manifests if the
+	// export source is a VirtualMachine, bundled into a single tar archive.
+	// +optional
+	All *VirtualMachineExportVolumeFormat `json:"all,omitempty"`
+
+	// Manifests is a list of manifests that can be used to recreate the export source on another
+	// cluster. It is only populated when the export source is a VirtualMachine.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Manifests []VirtualMachineExportManifest `json:"manifests,omitempty"`
+
+	// ExpirationTimestamp is when the exporter pod backing this link is expected to stop serving
+	// requests, whichever comes first of its DEADLINE and the serving certificate in Cert expiring.
+	// Automation can use this to re-trigger the export before URLs derived from this link stop
+	// working.
+	// +optional
+	ExpirationTimestamp *metav1.Time `json:"expirationTimestamp,omitempty"`
 }
 
+// VirtualMachineExportManifest contains the url of a manifest generated from the export source,
+// and the type of content it contains.
+type VirtualMachineExportManifest struct {
+	// Type is the type of manifest returned
+	Type ExportManifestType `json:"type"`
+
+	// Url is the url to get the manifest from
+	Url string `json:"url"`
+}
+
+// ExportManifestType defines the type of manifest advertised by a VirtualMachineExportManifest
+type ExportManifestType string
+
+const (
+	// AllManifests points to a manifest containing the exported VirtualMachine, including its
+	// DataVolumeTemplates, and a ConfigMap holding the export server's CA bundle, as separate
+	// YAML documents, so a consumer can recreate the VM and trust the other export formats on
+	// another cluster from the export alone.
+	AllManifests ExportManifestType = "all"
+
+	// AllManifestsWithExpandedSpec points to the same content as AllManifests, except that if the
+	// VirtualMachine references an instancetype or preference, its Spec.Template.Spec has their
+	// settings expanded into it directly and the references removed, so the manifest can be
+	// applied on a cluster that doesn't have the referenced instancetype or preference, or does
+	// not have the instancetype.kubevirt.io CRDs installed at all.
+	AllManifestsWithExpandedSpec ExportManifestType = "allWithExpandedSpec"
+)
+
 // VirtualMachineExportVolume contains the name and available formats for the exported volume
 type VirtualMachineExportVolume struct {
 	// Name is the name of the exported volume
@@ -131,6 +709,29 @@ const (
 	Dir ExportVolumeFormat = "dir"
 	// ArchiveGz is a tarred and gzipped version of the root of a PersistentVolumeClaim
 	ArchiveGz ExportVolumeFormat = "tar.gz"
+	// ArchiveZstd is a tarred and zstd-compressed version of the root of a PersistentVolumeClaim
+	ArchiveZstd ExportVolumeFormat = "tar.zst"
+	// KubeVirtQcow2 is the volume converted to qcow2 format
+	KubeVirtQcow2 ExportVolumeFormat = "qcow2"
+	// KubeVirtQcow2Gz is the volume converted to qcow2 format and gzipped
+	KubeVirtQcow2Gz ExportVolumeFormat = "qcow2.gz"
+	// KubeVirtZstd is the volume in zstd-compressed RAW format. This compresses noticeably faster
+	// than KubeVirtGz for large disks, at the cost of requiring a zstd-capable client to decompress.
+	KubeVirtZstd ExportVolumeFormat = "zstd"
+	// KubeVirtVmdk is the volume converted to streamOptimized VMDK format
+	KubeVirtVmdk ExportVolumeFormat = "vmdk"
+	// KubeVirtVhd is the volume converted to fixed-size VHD format
+	KubeVirtVhd ExportVolumeFormat = "vhd"
+	// KubeVirtVhdx is the volume converted to VHDX format
+	KubeVirtVhdx ExportVolumeFormat = "vhdx"
+	// Ova is every volume, converted to streamOptimized VMDK, plus a generated OVF descriptor,
+	// bundled into a single OVA archive. Unlike the other formats, it applies to the whole
+	// export rather than to a single volume.
+	Ova ExportVolumeFormat = "ova"
+	// All is every volume's raw disk image, plus the generated manifests if the export source is
+	// a VirtualMachine, bundled into a single uncompressed tar archive. Unlike the other formats,
+	// it applies to the whole export rather than to a single volume.
+	All ExportVolumeFormat = "all"
 )
 
 // VirtualMachineExportVolumeFormat contains the format type and URL to get the volume in that format
@@ -139,6 +740,14 @@ type VirtualMachineExportVolumeFormat struct {
 	Format ExportVolumeFormat `json:"format"`
 	// Url is the url that contains the volume in the format specified
 	Url string `json:"url"`
+	// Size is the logical size, in bytes, of the artifact at Url. It is populated by the exporter
+	// pod once the size has been computed, and omitted until then.
+	// +optional
+	Size *int64 `json:"size,omitempty"`
+	// Checksum is the sha256 checksum, hex encoded, of the artifact at Url. It is populated by
+	// the exporter pod once the checksum has been computed, and omitted until then.
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // ConditionType is the const type for Conditions
@@ -151,6 +760,13 @@ const (
 	ConditionPVC ConditionType = "PVCReady"
 	// ConditionVolumesCreated is the condition to see if volumes are created from volume snapshots
 	ConditionVolumesCreated ConditionType = "VolumesCreated"
+	// ConditionExternalLink is the condition reporting whether an externally reachable endpoint
+	// (an Ingress, a Route, or a NodePort/LoadBalancer service) was found for status.links.external
+	ConditionExternalLink ConditionType = "ExternalLinkFound"
+	// ConditionHooksReady reports whether spec.hooks' PreServeCommand and PostServeCommand, if
+	// set, have run successfully in the exporter pod. It is only present once the exporter pod
+	// has reported the outcome of at least one of them.
+	ConditionHooksReady ConditionType = "HooksReady"
 )
 
 // Condition defines conditions
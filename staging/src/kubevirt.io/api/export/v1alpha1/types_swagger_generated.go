@@ -18,34 +18,105 @@ func (VirtualMachineExportList) SwaggerDoc() map[string]string {
 
 func (VirtualMachineExportSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":               "VirtualMachineExportSpec is the spec for a VirtualMachineExport resource",
-		"tokenSecretRef": "TokenSecretRef is the name of the secret that contains the token used by the export server pod",
+		"":                          "VirtualMachineExportSpec is the spec for a VirtualMachineExport resource",
+		"tokenSecretRef":            "TokenSecretRef is the name of the secret that contains the token used by the export server pod.\nIf this is not set, a secret containing a randomly generated token is created and owned by the\nVirtualMachineExport, and its name is published in status.tokenSecretRef.\n+optional",
+		"additionalTokenSecretRefs": "AdditionalTokenSecretRefs is a list of names of secrets that also contain a token accepted by\nthe export server pod, in addition to the one in tokenSecretRef. This allows rotating the\ntoken in tokenSecretRef to a new secret while still accepting downloads that were started\nwith the previous token, until it is removed from this list.\n+optional\n+listType=set",
+		"ttlDuration":               "TTLDuration limits the lifetime of an export.\nIf this field is set, after this duration has passed from deployment,\nthe export is automatically deleted. If this field is not set, the\nexport will not get cleaned up.\n+optional",
+		"volumeNames":               "VolumeNames is a list of the volumes to export from the source. If this\nlist is empty, all volumes are exported. This only applies to sources\nthat resolve to more than one volume, such as a VirtualMachine.\n+optional\n+listType=set",
+		"quiesce":                   "Quiesce requests that, if the Virtual Machine source is running and the\nguest agent is available, the controller freeze the guest file systems\nbefore exposing the export, and thaw them again once the export is\nready. This allows an export to be taken from a running Virtual Machine\nwithout having to stop it first. If the guest agent is not available,\nthis field has no effect and an export of a source that is in use\ncontinues to be blocked until the source becomes available.\n+optional",
+		"deadline":                  "Deadline specifies how long the export server is allowed to run, starting from when the\nexporter pod is created, before the export is automatically stopped. If this field is not\nset, the cluster default configured in KubeVirtConfiguration is used, or 24 hours if that\nis also not set.\n+optional",
+		"deletionPolicy":            "DeletionPolicy controls what happens to the secret holding the export token that\nTokenSecretRef refers to when the VirtualMachineExport is deleted, if that secret was\nauto-generated rather than provided by the user in spec.tokenSecretRef. If this is not\nset, Delete is used.\n+optional",
+		"s3Upload":                  "S3Upload, if set, additionally has the exporter pod push each exported volume's primary\nartifact to an S3-compatible object store, alongside still serving it for HTTPS download.\n+optional",
+		"registryUpload":            "RegistryUpload, if set, additionally has the exporter pod build each exported volume's raw\nartifact into a containerDisk image and push it to an OCI registry, alongside still serving\nit for HTTPS download.\n+optional",
+		"clusterUpload":             "ClusterUpload, if set, additionally has the exporter pod create a DataVolume for each\nexported volume's raw artifact on another cluster and stream the artifact straight into\nit, alongside still serving it for HTTPS download.\n+optional",
+		"formats":                   "Formats restricts which of the volume formats that would otherwise be available are\nconverted and advertised in status.links. This is useful to skip the cost of converting\nto formats that will not be used. If this list is empty, every format applicable to the\nsource is made available, which is the same behavior as before this field was introduced.\n+optional\n+listType=set",
+		"podResourceRequirements":   "PodResourceRequirements specifies the resource requirements applied to this export's\nexporter pod container. If this is not set, the cluster default configured in\nKubeVirtConfiguration is used, or the exporter pod's container has no resource requests\nor limits if that is also not set.\n+optional",
+		"paused":                    "Paused, if true, tears down the exporter pod without deleting the VirtualMachineExport,\nits service, or its token secret. Setting this back to false, or unsetting it, resumes\nthe export, recreating the exporter pod. This allows operators to temporarily stop\nserving data, for example during a maintenance window, without losing the export's\nconfiguration or having consumers need a new token or URL once it resumes.\n+optional",
+		"onDemand":                  "OnDemand, if true, holds off creating the exporter pod until the export is claimed, by\nannotating the VirtualMachineExport with export.kubevirt.io/claimed, instead of creating it\nas soon as the source volumes become available. This lets many exports be pre-created\nwithout each of them consuming node resources while idle, at the cost of the first download\nhaving to wait for the exporter pod to start.\n+optional",
+		"perVolumePods":             "PerVolumePods, if true and the source resolves to more than one volume, has the controller\ncreate one exporter pod and one Service per additional volume, instead of bundling every\nvolume into the single exporter pod normally used. This lets conversions and downloads of\ndifferent volumes run in parallel, on different nodes, instead of serializing through one\npod. The first volume is still served by the export's regular exporter pod and Service,\npublished in status.links as before; the per-volume pods and Services this creates for the\nremaining volumes are not yet published there.\n+optional",
+		"serviceType":               "ServiceType controls the type of the Service created to front the exporter pod. Valid\nvalues are NodePort and LoadBalancer. If this is not set, a ClusterIP service is created,\nmatching the behavior as before this field was introduced. This is useful on clusters that\nhave no Ingress or Route available to reach the export externally: setting it to NodePort\nor LoadBalancer causes the resulting external address to be published in\nstatus.links.external, the same as would happen with an Ingress or Route host.\n+optional",
+		"externalHostname":          "ExternalHostname overrides the hostname published in status.links.external and used as the\nexporter pod's serving certificate's Subject Alternative Name. If this is not set, the\nhostname is auto-detected from the first Ingress or Route pointing at the export, or, if\nspec.serviceType requested a NodePort or LoadBalancer service, from that service's address,\nmatching the behavior as before this field was introduced.\n+optional",
+		"externalTLSSecretRef":      "ExternalTLSSecretRef is the name of a secret of type kubernetes.io/tls, in the same\nnamespace as the VirtualMachineExport, whose certificate is published as the external\nlink's cert instead of the one auto-detected from the matching Ingress or Route's TLS\nconfiguration. This is only used together with externalHostname, since without it there is\nno external link to attach the certificate to.\n+optional",
+		"encryptionSecretRef":       "EncryptionSecretRef is the name of a secret, in the same namespace as the\nVirtualMachineExport, containing a 32-byte AES-256 key under the key \"key\". If this is\nset, the exporter pod encrypts every artifact it serves with that key before sending it,\nso the client must decrypt what it downloads with the same key. This adds a layer of\nprotection for artifacts that traverse shared ingress infrastructure, on top of what TLS\nand the download token already provide. If this is not set, artifacts are served\nunencrypted, matching the behavior as before this field was introduced.\n+optional",
+		"restoreStorageClassName":   "RestoreStorageClassName overrides the StorageClass used for the temporary PVCs created to\nrestore a VirtualMachineSnapshot source's volumes for the duration of the export. If this\nis not set, each restore PVC reuses the StorageClass of the volume it was snapshotted from,\nmatching the behavior as before this field was introduced. This is only used when the\nsource is a VirtualMachineSnapshot; it has no effect on other source types.\n+optional",
+	}
+}
+
+func (VirtualMachineExportClusterUpload) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "VirtualMachineExportClusterUpload configures the exporter pod to create a DataVolume for each\nexported volume's raw artifact on another cluster and stream the artifact straight into it,\nvia that cluster's cdi-uploadproxy, in addition to serving it for HTTPS download. Only a\nvolume's raw artifact is transferred this way; volumes that only have a dir or archive\nartifact are not uploaded.",
+		"namespace":           "Namespace is the namespace on the target cluster to create DataVolumes in.",
+		"cdiUploadProxyUrl":   "CDIUploadProxyURL is the base URL of the target cluster's cdi-uploadproxy service, for\nexample https://cdi-uploadproxy.target-cluster.example.com.",
+		"kubeconfigSecretRef": "KubeconfigSecretRef is the name of a secret, in the same namespace as the\nVirtualMachineExport, containing a kubeconfig, under the key kubeconfig, used to\nauthenticate to the target cluster's API server in order to create the target\nDataVolumes and request upload tokens for them.",
+	}
+}
+
+func (VirtualMachineExportRegistryUpload) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                     "VirtualMachineExportRegistryUpload configures the exporter pod to build exported volumes into\ncontainerDisk images and push them to an OCI registry, in addition to serving them for HTTPS\ndownload. Only a volume's raw artifact is built into a containerDisk; volumes that only have a\ndir or archive artifact are not uploaded.",
+		"repository":           "Repository is the registry repository to push exported volumes to, for example\nregistry.example.com/exports/my-vm. Each volume is pushed as its own image, named\n<repository>/<volume name>.",
+		"tag":                  "Tag is the tag applied to each pushed image. If this is not set, latest is used.\n+optional",
+		"credentialsSecretRef": "CredentialsSecretRef is the name of a secret, in the same namespace as the\nVirtualMachineExport, containing the username and password used to authenticate to\nRepository.",
+	}
+}
+
+func (VirtualMachineExportS3Upload) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                     "VirtualMachineExportS3Upload configures the exporter pod to push exported volumes to an\nS3-compatible object store, in addition to serving them for HTTPS download.",
+		"endpoint":             "Endpoint is the base URL of the S3-compatible service to upload to, for example\nhttps://s3.us-east-1.amazonaws.com or https://minio.example.com.",
+		"bucket":               "Bucket is the name of the bucket that exported volumes are uploaded into.",
+		"region":               "Region is the region to sign upload requests for. If this is not set, requests are signed\nfor the us-east-1 region, which most S3-compatible services accept regardless of where\nthey are actually hosted.\n+optional",
+		"credentialsSecretRef": "CredentialsSecretRef is the name of a secret, in the same namespace as the\nVirtualMachineExport, containing the accessKeyId and secretAccessKey used to authenticate\nto Endpoint.",
 	}
 }
 
 func (VirtualMachineExportStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":            "VirtualMachineExportStatus is the status for a VirtualMachineExport resource",
-		"phase":       "+optional",
-		"links":       "+optional",
-		"serviceName": "+optional\nServiceName is the name of the service created associated with the Virtual Machine export. It will be used to\ncreate the internal URLs for downloading the images",
-		"conditions":  "+optional\n+listType=atomic",
+		"":                     "VirtualMachineExportStatus is the status for a VirtualMachineExport resource",
+		"phase":                "+optional",
+		"links":                "+optional",
+		"serviceName":          "+optional\nServiceName is the name of the service created associated with the Virtual Machine export. It will be used to\ncreate the internal URLs for downloading the images",
+		"tokenSecretRef":       "+optional\nTokenSecretRef is the name of the secret that contains the token used by the export server pod.\nThis is either the value of spec.tokenSecretRef, or the name of the secret automatically\ngenerated for this export if that field was not set.",
+		"conditions":           "+optional\n+listType=atomic",
+		"volumeStatuses":       "VolumeStatuses reports the transfer progress of each exported volume while the exporter pod\nis running. It is not populated before the export is Ready, and a volume stops being updated\nonce the exporter pod has terminated.\n+optional\n+listType=map\n+listMapKey=name",
+		"conditionTransitions": "ConditionTransitions records the most recent reasons the Ready condition has moved through,\nfor example InUse, PodPending, PodReady, PodCompleted, oldest first. It is bounded to a fixed\nnumber of entries, so support can reconstruct why an export bounced between states without\ndigging through events.\n+optional\n+listType=atomic",
+	}
+}
+
+func (VirtualMachineExportConditionTransition) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "VirtualMachineExportConditionTransition records a single point at which the Ready condition's\nreason changed.",
+		"reason":              "Reason is the Ready condition's reason as of this transition.",
+		"transitionTimestamp": "TransitionTimestamp is when this transition occurred.",
 	}
 }
 
 func (VirtualMachineExportLinks) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":         "VirtualMachineExportLinks contains the links that point the exported VM resources",
-		"internal": "+optional",
-		"external": "+optional",
+		"":              "VirtualMachineExportLinks contains the links that point the exported VM resources",
+		"internal":      "+optional",
+		"external":      "External is the first entry of ExternalLinks, kept for consumers that only look at a\nsingle external link. If ExternalLinks is empty, this is nil.\n+optional",
+		"externalLinks": "ExternalLinks lists every externally reachable VirtualMachineExportLink for this export,\none per matching Ingress, Route, or NodePort/LoadBalancer Service entry point, so that\nclients on different network paths can pick the one they can reach. If spec.externalHostname\nis set, or the cluster has no matching Ingress or Route, this has at most one entry.\n+optional\n+listType=atomic",
 	}
 }
 
 func (VirtualMachineExportLink) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":        "VirtualMachineExportLink contains a list of volumes available for export, as well as the URLs to obtain these volumes",
-		"cert":    "Cert is the public CA certificate base64 encoded",
-		"volumes": "Volumes is a list of available volumes to export\n+listType=map\n+listMapKey=name\n+optional",
+		"":                    "VirtualMachineExportLink contains a list of volumes available for export, as well as the URLs to obtain these volumes",
+		"cert":                "Cert is the public CA certificate base64 encoded",
+		"volumes":             "Volumes is a list of available volumes to export\n+listType=map\n+listMapKey=name\n+optional",
+		"ova":                 "Ova is the URL to download every volume plus a generated OVF descriptor bundled into a\nsingle OVA archive. It is only populated when the export source is a VirtualMachine.\n+optional",
+		"all":                 "All is the URL to download every exported volume, plus the generated manifests if the\nexport source is a VirtualMachine, bundled into a single tar archive.\n+optional",
+		"manifests":           "Manifests is a list of manifests that can be used to recreate the export source on another\ncluster. It is only populated when the export source is a VirtualMachine.\n+optional\n+listType=map\n+listMapKey=type",
+		"expirationTimestamp": "ExpirationTimestamp is when the exporter pod backing this link is expected to stop serving\nrequests, whichever comes first of its DEADLINE and the serving certificate in Cert expiring.\nAutomation can use this to re-trigger the export before URLs derived from this link stop\nworking.\n+optional",
+	}
+}
+
+func (VirtualMachineExportManifest) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":     "VirtualMachineExportManifest contains the url of a manifest generated from the export source,\nand the type of content it contains.",
+		"type": "Type is the type of manifest returned",
+		"url":  "Url is the url to get the manifest from",
 	}
 }
 
@@ -59,9 +130,60 @@ func (VirtualMachineExportVolume) SwaggerDoc() map[string]string {
 
 func (VirtualMachineExportVolumeFormat) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":       "VirtualMachineExportVolumeFormat contains the format type and URL to get the volume in that format",
-		"format": "Format is the format of the image at the specified URL",
-		"url":    "Url is the url that contains the volume in the format specified",
+		"":         "VirtualMachineExportVolumeFormat contains the format type and URL to get the volume in that format",
+		"format":   "Format is the format of the image at the specified URL",
+		"url":      "Url is the url that contains the volume in the format specified",
+		"size":     "Size is the logical size, in bytes, of the artifact at Url. It is populated by the exporter\npod once the size has been computed, and omitted until then.\n+optional",
+		"checksum": "Checksum is the sha256 checksum, hex encoded, of the artifact at Url. It is populated by\nthe exporter pod once the checksum has been computed, and omitted until then.\n+optional",
+	}
+}
+
+func (VirtualMachineExportVolumeProgress) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                 "VirtualMachineExportVolumeProgress reports the number of bytes transferred for a volume, and,\nwhen the volume's total size is known, what percentage of it that represents.",
+		"bytesTransferred": "BytesTransferred is the total number of bytes transferred for this volume so far, summed\nacross every download request made for it.",
+		"percentComplete":  "PercentComplete is BytesTransferred expressed as a percentage of the volume's total size.\nThis is omitted if the volume's total size could not be determined.\n+optional",
+	}
+}
+
+func (VirtualMachineExportVolumeStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "VirtualMachineExportVolumeStatus reports the transfer progress of a single exported volume.",
+		"name":           "Name is the name of the volume this status applies to.",
+		"progress":       "+optional",
+		"s3Upload":       "S3Upload reports the progress of this volume's upload to Spec.S3Upload, if that is set.\n+optional",
+		"registryUpload": "RegistryUpload reports the progress of this volume's upload to Spec.RegistryUpload, if\nthat is set.\n+optional",
+		"clusterUpload":  "ClusterUpload reports the progress of this volume's upload to Spec.ClusterUpload, if\nthat is set.\n+optional",
+	}
+}
+
+func (VirtualMachineExportS3UploadStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "VirtualMachineExportS3UploadStatus reports the progress of a single volume's upload to an\nS3-compatible object store configured in Spec.S3Upload.",
+		"phase":         "Phase is the current state of the upload.",
+		"bytesUploaded": "BytesUploaded is the number of bytes of the volume's artifact uploaded so far.\n+optional",
+		"objectUrl":     "ObjectUrl is the URL of the uploaded object, once Phase is Complete.\n+optional",
+		"error":         "Error is the error message from the most recent failed upload attempt, if Phase is Failed.\n+optional",
+	}
+}
+
+func (VirtualMachineExportRegistryUploadStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "VirtualMachineExportRegistryUploadStatus reports the progress of a single volume's upload to\nan OCI registry configured in Spec.RegistryUpload.",
+		"phase":         "Phase is the current state of the upload.",
+		"bytesUploaded": "BytesUploaded is the number of bytes of the volume's containerDisk image uploaded so far.\n+optional",
+		"imageUrl":      "ImageUrl is the repository and tag of the uploaded containerDisk image, once Phase is\nComplete.\n+optional",
+		"error":         "Error is the error message from the most recent failed upload attempt, if Phase is Failed.\n+optional",
+	}
+}
+
+func (VirtualMachineExportClusterUploadStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "VirtualMachineExportClusterUploadStatus reports the progress of a single volume's upload to\nanother cluster configured in Spec.ClusterUpload.",
+		"phase":          "Phase is the current state of the upload.",
+		"bytesUploaded":  "BytesUploaded is the number of bytes of the volume's artifact uploaded so far.\n+optional",
+		"dataVolumeName": "DataVolumeName is the name of the DataVolume created on the target cluster, once it has\nbeen created.\n+optional",
+		"error":          "Error is the error message from the most recent failed upload attempt, if Phase is Failed.\n+optional",
 	}
 }
 
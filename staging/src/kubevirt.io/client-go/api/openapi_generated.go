@@ -15868,7 +15868,7 @@ func schema_kubevirtio_api_core_v1_Disk(ref common.ReferenceCallback) common.Ope
 					},
 					"io": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IO specifies which QEMU disk IO mode should be used. Supported values are: native, default, threads.",
+							Description: "IO specifies which QEMU disk IO mode should be used. Supported values are: native, default, threads, io_uring.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -21597,6 +21597,13 @@ func schema_kubevirtio_api_core_v1_VirtualMachineInstanceSpec(ref common.Referen
 							Format:      "",
 						},
 					},
+					"selinuxContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, overrides the cluster-wide SELinux type configured for virt-launcher with a custom SELinux type for this VirtualMachineInstance's compute container. Setting this field requires the SELinuxCustomType feature gate to be enabled.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"domain": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Specification of the desired behavior of the VirtualMachineInstance on the host.",
@@ -22995,12 +23002,18 @@ func schema_kubevirtio_api_export_v1alpha1_VirtualMachineExportSpec(ref common.R
 							Format:      "",
 						},
 					},
+					"ttlDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTLDuration limits the lifetime of an export. If this field is set, after this duration has passed from deployment, the export is automatically deleted. If this field is not set, the export will not get cleaned up.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 				},
 				Required: []string{"source", "tokenSecretRef"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.TypedLocalObjectReference"},
+			"k8s.io/api/core/v1.TypedLocalObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
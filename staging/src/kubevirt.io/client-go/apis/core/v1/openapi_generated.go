@@ -20101,7 +20101,7 @@ func schema_client_go_apis_core_v1_Disk(ref common.ReferenceCallback) common.Ope
 					},
 					"io": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IO specifies which QEMU disk IO mode should be used. Supported values are: native, default, threads.",
+							Description: "IO specifies which QEMU disk IO mode should be used. Supported values are: native, default, threads, io_uring.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -25302,6 +25302,13 @@ func schema_client_go_apis_core_v1_VirtualMachineInstanceSpec(ref common.Referen
 							Format:      "",
 						},
 					},
+					"selinuxContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, overrides the cluster-wide SELinux type configured for virt-launcher with a custom SELinux type for this VirtualMachineInstance's compute container. Setting this field requires the SELinuxCustomType feature gate to be enabled.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"domain": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Specification of the desired behavior of the VirtualMachineInstance on the host.",
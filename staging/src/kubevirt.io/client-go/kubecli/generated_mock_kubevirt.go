@@ -1650,6 +1650,16 @@ func (_mr *_MockVirtualMachineInterfaceRecorder) RemoveMemoryDump(arg0 interface
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "RemoveMemoryDump", arg0)
 }
 
+func (_m *MockVirtualMachineInterface) BackupCheckpoint(name string, backupCheckpointOptions *v120.BackupCheckpointOptions) error {
+	ret := _m.ctrl.Call(_m, "BackupCheckpoint", name, backupCheckpointOptions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInterfaceRecorder) BackupCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "BackupCheckpoint", arg0, arg1)
+}
+
 // Mock of VirtualMachineInstanceMigrationInterface interface
 type MockVirtualMachineInstanceMigrationInterface struct {
 	ctrl     *gomock.Controller
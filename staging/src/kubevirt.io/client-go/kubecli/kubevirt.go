@@ -296,6 +296,7 @@ type VirtualMachineInterface interface {
 	PortForward(name string, port int, protocol string) (StreamInterface, error)
 	MemoryDump(name string, memoryDumpRequest *v1.VirtualMachineMemoryDumpRequest) error
 	RemoveMemoryDump(name string) error
+	BackupCheckpoint(name string, backupCheckpointOptions *v1.BackupCheckpointOptions) error
 }
 
 type VirtualMachineInstanceMigrationInterface interface {
@@ -269,6 +269,17 @@ func (v *vm) RemoveMemoryDump(name string) error {
 	return v.restClient.Put().RequestURI(v.adaptUriForHostPath(uri)).Do(context.Background()).Error()
 }
 
+func (v *vm) BackupCheckpoint(name string, backupCheckpointOptions *v1.BackupCheckpointOptions) error {
+	uri := fmt.Sprintf(vmSubresourceURLFmt, v1.ApiStorageVersion, v.namespace, name, "backupcheckpoint")
+
+	JSON, err := json.Marshal(backupCheckpointOptions)
+	if err != nil {
+		return err
+	}
+
+	return v.restClient.Put().RequestURI(v.adaptUriForHostPath(uri)).Body([]byte(JSON)).Do(context.Background()).Error()
+}
+
 func (v *vm) AddVolume(name string, addVolumeOptions *v1.AddVolumeOptions) error {
 	uri := fmt.Sprintf(vmSubresourceURLFmt, v1.ApiStorageVersion, v.namespace, name, "addvolume")
 
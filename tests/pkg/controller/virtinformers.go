@@ -158,6 +158,9 @@ type KubeInformerFactory interface {
 	// Watches for PersistentVolumeClaim objects
 	PersistentVolumeClaim() cache.SharedIndexInformer
 
+	// Watches for PersistentVolume objects
+	PersistentVolume() cache.SharedIndexInformer
+
 	// Watches for ControllerRevision objects
 	ControllerRevision() cache.SharedIndexInformer
 
@@ -557,6 +560,13 @@ func GetVirtualMachineExportInformerIndexers() cache.Indexers {
 				return []string{fmt.Sprintf("%s/%s", export.Namespace, export.Spec.Source.Name)}, nil
 			}
 
+			// TODO, look up the correct PVC name based on the DataVolume, right now they match, but that will not always be true.
+			if export.Spec.Source.APIGroup != nil &&
+				*export.Spec.Source.APIGroup == cdiv1.SchemeGroupVersion.Group &&
+				export.Spec.Source.Kind == "DataVolume" {
+				return []string{fmt.Sprintf("%s/%s", export.Namespace, export.Spec.Source.Name)}, nil
+			}
+
 			return nil, nil
 		},
 		"vmsnapshot": func(obj interface{}) ([]string, error) {
@@ -585,6 +595,20 @@ func GetVirtualMachineExportInformerIndexers() cache.Indexers {
 				return []string{fmt.Sprintf("%s/%s", export.Namespace, export.Spec.Source.Name)}, nil
 			}
 
+			return nil, nil
+		},
+		"datasource": func(obj interface{}) ([]string, error) {
+			export, ok := obj.(*exportv1.VirtualMachineExport)
+			if !ok {
+				return nil, unexpectedObjectError
+			}
+
+			if export.Spec.Source.APIGroup != nil &&
+				*export.Spec.Source.APIGroup == cdiv1.SchemeGroupVersion.Group &&
+				export.Spec.Source.Kind == "DataSource" {
+				return []string{fmt.Sprintf("%s/%s", export.Namespace, export.Spec.Source.Name)}, nil
+			}
+
 			return nil, nil
 		},
 	}
@@ -807,6 +831,14 @@ func (f *kubeInformerFactory) PersistentVolumeClaim() cache.SharedIndexInformer
 	})
 }
 
+func (f *kubeInformerFactory) PersistentVolume() cache.SharedIndexInformer {
+	return f.getInformer("persistentVolumeInformer", func() cache.SharedIndexInformer {
+		restClient := f.clientSet.CoreV1().RESTClient()
+		lw := cache.NewListWatchFromClient(restClient, "persistentvolumes", k8sv1.NamespaceAll, fields.Everything())
+		return cache.NewSharedIndexInformer(lw, &k8sv1.PersistentVolume{}, f.defaultResync, cache.Indexers{})
+	})
+}
+
 func (f *kubeInformerFactory) LimitRanges() cache.SharedIndexInformer {
 	return f.getInformer("limitrangeInformer", func() cache.SharedIndexInformer {
 		restClient := f.clientSet.CoreV1().RESTClient()
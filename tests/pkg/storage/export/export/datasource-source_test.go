@@ -0,0 +1,224 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+package export
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	routev1 "github.com/openshift/api/route/v1"
+
+	k8sv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	exportv1 "kubevirt.io/api/export/v1alpha1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1alpha1"
+	kubevirtfake "kubevirt.io/client-go/generated/kubevirt/clientset/versioned/fake"
+	"kubevirt.io/client-go/kubecli"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/certificates/bootstrap"
+	virtcontroller "kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	"kubevirt.io/kubevirt/pkg/virt-controller/services"
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
+)
+
+const (
+	testDataSourceName = "test-datasource"
+)
+
+var _ = Describe("DataSource source", func() {
+	var (
+		ctrl                       *gomock.Controller
+		controller                 *VMExportController
+		recorder                   *record.FakeRecorder
+		pvcInformer                cache.SharedIndexInformer
+		podInformer                cache.SharedIndexInformer
+		cmInformer                 cache.SharedIndexInformer
+		vmExportInformer           cache.SharedIndexInformer
+		serviceInformer            cache.SharedIndexInformer
+		dvInformer                 cache.SharedIndexInformer
+		dataSourceInformer         cache.SharedIndexInformer
+		vmSnapshotInformer         cache.SharedIndexInformer
+		vmSnapshotContentInformer  cache.SharedIndexInformer
+		secretInformer             cache.SharedIndexInformer
+		vmInformer                 cache.SharedIndexInformer
+		vmiInformer                cache.SharedIndexInformer
+		k8sClient                  *k8sfake.Clientset
+		vmExportClient             *kubevirtfake.Clientset
+		fakeVolumeSnapshotProvider *MockVolumeSnapshotProvider
+		mockVMExportQueue          *testutils.MockWorkQueue
+		routeCache                 cache.Store
+		ingressCache               cache.Store
+		certDir                    string
+		certFilePath               string
+		keyFilePath                string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		var err error
+		certDir, err = ioutil.TempDir("", "certs")
+		Expect(err).ToNot(HaveOccurred())
+		certFilePath = filepath.Join(certDir, "tls.crt")
+		keyFilePath = filepath.Join(certDir, "tls.key")
+		writeCertsToDir(certDir)
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		pvcInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolumeClaim{})
+		podInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Pod{})
+		cmInformer, _ = testutils.NewFakeInformerFor(&k8sv1.ConfigMap{})
+		serviceInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Service{})
+		vmExportInformer, _ = testutils.NewFakeInformerWithIndexersFor(&exportv1.VirtualMachineExport{}, virtcontroller.GetVirtualMachineExportInformerIndexers())
+		dvInformer, _ = testutils.NewFakeInformerFor(&cdiv1.DataVolume{})
+		dataSourceInformer, _ = testutils.NewFakeInformerFor(&cdiv1.DataSource{})
+		vmSnapshotInformer, _ = testutils.NewFakeInformerFor(&snapshotv1.VirtualMachineSnapshot{})
+		vmSnapshotContentInformer, _ = testutils.NewFakeInformerFor(&snapshotv1.VirtualMachineSnapshotContent{})
+		vmInformer, _ = testutils.NewFakeInformerFor(&virtv1.VirtualMachine{})
+		vmiInformer, _ = testutils.NewFakeInformerFor(&virtv1.VirtualMachineInstance{})
+		routeInformer, _ := testutils.NewFakeInformerFor(&routev1.Route{})
+		routeCache = routeInformer.GetStore()
+		ingressInformer, _ := testutils.NewFakeInformerFor(&networkingv1.Ingress{})
+		ingressCache = ingressInformer.GetStore()
+		secretInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Secret{})
+		fakeVolumeSnapshotProvider = &MockVolumeSnapshotProvider{
+			volumeSnapshots: []*vsv1.VolumeSnapshot{},
+		}
+
+		config, _, _ := testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{})
+		k8sClient = k8sfake.NewSimpleClientset()
+		vmExportClient = kubevirtfake.NewSimpleClientset()
+		recorder = record.NewFakeRecorder(100)
+
+		virtClient.EXPECT().CoreV1().Return(k8sClient.CoreV1()).AnyTimes()
+		virtClient.EXPECT().VirtualMachineExport(testNamespace).
+			Return(vmExportClient.ExportV1alpha1().VirtualMachineExports(testNamespace)).AnyTimes()
+
+		controller = &VMExportController{
+			Client:                    virtClient,
+			Recorder:                  recorder,
+			PVCInformer:               pvcInformer,
+			PodInformer:               podInformer,
+			ConfigMapInformer:         cmInformer,
+			VMExportInformer:          vmExportInformer,
+			ServiceInformer:           serviceInformer,
+			DataVolumeInformer:        dvInformer,
+			DataSourceInformer:        dataSourceInformer,
+			KubevirtNamespace:         "kubevirt",
+			TemplateService:           services.NewTemplateService("a", 240, "b", "c", "d", "e", "f", "g", pvcInformer.GetStore(), virtClient, config, qemuGid, "h"),
+			caCertManager:             bootstrap.NewFileCertificateManager(certFilePath, keyFilePath),
+			RouteCache:                routeCache,
+			IngressCache:              ingressCache,
+			RouteConfigMapInformer:    cmInformer,
+			SecretInformer:            secretInformer,
+			VMSnapshotInformer:        vmSnapshotInformer,
+			VMSnapshotContentInformer: vmSnapshotContentInformer,
+			VolumeSnapshotProvider:    fakeVolumeSnapshotProvider,
+			VMInformer:                vmInformer,
+			VMIInformer:               vmiInformer,
+			ClusterConfig:             config,
+		}
+		initCert = func(ctrl *VMExportController) {
+			go controller.caCertManager.Start()
+			// Give the thread time to read the certs.
+			Eventually(func() *tls.Certificate {
+				return controller.caCertManager.Current()
+			}, time.Second, time.Millisecond).ShouldNot(BeNil())
+		}
+
+		controller.Init()
+		mockVMExportQueue = testutils.NewMockWorkQueue(controller.vmExportQueue)
+		controller.vmExportQueue = mockVMExportQueue
+
+		cmInformer.GetStore().Add(&k8sv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: controller.KubevirtNamespace,
+				Name:      components.KubeVirtExportCASecretName,
+			},
+			Data: map[string]string{
+				"ca-bundle": "replace me with ca cert",
+			},
+		})
+	})
+
+	AfterEach(func() {
+		controller.caCertManager.Stop()
+		os.RemoveAll(certDir)
+	})
+
+	createTestDataSource := func(pvcName string) *cdiv1.DataSource {
+		return &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testDataSourceName,
+				Namespace: testNamespace,
+			},
+			Spec: cdiv1.DataSourceSpec{
+				Source: cdiv1.DataSourceSource{
+					PVC: &cdiv1.DataVolumeSourcePVC{
+						Namespace: testNamespace,
+						Name:      pvcName,
+					},
+				},
+			},
+		}
+	}
+
+	It("Should identify a DataSource source", func() {
+		export := createDataSourceVMExport()
+		Expect(controller.isSourceDataSource(&export.Spec)).To(BeTrue())
+		Expect(controller.isSourcePvc(&export.Spec)).To(BeFalse())
+	})
+
+	It("Should report the DataSource as unavailable if it does not exist", func() {
+		testVMExport := createDataSourceVMExport()
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+	})
+
+	It("Should report the backing PVC as unavailable if it does not exist", func() {
+		testVMExport := createDataSourceVMExport()
+		dataSourceInformer.GetStore().Add(createTestDataSource(testPVCName))
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+	})
+
+	It("Should properly update VMExport status with a valid token and kubevirt pvc from a DataSource source", func() {
+		testVMExport := createDataSourceVMExport()
+		dataSourceInformer.GetStore().Add(createTestDataSource(testPVCName))
+		pvcInformer.GetStore().Add(createPVC(testPVCName, string(cdiv1.DataVolumeKubeVirt)))
+		expectExporterCreate(k8sClient, k8sv1.PodPending)
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+	})
+})
@@ -24,11 +24,15 @@ import (
 	"crypto/rsa"
 	"fmt"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -50,10 +54,14 @@ import (
 	"kubevirt.io/kubevirt/pkg/certificates/triple"
 	"kubevirt.io/kubevirt/pkg/certificates/triple/cert"
 	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/instancetype"
+	"kubevirt.io/kubevirt/pkg/monitoring/vmexport"
 	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
 
 	"kubevirt.io/kubevirt/pkg/storage/snapshot"
 	"kubevirt.io/kubevirt/pkg/storage/types"
+	"kubevirt.io/kubevirt/pkg/util"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 	"kubevirt.io/kubevirt/pkg/virt-controller/services"
 	watchutil "kubevirt.io/kubevirt/pkg/virt-controller/watch/util"
 
@@ -66,23 +74,67 @@ const (
 	failedKeyFromObjectFmt = "failed to get key from object: %v, %v"
 	enqueuedForSyncFmt     = "enqueued %q for sync"
 
-	pvcNotFoundReason  = "PVCNotFound"
-	pvcBoundReason     = "PVCBound"
-	pvcPendingReason   = "PVCPending"
-	unknownReason      = "Unknown"
-	initializingReason = "Initializing"
-	inUseReason        = "InUse"
-	podPendingReason   = "PodPending"
-	podReadyReason     = "PodReady"
-	podCompletedReason = "PodCompleted"
+	pvcNotFoundReason   = "PVCNotFound"
+	pvcBoundReason      = "PVCBound"
+	pvcPendingReason    = "PVCPending"
+	unknownReason       = "Unknown"
+	initializingReason  = "Initializing"
+	inUseReason         = "InUse"
+	podPendingReason    = "PodPending"
+	podReadyReason      = "PodReady"
+	podNotReadyReason   = "PodNotReady"
+	podCompletedReason  = "PodCompleted"
+	quotaExceededReason = "QuotaExceeded"
+	pausedReason        = "Paused"
+	awaitingClaimReason = "AwaitingClaim"
+	migratingReason     = "MigrationInProgress"
+
+	externalEndpointFoundReason = "ExternalEndpointFound"
+	// noExternalEndpointReason is used when status.links.external is empty because no Ingress or
+	// Route matched the export, and the export's Service isn't a NodePort or LoadBalancer either.
+	noExternalEndpointReason = "NoExternalEndpoint"
+
+	// hooksSucceededReason is used when every hook configured in spec.hooks has reported success.
+	hooksSucceededReason = "HooksSucceeded"
+	// hooksFailedReason is used when a hook configured in spec.hooks has reported failure.
+	hooksFailedReason = "HooksFailed"
+	// hooksPendingReason is used when a hook configured in spec.hooks hasn't reported an outcome yet.
+	hooksPendingReason = "HooksPending"
 
 	exportServiceLabel = "kubevirt.io.virt-export-service"
 
+	// exportVolumeLabel additionally identifies which volume a per-volume exporter pod (and its
+	// Service) was created for, when spec.perVolumePods is set, alongside exportServiceLabel. The
+	// primary exporter pod(s) and Service carry it too, set to the empty string, so the primary
+	// Service's selector can require exportVolumeLabel=="" and thereby exclude per-volume pods —
+	// otherwise a Service selector's equality-only matching would treat the primary Service's
+	// {exportServiceLabel: vmExport.Name} selector as a subset of every per-volume pod's labels too.
+	exportVolumeLabel = "kubevirt.io.virt-export-volume"
+
+	// exporterPodDeadlineAnnotation records, on the exporter pod, the deadline this controller
+	// last pushed to it over its internal deadline-extension endpoint, so maybeExtendExporterDeadline
+	// doesn't re-push the same value on every reconcile.
+	exporterPodDeadlineAnnotation = "export.kubevirt.io/deadline"
+
 	exportPrefix = "virt-export"
 
 	blockVolumeMountPath = "/dev/export-volumes"
 	fileSystemMountPath  = "/export-volumes"
 	urlBasePath          = "/volumes"
+	// ovaURLPath is the path the exporter pod serves a bundled OVA at, when the source is a
+	// VirtualMachine. Unlike the other formats, it is not per-volume.
+	ovaURLPath = "/ova"
+	// manifestURLPath is the path the exporter pod serves the generated manifest at, when the
+	// source is a VirtualMachine. Unlike the other formats, it is not per-volume.
+	manifestURLPath = "/manifest"
+	// expandedManifestURLPath is the path the exporter pod serves the generated manifest with an
+	// expanded VM spec at, when the source is a VirtualMachine referencing an instancetype or
+	// preference. Unlike the other formats, it is not per-volume.
+	expandedManifestURLPath = "/manifest-expanded"
+	// allURLPath is the path the exporter pod serves a single tar archive containing every
+	// volume, plus the generated manifests if the source is a VirtualMachine, at. Unlike the
+	// other formats, it is not per-volume.
+	allURLPath = "/all"
 
 	// annContentType is an annotation on a PVC indicating the content type. This is populated by CDI.
 	annContentType = "cdi.kubevirt.io/storage.contentType"
@@ -92,19 +144,108 @@ const (
 	caKeyFile     = caDefaultPath + "/tls.key"
 	// name of certificate secret volume in pod
 	certificates = "certificates"
+	// name of the S3 credentials secret volume in pod
+	s3Credentials = "s3-credentials"
+	// s3CredentialsAccessKeyIDKey and s3CredentialsSecretAccessKeyKey are the keys expected in
+	// the secret referenced by spec.s3Upload.credentialsSecretRef
+	s3CredentialsAccessKeyIDKey     = "accessKeyId"
+	s3CredentialsSecretAccessKeyKey = "secretAccessKey"
+	// name of the registry credentials secret volume in pod
+	registryCredentials = "registry-credentials"
+	// registryCredentialsUsernameKey and registryCredentialsPasswordKey are the keys expected
+	// in the secret referenced by spec.registryUpload.credentialsSecretRef
+	registryCredentialsUsernameKey = "username"
+	registryCredentialsPasswordKey = "password"
+	// name of the target cluster kubeconfig secret volume in pod
+	clusterUploadKubeconfig = "cluster-upload-kubeconfig"
+	// clusterUploadKubeconfigKey is the key expected in the secret referenced by
+	// spec.clusterUpload.kubeconfigSecretRef
+	clusterUploadKubeconfigKey = "kubeconfig"
+	// name of the generated OVF descriptor secret volume in pod
+	ovaDescriptor = "ova-descriptor"
+	// ovaDescriptorKey is the entry used to store the generated OVF XML in the OVA descriptor secret
+	ovaDescriptorKey = "vm.ovf"
+	// name of the generated manifest secret volume in pod
+	manifestSecretVolume = "manifest"
+	// manifestKey is the entry used to store the generated manifest in the manifest secret
+	manifestKey = "all.yaml"
+	// expandedManifestKey is the entry used to store the generated manifest with an expanded VM
+	// spec in the manifest secret
+	expandedManifestKey = "all-expanded.yaml"
+	// name of the encryption key secret volume in pod
+	encryptionKeyVolume = "encryption-key"
+	// encryptionKeyDataKey is the key expected in the secret referenced by
+	// spec.encryptionSecretRef
+	encryptionKeyDataKey = "key"
+	// name of the client CA secret volume in pod
+	clientCAVolume = "client-ca"
+	// clientCADataKey is the key expected in the secret referenced by
+	// spec.clientCertificateAuthorityRef
+	clientCADataKey = "ca.crt"
 
 	exporterPodFailedOrCompletedEvent = "ExporterPodFailedOrCompleted"
 	exporterPodCreatedEvent           = "ExporterPodCreated"
 	ExportPaused                      = "ExportPaused"
 	secretCreatedEvent                = "SecretCreated"
 	serviceCreatedEvent               = "ServiceCreated"
+	networkPolicyCreatedEvent         = "NetworkPolicyCreated"
 
 	certExpiry = time.Duration(30 * time.Hour) // 30 hours
 	deadline   = time.Duration(24 * time.Hour) // 24 hours
 
+	// defaultZstdCompressionLevel is zstd's own default compression level, used for the zstd and
+	// tar.zst formats when the cluster has not configured one.
+	defaultZstdCompressionLevel = 3
+
+	// defaultGzipCompressionLevel is compress/gzip's own default compression level, used for the
+	// gz and tar.gz formats when the cluster has not configured one.
+	defaultGzipCompressionLevel = -1
+
+	// defaultShutdownGracePeriod is how long an exporter pod waits for in-flight downloads to
+	// finish once it starts shutting down, when the cluster has not configured one.
+	defaultShutdownGracePeriod = 30 * time.Second
+
+	// shutdownGracePeriodBuffer is added on top of the exporter's own shutdown grace period when
+	// setting the pod's TerminationGracePeriodSeconds, so kubelet doesn't SIGKILL the container
+	// while it is still within its own drain phase.
+	shutdownGracePeriodBuffer = 10 * time.Second
+
+	// nbdBasePort is the first port an exporter pod listens on for NBD, when NBD is enabled. Each
+	// volume gets its own NBD export on its own port, starting from nbdBasePort and incrementing
+	// by one per volume.
+	nbdBasePort = 10810
+
+	tokenPrefix = "token"
+	// secretTokenKey is the entry used to store the token in the generated token secret
+	secretTokenKey = "token"
+
+	// scratchSpaceVolumeName is the emptyDir volume mounted into the exporter pod's container to
+	// back its writable scratch directory now that the container's root filesystem is read-only.
+	scratchSpaceVolumeName = "scratch-space"
+	// scratchSpaceMountPath is where scratchSpaceVolumeName is mounted, and is also exported to
+	// the exporter process as its TMPDIR, so the temporary files it already creates for format
+	// conversion and compression land there instead of the (now read-only) root filesystem.
+	scratchSpaceMountPath = "/var/run/kubevirt/export-scratch"
+	// secretTokenLength is the length of the randomly generated token
+	secretTokenLength = 20
+
 	kvm = 107
 
 	requeueTime = time.Second * 3
+
+	// restoreRequeueTime is used instead of requeueTime while waiting on a VirtualMachineSnapshot
+	// or VolumeSnapshot to restore into a PVC, since that takes noticeably longer than a PVC simply
+	// becoming bound and populated, and polling every requeueTime would just churn the API server.
+	restoreRequeueTime = time.Second * 15
+
+	// migrationRequeueTime is used instead of requeueTime while the source VirtualMachineInstance
+	// is being live migrated, as a fallback in case the VMI update marking the migration's end is
+	// ever missed.
+	migrationRequeueTime = time.Second * 10
+
+	// vmExportFinalizer is used to ensure that an auto-generated token secret is handled
+	// according to spec.deletionPolicy before the VirtualMachineExport that owns it is removed.
+	vmExportFinalizer = "export.kubevirt.io/vmexport-protection"
 )
 
 // variable so can be overridden in tests
@@ -113,6 +254,10 @@ var currentTime = func() *metav1.Time {
 	return &t
 }
 
+// defaultScratchSpaceSize is the size of the exporter pod's scratch emptyDir when neither
+// VirtualMachineExportSpec.ScratchSpaceSize nor the cluster default is configured.
+var defaultScratchSpaceSize = resource.MustParse("1Gi")
+
 var exportGVK = schema.GroupVersionKind{
 	Group:   exportv1.SchemeGroupVersion.Group,
 	Version: exportv1.SchemeGroupVersion.Version,
@@ -137,6 +282,34 @@ func archiveURI(pvc *corev1.PersistentVolumeClaim) string {
 	return path.Join(fmt.Sprintf("%s/%s/disk.tar.gz", urlBasePath, pvc.Name))
 }
 
+func archiveZstdURI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.tar.zst", urlBasePath, pvc.Name))
+}
+
+func qcow2URI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.qcow2", urlBasePath, pvc.Name))
+}
+
+func qcow2GzipURI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.qcow2.gz", urlBasePath, pvc.Name))
+}
+
+func zstdURI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.img.zst", urlBasePath, pvc.Name))
+}
+
+func vmdkURI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.vmdk", urlBasePath, pvc.Name))
+}
+
+func vhdURI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.vhd", urlBasePath, pvc.Name))
+}
+
+func vhdxURI(pvc *corev1.PersistentVolumeClaim) string {
+	return path.Join(fmt.Sprintf("%s/%s/disk.vhdx", urlBasePath, pvc.Name))
+}
+
 func dirURI(pvc *corev1.PersistentVolumeClaim) string {
 	return path.Join(fmt.Sprintf("%s/%s/dir", urlBasePath, pvc.Name)) + "/"
 }
@@ -146,39 +319,83 @@ type sourceVolumes struct {
 	inUse            bool
 	isPopulated      bool
 	availableMessage string
+	// quotaExceeded is set when the namespace has already reached its configured limit of
+	// Ready VirtualMachineExports. It only prevents a new exporter pod from being created;
+	// an already-running export is left alone until it completes.
+	quotaExceeded bool
+	// waitingForFirstConsumer is set when the source PVC's backing DataVolume is in the
+	// WaitForFirstConsumer phase: the PVC is not yet bound, and its storage class won't bind it
+	// until some pod references it. See needsExporterPod.
+	waitingForFirstConsumer bool
+	// vmiSpec is set when the source is a VirtualMachine, describing its CPU, memory and
+	// network interfaces. It is used to generate the OVF descriptor bundled into an OVA export.
+	vmiSpec *virtv1.VirtualMachineInstanceSpec
+	// migrating is set when the source VirtualMachineInstance is in the middle of a live migration,
+	// so the Ready condition can call that out instead of the generic InUse reason, and so
+	// exporter pod creation is held off until the migration settles instead of churning the pod as
+	// the VMI's node flips back and forth.
+	migrating bool
+	// requeueAfter, if set, overrides the default poll interval used while the source is not yet
+	// available, for example a longer interval while waiting on a snapshot restore.
+	requeueAfter time.Duration
+	// insufficientCapacity is set when a restore PVC could not be created from a
+	// VirtualMachineSnapshot because the namespace's storage ResourceQuota doesn't have room for
+	// it, so the caller can report a specific reason instead of leaving the export looking like
+	// it is merely waiting on a restore that will never complete.
+	insufficientCapacity bool
 }
 
 func (sv *sourceVolumes) isSourceAvailable() bool {
 	return !sv.inUse && sv.isPopulated
 }
 
+// needsExporterPod returns whether the exporter pod should be created, or kept running, even
+// though the source isn't fully available yet. This is the case while waitingForFirstConsumer is
+// set: the exporter pod doubles as the first consumer a WaitForFirstConsumer storage class is
+// waiting on before it binds the PVC, so the pod has to exist before the PVC - and in turn the
+// source - can ever become available.
+func (sv *sourceVolumes) needsExporterPod() bool {
+	return sv.isSourceAvailable() || sv.waitingForFirstConsumer
+}
+
 // VMExportController is resonsible for exporting VMs
 type VMExportController struct {
 	Client kubecli.KubevirtClient
 
 	TemplateService services.TemplateService
 
-	VMExportInformer          cache.SharedIndexInformer
+	VMExportInformer cache.SharedIndexInformer
+	// PVCInformer watches every PVC cluster-wide and cannot be label-filtered: the source of a
+	// VirtualMachineExport can be any PVC in its namespace, not just ones created by this controller.
 	PVCInformer               cache.SharedIndexInformer
+	PVInformer                cache.SharedIndexInformer
 	VMSnapshotInformer        cache.SharedIndexInformer
 	VMSnapshotContentInformer cache.SharedIndexInformer
-	PodInformer               cache.SharedIndexInformer
-	DataVolumeInformer        cache.SharedIndexInformer
-	ConfigMapInformer         cache.SharedIndexInformer
-	ServiceInformer           cache.SharedIndexInformer
-	VMInformer                cache.SharedIndexInformer
-	VMIInformer               cache.SharedIndexInformer
-	RouteConfigMapInformer    cache.SharedInformer
-	RouteCache                cache.Store
-	IngressCache              cache.Store
-	SecretInformer            cache.SharedIndexInformer
-	VolumeSnapshotProvider    snapshot.VolumeSnapshotProvider
+	// PodInformer watches every pod cluster-wide and cannot be label-filtered: isPVCInUse has to
+	// see every pod that might be mounting a source PVC, not just the exporter pods this
+	// controller manages itself.
+	PodInformer            cache.SharedIndexInformer
+	DataVolumeInformer     cache.SharedIndexInformer
+	DataSourceInformer     cache.SharedIndexInformer
+	ConfigMapInformer      cache.SharedIndexInformer
+	ServiceInformer        cache.SharedIndexInformer
+	VMInformer             cache.SharedIndexInformer
+	VMIInformer            cache.SharedIndexInformer
+	RouteConfigMapInformer cache.SharedInformer
+	RouteCache             cache.Store
+	IngressCache           cache.Store
+	SecretInformer         cache.SharedIndexInformer
+	VolumeSnapshotProvider snapshot.VolumeSnapshotProvider
+
+	InstancetypeMethods instancetype.Methods
 
 	Recorder record.EventRecorder
 
 	KubevirtNamespace string
 	ResyncPeriod      time.Duration
 
+	ClusterConfig *virtconfig.ClusterConfig
+
 	vmExportQueue workqueue.RateLimitingInterface
 
 	caCertManager *bootstrap.FileCertificateManager
@@ -248,6 +465,14 @@ func (ctrl *VMExportController) Init() {
 		},
 		ctrl.ResyncPeriod,
 	)
+	ctrl.DataSourceInformer.AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.handleDataSource,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.handleDataSource(newObj) },
+			DeleteFunc: ctrl.handleDataSource,
+		},
+		ctrl.ResyncPeriod,
+	)
 
 	initCert(ctrl)
 }
@@ -264,6 +489,7 @@ func (ctrl *VMExportController) Run(threadiness int, stopCh <-chan struct{}) err
 		stopCh,
 		ctrl.VMExportInformer.HasSynced,
 		ctrl.PVCInformer.HasSynced,
+		ctrl.PVInformer.HasSynced,
 		ctrl.PodInformer.HasSynced,
 		ctrl.DataVolumeInformer.HasSynced,
 		ctrl.ConfigMapInformer.HasSynced,
@@ -306,7 +532,14 @@ func (ctrl *VMExportController) processVMExportWorkItem() bool {
 			return 0, fmt.Errorf(unexpectedResourceFmt, storeObj)
 		}
 
-		return ctrl.updateVMExport(vmExport.DeepCopy())
+		start := time.Now()
+		requeueAfter, err := ctrl.updateVMExport(vmExport.DeepCopy())
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		vmexport.ReconcileDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+		return requeueAfter, err
 	})
 }
 
@@ -364,14 +597,70 @@ func (ctrl *VMExportController) updateVMExport(vmExport *exportv1.VirtualMachine
 	log.Log.V(3).Infof("Updating VirtualMachineExport %s/%s", vmExport.Namespace, vmExport.Name)
 
 	if vmExport.DeletionTimestamp != nil {
+		if ctrl.isSourceVM(&vmExport.Spec) && quiesce(vmExport) {
+			if err := ctrl.thawVMIIfFrozen(vmExport.Namespace, vmExport.Spec.Source.Name); err != nil {
+				return 0, err
+			}
+		}
+		if controller.HasFinalizer(vmExport, vmExportFinalizer) {
+			if err := ctrl.handleTokenSecretDeletionPolicy(vmExport); err != nil {
+				return 0, err
+			}
+			vmExportCopy := vmExport.DeepCopy()
+			controller.RemoveFinalizer(vmExportCopy, vmExportFinalizer)
+			if err := ctrl.updateVMExportStatus(vmExport, vmExportCopy); err != nil {
+				return 0, err
+			}
+		}
 		return 0, nil
 	}
 
+	expired, ttlRequeueAfter := ctrl.checkTTL(vmExport)
+	if expired {
+		log.Log.V(3).Infof("VirtualMachineExport %s/%s ttlDuration expired, deleting", vmExport.Namespace, vmExport.Name)
+		return 0, ctrl.Client.VirtualMachineExport(vmExport.Namespace).Delete(context.Background(), vmExport.Name, metav1.DeleteOptions{})
+	}
+
+	requeueAfter, err := ctrl.syncSource(vmExport)
+	if err != nil {
+		return requeueAfter, err
+	}
+	if ttlRequeueAfter > 0 && (requeueAfter == 0 || ttlRequeueAfter < requeueAfter) {
+		requeueAfter = ttlRequeueAfter
+	}
+	return requeueAfter, nil
+}
+
+// checkTTL returns whether vmExport's ttlDuration (if any) has already elapsed. If it hasn't,
+// it returns the remaining time until it does, so the caller can make sure the export gets
+// reconciled again around that time even without any other triggering event. The per-export
+// Spec.TTLDuration takes precedence, then the cluster default configured in KubeVirtConfiguration.
+func (ctrl *VMExportController) checkTTL(vmExport *exportv1.VirtualMachineExport) (bool, time.Duration) {
+	ttlDuration := vmExport.Spec.TTLDuration
+	if ttlDuration == nil {
+		ttlDuration = ctrl.ClusterConfig.GetVMExportTTL()
+	}
+	if ttlDuration == nil {
+		return false, 0
+	}
+	expiry := vmExport.CreationTimestamp.Add(ttlDuration.Duration)
+	remaining := expiry.Sub(currentTime().Time)
+	if remaining <= 0 {
+		return true, 0
+	}
+	return false, remaining
+}
+
+func (ctrl *VMExportController) syncSource(vmExport *exportv1.VirtualMachineExport) (time.Duration, error) {
 	service, err := ctrl.getOrCreateExportService(vmExport)
 	if err != nil {
 		return 0, err
 	}
 
+	if err := ctrl.getOrCreateExportNetworkPolicy(vmExport); err != nil {
+		return 0, err
+	}
+
 	if ctrl.isSourcePvc(&vmExport.Spec) {
 		return ctrl.handleSource(vmExport, service, ctrl.getPVCFromSourcePVC, ctrl.updateVMExportPvcStatus)
 	}
@@ -381,6 +670,12 @@ func (ctrl *VMExportController) updateVMExport(vmExport *exportv1.VirtualMachine
 	if ctrl.isSourceVM(&vmExport.Spec) {
 		return ctrl.handleSource(vmExport, service, ctrl.getPVCFromSourceVM, ctrl.updateVMExportVMStatus)
 	}
+	if ctrl.isSourceVolumeSnapshot(&vmExport.Spec) {
+		return ctrl.handleSource(vmExport, service, ctrl.getPVCFromSourceVolumeSnapshot, ctrl.updateVMExportPvcStatus)
+	}
+	if ctrl.isSourceDataSource(&vmExport.Spec) {
+		return ctrl.handleSource(vmExport, service, ctrl.getPVCFromSourceDataSource, ctrl.updateVMExportPvcStatus)
+	}
 	return 0, nil
 }
 
@@ -388,55 +683,169 @@ type pvcFromSourceFunc func(*exportv1.VirtualMachineExport) (*sourceVolumes, err
 type updateVMExportStatusFunc func(*exportv1.VirtualMachineExport, *corev1.Pod, *corev1.Service, *sourceVolumes) (time.Duration, error)
 
 func (ctrl *VMExportController) handleSource(vmExport *exportv1.VirtualMachineExport, service *corev1.Service, getPVCFromSource pvcFromSourceFunc, updateStatus updateVMExportStatusFunc) (time.Duration, error) {
+	if _, err := ctrl.getOrCreateTokenSecret(vmExport); err != nil {
+		return 0, err
+	}
+
 	sourceVolumes, err := getPVCFromSource(vmExport)
 	if err != nil {
 		return 0, err
 	}
 	log.Log.V(4).Infof("Source volumes %v", sourceVolumes)
 
-	pod, err := ctrl.manageExporterPod(vmExport, sourceVolumes)
+	_, exporterPodExists, err := ctrl.getExporterPod(vmExport)
+	if err != nil {
+		return 0, err
+	}
+	if !exporterPodExists && sourceVolumes.isSourceAvailable() {
+		sourceVolumes.quotaExceeded, err = ctrl.isNamespaceExportQuotaExceeded(vmExport)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	pod, podRequeue, err := ctrl.manageExporterPod(vmExport, sourceVolumes)
+	if err != nil {
+		return 0, err
+	}
+
+	requeue, err := updateStatus(vmExport, pod, service, sourceVolumes)
 	if err != nil {
 		return 0, err
 	}
+	return minRequeueAfter(podRequeue, requeue), nil
+}
+
+// minRequeueAfter returns the sooner of a and b, treating 0 as "no requeue requested" rather
+// than "requeue immediately", so it can be used to combine independent requeue durations without
+// one that doesn't apply overriding one that does.
+func minRequeueAfter(a, b time.Duration) time.Duration {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// isPaused returns whether spec.paused is set, in which case the exporter pod is torn down
+// (keeping the service and token secret intact) until it is unset again.
+func isPaused(vmExport *exportv1.VirtualMachineExport) bool {
+	return vmExport.Spec.Paused != nil && *vmExport.Spec.Paused
+}
+
+// isPodReady returns whether pod has passed its readiness probe. Links are only published once
+// this is true, so that clients aren't handed a link to an exporter pod that can't yet serve
+// downloads.
+func isPodReady(pod *corev1.Pod) bool {
+	return controller.NewPodConditionManager().HasConditionWithStatus(pod, corev1.PodReady, corev1.ConditionTrue)
+}
+
+// isOnDemand returns whether spec.onDemand is set, in which case the exporter pod is only
+// created once the export has been claimed, instead of as soon as its source volumes are
+// available.
+func isOnDemand(vmExport *exportv1.VirtualMachineExport) bool {
+	return vmExport.Spec.OnDemand != nil && *vmExport.Spec.OnDemand
+}
+
+// isClaimed returns whether vmExport is eligible to have its exporter pod created: either it is
+// not in on-demand mode, or it is and has been annotated with AnnotationExportClaimed.
+func isClaimed(vmExport *exportv1.VirtualMachineExport) bool {
+	if !isOnDemand(vmExport) {
+		return true
+	}
+	claimed, _ := strconv.ParseBool(vmExport.Annotations[exportv1.AnnotationExportClaimed])
+	return claimed
+}
+
+// isPerVolumePods returns whether spec.perVolumePods is set, in which case every volume beyond
+// the first one gets its own exporter pod and Service, instead of being bundled into the same
+// exporter pod as the rest.
+func isPerVolumePods(vmExport *exportv1.VirtualMachineExport) bool {
+	return vmExport.Spec.PerVolumePods != nil && *vmExport.Spec.PerVolumePods
+}
 
-	return updateStatus(vmExport, pod, service, sourceVolumes)
+// splitPerVolumePodVolumes splits volumes into the ones bundled into the primary exporter pod and
+// the ones that get their own per-volume exporter pod, according to isPerVolumePods. When it is
+// not set, or there is only one volume to begin with, every volume stays in the primary pod.
+func splitPerVolumePodVolumes(vmExport *exportv1.VirtualMachineExport, volumes []*corev1.PersistentVolumeClaim) ([]*corev1.PersistentVolumeClaim, []*corev1.PersistentVolumeClaim) {
+	if !isPerVolumePods(vmExport) || len(volumes) < 2 {
+		return volumes, nil
+	}
+	return volumes[:1], volumes[1:]
 }
 
-func (ctrl *VMExportController) manageExporterPod(vmExport *exportv1.VirtualMachineExport, sourceVolumes *sourceVolumes) (*corev1.Pod, error) {
+func (ctrl *VMExportController) manageExporterPod(vmExport *exportv1.VirtualMachineExport, sourceVolumes *sourceVolumes) (*corev1.Pod, time.Duration, error) {
 	pod, podExists, err := ctrl.getExporterPod(vmExport)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	primaryVolumes, perVolumePodVolumes := splitPerVolumePodVolumes(vmExport, sourceVolumes.volumes)
+	if isPaused(vmExport) {
+		if podExists {
+			if err := ctrl.deleteExporterPod(vmExport, pod, ExportPaused, "VirtualMachineExport is paused"); err != nil {
+				return nil, 0, err
+			}
+			if err := ctrl.deleteExporterPodReplicas(vmExport); err != nil {
+				return nil, 0, err
+			}
+			if err := ctrl.deletePerVolumeExporterPods(vmExport, perVolumePodVolumes); err != nil {
+				return nil, 0, err
+			}
+		}
+		return nil, 0, nil
 	}
 	if !podExists {
-		if sourceVolumes.isSourceAvailable() {
-			if len(sourceVolumes.volumes) > 0 {
-				pod, err = ctrl.createExporterPod(vmExport, sourceVolumes.volumes)
+		if sourceVolumes.needsExporterPod() && !sourceVolumes.quotaExceeded && isClaimed(vmExport) {
+			if len(primaryVolumes) > 0 {
+				pod, err = ctrl.createExporterPod(vmExport, primaryVolumes, sourceVolumes.vmiSpec)
 				if err != nil {
-					return nil, err
+					return nil, 0, err
 				}
 			}
 		}
 	}
+	var requeue time.Duration
 	if pod != nil {
 		if pod.Status.Phase == corev1.PodPending {
 			if err := ctrl.getOrCreateCertSecret(vmExport, pod); err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 		}
 
 		if sourceVolumes.isSourceAvailable() {
-			if err := ctrl.handlePodSucceededOrFailed(vmExport, pod); err != nil {
-				return nil, err
+			finishedPhase := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+			pod, requeue, err = ctrl.handlePodSucceededOrFailed(vmExport, pod)
+			if err != nil {
+				return nil, 0, err
 			}
-		} else {
+			if !finishedPhase {
+				if err := ctrl.manageExporterPodReplicas(vmExport, primaryVolumes, sourceVolumes.vmiSpec); err != nil {
+					return nil, 0, err
+				}
+				if err := ctrl.managePerVolumeExporterPods(vmExport, perVolumePodVolumes, sourceVolumes.vmiSpec); err != nil {
+					return nil, 0, err
+				}
+			}
+		} else if !sourceVolumes.needsExporterPod() {
 			// source is not available, stop the exporter pod if started
 			if err := ctrl.deleteExporterPod(vmExport, pod, ExportPaused, sourceVolumes.availableMessage); err != nil {
-				return nil, err
+				return nil, 0, err
+			}
+			if err := ctrl.deleteExporterPodReplicas(vmExport); err != nil {
+				return nil, 0, err
+			}
+			if err := ctrl.deletePerVolumeExporterPods(vmExport, perVolumePodVolumes); err != nil {
+				return nil, 0, err
 			}
 			pod = nil
 		}
 	}
-	return pod, nil
+	return pod, requeue, nil
 }
 
 func (ctrl *VMExportController) deleteExporterPod(vmExport *exportv1.VirtualMachineExport, pod *corev1.Pod, deleteReason, message string) error {
@@ -447,16 +856,85 @@ func (ctrl *VMExportController) deleteExporterPod(vmExport *exportv1.VirtualMach
 	return nil
 }
 
-func (ctrl *VMExportController) handlePodSucceededOrFailed(vmExport *exportv1.VirtualMachineExport, pod *corev1.Pod) error {
-	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
-		// The server died or completed, delete the pod.
-		return ctrl.deleteExporterPod(vmExport, pod, exporterPodFailedOrCompletedEvent, fmt.Sprintf("Exporter pod %s/%s is in phase %s", pod.Namespace, pod.Name, pod.Status.Phase))
+// handlePodSucceededOrFailed deletes pod, along with its replicas, once it has finished, unless
+// the cluster is configured with ExportConfiguration.PodTTLAfterFinished, in which case it is
+// kept around for that long first, so its logs stay available to diagnose a failed download. It
+// returns the pod (nil if it was deleted) and how much longer to wait before checking again, or 0
+// if pod either wasn't finished or was deleted.
+func (ctrl *VMExportController) handlePodSucceededOrFailed(vmExport *exportv1.VirtualMachineExport, pod *corev1.Pod) (*corev1.Pod, time.Duration, error) {
+	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		return pod, 0, nil
 	}
-	return nil
+
+	ttl := ctrl.getPodTTLAfterFinished()
+	if ttl > 0 {
+		finishedAt := podFinishedAt(pod)
+		if finishedAt == nil {
+			return pod, 0, nil
+		}
+		if remaining := ttl - time.Since(finishedAt.Time); remaining > 0 {
+			return pod, remaining, nil
+		}
+	}
+
+	// The server died or completed, delete the pod and its replicas.
+	if err := ctrl.deleteExporterPod(vmExport, pod, exporterPodFailedOrCompletedEvent, fmt.Sprintf("Exporter pod %s/%s is in phase %s", pod.Namespace, pod.Name, pod.Status.Phase)); err != nil {
+		return nil, 0, err
+	}
+	return nil, 0, ctrl.deleteExporterPodReplicas(vmExport)
+}
+
+// getPodTTLAfterFinished returns how long a finished exporter pod is kept around before being
+// deleted. The cluster default configured in KubeVirtConfiguration takes precedence, then the
+// pod is deleted immediately, preserving the behavior before PodTTLAfterFinished was introduced.
+func (ctrl *VMExportController) getPodTTLAfterFinished() time.Duration {
+	if podTTL := ctrl.ClusterConfig.GetVMExportPodTTLAfterFinished(); podTTL != nil {
+		return podTTL.Duration
+	}
+	return 0
+}
+
+// podFinishedAt returns when the last of pod's containers to terminate did so, or nil if any
+// container hasn't reported a terminated status yet.
+func podFinishedAt(pod *corev1.Pod) *metav1.Time {
+	var finishedAt *metav1.Time
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated == nil {
+			return nil
+		}
+		if finishedAt == nil || containerStatus.State.Terminated.FinishedAt.After(finishedAt.Time) {
+			finishedAt = &containerStatus.State.Terminated.FinishedAt
+		}
+	}
+	return finishedAt
 }
 
 func (ctrl *VMExportController) isPVCPopulated(pvc *corev1.PersistentVolumeClaim) (bool, error) {
-	return cdiv1.IsPopulated(pvc, func(name, namespace string) (*cdiv1.DataVolume, error) {
+	if isPopulatorPVC(pvc) {
+		// Generic volume populators are not bound until the populator has finished writing
+		// data, unlike DataVolume-backed PVCs which can already be bound while still
+		// importing. Bound is therefore a reliable populated signal here.
+		return pvc.Status.Phase == corev1.ClaimBound, nil
+	}
+	return cdiv1.IsPopulated(pvc, ctrl.getDataVolumeFunc())
+}
+
+// isPopulatorPVC returns whether pvc's contents are filled by a CDI volume populator (its
+// spec.dataSourceRef points at a populator source kind such as VolumeImportSource,
+// VolumeUploadSource or VolumeCloneSource) rather than the PVC being owned by a DataVolume.
+func isPopulatorPVC(pvc *corev1.PersistentVolumeClaim) bool {
+	ref := pvc.Spec.DataSourceRef
+	return ref != nil && ref.APIGroup != nil && *ref.APIGroup == cdiv1.SchemeGroupVersion.Group
+}
+
+// isPVCWaitingForFirstConsumer returns whether pvc's backing DataVolume, if any, is in the
+// WaitForFirstConsumer phase, meaning the PVC won't become populated until something consumes it.
+func (ctrl *VMExportController) isPVCWaitingForFirstConsumer(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	return cdiv1.IsWaitForFirstConsumerBeforePopulating(pvc, ctrl.getDataVolumeFunc())
+}
+
+func (ctrl *VMExportController) getDataVolumeFunc() func(name, namespace string) (*cdiv1.DataVolume, error) {
+	return func(name, namespace string) (*cdiv1.DataVolume, error) {
 		obj, exists, err := ctrl.DataVolumeInformer.GetStore().GetByKey(controller.NamespacedKey(namespace, name))
 		if err != nil {
 			return nil, err
@@ -468,7 +946,109 @@ func (ctrl *VMExportController) isPVCPopulated(pvc *corev1.PersistentVolumeClaim
 			}
 		}
 		return nil, fmt.Errorf("datavolume %s/%s not found", namespace, name)
-	})
+	}
+}
+
+// isNamespaceExportQuotaExceeded returns whether vmExport's namespace already has as many Ready
+// VirtualMachineExports as allowed by the cluster-configured MaxConcurrentNamespaceExports. It
+// never counts vmExport itself, so an export that is already Ready is not blocked from staying so.
+func (ctrl *VMExportController) isNamespaceExportQuotaExceeded(vmExport *exportv1.VirtualMachineExport) (bool, error) {
+	quota := ctrl.ClusterConfig.GetVMExportNamespaceQuota()
+	if quota == nil {
+		return false, nil
+	}
+
+	var readyCount int32
+	for _, obj := range ctrl.VMExportInformer.GetStore().List() {
+		export, ok := obj.(*exportv1.VirtualMachineExport)
+		if !ok {
+			return false, fmt.Errorf(unexpectedResourceFmt, obj)
+		}
+		if export.Namespace != vmExport.Namespace || export.Name == vmExport.Name {
+			continue
+		}
+		if export.Status != nil && export.Status.Phase == exportv1.Ready {
+			readyCount++
+		}
+	}
+
+	return readyCount >= *quota, nil
+}
+
+// getTokenSecretName returns the name of the secret that holds the token for vmExport, whether it
+// was set explicitly in spec.tokenSecretRef or automatically generated by getOrCreateTokenSecret.
+func (ctrl *VMExportController) getTokenSecretName(vmExport *exportv1.VirtualMachineExport) string {
+	if vmExport.Spec.TokenSecretRef != nil {
+		return *vmExport.Spec.TokenSecretRef
+	}
+	return naming.GetName(tokenPrefix, vmExport.Name, validation.DNS1035LabelMaxLength)
+}
+
+// getOrCreateTokenSecret ensures a secret holding the export token exists, generating one owned by
+// vmExport when spec.tokenSecretRef is not set, and returns its name.
+func (ctrl *VMExportController) getOrCreateTokenSecret(vmExport *exportv1.VirtualMachineExport) (string, error) {
+	secretName := ctrl.getTokenSecretName(vmExport)
+	if vmExport.Spec.TokenSecretRef != nil {
+		return secretName, nil
+	}
+
+	token, err := util.GenerateSecureRandomString(secretTokenLength)
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: vmExport.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vmExport, schema.GroupVersionKind{
+					Group:   exportGVK.Group,
+					Version: exportGVK.Version,
+					Kind:    exportGVK.Kind,
+				}),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			secretTokenKey: []byte(token),
+		},
+	}
+
+	if _, err := ctrl.Client.CoreV1().Secrets(vmExport.Namespace).Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return "", err
+		}
+	} else {
+		ctrl.Recorder.Eventf(vmExport, corev1.EventTypeNormal, secretCreatedEvent, "Created exporter pod token secret")
+	}
+	return secretName, nil
+}
+
+// handleTokenSecretDeletionPolicy runs while vmExport is being deleted, before its finalizer is
+// removed. When spec.deletionPolicy is Retain, it strips vmExport's owner reference from the
+// auto-generated token secret so it survives the VirtualMachineExport's garbage collection.
+// Otherwise it does nothing, leaving the secret to be garbage collected as normal.
+func (ctrl *VMExportController) handleTokenSecretDeletionPolicy(vmExport *exportv1.VirtualMachineExport) error {
+	if vmExport.Spec.DeletionPolicy == nil || *vmExport.Spec.DeletionPolicy != exportv1.VirtualMachineExportDeletionPolicyRetain {
+		return nil
+	}
+
+	secretName := ctrl.getTokenSecretName(vmExport)
+	secret, err := ctrl.Client.CoreV1().Secrets(vmExport.Namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	secretCopy := secret.DeepCopy()
+	secretCopy.OwnerReferences = nil
+	if _, err := ctrl.Client.CoreV1().Secrets(vmExport.Namespace).Update(context.Background(), secretCopy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (ctrl *VMExportController) getOrCreateCertSecret(vmExport *exportv1.VirtualMachineExport, ownerPod *corev1.Pod) error {
@@ -555,6 +1135,7 @@ func (ctrl *VMExportController) getOrCreateExportService(vmExport *exportv1.Virt
 }
 
 func (ctrl *VMExportController) createServiceManifest(vmExport *exportv1.VirtualMachineExport) *corev1.Service {
+	ipFamilyPolicy := corev1.IPFamilyPolicyPreferDualStack
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ctrl.getExportServiceName(vmExport),
@@ -573,6 +1154,7 @@ func (ctrl *VMExportController) createServiceManifest(vmExport *exportv1.Virtual
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
 				{
+					Name:     "exportapi",
 					Protocol: "TCP",
 					Port:     443,
 					TargetPort: intstr.IntOrString{
@@ -580,15 +1162,115 @@ func (ctrl *VMExportController) createServiceManifest(vmExport *exportv1.Virtual
 						IntVal: 8443,
 					},
 				},
+				{
+					// A separate, named ServicePort pointing at the same exporter listener as
+					// "exportapi", so a ServiceMonitor can scrape the /metrics path this
+					// exporter pod serves there without needing its own container port.
+					Name:     "metrics",
+					Protocol: "TCP",
+					Port:     8443,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 8443,
+					},
+				},
 			},
 			Selector: map[string]string{
 				exportServiceLabel: vmExport.Name,
+				// Excludes per-volume exporter pods, which set exportVolumeLabel to their PVC's
+				// name instead of leaving it empty. See exportVolumeLabel.
+				exportVolumeLabel: "",
 			},
+			// PreferDualStack lets the exporter Service get both an IPv4 and an IPv6 cluster IP
+			// on a dual-stack cluster, and falls back to whichever single family the cluster
+			// supports otherwise, so exports work unmodified on IPv6-only clusters too.
+			IPFamilyPolicy: &ipFamilyPolicy,
 		},
 	}
+	if vmExport.Spec.ServiceType != "" {
+		service.Spec.Type = vmExport.Spec.ServiceType
+	}
 	return service
 }
 
+// getOrCreateExportNetworkPolicy makes sure a NetworkPolicy exists restricting ingress to the
+// exporter pod(s) of vmExport to virt-exportproxy and clients in the same namespace, so export
+// endpoints aren't reachable from arbitrary pods elsewhere in the cluster. Like the exporter
+// Service, it is owned by vmExport and left for Kubernetes to garbage collect, rather than being
+// explicitly torn down while the export is merely paused.
+func (ctrl *VMExportController) getOrCreateExportNetworkPolicy(vmExport *exportv1.VirtualMachineExport) error {
+	networkPolicy := ctrl.createNetworkPolicyManifest(vmExport)
+	_, err := ctrl.Client.NetworkingV1().NetworkPolicies(vmExport.Namespace).Create(context.Background(), networkPolicy, metav1.CreateOptions{})
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+	ctrl.Recorder.Eventf(vmExport, corev1.EventTypeNormal, networkPolicyCreatedEvent, "Created network policy %s/%s", networkPolicy.Namespace, networkPolicy.Name)
+	return nil
+}
+
+func (ctrl *VMExportController) createNetworkPolicyManifest(vmExport *exportv1.VirtualMachineExport) *networkingv1.NetworkPolicy {
+	tcp := corev1.ProtocolTCP
+	port := intstr.FromInt(8443)
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctrl.getExportServiceName(vmExport),
+			Namespace: vmExport.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vmExport, schema.GroupVersionKind{
+					Group:   exportGVK.Group,
+					Version: exportGVK.Version,
+					Kind:    exportGVK.Kind,
+				}),
+			},
+			Labels: map[string]string{
+				virtv1.AppLabel: exportv1.App,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					exportServiceLabel: vmExport.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Protocol: &tcp,
+							Port:     &port,
+						},
+					},
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							// Same-namespace clients, e.g. a VirtualMachineExport consumer running
+							// alongside the source workload.
+							PodSelector: &metav1.LabelSelector{},
+						},
+						{
+							// virt-exportproxy, which fronts the export for clients outside the
+							// cluster or namespace.
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									corev1.LabelMetadataName: ctrl.KubevirtNamespace,
+								},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									virtv1.AppLabel: components.VirtExportProxyName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func (ctrl *VMExportController) getExporterPod(vmExport *exportv1.VirtualMachineExport) (*corev1.Pod, bool, error) {
 	key := controller.NamespacedKey(vmExport.Namespace, ctrl.getExportPodName(vmExport))
 	if obj, exists, err := ctrl.PodInformer.GetStore().GetByKey(key); err != nil {
@@ -602,19 +1284,24 @@ func (ctrl *VMExportController) getExporterPod(vmExport *exportv1.VirtualMachine
 	}
 }
 
-func (ctrl *VMExportController) createExporterPod(vmExport *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim) (*corev1.Pod, error) {
+func (ctrl *VMExportController) createExporterPod(vmExport *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim, vmiSpec *virtv1.VirtualMachineInstanceSpec) (*corev1.Pod, error) {
 	log.Log.V(3).Infof("Checking if pod exist: %s/%s", vmExport.Namespace, ctrl.getExportPodName(vmExport))
 	key := controller.NamespacedKey(vmExport.Namespace, ctrl.getExportPodName(vmExport))
 	if obj, exists, err := ctrl.PodInformer.GetStore().GetByKey(key); err != nil {
 		log.Log.V(3).Errorf("error %v", err)
 		return nil, err
 	} else if !exists {
-		manifest := ctrl.createExporterPodManifest(vmExport, pvcs)
+		manifest, err := ctrl.createExporterPodManifest(vmExport, pvcs, vmiSpec)
+		if err != nil {
+			return nil, err
+		}
 
 		log.Log.V(3).Infof("Creating new exporter pod %s/%s", manifest.Namespace, manifest.Name)
 		pod, err := ctrl.Client.CoreV1().Pods(vmExport.Namespace).Create(context.Background(), manifest, metav1.CreateOptions{})
 		if err == nil {
 			ctrl.Recorder.Eventf(vmExport, corev1.EventTypeNormal, exporterPodCreatedEvent, "Created exporter pod %s/%s", manifest.Namespace, manifest.Name)
+		} else {
+			vmexport.ExporterPodCreationFailuresTotal.WithLabelValues(vmExport.Namespace).Inc()
 		}
 		return pod, nil
 	} else {
@@ -623,117 +1310,1160 @@ func (ctrl *VMExportController) createExporterPod(vmExport *exportv1.VirtualMach
 	}
 }
 
-func (ctrl *VMExportController) createExporterPodManifest(vmExport *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim) *corev1.Pod {
-	podManifest := ctrl.TemplateService.RenderExporterManifest(vmExport, exportPrefix)
-	podManifest.ObjectMeta.Labels = map[string]string{exportServiceLabel: vmExport.Name}
-	podManifest.Spec.SecurityContext = &corev1.PodSecurityContext{
-		RunAsNonRoot: pointer.Bool(true),
-		RunAsGroup:   pointer.Int64Ptr(kvm),
-		FSGroup:      pointer.Int64Ptr(kvm),
+// getExportReplicaCount returns how many exporter pods should be running for every claimed
+// VirtualMachineExport: the cluster-configured ExportConfiguration.Replicas, or 1 if unset.
+func (ctrl *VMExportController) getExportReplicaCount() int32 {
+	if replicas := ctrl.ClusterConfig.GetVMExportReplicas(); replicas != nil {
+		return *replicas
 	}
-	for i, pvc := range pvcs {
-		var mountPoint string
-		if types.IsPVCBlock(pvc.Spec.VolumeMode) {
-			mountPoint = fmt.Sprintf("%s/%s", blockVolumeMountPath, pvc.Name)
-			podManifest.Spec.Containers[0].VolumeDevices = append(podManifest.Spec.Containers[0].VolumeDevices, corev1.VolumeDevice{
-				Name:       pvc.Name,
-				DevicePath: mountPoint,
-			})
-		} else {
-			mountPoint = fmt.Sprintf("%s/%s", fileSystemMountPath, pvc.Name)
-			podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
-				Name:      pvc.Name,
-				ReadOnly:  true,
-				MountPath: mountPoint,
-			})
+	return 1
+}
+
+// getReplicaExporterPodName returns the name of the index'th additional exporter pod replica for
+// vmExport, where index starts at 1 since index 0 is the primary pod managed by createExporterPod.
+func (ctrl *VMExportController) getReplicaExporterPodName(vmExport *exportv1.VirtualMachineExport, index int32) string {
+	return naming.GetName(exportPrefix, fmt.Sprintf("%s-%d", vmExport.Name, index), validation.DNS1035LabelMaxLength)
+}
+
+// manageExporterPodReplicas creates whatever additional exporter pods are needed to reach
+// getExportReplicaCount, beyond the primary one managed by createExporterPod. Replicas use the
+// same manifest as the primary pod and share its exportServiceLabel, so the export Service
+// load-balances traffic across all of them; they are not tracked in VirtualMachineExportStatus.
+func (ctrl *VMExportController) manageExporterPodReplicas(vmExport *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim, vmiSpec *virtv1.VirtualMachineInstanceSpec) error {
+	for i := int32(1); i < ctrl.getExportReplicaCount(); i++ {
+		name := ctrl.getReplicaExporterPodName(vmExport, i)
+		key := controller.NamespacedKey(vmExport.Namespace, name)
+		if _, exists, err := ctrl.PodInformer.GetStore().GetByKey(key); err != nil {
+			return err
+		} else if !exists {
+			manifest, err := ctrl.createExporterPodManifest(vmExport, pvcs, vmiSpec)
+			if err != nil {
+				return err
+			}
+			manifest.Name = name
+			log.Log.V(3).Infof("Creating new exporter pod replica %s/%s", manifest.Namespace, manifest.Name)
+			if _, err := ctrl.Client.CoreV1().Pods(vmExport.Namespace).Create(context.Background(), manifest, metav1.CreateOptions{}); err != nil {
+				return err
+			}
 		}
-		podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
-			Name: pvc.Name,
-			VolumeSource: corev1.VolumeSource{
-				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: pvc.Name,
-				},
-			},
-		})
-		ctrl.addVolumeEnvironmentVariables(&podManifest.Spec.Containers[0], pvc, i, mountPoint)
+	}
+	return nil
+}
+
+// deleteExporterPodReplicas tears down every exporter pod replica created by
+// manageExporterPodReplicas for vmExport, mirroring the lifecycle of the primary exporter pod.
+func (ctrl *VMExportController) deleteExporterPodReplicas(vmExport *exportv1.VirtualMachineExport) error {
+	for i := int32(1); i < ctrl.getExportReplicaCount(); i++ {
+		name := ctrl.getReplicaExporterPodName(vmExport, i)
+		key := controller.NamespacedKey(vmExport.Namespace, name)
+		if _, exists, err := ctrl.PodInformer.GetStore().GetByKey(key); err != nil {
+			return err
+		} else if exists {
+			if err := ctrl.Client.CoreV1().Pods(vmExport.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getPerVolumePodName returns the name of the dedicated exporter pod for pvc, when spec.perVolumePods
+// splits it out of the primary exporter pod. It is also used as the name of that pod's Service.
+func (ctrl *VMExportController) getPerVolumePodName(vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim) string {
+	return naming.GetName(exportPrefix, fmt.Sprintf("%s-%s", vmExport.Name, pvc.Name), validation.DNS1035LabelMaxLength)
+}
+
+// managePerVolumeExporterPods creates a dedicated exporter pod and Service for every volume in
+// volumes, so each can be converted and downloaded in parallel, independently of the primary
+// exporter pod. See isPerVolumePods.
+func (ctrl *VMExportController) managePerVolumeExporterPods(vmExport *exportv1.VirtualMachineExport, volumes []*corev1.PersistentVolumeClaim, vmiSpec *virtv1.VirtualMachineInstanceSpec) error {
+	for _, pvc := range volumes {
+		if err := ctrl.managePerVolumeExporterPod(vmExport, pvc, vmiSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctrl *VMExportController) managePerVolumeExporterPod(vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim, vmiSpec *virtv1.VirtualMachineInstanceSpec) error {
+	name := ctrl.getPerVolumePodName(vmExport, pvc)
+	podKey := controller.NamespacedKey(vmExport.Namespace, name)
+	if _, exists, err := ctrl.PodInformer.GetStore().GetByKey(podKey); err != nil {
+		return err
+	} else if !exists {
+		manifest, err := ctrl.createExporterPodManifest(vmExport, []*corev1.PersistentVolumeClaim{pvc}, vmiSpec)
+		if err != nil {
+			return err
+		}
+		manifest.Name = name
+		manifest.Labels[exportVolumeLabel] = pvc.Name
+		log.Log.V(3).Infof("Creating new per-volume exporter pod %s/%s", manifest.Namespace, manifest.Name)
+		if _, err := ctrl.Client.CoreV1().Pods(vmExport.Namespace).Create(context.Background(), manifest, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	serviceKey := controller.NamespacedKey(vmExport.Namespace, name)
+	if _, exists, err := ctrl.ServiceInformer.GetStore().GetByKey(serviceKey); err != nil {
+		return err
+	} else if !exists {
+		service := ctrl.createServiceManifest(vmExport)
+		service.Name = name
+		service.Spec.Selector[exportVolumeLabel] = pvc.Name
+		log.Log.V(3).Infof("Creating new per-volume exporter service %s/%s", service.Namespace, service.Name)
+		if _, err := ctrl.Client.CoreV1().Services(vmExport.Namespace).Create(context.Background(), service, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePerVolumeExporterPods tears down the dedicated exporter pod and Service, created by
+// managePerVolumeExporterPods, for every volume in volumes.
+func (ctrl *VMExportController) deletePerVolumeExporterPods(vmExport *exportv1.VirtualMachineExport, volumes []*corev1.PersistentVolumeClaim) error {
+	for _, pvc := range volumes {
+		name := ctrl.getPerVolumePodName(vmExport, pvc)
+		key := controller.NamespacedKey(vmExport.Namespace, name)
+		if _, exists, err := ctrl.PodInformer.GetStore().GetByKey(key); err != nil {
+			return err
+		} else if exists {
+			if err := ctrl.Client.CoreV1().Pods(vmExport.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		if _, exists, err := ctrl.ServiceInformer.GetStore().GetByKey(key); err != nil {
+			return err
+		} else if exists {
+			if err := ctrl.Client.CoreV1().Services(vmExport.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getDeadline returns how long the exporter pod for vmExport is allowed to serve the export before it
+// stops itself. The per-export Spec.Deadline takes precedence, then the cluster default configured in
+// KubeVirtConfiguration, then the hardcoded default.
+func (ctrl *VMExportController) getDeadline(vmExport *exportv1.VirtualMachineExport) time.Duration {
+	if vmExport.Spec.Deadline != nil {
+		return vmExport.Spec.Deadline.Duration
+	}
+	if clusterDeadline := ctrl.ClusterConfig.GetVMExportDeadline(); clusterDeadline != nil {
+		return clusterDeadline.Duration
+	}
+	return deadline
+}
+
+// getLinkExpiration returns when the exporter pod backing vmExport is expected to stop serving
+// requests: whichever comes first of its DEADLINE (see getDeadline) and its serving certificate,
+// generated with a certExpiry lifetime, expiring. It returns nil if exporterPod hasn't been
+// created yet, since neither timeout has started counting down.
+func (ctrl *VMExportController) getLinkExpiration(vmExport *exportv1.VirtualMachineExport, exporterPod *corev1.Pod) *metav1.Time {
+	if exporterPod == nil {
+		return nil
+	}
+	expiration := exporterPod.CreationTimestamp.Add(ctrl.getDeadline(vmExport))
+	if certExpiration := exporterPod.CreationTimestamp.Add(certExpiry); certExpiration.Before(expiration) {
+		expiration = certExpiration
+	}
+	return &metav1.Time{Time: expiration}
+}
+
+// getRequeueInterval returns how long to wait before polling again while a VirtualMachineExport's
+// source is not yet available. The cluster default configured in KubeVirtConfiguration takes
+// precedence, then the hardcoded default.
+func (ctrl *VMExportController) getRequeueInterval() time.Duration {
+	if clusterRequeueInterval := ctrl.ClusterConfig.GetVMExportRequeueInterval(); clusterRequeueInterval != nil {
+		return clusterRequeueInterval.Duration
+	}
+	return requeueTime
+}
+
+// requeueInterval returns sourceVolumes.requeueAfter if the source set one, otherwise
+// getRequeueInterval, so sources that know they're in for a longer wait (e.g. a snapshot restore)
+// can poll less aggressively than the default.
+func (ctrl *VMExportController) requeueInterval(sourceVolumes *sourceVolumes) time.Duration {
+	if sourceVolumes.requeueAfter > 0 {
+		return sourceVolumes.requeueAfter
+	}
+	return ctrl.getRequeueInterval()
+}
+
+// getPodResourceRequirements returns the resource requirements to apply to vmExport's exporter
+// pod container. The per-export Spec.PodResourceRequirements takes precedence, then the cluster
+// default configured in KubeVirtConfiguration. It returns nil, leaving the container's resources
+// unset, if neither is configured.
+func (ctrl *VMExportController) getPodResourceRequirements(vmExport *exportv1.VirtualMachineExport) *corev1.ResourceRequirements {
+	if vmExport.Spec.PodResourceRequirements != nil {
+		return vmExport.Spec.PodResourceRequirements
+	}
+	return ctrl.ClusterConfig.GetVMExportPodResourceRequirements()
+}
+
+// getPodFSGroup returns the group ID the exporter pod's volumes should be made accessible to: the
+// cluster-configured ExportConfiguration.PodFSGroup, or the kvm group, preserving the behavior
+// before that field was introduced.
+func (ctrl *VMExportController) getPodFSGroup() *int64 {
+	if fsGroup := ctrl.ClusterConfig.GetVMExportPodFSGroup(); fsGroup != nil {
+		return fsGroup
+	}
+	return pointer.Int64Ptr(kvm)
+}
+
+// getZstdCompressionLevel returns the compression level the exporter pod should use for the zstd
+// and tar.zst formats, defaulting to 3, zstd's own default, if the cluster has not configured one.
+func (ctrl *VMExportController) getZstdCompressionLevel() int32 {
+	if level := ctrl.ClusterConfig.GetVMExportZstdCompressionLevel(); level != nil {
+		return *level
+	}
+	return defaultZstdCompressionLevel
+}
+
+// getGzipCompressionLevel returns the compression level the exporter pod should use for the gz
+// and tar.gz formats, defaulting to compress/gzip's own default if the cluster has not configured
+// one.
+func (ctrl *VMExportController) getGzipCompressionLevel() int32 {
+	if level := ctrl.ClusterConfig.GetVMExportGzipCompressionLevel(); level != nil {
+		return *level
+	}
+	return defaultGzipCompressionLevel
+}
+
+// getShutdownGracePeriod returns how long the exporter pod should wait for in-flight downloads
+// to finish once it starts shutting down, whether because its deadline was reached or because
+// the VirtualMachineExport was deleted, before forcibly closing remaining connections. It
+// defaults to defaultShutdownGracePeriod if the cluster has not configured one.
+func (ctrl *VMExportController) getShutdownGracePeriod() time.Duration {
+	if gracePeriod := ctrl.ClusterConfig.GetVMExportShutdownGracePeriod(); gracePeriod != nil {
+		return gracePeriod.Duration
+	}
+	return defaultShutdownGracePeriod
+}
+
+// getPerConnectionBandwidthLimit returns the egress bytes/second limit the exporter pod should
+// enforce on each individual download connection, or 0 if the cluster has not configured one,
+// meaning individual connections are unlimited.
+func (ctrl *VMExportController) getPerConnectionBandwidthLimit() int64 {
+	if limit := ctrl.ClusterConfig.GetVMExportPerConnectionBandwidthLimit(); limit != nil {
+		return limit.Value()
+	}
+	return 0
+}
+
+// getTotalBandwidthLimit returns the egress bytes/second limit the exporter pod should enforce
+// across all of its connections combined, or 0 if the cluster has not configured one, meaning the
+// exporter pod's total bandwidth is unlimited.
+func (ctrl *VMExportController) getTotalBandwidthLimit() int64 {
+	if limit := ctrl.ClusterConfig.GetVMExportTotalBandwidthLimit(); limit != nil {
+		return limit.Value()
+	}
+	return 0
+}
+
+// getMaxConcurrentDownloads returns how many downloads the exporter pod should serve at once,
+// across every volume and format it exposes combined, or 0 if the cluster has not configured
+// one, meaning the number of concurrent downloads is unlimited.
+func (ctrl *VMExportController) getMaxConcurrentDownloads() int32 {
+	if limit := ctrl.ClusterConfig.GetVMExportMaxConcurrentDownloads(); limit != nil {
+		return *limit
+	}
+	return 0
+}
+
+// getPerClientIPRequestLimit returns how many requests per second the exporter pod should allow
+// a single client IP to make, or 0 if the cluster has not configured one, meaning per-client IP
+// request rate limiting is disabled.
+func (ctrl *VMExportController) getPerClientIPRequestLimit() int32 {
+	if limit := ctrl.ClusterConfig.GetVMExportPerClientIPRequestLimit(); limit != nil {
+		return *limit
+	}
+	return 0
+}
+
+// getPerClientIPRequestBurst returns how many requests a client IP may make in a single burst
+// before getPerClientIPRequestLimit applies, or 0 if the cluster has not configured one, meaning
+// the exporter pod should fall back to its own default burst size.
+func (ctrl *VMExportController) getPerClientIPRequestBurst() int32 {
+	if burst := ctrl.ClusterConfig.GetVMExportPerClientIPRequestBurst(); burst != nil {
+		return *burst
+	}
+	return 0
+}
+
+// getPrecomputeFormats returns the raw-volume export formats the exporter pod should compress
+// into scratch space right after startup, preferring vmExport's own spec.precomputeFormats over
+// the cluster-wide default, or nil if neither is set, meaning every download is compressed on
+// demand as before.
+func (ctrl *VMExportController) getPrecomputeFormats(vmExport *exportv1.VirtualMachineExport) []string {
+	if formats := vmExport.Spec.PrecomputeFormats; len(formats) > 0 {
+		return formats
+	}
+	return ctrl.ClusterConfig.GetVMExportPrecomputeFormats()
+}
+
+// getEnableNBD returns whether the exporter pod should additionally expose each of its volumes
+// over NBD, defaulting to false if the cluster has not configured it.
+func (ctrl *VMExportController) getEnableNBD() bool {
+	if enable := ctrl.ClusterConfig.GetVMExportEnableNBD(); enable != nil {
+		return *enable
+	}
+	return false
+}
+
+// getScratchSpaceSize returns the size of the emptyDir backing the exporter pod's scratch
+// directory. The per-export Spec.ScratchSpaceSize takes precedence, then the cluster default
+// configured in KubeVirtConfiguration, then defaultScratchSpaceSize.
+func (ctrl *VMExportController) getScratchSpaceSize(vmExport *exportv1.VirtualMachineExport) resource.Quantity {
+	if size := vmExport.Spec.ScratchSpaceSize; size != nil {
+		return *size
+	}
+	if size := ctrl.ClusterConfig.GetVMExportScratchSpaceSize(); size != nil {
+		return *size
+	}
+	return defaultScratchSpaceSize
+}
+
+// getMinTLSVersion returns the minimum TLS version the exporter pod should accept connections
+// with, as configured cluster-wide, or "" if the cluster has not configured one, meaning the
+// exporter pod falls back to its own default.
+func (ctrl *VMExportController) getMinTLSVersion() string {
+	if tlsConfiguration := ctrl.ClusterConfig.GetTLSConfiguration(); tlsConfiguration != nil {
+		return string(tlsConfiguration.MinTLSVersion)
+	}
+	return ""
+}
+
+// getTLSCiphers returns the TLS cipher suites the exporter pod should accept connections with,
+// as configured cluster-wide, or nil if none are configured, meaning Go's default cipher suite
+// selection is used.
+func (ctrl *VMExportController) getTLSCiphers() []string {
+	if tlsConfiguration := ctrl.ClusterConfig.GetTLSConfiguration(); tlsConfiguration != nil {
+		return tlsConfiguration.Ciphers
+	}
+	return nil
+}
+
+// addNodeAffinityFromPVCs constrains podManifest to nodes that can actually attach every
+// ReadWriteOnce PVC in pvcs, by copying the required node affinity of each PVC's bound
+// PersistentVolume onto the pod. Local and other topology-constrained volumes only exist on
+// specific nodes, so without this the scheduler can place the exporter pod on a node the volume
+// can't be attached to, which only surfaces as a failed attach after the fact.
+func (ctrl *VMExportController) addNodeAffinityFromPVCs(podManifest *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim) error {
+	for _, pvc := range pvcs {
+		if !isReadWriteOnce(pvc) || pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, exists, err := ctrl.getPersistentVolume(pvc.Spec.VolumeName)
+		if err != nil {
+			return err
+		}
+		if !exists || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+		addRequiredNodeAffinity(podManifest, pv.Spec.NodeAffinity.Required)
+	}
+	return nil
+}
+
+func isReadWriteOnce(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, accessMode := range pvc.Spec.AccessModes {
+		if accessMode == corev1.ReadWriteOnce {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctrl *VMExportController) getPersistentVolume(name string) (*corev1.PersistentVolume, bool, error) {
+	obj, exists, err := ctrl.PVInformer.GetStore().GetByKey(name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return obj.(*corev1.PersistentVolume), true, nil
+}
+
+// addRequiredNodeAffinity merges selector into pod's existing required node affinity, if any.
+// Since NodeSelectorTerms are ORed and the MatchExpressions within a term are ANDed, every
+// existing term is combined with every term in selector so that both constraints keep holding.
+func addRequiredNodeAffinity(pod *corev1.Pod, selector *corev1.NodeSelector) {
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: selector.NodeSelectorTerms,
+		}
+		return
+	}
+
+	var terms []corev1.NodeSelectorTerm
+	for _, existingTerm := range required.NodeSelectorTerms {
+		for _, newTerm := range selector.NodeSelectorTerms {
+			terms = append(terms, corev1.NodeSelectorTerm{
+				MatchExpressions: append(append([]corev1.NodeSelectorRequirement{}, existingTerm.MatchExpressions...), newTerm.MatchExpressions...),
+				MatchFields:      append(append([]corev1.NodeSelectorRequirement{}, existingTerm.MatchFields...), newTerm.MatchFields...),
+			})
+		}
+	}
+	required.NodeSelectorTerms = terms
+}
+
+func (ctrl *VMExportController) createExporterPodManifest(vmExport *exportv1.VirtualMachineExport, pvcs []*corev1.PersistentVolumeClaim, vmiSpec *virtv1.VirtualMachineInstanceSpec) (*corev1.Pod, error) {
+	podManifest := ctrl.TemplateService.RenderExporterManifest(vmExport, exportPrefix)
+	podManifest.ObjectMeta.Labels = map[string]string{exportServiceLabel: vmExport.Name, exportVolumeLabel: ""}
+	podManifest.Spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot:   pointer.Bool(true),
+		RunAsUser:      ctrl.ClusterConfig.GetVMExportPodRunAsUser(),
+		RunAsGroup:     pointer.Int64Ptr(kvm),
+		FSGroup:        ctrl.getPodFSGroup(),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	podManifest.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		RunAsNonRoot:             pointer.Bool(true),
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		ReadOnlyRootFilesystem:   pointer.Bool(true),
+	}
+
+	scratchSpaceSize := ctrl.getScratchSpaceSize(vmExport)
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: scratchSpaceVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				SizeLimit: &scratchSpaceSize,
+			},
+		},
+	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      scratchSpaceVolumeName,
+		MountPath: scratchSpaceMountPath,
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "TMPDIR",
+		Value: scratchSpaceMountPath,
+	})
+	if resourceRequirements := ctrl.getPodResourceRequirements(vmExport); resourceRequirements != nil {
+		podManifest.Spec.Containers[0].Resources = *resourceRequirements
+	}
+	if err := ctrl.addNodeAffinityFromPVCs(podManifest, pvcs); err != nil {
+		return nil, err
+	}
+	for i, pvc := range pvcs {
+		var mountPoint string
+		if types.IsPVCBlock(pvc.Spec.VolumeMode) {
+			mountPoint = fmt.Sprintf("%s/%s", blockVolumeMountPath, pvc.Name)
+			podManifest.Spec.Containers[0].VolumeDevices = append(podManifest.Spec.Containers[0].VolumeDevices, corev1.VolumeDevice{
+				Name:       pvc.Name,
+				DevicePath: mountPoint,
+			})
+		} else {
+			mountPoint = fmt.Sprintf("%s/%s", fileSystemMountPath, pvc.Name)
+			podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+				Name:      pvc.Name,
+				ReadOnly:  true,
+				MountPath: mountPoint,
+			})
+		}
+		podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+			Name: pvc.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvc.Name,
+				},
+			},
+		})
+		ctrl.addVolumeEnvironmentVariables(&podManifest.Spec.Containers[0], vmExport, pvc, i, mountPoint)
 	}
 
 	// Add token and certs ENV variables
 	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
-		Name:  "CERT_FILE",
-		Value: "/cert/tls.crt",
+		Name:  "CERT_FILE",
+		Value: "/cert/tls.crt",
+	}, corev1.EnvVar{
+		Name:  "KEY_FILE",
+		Value: "/cert/tls.key",
+	}, corev1.EnvVar{
+		Name:  "TOKEN_FILE",
+		Value: "/token/token",
+	}, corev1.EnvVar{
+		Name:  "DEADLINE",
+		Value: currentTime().Add(ctrl.getDeadline(vmExport)).Format(time.RFC3339),
+	}, corev1.EnvVar{
+		Name:  "ZSTD_COMPRESSION_LEVEL",
+		Value: strconv.Itoa(int(ctrl.getZstdCompressionLevel())),
+	}, corev1.EnvVar{
+		Name:  "GZIP_COMPRESSION_LEVEL",
+		Value: strconv.Itoa(int(ctrl.getGzipCompressionLevel())),
+	}, corev1.EnvVar{
+		Name:  "SHUTDOWN_GRACE_PERIOD",
+		Value: ctrl.getShutdownGracePeriod().String(),
+	})
+
+	// Give the exporter's own drain phase (see SHUTDOWN_GRACE_PERIOD above) room to finish before
+	// kubelet sends SIGKILL, so a download in progress when the VirtualMachineExport is deleted
+	// still has a chance to complete.
+	podManifest.Spec.TerminationGracePeriodSeconds = pointer.Int64(int64((ctrl.getShutdownGracePeriod() + shutdownGracePeriodBuffer).Seconds()))
+
+	if limit := ctrl.getPerConnectionBandwidthLimit(); limit > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "PER_CONNECTION_BANDWIDTH_LIMIT",
+			Value: strconv.FormatInt(limit, 10),
+		})
+	}
+	if limit := ctrl.getTotalBandwidthLimit(); limit > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "TOTAL_BANDWIDTH_LIMIT",
+			Value: strconv.FormatInt(limit, 10),
+		})
+	}
+	if limit := ctrl.getMaxConcurrentDownloads(); limit > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "MAX_CONCURRENT_DOWNLOADS",
+			Value: strconv.Itoa(int(limit)),
+		})
+	}
+	if limit := ctrl.getPerClientIPRequestLimit(); limit > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "PER_CLIENT_IP_REQUEST_LIMIT",
+			Value: strconv.Itoa(int(limit)),
+		})
+		if burst := ctrl.getPerClientIPRequestBurst(); burst > 0 {
+			podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  "PER_CLIENT_IP_REQUEST_BURST",
+				Value: strconv.Itoa(int(burst)),
+			})
+		}
+	}
+	if formats := ctrl.getPrecomputeFormats(vmExport); len(formats) > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "PRECOMPUTE_FORMATS",
+			Value: strings.Join(formats, ","),
+		})
+	}
+	// The NBD ports (nbdBasePort, plus one per volume) are not among the exporter Service's ports,
+	// and the exporter NetworkPolicy admits ingress only on port 8443, so where NetworkPolicy is
+	// enforced NBD connections to the exporter pod are not admitted.
+	if ctrl.getEnableNBD() {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "NBD_LISTEN_ADDR",
+			Value: fmt.Sprintf("0.0.0.0:%d", nbdBasePort),
+		})
+	}
+	if minVersion := ctrl.getMinTLSVersion(); minVersion != "" {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "MIN_TLS_VERSION",
+			Value: minVersion,
+		})
+	}
+	if ciphers := ctrl.getTLSCiphers(); len(ciphers) > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "TLS_CIPHERS",
+			Value: strings.Join(ciphers, ","),
+		})
+	}
+	if hooks := vmExport.Spec.Hooks; hooks != nil {
+		if len(hooks.PreServeCommand) > 0 {
+			podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  "PRE_SERVE_COMMAND",
+				Value: strings.Join(hooks.PreServeCommand, ","),
+			})
+		}
+		if len(hooks.PostServeCommand) > 0 {
+			podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  "POST_SERVE_COMMAND",
+				Value: strings.Join(hooks.PostServeCommand, ","),
+			})
+		}
+	}
+
+	secretName := fmt.Sprintf("secret-%s", rand.String(10))
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: certificates,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}, corev1.Volume{
+		Name: ctrl.getTokenSecretName(vmExport),
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: ctrl.getTokenSecretName(vmExport),
+			},
+		},
+	})
+
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      certificates,
+		MountPath: "/cert",
+	}, corev1.VolumeMount{
+		Name:      ctrl.getTokenSecretName(vmExport),
+		MountPath: "/token",
+	})
+
+	ctrl.addAdditionalTokenVolumes(podManifest, vmExport)
+	ctrl.addScopedTokenVolumes(podManifest, vmExport)
+	ctrl.addEncryptionKeyVolume(podManifest, vmExport)
+	ctrl.addClientCAVolume(podManifest, vmExport)
+	ctrl.addS3UploadVolume(podManifest, vmExport)
+	ctrl.addRegistryUploadVolume(podManifest, vmExport)
+	ctrl.addClusterUploadVolume(podManifest, vmExport)
+	if err := ctrl.addOvaVolume(podManifest, vmExport, vmiSpec, pvcs); err != nil {
+		return nil, err
+	}
+	if err := ctrl.addManifestVolume(podManifest, vmExport); err != nil {
+		return nil, err
+	}
+	ctrl.addAllVolume(podManifest)
+
+	return podManifest, nil
+}
+
+// addS3UploadVolume mounts the secret referenced by vmExport's spec.s3Upload.credentialsSecretRef
+// into the exporter pod and sets the S3_* environment variables that tell it where to upload to
+// and how to authenticate, if spec.s3Upload is set.
+func (ctrl *VMExportController) addS3UploadVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	s3Upload := vmExport.Spec.S3Upload
+	if s3Upload == nil {
+		return
+	}
+
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: s3Credentials,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: s3Upload.CredentialsSecretRef,
+			},
+		},
+	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      s3Credentials,
+		MountPath: "/s3-credentials",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "S3_ENDPOINT",
+		Value: s3Upload.Endpoint,
+	}, corev1.EnvVar{
+		Name:  "S3_BUCKET",
+		Value: s3Upload.Bucket,
+	}, corev1.EnvVar{
+		Name:  "S3_REGION",
+		Value: s3Upload.Region,
+	}, corev1.EnvVar{
+		Name:  "S3_ACCESS_KEY_ID_FILE",
+		Value: fmt.Sprintf("/s3-credentials/%s", s3CredentialsAccessKeyIDKey),
+	}, corev1.EnvVar{
+		Name:  "S3_SECRET_ACCESS_KEY_FILE",
+		Value: fmt.Sprintf("/s3-credentials/%s", s3CredentialsSecretAccessKeyKey),
+	})
+}
+
+// addEncryptionKeyVolume mounts the secret referenced by vmExport's spec.encryptionSecretRef into
+// the exporter pod and sets the ENCRYPTION_KEY_FILE environment variable that tells it to encrypt
+// every artifact it serves with that key, if spec.encryptionSecretRef is set.
+func (ctrl *VMExportController) addEncryptionKeyVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	if vmExport.Spec.EncryptionSecretRef == nil {
+		return
+	}
+
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: encryptionKeyVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: *vmExport.Spec.EncryptionSecretRef,
+			},
+		},
+	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      encryptionKeyVolume,
+		MountPath: "/encryption-key",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "ENCRYPTION_KEY_FILE",
+		Value: fmt.Sprintf("/encryption-key/%s", encryptionKeyDataKey),
+	})
+}
+
+// addClientCAVolume mounts the secret referenced by vmExport's spec.clientCertificateAuthorityRef
+// into the exporter pod and sets the CLIENT_CA_FILE environment variable, telling it to also
+// accept client certificates signed by that CA as an alternative to the download token.
+func (ctrl *VMExportController) addClientCAVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	if vmExport.Spec.ClientCertificateAuthorityRef == nil {
+		return
+	}
+
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: clientCAVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: *vmExport.Spec.ClientCertificateAuthorityRef,
+			},
+		},
+	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      clientCAVolume,
+		MountPath: "/client-ca",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "CLIENT_CA_FILE",
+		Value: fmt.Sprintf("/client-ca/%s", clientCADataKey),
+	})
+}
+
+// addRegistryUploadVolume mounts the secret referenced by vmExport's
+// spec.registryUpload.credentialsSecretRef into the exporter pod and sets the REGISTRY_*
+// environment variables that tell it where to push to and how to authenticate, if
+// spec.registryUpload is set.
+func (ctrl *VMExportController) addRegistryUploadVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	registryUpload := vmExport.Spec.RegistryUpload
+	if registryUpload == nil {
+		return
+	}
+
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: registryCredentials,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: registryUpload.CredentialsSecretRef,
+			},
+		},
+	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      registryCredentials,
+		MountPath: "/registry-credentials",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "REGISTRY_REPOSITORY",
+		Value: registryUpload.Repository,
 	}, corev1.EnvVar{
-		Name:  "KEY_FILE",
-		Value: "/cert/tls.key",
+		Name:  "REGISTRY_TAG",
+		Value: registryUpload.Tag,
 	}, corev1.EnvVar{
-		Name:  "TOKEN_FILE",
-		Value: "/token/token",
+		Name:  "REGISTRY_USERNAME_FILE",
+		Value: fmt.Sprintf("/registry-credentials/%s", registryCredentialsUsernameKey),
 	}, corev1.EnvVar{
-		Name:  "DEADLINE",
-		Value: currentTime().Add(deadline).Format(time.RFC3339),
+		Name:  "REGISTRY_PASSWORD_FILE",
+		Value: fmt.Sprintf("/registry-credentials/%s", registryCredentialsPasswordKey),
 	})
+}
+
+// addClusterUploadVolume mounts the secret referenced by vmExport's
+// spec.clusterUpload.kubeconfigSecretRef into the exporter pod and sets the CLUSTER_UPLOAD_*
+// environment variables that tell it which target cluster and namespace to create DataVolumes
+// in and how to authenticate to it, if spec.clusterUpload is set.
+func (ctrl *VMExportController) addClusterUploadVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	clusterUpload := vmExport.Spec.ClusterUpload
+	if clusterUpload == nil {
+		return
+	}
 
-	secretName := fmt.Sprintf("secret-%s", rand.String(10))
 	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
-		Name: certificates,
+		Name: clusterUploadKubeconfig,
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
-				SecretName: secretName,
+				SecretName: clusterUpload.KubeconfigSecretRef,
 			},
 		},
-	}, corev1.Volume{
-		Name: vmExport.Spec.TokenSecretRef,
+	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      clusterUploadKubeconfig,
+		MountPath: "/cluster-upload-kubeconfig",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "CLUSTER_UPLOAD_NAMESPACE",
+		Value: clusterUpload.Namespace,
+	}, corev1.EnvVar{
+		Name:  "CLUSTER_UPLOAD_PROXY_URL",
+		Value: clusterUpload.CDIUploadProxyURL,
+	}, corev1.EnvVar{
+		Name:  "CLUSTER_UPLOAD_KUBECONFIG_FILE",
+		Value: fmt.Sprintf("/cluster-upload-kubeconfig/%s", clusterUploadKubeconfigKey),
+	})
+}
+
+// getOrCreateOvaSecret ensures a secret holding the OVF descriptor generated from vmiSpec and
+// pvcs exists, owned by vmExport, and returns its name. It is only called for VirtualMachine
+// sources, since generating an OVF descriptor requires a VirtualMachineInstanceSpec.
+func (ctrl *VMExportController) getOrCreateOvaSecret(vmExport *exportv1.VirtualMachineExport, vmiSpec *virtv1.VirtualMachineInstanceSpec, pvcs []*corev1.PersistentVolumeClaim) (string, error) {
+	secretName := naming.GetName(ovaDescriptor, vmExport.Name, validation.DNS1035LabelMaxLength)
+
+	descriptor, err := buildOvfDescriptor(vmExport.Spec.Source.Name, vmiSpec, pvcs)
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: vmExport.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vmExport, schema.GroupVersionKind{
+					Group:   exportGVK.Group,
+					Version: exportGVK.Version,
+					Kind:    exportGVK.Kind,
+				}),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			ovaDescriptorKey: descriptor,
+		},
+	}
+
+	if _, err := ctrl.Client.CoreV1().Secrets(vmExport.Namespace).Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return "", err
+		}
+	} else {
+		ctrl.Recorder.Eventf(vmExport, corev1.EventTypeNormal, secretCreatedEvent, "Created exporter pod OVA descriptor secret")
+	}
+	return secretName, nil
+}
+
+// addOvaVolume mounts a generated OVF descriptor into the exporter pod and sets the OVA_URI,
+// OVA_DESCRIPTOR_FILE and per-volume VOLUME%d_EXPORT_OVA_DISK_NAME environment variables that
+// tell it to bundle an OVA containing every volume's disk and the descriptor. vmiSpec is nil for
+// every source type other than VirtualMachine, since only a VirtualMachineInstanceSpec can be
+// described by an OVF descriptor; in that case this is a no-op.
+func (ctrl *VMExportController) addOvaVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport, vmiSpec *virtv1.VirtualMachineInstanceSpec, pvcs []*corev1.PersistentVolumeClaim) error {
+	if vmiSpec == nil {
+		return nil
+	}
+
+	secretName, err := ctrl.getOrCreateOvaSecret(vmExport, vmiSpec, pvcs)
+	if err != nil {
+		return err
+	}
+
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: ovaDescriptor,
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
-				SecretName: vmExport.Spec.TokenSecretRef,
+				SecretName: secretName,
 			},
 		},
 	})
+	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      ovaDescriptor,
+		MountPath: "/ova-descriptor",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "OVA_URI",
+		Value: ovaURLPath,
+	}, corev1.EnvVar{
+		Name:  "OVA_DESCRIPTOR_FILE",
+		Value: fmt.Sprintf("/ova-descriptor/%s", ovaDescriptorKey),
+	})
+	for i, pvc := range pvcs {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_OVA_DISK_NAME", i),
+			Value: ovaDiskName(pvc),
+		})
+	}
+	return nil
+}
+
+// getOrCreateManifestSecret ensures a secret holding the manifest generated from vm, and, if vm
+// references an instancetype or preference, a second manifest with its spec expanded, exists,
+// owned by vmExport, and returns its name.
+func (ctrl *VMExportController) getOrCreateManifestSecret(vmExport *exportv1.VirtualMachineExport, vm *virtv1.VirtualMachine) (string, error) {
+	secretName := naming.GetName(manifestSecretVolume, vmExport.Name, validation.DNS1035LabelMaxLength)
+
+	caCert, err := ctrl.internalExportCa()
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := buildManifest(vm, caCert)
+	if err != nil {
+		return "", err
+	}
+	secretData := map[string][]byte{
+		manifestKey: manifest,
+	}
+
+	if vm.Spec.Instancetype != nil || vm.Spec.Preference != nil {
+		expandedManifest, err := buildExpandedManifest(vm, caCert, ctrl.InstancetypeMethods)
+		if err != nil {
+			return "", err
+		}
+		secretData[expandedManifestKey] = expandedManifest
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: vmExport.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vmExport, schema.GroupVersionKind{
+					Group:   exportGVK.Group,
+					Version: exportGVK.Version,
+					Kind:    exportGVK.Kind,
+				}),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: secretData,
+	}
+
+	if _, err := ctrl.Client.CoreV1().Secrets(vmExport.Namespace).Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return "", err
+		}
+	} else {
+		ctrl.Recorder.Eventf(vmExport, corev1.EventTypeNormal, secretCreatedEvent, "Created exporter pod manifest secret")
+	}
+	return secretName, nil
+}
+
+// addManifestVolume mounts a manifest generated from the source VM -- the VM itself, including
+// its DataVolumeTemplates, plus a ConfigMap containing the export CA bundle -- into the exporter
+// pod and sets the MANIFEST_URI and MANIFEST_FILE environment variables that tell it to serve it.
+// If the VM references an instancetype or preference, it additionally generates a second manifest
+// with those expanded into the VM's spec, and sets EXPANDED_MANIFEST_URI and
+// EXPANDED_MANIFEST_FILE to have it served alongside the first. It is a no-op for every source
+// type other than VirtualMachine, since only a VirtualMachine can be described this way.
+func (ctrl *VMExportController) addManifestVolume(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) error {
+	if !ctrl.isSourceVM(&vmExport.Spec) {
+		return nil
+	}
+	vm, exists, err := ctrl.getVm(vmExport.Namespace, vmExport.Spec.Source.Name)
+	if err != nil || !exists {
+		return err
+	}
 
+	secretName, err := ctrl.getOrCreateManifestSecret(vmExport, vm)
+	if err != nil {
+		return err
+	}
+
+	podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+		Name: manifestSecretVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	})
 	podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
-		Name:      certificates,
-		MountPath: "/cert",
-	}, corev1.VolumeMount{
-		Name:      vmExport.Spec.TokenSecretRef,
-		MountPath: "/token",
+		Name:      manifestSecretVolume,
+		MountPath: "/manifest",
+	})
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "MANIFEST_URI",
+		Value: manifestURLPath,
+	}, corev1.EnvVar{
+		Name:  "MANIFEST_FILE",
+		Value: fmt.Sprintf("/manifest/%s", manifestKey),
+	})
+	if vm.Spec.Instancetype != nil || vm.Spec.Preference != nil {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "EXPANDED_MANIFEST_URI",
+			Value: expandedManifestURLPath,
+		}, corev1.EnvVar{
+			Name:  "EXPANDED_MANIFEST_FILE",
+			Value: fmt.Sprintf("/manifest/%s", expandedManifestKey),
+		})
+	}
+	return nil
+}
+
+// addAllVolume sets the ALL_URI environment variable that tells the exporter pod to bundle every
+// volume's raw disk image, plus the generated manifests if present, into a single downloadable
+// tar archive. Unlike addOvaVolume and addManifestVolume, this doesn't mount anything new, since
+// it only combines files the pod already has mounted for its per-volume and manifest formats.
+func (ctrl *VMExportController) addAllVolume(podManifest *corev1.Pod) {
+	podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "ALL_URI",
+		Value: allURLPath,
 	})
-	return podManifest
 }
 
-func (ctrl *VMExportController) addVolumeEnvironmentVariables(exportContainer *corev1.Container, pvc *corev1.PersistentVolumeClaim, index int, mountPoint string) {
+// addAdditionalTokenVolumes mounts each secret in spec.additionalTokenSecretRefs into the
+// exporter pod and tells it, via the ADDITIONAL_TOKEN_FILES env var, to also accept the
+// tokens they contain. This lets a download started with a previous token keep working while
+// tokenSecretRef is rotated to a new secret.
+func (ctrl *VMExportController) addAdditionalTokenVolumes(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	var additionalTokenFiles []string
+	for i, secretRef := range vmExport.Spec.AdditionalTokenSecretRefs {
+		volumeName := fmt.Sprintf("additional-token-%d", i)
+		mountPath := fmt.Sprintf("/additional-token-%d", i)
+		podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretRef,
+				},
+			},
+		})
+		podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: mountPath,
+		})
+		additionalTokenFiles = append(additionalTokenFiles, fmt.Sprintf("%s/%s", mountPath, secretTokenKey))
+	}
+
+	if len(additionalTokenFiles) > 0 {
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "ADDITIONAL_TOKEN_FILES",
+			Value: strings.Join(additionalTokenFiles, ","),
+		})
+	}
+}
+
+// addScopedTokenVolumes mounts each secret in spec.scopedTokenSecretRefs into the exporter pod,
+// and tells it about the scope narrowing what that token grants access to via a
+// SCOPED_TOKEN%d_FILE/_VOLUMES/_MAX_READS set of env vars per entry, the same indexed-env-var
+// convention getVolumeInfo uses for per-volume settings. Unlike addAdditionalTokenVolumes, these
+// tokens are not full-access: the exporter only accepts them for the volumes (and up to the
+// number of reads) their VirtualMachineExportScopedTokenSecretRef specifies.
+func (ctrl *VMExportController) addScopedTokenVolumes(podManifest *corev1.Pod, vmExport *exportv1.VirtualMachineExport) {
+	for i, ref := range vmExport.Spec.ScopedTokenSecretRefs {
+		volumeName := fmt.Sprintf("scoped-token-%d", i)
+		mountPath := fmt.Sprintf("/scoped-token-%d", i)
+		podManifest.Spec.Volumes = append(podManifest.Spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ref.SecretRef,
+				},
+			},
+		})
+		podManifest.Spec.Containers[0].VolumeMounts = append(podManifest.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: mountPath,
+		})
+
+		envPrefix := fmt.Sprintf("SCOPED_TOKEN%d", i)
+		podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  envPrefix + "_FILE",
+			Value: fmt.Sprintf("%s/%s", mountPath, secretTokenKey),
+		})
+		if len(ref.Volumes) > 0 {
+			podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  envPrefix + "_VOLUMES",
+				Value: strings.Join(ref.Volumes, ","),
+			})
+		}
+		if ref.MaxReads != nil {
+			podManifest.Spec.Containers[0].Env = append(podManifest.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  envPrefix + "_MAX_READS",
+				Value: strconv.Itoa(int(*ref.MaxReads)),
+			})
+		}
+	}
+}
+
+// isFormatEnabled reports whether format is one of vmExport's spec.formats. If spec.formats is
+// empty, every format is enabled, which is the same behavior as before spec.formats existed.
+func (ctrl *VMExportController) isFormatEnabled(vmExport *exportv1.VirtualMachineExport, format exportv1.ExportVolumeFormat) bool {
+	if len(vmExport.Spec.Formats) == 0 {
+		return true
+	}
+	for _, f := range vmExport.Spec.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctrl *VMExportController) addVolumeEnvironmentVariables(exportContainer *corev1.Container, vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim, index int, mountPoint string) {
 	exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
 		Name:  fmt.Sprintf("VOLUME%d_EXPORT_PATH", index),
 		Value: mountPoint,
+	}, corev1.EnvVar{
+		Name:  fmt.Sprintf("VOLUME%d_EXPORT_NAME", index),
+		Value: pvc.Name,
 	})
-	if types.IsPVCBlock(pvc.Spec.VolumeMode) {
+	if types.IsPVCBlock(pvc.Spec.VolumeMode) || ctrl.isKubevirtContentType(pvc) {
+		ctrl.addRawCapableVolumeEnvironmentVariables(exportContainer, vmExport, pvc, index)
+	} else {
 		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
-			Name:  fmt.Sprintf("VOLUME%d_EXPORT_RAW_URI", index),
-			Value: rawURI(pvc),
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_ARCHIVE_URI", index),
+			Value: archiveURI(pvc),
 		}, corev1.EnvVar{
-			Name:  fmt.Sprintf("VOLUME%d_EXPORT_RAW_GZIP_URI", index),
-			Value: rawGzipURI(pvc),
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_ARCHIVE_ZSTD_URI", index),
+			Value: archiveZstdURI(pvc),
+		}, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_DIR_URI", index),
+			Value: dirURI(pvc),
 		})
-	} else {
-		if ctrl.isKubevirtContentType(pvc) {
+		if len(vmExport.Spec.ArchiveIncludePatterns) > 0 {
 			exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
-				Name:  fmt.Sprintf("VOLUME%d_EXPORT_RAW_URI", index),
-				Value: rawURI(pvc),
-			}, corev1.EnvVar{
-				Name:  fmt.Sprintf("VOLUME%d_EXPORT_RAW_GZIP_URI", index),
-				Value: rawGzipURI(pvc),
+				Name:  fmt.Sprintf("VOLUME%d_EXPORT_INCLUDE_PATTERNS", index),
+				Value: strings.Join(vmExport.Spec.ArchiveIncludePatterns, ","),
 			})
-		} else {
+		}
+		if len(vmExport.Spec.ArchiveExcludePatterns) > 0 {
 			exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
-				Name:  fmt.Sprintf("VOLUME%d_EXPORT_ARCHIVE_URI", index),
-				Value: archiveURI(pvc),
-			}, corev1.EnvVar{
-				Name:  fmt.Sprintf("VOLUME%d_EXPORT_DIR_URI", index),
-				Value: dirURI(pvc),
+				Name:  fmt.Sprintf("VOLUME%d_EXPORT_EXCLUDE_PATTERNS", index),
+				Value: strings.Join(vmExport.Spec.ArchiveExcludePatterns, ","),
 			})
 		}
 	}
 }
 
+// addRawCapableVolumeEnvironmentVariables sets the env vars for every format that can be produced
+// from a block volume or a filesystem volume holding a kubevirt disk image, skipping any format
+// that vmExport's spec.formats excludes.
+func (ctrl *VMExportController) addRawCapableVolumeEnvironmentVariables(exportContainer *corev1.Container, vmExport *exportv1.VirtualMachineExport, pvc *corev1.PersistentVolumeClaim, index int) {
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtRaw) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_RAW_URI", index),
+			Value: rawURI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtGz) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_RAW_GZIP_URI", index),
+			Value: rawGzipURI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtQcow2) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_QCOW2_URI", index),
+			Value: qcow2URI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtQcow2Gz) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_QCOW2_GZIP_URI", index),
+			Value: qcow2GzipURI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtZstd) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_ZSTD_URI", index),
+			Value: zstdURI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtVmdk) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_VMDK_URI", index),
+			Value: vmdkURI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtVhd) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_VHD_URI", index),
+			Value: vhdURI(pvc),
+		})
+	}
+	if ctrl.isFormatEnabled(vmExport, exportv1.KubeVirtVhdx) {
+		exportContainer.Env = append(exportContainer.Env, corev1.EnvVar{
+			Name:  fmt.Sprintf("VOLUME%d_EXPORT_VHDX_URI", index),
+			Value: vhdxURI(pvc),
+		})
+	}
+}
+
 func (ctrl *VMExportController) isKubevirtContentType(pvc *corev1.PersistentVolumeClaim) bool {
 	// Block volumes are assumed always KubevirtContentType
 	if types.IsPVCBlock(pvc.Spec.VolumeMode) {
@@ -763,6 +2493,11 @@ func (ctrl *VMExportController) isKubevirtContentType(pvc *corev1.PersistentVolu
 
 func (ctrl *VMExportController) updateCommonVMExportStatusFields(vmExport, vmExportCopy *exportv1.VirtualMachineExport, exporterPod *corev1.Pod, service *corev1.Service, sourceVolumes *sourceVolumes) error {
 	var err error
+	if vmExport.Spec.TokenSecretRef == nil {
+		// The finalizer gives us a chance to apply spec.deletionPolicy to the auto-generated
+		// token secret before it is garbage collected along with the VirtualMachineExport.
+		controller.AddFinalizer(vmExportCopy, vmExportFinalizer)
+	}
 	if vmExportCopy.Status == nil {
 		vmExportCopy.Status = &exportv1.VirtualMachineExportStatus{
 			Phase: exportv1.Pending,
@@ -774,30 +2509,71 @@ func (ctrl *VMExportController) updateCommonVMExportStatusFields(vmExport, vmExp
 	}
 
 	vmExportCopy.Status.ServiceName = service.Name
+	vmExportCopy.Status.TokenSecretRef = pointer.String(ctrl.getTokenSecretName(vmExport))
 	vmExportCopy.Status.Links = &exportv1.VirtualMachineExportLinks{}
 	if exporterPod == nil {
-		vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionFalse, inUseReason, sourceVolumes.availableMessage))
-		vmExportCopy.Status.Phase = exportv1.Pending
+		if isPaused(vmExport) {
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, pausedReason, "VirtualMachineExport is paused")
+			vmExportCopy.Status.Phase = exportv1.Paused
+		} else if sourceVolumes.quotaExceeded {
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, quotaExceededReason, "Namespace has reached its maximum number of concurrent exports")
+			vmExportCopy.Status.Phase = exportv1.Pending
+		} else if sourceVolumes.needsExporterPod() && !isClaimed(vmExport) {
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, awaitingClaimReason, "VirtualMachineExport is on-demand and has not yet been claimed")
+			vmExportCopy.Status.Phase = exportv1.Pending
+		} else if sourceVolumes.migrating {
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, migratingReason, sourceVolumes.availableMessage)
+			vmExportCopy.Status.Phase = exportv1.Pending
+		} else {
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, inUseReason, sourceVolumes.availableMessage)
+			vmExportCopy.Status.Phase = exportv1.Pending
+		}
 	} else {
-		if exporterPod.Status.Phase == corev1.PodRunning {
-			vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionTrue, podReadyReason, ""))
+		if exporterPod.Status.Phase == corev1.PodRunning && isPodReady(exporterPod) {
+			if vmExport.Status == nil || vmExport.Status.Phase != exportv1.Ready {
+				vmexport.TimeToReadyDuration.Observe(time.Since(vmExport.CreationTimestamp.Time).Seconds())
+			}
+			setReadyCondition(vmExportCopy, corev1.ConditionTrue, podReadyReason, "")
 			vmExportCopy.Status.Phase = exportv1.Ready
-			vmExportCopy.Status.Links.Internal, err = ctrl.getInteralLinks(sourceVolumes.volumes, exporterPod, service)
+			vmExportCopy.Status.Links.Internal, err = ctrl.getInteralLinks(sourceVolumes.volumes, exporterPod, service, vmExport)
 			if err != nil {
+				vmexport.LinkGenerationErrorsTotal.WithLabelValues(vmExport.Namespace).Inc()
 				return err
 			}
-			vmExportCopy.Status.Links.External, err = ctrl.getExternalLinks(sourceVolumes.volumes, exporterPod, vmExport)
+			externalLinks, err := ctrl.getExternalLinks(sourceVolumes.volumes, exporterPod, service, vmExport)
 			if err != nil {
+				vmexport.LinkGenerationErrorsTotal.WithLabelValues(vmExport.Namespace).Inc()
 				return err
 			}
+			vmExportCopy.Status.Links.ExternalLinks = externalLinks
+			if len(externalLinks) > 0 {
+				vmExportCopy.Status.Links.External = &externalLinks[0]
+				vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions,
+					newExternalLinkCondition(corev1.ConditionTrue, externalEndpointFoundReason, ""))
+			} else {
+				vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions,
+					newExternalLinkCondition(corev1.ConditionFalse, noExternalEndpointReason,
+						"No Ingress or Route was found to reach this export externally, and its Service is not a NodePort or LoadBalancer"))
+			}
+			vmExportCopy.Status.VolumeStatuses = ctrl.getVolumeProgress(vmExport, sourceVolumes.volumes, exporterPod, service)
+			ctrl.maybeExtendExporterDeadline(vmExport, exporterPod, service)
+			if vmExport.Spec.Hooks != nil {
+				vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions,
+					ctrl.getHooksCondition(vmExport, exporterPod, service))
+			}
 		} else if exporterPod.Status.Phase == corev1.PodSucceeded {
-			vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionFalse, podCompletedReason, ""))
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, podCompletedReason, "")
 			vmExportCopy.Status.Phase = exportv1.Terminated
 		} else if exporterPod.Status.Phase == corev1.PodPending {
-			vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionFalse, podPendingReason, ""))
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, podPendingReason, "")
+			vmExportCopy.Status.Phase = exportv1.Pending
+		} else if exporterPod.Status.Phase == corev1.PodRunning {
+			// The exporter pod is running but hasn't passed its readiness probe yet, so its
+			// download links aren't ready to be served.
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, podNotReadyReason, "")
 			vmExportCopy.Status.Phase = exportv1.Pending
 		} else {
-			vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(corev1.ConditionFalse, unknownReason, ""))
+			setReadyCondition(vmExportCopy, corev1.ConditionFalse, unknownReason, "")
 			vmExportCopy.Status.Phase = exportv1.Pending
 		}
 	}
@@ -844,6 +2620,84 @@ func newVolumesCreatedCondition(status corev1.ConditionStatus, reason, message s
 	}
 }
 
+func newExternalLinkCondition(status corev1.ConditionStatus, reason, message string) exportv1.Condition {
+	return exportv1.Condition{
+		Type:               exportv1.ConditionExternalLink,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: *currentTime(),
+	}
+}
+
+func newHooksReadyCondition(status corev1.ConditionStatus, reason, message string) exportv1.Condition {
+	return exportv1.Condition{
+		Type:               exportv1.ConditionHooksReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: *currentTime(),
+	}
+}
+
+// getHooksCondition reports the outcome of vmExport.Spec.Hooks' commands, fetched from the
+// exporter pod's internal hooks endpoint: HooksFailed if either hook has failed, HooksPending if
+// any configured hook hasn't reported an outcome yet (or the endpoint can't be reached), and HooksSucceeded once
+// every configured hook has reported success.
+func (ctrl *VMExportController) getHooksCondition(vmExport *exportv1.VirtualMachineExport, exporterPod *corev1.Pod, service *corev1.Service) exportv1.Condition {
+	statusByName := ctrl.getHookStatuses(exporterPod, service)
+
+	var configuredHooks []string
+	if len(vmExport.Spec.Hooks.PreServeCommand) > 0 {
+		configuredHooks = append(configuredHooks, preServeHookName)
+	}
+	if len(vmExport.Spec.Hooks.PostServeCommand) > 0 {
+		configuredHooks = append(configuredHooks, postServeHookName)
+	}
+
+	pending := false
+	for _, name := range configuredHooks {
+		status, ok := statusByName[name]
+		if !ok || status.Phase == hookPending || status.Phase == hookRunning {
+			pending = true
+			continue
+		}
+		if status.Phase == hookFailed {
+			return newHooksReadyCondition(corev1.ConditionFalse, hooksFailedReason,
+				fmt.Sprintf("%s hook failed: %s", name, status.Error))
+		}
+	}
+	if pending {
+		return newHooksReadyCondition(corev1.ConditionFalse, hooksPendingReason, "")
+	}
+	return newHooksReadyCondition(corev1.ConditionTrue, hooksSucceededReason, "")
+}
+
+// maxConditionTransitions bounds how many entries status.conditionTransitions keeps, oldest
+// first, so the history stays useful without growing the object without limit.
+const maxConditionTransitions = 10
+
+// setReadyCondition updates vmExportCopy's Ready condition and, if its reason changed, appends
+// the new reason to status.conditionTransitions, so support can reconstruct why an export bounced
+// between states (e.g. InUse -> PodPending -> PodReady -> PodCompleted) without digging through
+// events.
+func setReadyCondition(vmExportCopy *exportv1.VirtualMachineExport, status corev1.ConditionStatus, reason, message string) {
+	vmExportCopy.Status.Conditions = updateCondition(vmExportCopy.Status.Conditions, newReadyCondition(status, reason, message))
+
+	transitions := vmExportCopy.Status.ConditionTransitions
+	if len(transitions) > 0 && transitions[len(transitions)-1].Reason == reason {
+		return
+	}
+	transitions = append(transitions, exportv1.VirtualMachineExportConditionTransition{
+		Reason:              reason,
+		TransitionTimestamp: *currentTime(),
+	})
+	if len(transitions) > maxConditionTransitions {
+		transitions = transitions[len(transitions)-maxConditionTransitions:]
+	}
+	vmExportCopy.Status.ConditionTransitions = transitions
+}
+
 func updateCondition(conditions []exportv1.Condition, c exportv1.Condition) []exportv1.Condition {
 	found := false
 	for i := range conditions {
@@ -0,0 +1,136 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package export
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	virtv1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype"
+)
+
+// manifestCAConfigMapName is the name given to the ConfigMap holding the export server's CA
+// bundle inside the generated manifest, so a consumer applying the manifest on another cluster
+// can trust the exporter pod's serving certificate when downloading the other export formats.
+const manifestCAConfigMapName = "export-ca-bundle"
+
+// sanitizeVMForManifest returns a copy of vm, including its DataVolumeTemplates, with
+// cluster-specific metadata and status cleared, so it can be applied as-is to recreate the VM on
+// another cluster.
+func sanitizeVMForManifest(vm *virtv1.VirtualMachine) *virtv1.VirtualMachine {
+	sanitized := vm.DeepCopy()
+	sanitized.TypeMeta = metav1.TypeMeta{
+		APIVersion: virtv1.GroupVersion.String(),
+		Kind:       "VirtualMachine",
+	}
+	sanitized.ObjectMeta = metav1.ObjectMeta{
+		Name:        vm.Name,
+		Namespace:   vm.Namespace,
+		Labels:      vm.Labels,
+		Annotations: vm.Annotations,
+	}
+	sanitized.Status = virtv1.VirtualMachineStatus{}
+	return sanitized
+}
+
+// expandVMSpecForManifest returns a copy of vm with its InstancetypeMatcher and PreferenceMatcher,
+// if set, applied to Spec.Template.Spec and then removed, using instancetypeMethods, so the
+// resulting VM no longer depends on the instancetype or preference it referenced. It returns vm
+// unchanged if neither matcher is set.
+func expandVMSpecForManifest(vm *virtv1.VirtualMachine, instancetypeMethods instancetype.Methods) (*virtv1.VirtualMachine, error) {
+	if vm.Spec.Instancetype == nil && vm.Spec.Preference == nil {
+		return vm, nil
+	}
+
+	instancetypeSpec, err := instancetypeMethods.FindInstancetypeSpec(vm)
+	if err != nil {
+		return nil, err
+	}
+	preferenceSpec, err := instancetypeMethods.FindPreferenceSpec(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := vm.DeepCopy()
+	conflicts := instancetypeMethods.ApplyToVmi(k8sfield.NewPath("spec", "template", "spec"), instancetypeSpec, preferenceSpec, &expanded.Spec.Template.Spec)
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("cannot expand instancetype and preference onto VirtualMachine %s/%s: %s", vm.Namespace, vm.Name, conflicts.String())
+	}
+	expanded.Spec.Instancetype = nil
+	expanded.Spec.Preference = nil
+	return expanded, nil
+}
+
+// buildManifest generates a manifest, as separate "---\n"-delimited YAML documents, containing
+// vm, sanitized of cluster-specific metadata, and a ConfigMap holding caCert, so a consumer can
+// recreate the VM and trust the other export formats' downloads on another cluster from the
+// export alone.
+func buildManifest(vm *virtv1.VirtualMachine, caCert string) ([]byte, error) {
+	return manifestFromVM(sanitizeVMForManifest(vm), caCert)
+}
+
+// buildExpandedManifest generates the same manifest as buildManifest, except that vm's
+// InstancetypeMatcher and PreferenceMatcher, if set, are expanded onto its Spec.Template.Spec and
+// removed using instancetypeMethods first, so the manifest is self-contained.
+func buildExpandedManifest(vm *virtv1.VirtualMachine, caCert string, instancetypeMethods instancetype.Methods) ([]byte, error) {
+	expanded, err := expandVMSpecForManifest(vm, instancetypeMethods)
+	if err != nil {
+		return nil, err
+	}
+	return manifestFromVM(sanitizeVMForManifest(expanded), caCert)
+}
+
+// manifestFromVM generates a manifest, as separate "---\n"-delimited YAML documents, containing
+// vm and a ConfigMap holding caCert, so a consumer can recreate the VM and trust the other export
+// formats' downloads on another cluster from the export alone.
+func manifestFromVM(vm *virtv1.VirtualMachine, caCert string) ([]byte, error) {
+	vmBytes, err := yaml.Marshal(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	caConfigMap := corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      manifestCAConfigMapName,
+			Namespace: vm.Namespace,
+		},
+		Data: map[string]string{
+			caBundle: caCert,
+		},
+	}
+	caBytes, err := yaml.Marshal(caConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := append(vmBytes, []byte("---\n")...)
+	manifest = append(manifest, caBytes...)
+	return manifest, nil
+}
@@ -0,0 +1,197 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// CIM_ResourceAllocationSettingData.ResourceType values used in the generated
+// VirtualHardwareSection, as defined by DSP0004.
+const (
+	ovfResourceTypeProcessor       = "3"
+	ovfResourceTypeMemory          = "4"
+	ovfResourceTypeEthernetAdapter = "10"
+	ovfResourceTypeDiskDrive       = "17"
+)
+
+// ovfEnvelope models just enough of the OVF 2.x envelope (DSP0243) to describe an exported VM's
+// disks, CPU, memory and network interfaces. It is not a complete implementation of the schema.
+type ovfEnvelope struct {
+	XMLName        xml.Name          `xml:"Envelope"`
+	Xmlns          string            `xml:"xmlns,attr"`
+	XmlnsOvf       string            `xml:"xmlns:ovf,attr"`
+	XmlnsRasd      string            `xml:"xmlns:rasd,attr"`
+	References     ovfReferences     `xml:"References"`
+	DiskSection    ovfDiskSection    `xml:"DiskSection"`
+	NetworkSection ovfNetworkSection `xml:"NetworkSection"`
+	VirtualSystem  ovfVirtualSystem  `xml:"VirtualSystem"`
+}
+
+type ovfReferences struct {
+	Files []ovfFile `xml:"File"`
+}
+
+type ovfFile struct {
+	ID   string `xml:"ovf:id,attr"`
+	Href string `xml:"ovf:href,attr"`
+}
+
+type ovfDiskSection struct {
+	Info  string    `xml:"Info"`
+	Disks []ovfDisk `xml:"Disk"`
+}
+
+type ovfDisk struct {
+	DiskId                  string `xml:"ovf:diskId,attr"`
+	FileRef                 string `xml:"ovf:fileRef,attr"`
+	Capacity                string `xml:"ovf:capacity,attr"`
+	CapacityAllocationUnits string `xml:"ovf:capacityAllocationUnits,attr"`
+	Format                  string `xml:"ovf:format,attr"`
+}
+
+type ovfNetworkSection struct {
+	Info     string       `xml:"Info"`
+	Networks []ovfNetwork `xml:"Network"`
+}
+
+type ovfNetwork struct {
+	Name        string `xml:"ovf:name,attr"`
+	Description string `xml:"Description"`
+}
+
+type ovfVirtualSystem struct {
+	ID                     string                    `xml:"ovf:id,attr"`
+	Info                   string                    `xml:"Info"`
+	Name                   string                    `xml:"Name"`
+	VirtualHardwareSection ovfVirtualHardwareSection `xml:"VirtualHardwareSection"`
+}
+
+type ovfVirtualHardwareSection struct {
+	Info  string    `xml:"Info"`
+	Items []ovfItem `xml:"Item"`
+}
+
+type ovfItem struct {
+	ElementName     string `xml:"rasd:ElementName"`
+	InstanceID      string `xml:"rasd:InstanceID"`
+	ResourceType    string `xml:"rasd:ResourceType"`
+	VirtualQuantity string `xml:"rasd:VirtualQuantity,omitempty"`
+	Connection      string `xml:"rasd:Connection,omitempty"`
+	HostResource    string `xml:"rasd:HostResource,omitempty"`
+}
+
+// ovaDiskName is the file name given to pvc's disk inside an OVA bundle, as referenced from the
+// generated OVF descriptor's References and DiskSection. It must match the name the exporter pod
+// gives the same disk when it bundles the OVA.
+func ovaDiskName(pvc *corev1.PersistentVolumeClaim) string {
+	return fmt.Sprintf("%s.vmdk", pvc.Name)
+}
+
+// buildOvfDescriptor generates an OVF descriptor describing vmName's CPU, memory and network
+// interfaces, as configured in vmiSpec, and referencing, in order, the VMDK that each of pvcs is
+// converted to when bundled into an OVA.
+func buildOvfDescriptor(vmName string, vmiSpec *virtv1.VirtualMachineInstanceSpec, pvcs []*corev1.PersistentVolumeClaim) ([]byte, error) {
+	envelope := ovfEnvelope{
+		Xmlns:     "http://schemas.dmtf.org/ovf/envelope/2",
+		XmlnsOvf:  "http://schemas.dmtf.org/ovf/envelope/2",
+		XmlnsRasd: "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData",
+		VirtualSystem: ovfVirtualSystem{
+			ID:   vmName,
+			Info: fmt.Sprintf("Virtual machine %s", vmName),
+			Name: vmName,
+		},
+	}
+
+	for i, pvc := range pvcs {
+		diskId := fmt.Sprintf("disk%d", i)
+		fileId := fmt.Sprintf("file%d", i)
+		envelope.References.Files = append(envelope.References.Files, ovfFile{ID: fileId, Href: ovaDiskName(pvc)})
+
+		capacity := "0"
+		if size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			capacity = fmt.Sprintf("%d", size.Value())
+		}
+		envelope.DiskSection.Disks = append(envelope.DiskSection.Disks, ovfDisk{
+			DiskId:                  diskId,
+			FileRef:                 fileId,
+			Capacity:                capacity,
+			CapacityAllocationUnits: "byte",
+			Format:                  "http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized",
+		})
+		envelope.addHardwareItem(ovfItem{
+			ElementName:  pvc.Name,
+			ResourceType: ovfResourceTypeDiskDrive,
+			HostResource: fmt.Sprintf("ovf:/disk/%s", diskId),
+		})
+	}
+
+	if vmiSpec != nil {
+		var cores uint32 = 1
+		if vmiSpec.Domain.CPU != nil && vmiSpec.Domain.CPU.Cores > 0 {
+			cores = vmiSpec.Domain.CPU.Cores
+		}
+		envelope.addHardwareItem(ovfItem{
+			ElementName:     "vCPU",
+			ResourceType:    ovfResourceTypeProcessor,
+			VirtualQuantity: fmt.Sprintf("%d", cores),
+		})
+
+		if memory, ok := vmiSpec.Domain.Resources.Requests[corev1.ResourceMemory]; ok {
+			envelope.addHardwareItem(ovfItem{
+				ElementName:     "Memory",
+				ResourceType:    ovfResourceTypeMemory,
+				VirtualQuantity: fmt.Sprintf("%d", memory.Value()/(1024*1024)),
+			})
+		}
+
+		for _, network := range vmiSpec.Networks {
+			envelope.NetworkSection.Networks = append(envelope.NetworkSection.Networks, ovfNetwork{
+				Name:        network.Name,
+				Description: fmt.Sprintf("Network %s", network.Name),
+			})
+		}
+		for _, iface := range vmiSpec.Domain.Devices.Interfaces {
+			envelope.addHardwareItem(ovfItem{
+				ElementName:  iface.Name,
+				ResourceType: ovfResourceTypeEthernetAdapter,
+				Connection:   iface.Name,
+			})
+		}
+	}
+
+	body, err := xml.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// addHardwareItem appends item to the VirtualHardwareSection, numbering it with the next unused
+// InstanceID.
+func (e *ovfEnvelope) addHardwareItem(item ovfItem) {
+	item.InstanceID = fmt.Sprintf("%d", len(e.VirtualSystem.VirtualHardwareSection.Items)+1)
+	e.VirtualSystem.VirtualHardwareSection.Items = append(e.VirtualSystem.VirtualHardwareSection.Items, item)
+}
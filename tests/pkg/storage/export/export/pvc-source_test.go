@@ -42,6 +42,7 @@ import (
 	"k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 
 	virtv1 "kubevirt.io/api/core/v1"
 	exportv1 "kubevirt.io/api/export/v1alpha1"
@@ -146,6 +147,7 @@ var _ = Describe("PVC source", func() {
 			VolumeSnapshotProvider:    fakeVolumeSnapshotProvider,
 			VMInformer:                vmInformer,
 			VMIInformer:               vmiInformer,
+			ClusterConfig:             config,
 		}
 		initCert = func(ctrl *VMExportController) {
 			go controller.caCertManager.Start()
@@ -241,6 +243,29 @@ var _ = Describe("PVC source", func() {
 		Expect(service.Name).To(Equal(fmt.Sprintf("%s-%s", exportPrefix, testVMExport.Name)))
 	})
 
+	It("Should properly update VMExport status with a valid token and kubevirt pvc from a DataVolume source", func() {
+		testVMExport := createDataVolumeVMExport()
+		pvcInformer.GetStore().Add(createPVC(testPVCName, "kubevirt"))
+		expectExporterCreate(k8sClient, k8sv1.PodRunning)
+		controller.RouteCache.Add(routeToHostAndService(components.VirtExportProxyServiceName))
+
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			verifyKubevirtInternal(vmExport, vmExport.Name, testNamespace, testVMExport.Spec.Source.Name)
+			verifyKubevirtExternal(vmExport, vmExport.Name, testNamespace, testVMExport.Spec.Source.Name)
+			return true, vmExport, nil
+		})
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+		service, err := k8sClient.CoreV1().Services(testNamespace).Get(context.Background(), fmt.Sprintf("%s-%s", exportPrefix, testVMExport.Name), metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(service.Name).To(Equal(fmt.Sprintf("%s-%s", exportPrefix, testVMExport.Name)))
+	})
+
 	It("Should properly update VMExport status with a valid token and no pvc, pending pod", func() {
 		testVMExport := createPVCVMExport()
 		expectExporterCreate(k8sClient, k8sv1.PodPending)
@@ -355,6 +380,103 @@ var _ = Describe("PVC source", func() {
 		Entry("content-type archive", cdiv1.DataVolumeArchive, false),
 	)
 
+	DescribeTable("should report whether a DataVolume-owned PVC is waiting for a first consumer", func(dvPhase cdiv1.DataVolumePhase, expectedWaiting bool) {
+		dv := &cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-dv",
+				Namespace: testNamespace,
+			},
+			Status: cdiv1.DataVolumeStatus{
+				Phase: dvPhase,
+			},
+		}
+		controller.DataVolumeInformer.GetStore().Add(dv)
+		pvc := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-dv",
+				Namespace: testNamespace,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(dv, schema.GroupVersionKind{
+						Group:   cdiv1.SchemeGroupVersion.Group,
+						Version: cdiv1.SchemeGroupVersion.Version,
+						Kind:    "DataVolume",
+					}),
+				},
+			},
+		}
+		waiting, err := controller.isPVCWaitingForFirstConsumer(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(waiting).To(Equal(expectedWaiting))
+	},
+		Entry("DataVolume waiting for first consumer", cdiv1.WaitForFirstConsumer, true),
+		Entry("DataVolume already importing", cdiv1.ImportInProgress, false),
+		Entry("DataVolume succeeded", cdiv1.Succeeded, false),
+	)
+
+	DescribeTable("should report whether a volume-populator-filled PVC is populated", func(phase k8sv1.PersistentVolumeClaimPhase, expectedPopulated bool) {
+		pvc := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-populator-pvc",
+				Namespace: testNamespace,
+			},
+			Spec: k8sv1.PersistentVolumeClaimSpec{
+				DataSourceRef: &k8sv1.TypedLocalObjectReference{
+					APIGroup: pointer.String(cdiv1.SchemeGroupVersion.Group),
+					Kind:     "VolumeImportSource",
+					Name:     "test-import-source",
+				},
+			},
+			Status: k8sv1.PersistentVolumeClaimStatus{
+				Phase: phase,
+			},
+		}
+		populated, err := controller.isPVCPopulated(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(populated).To(Equal(expectedPopulated))
+	},
+		Entry("populator PVC still pending", k8sv1.ClaimPending, false),
+		Entry("populator PVC bound", k8sv1.ClaimBound, true),
+	)
+
+	It("should create the exporter pod for a PVC that is waiting for a first consumer, to trigger binding", func() {
+		dv := &cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-dv",
+				Namespace: testNamespace,
+			},
+			Status: cdiv1.DataVolumeStatus{
+				Phase: cdiv1.WaitForFirstConsumer,
+			},
+		}
+		controller.DataVolumeInformer.GetStore().Add(dv)
+		pvc := &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-dv",
+				Namespace: testNamespace,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(dv, schema.GroupVersionKind{
+						Group:   cdiv1.SchemeGroupVersion.Group,
+						Version: cdiv1.SchemeGroupVersion.Version,
+						Kind:    "DataVolume",
+					}),
+				},
+			},
+			Status: k8sv1.PersistentVolumeClaimStatus{
+				Phase: k8sv1.ClaimPending,
+			},
+		}
+		controller.PVCInformer.GetStore().Add(pvc)
+
+		sourceVolumes := &sourceVolumes{
+			volumes:                 []*k8sv1.PersistentVolumeClaim{pvc},
+			isPopulated:             false,
+			waitingForFirstConsumer: true,
+		}
+		pod, _, err := controller.manageExporterPod(createPVCVMExport(), sourceVolumes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod).ToNot(BeNil())
+	})
+
 	DescribeTable("should create proper condition from PVC", func(phase k8sv1.PersistentVolumeClaimPhase, status k8sv1.ConditionStatus, reason, message string) {
 		pvc := &k8sv1.PersistentVolumeClaim{
 			Status: k8sv1.PersistentVolumeClaimStatus{
@@ -148,6 +148,7 @@ var _ = Describe("VMSnapshot source", func() {
 			VolumeSnapshotProvider:    fakeVolumeSnapshotProvider,
 			VMInformer:                vmInformer,
 			VMIInformer:               vmiInformer,
+			ClusterConfig:             config,
 		}
 		initCert = func(ctrl *VMExportController) {
 			go controller.caCertManager.Start()
@@ -445,6 +446,31 @@ var _ = Describe("VMSnapshot source", func() {
 		Expect(retry).To(BeEquivalentTo(0))
 	})
 
+	It("Should create restored PVCs from VMSnapshot using spec.restoreStorageClassName", func() {
+		testVMExport := createSnapshotVMExport()
+		testVMExport.Spec.RestoreStorageClassName = pointer.StringPtr("cheap-ephemeral")
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			return true, testVMExport, nil
+		})
+
+		k8sClient.Fake.PrependReactor("create", "persistentvolumeclaims", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			create, ok := action.(testing.CreateAction)
+			Expect(ok).To(BeTrue())
+			pvc, ok := create.GetObject().(*k8sv1.PersistentVolumeClaim)
+			Expect(ok).To(BeTrue())
+			Expect(pvc.Spec.StorageClassName).To(Equal(pointer.StringPtr("cheap-ephemeral")))
+			return true, pvc, nil
+		})
+		expectExporterCreate(k8sClient, k8sv1.PodPending)
+
+		vmSnapshotInformer.GetStore().Add(createTestVMSnapshot(true))
+		vmSnapshotContentInformer.GetStore().Add(createTestVMSnapshotContent("snapshot-content"))
+		fakeVolumeSnapshotProvider.Add(createTestVolumeSnapshot(testVolumesnapshotName))
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(BeEquivalentTo(0))
+	})
+
 	It("Should not re-create restored PVCs from VMSnapshot if pvc already exists", func() {
 		testVMExport := createSnapshotVMExport()
 		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
@@ -488,6 +514,52 @@ var _ = Describe("VMSnapshot source", func() {
 		Expect(retry).To(BeEquivalentTo(0))
 	})
 
+	It("Should not create a restore PVC from VMSnapshot when the namespace storage quota is exceeded", func() {
+		testVMExport := createSnapshotVMExport()
+		vmExportClient.Fake.PrependReactor("update", "virtualmachineexports", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			update, ok := action.(testing.UpdateAction)
+			Expect(ok).To(BeTrue())
+			vmExport, ok := update.GetObject().(*exportv1.VirtualMachineExport)
+			Expect(ok).To(BeTrue())
+			verifyLinksEmpty(vmExport)
+			volumeCreateConditionSet := false
+			for _, condition := range vmExport.Status.Conditions {
+				if condition.Type == exportv1.ConditionVolumesCreated {
+					volumeCreateConditionSet = true
+					Expect(condition.Status).To(Equal(k8sv1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(insufficientCapacityReason))
+					Expect(condition.Message).ToNot(BeEmpty())
+				}
+			}
+			Expect(volumeCreateConditionSet).To(BeTrue())
+			Expect(vmExport.Status.Phase).To(Equal(exportv1.Pending))
+			return true, vmExport, nil
+		})
+
+		k8sClient.Fake.PrependReactor("create", "persistentvolumeclaims", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			_, ok := action.(testing.CreateAction)
+			Expect(ok).To(BeTrue())
+			Fail("unexpected create persistentvolumeclaims called")
+			return true, nil, nil
+		})
+
+		_, err := k8sClient.CoreV1().ResourceQuotas(testNamespace).Create(context.Background(), &k8sv1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-quota", Namespace: testNamespace},
+			Status: k8sv1.ResourceQuotaStatus{
+				Hard: k8sv1.ResourceList{k8sv1.ResourceRequestsStorage: resource.MustParse("500Mi")},
+				Used: k8sv1.ResourceList{k8sv1.ResourceRequestsStorage: resource.MustParse("0")},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		vmSnapshotInformer.GetStore().Add(createTestVMSnapshot(true))
+		vmSnapshotContentInformer.GetStore().Add(createTestVMSnapshotContent("snapshot-content"))
+		fakeVolumeSnapshotProvider.Add(createTestVolumeSnapshot(testVolumesnapshotName))
+		retry, err := controller.updateVMExport(testVMExport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retry).To(Equal(restoreRequeueTime))
+	})
+
 	It("Should update status with correct links from snapshot with kubevirt content type", func() {
 		testVMExport := createSnapshotVMExport()
 		restoreName := fmt.Sprintf("%s-%s", testVMExport.Name, testVolumesnapshotName)
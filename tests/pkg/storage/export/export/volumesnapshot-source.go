@@ -0,0 +1,123 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package export
+
+import (
+	"context"
+	"fmt"
+
+	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	exportv1 "kubevirt.io/api/export/v1alpha1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1alpha1"
+
+	"kubevirt.io/kubevirt/pkg/storage/snapshot"
+)
+
+func (ctrl *VMExportController) isSourceVolumeSnapshot(source *exportv1.VirtualMachineExportSpec) bool {
+	return source != nil && source.Source.APIGroup != nil && *source.Source.APIGroup == vsv1.SchemeGroupVersion.Group && source.Source.Kind == "VolumeSnapshot"
+}
+
+func (ctrl *VMExportController) getPVCFromSourceVolumeSnapshot(vmExport *exportv1.VirtualMachineExport) (*sourceVolumes, error) {
+	volumeSnapshot, err := ctrl.VolumeSnapshotProvider.GetVolumeSnapshot(vmExport.Namespace, vmExport.Spec.Source.Name)
+	if err != nil {
+		return &sourceVolumes{}, err
+	}
+	if volumeSnapshot == nil {
+		return &sourceVolumes{
+			volumes:          nil,
+			inUse:            false,
+			isPopulated:      false,
+			availableMessage: fmt.Sprintf("VolumeSnapshot %s/%s does not exist", vmExport.Namespace, vmExport.Spec.Source.Name)}, nil
+	}
+	if volumeSnapshot.Status == nil || volumeSnapshot.Status.ReadyToUse == nil || !*volumeSnapshot.Status.ReadyToUse {
+		return &sourceVolumes{
+			volumes:          nil,
+			inUse:            false,
+			isPopulated:      false,
+			availableMessage: fmt.Sprintf("VolumeSnapshot %s/%s is not ready to use", vmExport.Namespace, vmExport.Spec.Source.Name),
+			requeueAfter:     restoreRequeueTime}, nil
+	}
+
+	pvc, err := ctrl.getOrCreatePVCFromVolumeSnapshot(vmExport, volumeSnapshot)
+	if err != nil {
+		return &sourceVolumes{}, err
+	}
+
+	isPopulated, err := ctrl.isPVCPopulated(pvc)
+	if err != nil {
+		return &sourceVolumes{}, err
+	}
+	availableMessage := ""
+	if !isPopulated {
+		availableMessage = fmt.Sprintf("pvc %s/%s is not populated", pvc.Namespace, pvc.Name)
+	}
+	return &sourceVolumes{
+		volumes:          []*corev1.PersistentVolumeClaim{pvc},
+		inUse:            false,
+		isPopulated:      isPopulated,
+		availableMessage: availableMessage}, nil
+}
+
+// getOrCreatePVCFromVolumeSnapshot restores volumeSnapshot into a PVC owned by vmExport, so the
+// exporter pod has something to mount. A VirtualMachineSnapshot keeps a VolumeBackup recording the
+// original PVC's accessModes and storage class for each of its volumes, but a bare VolumeSnapshot
+// source has no such record, so the restore PVC is created with the default storage class and
+// ReadWriteOnce, sized to the snapshot's reported restore size.
+func (ctrl *VMExportController) getOrCreatePVCFromVolumeSnapshot(vmExport *exportv1.VirtualMachineExport, volumeSnapshot *vsv1.VolumeSnapshot) (*corev1.PersistentVolumeClaim, error) {
+	restorePVCName := fmt.Sprintf("%s-%s", vmExport.Name, volumeSnapshot.Name)
+
+	if pvc, exists, err := ctrl.getPvc(vmExport.Namespace, restorePVCName); err != nil {
+		return nil, err
+	} else if exists {
+		return pvc, nil
+	}
+
+	volumeSnapshotName := volumeSnapshot.Name
+	volumeBackup := &snapshotv1.VolumeBackup{
+		VolumeSnapshotName: &volumeSnapshotName,
+		PersistentVolumeClaim: snapshotv1.PersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{},
+				},
+			},
+		},
+	}
+
+	pvc := snapshot.CreateRestorePVCDef(restorePVCName, volumeSnapshot, volumeBackup)
+	pvc.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion:         exportGVK.GroupVersion().String(),
+			Kind:               exportGVK.Kind,
+			Name:               vmExport.Name,
+			UID:                vmExport.UID,
+			Controller:         pointer.BoolPtr(true),
+			BlockOwnerDeletion: pointer.BoolPtr(true),
+		},
+	})
+
+	return ctrl.Client.CoreV1().PersistentVolumeClaims(vmExport.Namespace).Create(context.Background(), pvc, metav1.CreateOptions{})
+}
@@ -0,0 +1,116 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"crypto/tls"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"kubevirt.io/client-go/log"
+)
+
+// certReloader keeps the exporter pod's TLS certificate current as the controller renews it,
+// without requiring a pod restart: the controller updates the secret mounted at certFile/keyFile
+// in place, and certReloader watches the mount for changes and reloads it.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+
+	// watching is closed once watch has started watching certFile/keyFile's directories, so a
+	// caller (namely tests) can tell when it's safe to change those files without racing watch's
+	// own startup.
+	watching chan struct{}
+}
+
+// newCertReloader loads certFile/keyFile once up front, so Run fails fast if they are invalid,
+// and returns a certReloader ready to be passed to watch and GetCertificate.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, watching: make(chan struct{})}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate is a tls.Config.GetCertificate callback that always hands back the most recently
+// loaded certificate, so a renewal picked up by watch takes effect on the very next handshake.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// watch reloads the certificate whenever certFile or keyFile changes, until the process exits.
+// Secret volume mounts are updated by kubelet re-pointing a symlink at a new directory rather
+// than writing the watched files directly, so it watches the containing directories instead of
+// the files themselves to see the rename that a plain file watch on the files could miss.
+func (r *certReloader) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Log.Reason(err).Error("failed to start TLS certificate watcher, certificate renewal will require a pod restart")
+		return
+	}
+
+	dirs := map[string]bool{filepath.Dir(r.certFile): true, filepath.Dir(r.keyFile): true}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			log.Log.Reason(err).Errorf("failed to watch %s for TLS certificate changes", dir)
+		}
+	}
+	close(r.watching)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) == 0 {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				log.Log.Reason(err).Warning("failed to reload TLS certificate, keeping the previous one")
+				continue
+			}
+			log.Log.Info("Reloaded TLS certificate")
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Log.Reason(err).Error("error watching TLS certificate for changes")
+		}
+	}
+}
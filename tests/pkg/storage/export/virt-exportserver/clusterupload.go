@@ -0,0 +1,382 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	cdiuploadv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/upload/v1beta1"
+
+	"kubevirt.io/client-go/log"
+)
+
+// ClusterUploadConfig configures the exporter pod to additionally create a DataVolume for each
+// exported volume's raw artifact on another cluster and stream the artifact straight into it via
+// that cluster's cdi-uploadproxy, alongside still serving it for HTTPS download.
+//
+// Only volumes with a RawURI are uploaded, for the same reason as S3UploadConfig: DataVolume
+// uploads require a known size up front, which RawGzURI and ArchiveURI, produced by streaming
+// pipelines, don't have.
+type ClusterUploadConfig struct {
+	Namespace         string
+	CDIUploadProxyURL string
+	KubeconfigFile    string
+}
+
+const (
+	clusterUploadPending      = "Pending"
+	clusterUploadInProgress   = "InProgress"
+	clusterUploadComplete     = "Complete"
+	clusterUploadFailed       = "Failed"
+	internalClusterUploadPath = "/internal/clusterupload"
+
+	dataVolumeUploadPollInterval = 2 * time.Second
+	dataVolumeUploadPollTimeout  = 5 * time.Minute
+)
+
+// clusterUploadStatus mirrors kubevirt.io/api/export/v1alpha1.VirtualMachineExportClusterUploadStatus.
+// It is kept as a separate type rather than importing the API package, consistent with this
+// package's existing internal status types (artifactMetadata, s3UploadStatus), which the
+// controller translates into API types rather than the exporter pod depending on the API.
+type clusterUploadStatus struct {
+	Phase          string `json:"phase"`
+	BytesUploaded  int64  `json:"bytesUploaded,omitempty"`
+	DataVolumeName string `json:"dataVolumeName,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// clusterUploadStatusStore tracks clusterUploadStatus by volume name.
+type clusterUploadStatusStore struct {
+	mu       sync.Mutex
+	byVolume map[string]clusterUploadStatus
+}
+
+func newClusterUploadStatusStore() *clusterUploadStatusStore {
+	return &clusterUploadStatusStore{byVolume: make(map[string]clusterUploadStatus)}
+}
+
+func (s *clusterUploadStatusStore) set(name string, status clusterUploadStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byVolume[name] = status
+}
+
+func (s *clusterUploadStatusStore) snapshot() map[string]clusterUploadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]clusterUploadStatus, len(s.byVolume))
+	for name, status := range s.byVolume {
+		result[name] = status
+	}
+	return result
+}
+
+func clusterUploadHandler(store *clusterUploadStatusStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(store.snapshot()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+// clusterProgressReader wraps an io.Reader to report every byte read from it to a
+// clusterUploadStatusStore, so that an in-progress upload's BytesUploaded can be polled before it
+// completes.
+type clusterProgressReader struct {
+	io.Reader
+	name  string
+	store *clusterUploadStatusStore
+	n     int64
+}
+
+func (r *clusterProgressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.n += int64(n)
+		r.store.set(r.name, clusterUploadStatus{Phase: clusterUploadInProgress, BytesUploaded: r.n})
+	}
+	return n, err
+}
+
+// computeClusterUploads creates a DataVolume on the target cluster configured by config for the
+// raw artifact of every volume that has one, and streams it into that DataVolume, recording
+// progress and the outcome of each upload in store. It returns once every upload has finished,
+// successfully or not; callers are expected to run it in a goroutine and poll store rather than
+// wait for it.
+func computeClusterUploads(config *ClusterUploadConfig, volumes []VolumeInfo, store *clusterUploadStatusStore) {
+	client, err := newTargetClusterClient(config.KubeconfigFile)
+	if err != nil {
+		log.Log.Reason(err).Error("error building client for target cluster")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, vi := range volumes {
+		if vi.RawURI == "" {
+			continue
+		}
+		vi := vi
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uploadVolumeToCluster(config, client, vi, store)
+		}()
+	}
+	wg.Wait()
+}
+
+func uploadVolumeToCluster(config *ClusterUploadConfig, client *targetClusterClient, vi VolumeInfo, store *clusterUploadStatusStore) {
+	store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadInProgress})
+
+	f, err := openDiskImage(vi.Path)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error opening %s to upload volume %s to target cluster", vi.Path, vi.Name)
+		store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadFailed, Error: err.Error()})
+		return
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		log.Log.Reason(err).Errorf("error statting %s to upload volume %s to target cluster", vi.Path, vi.Name)
+		store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadFailed, Error: err.Error()})
+		return
+	}
+
+	dvName := vi.Name
+	if err := client.createUploadDataVolume(config.Namespace, dvName, fi.Size()); err != nil {
+		log.Log.Reason(err).Errorf("error creating DataVolume %s on target cluster", dvName)
+		store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadFailed, Error: err.Error()})
+		return
+	}
+	store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadInProgress, DataVolumeName: dvName})
+
+	if err := client.waitForUploadReady(config.Namespace, dvName, dataVolumeUploadPollTimeout); err != nil {
+		log.Log.Reason(err).Errorf("error waiting for DataVolume %s to become ready for upload", dvName)
+		store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadFailed, DataVolumeName: dvName, Error: err.Error()})
+		return
+	}
+
+	token, err := client.requestUploadToken(config.Namespace, dvName)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error requesting upload token for DataVolume %s", dvName)
+		store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadFailed, DataVolumeName: dvName, Error: err.Error()})
+		return
+	}
+
+	reader := &clusterProgressReader{Reader: f, name: vi.Name, store: store}
+	if err := uploadImage(config.CDIUploadProxyURL, token, reader, fi.Size()); err != nil {
+		log.Log.Reason(err).Errorf("error uploading volume %s to target cluster", vi.Name)
+		store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadFailed, DataVolumeName: dvName, Error: err.Error()})
+		return
+	}
+
+	store.set(vi.Name, clusterUploadStatus{Phase: clusterUploadComplete, BytesUploaded: fi.Size(), DataVolumeName: dvName})
+}
+
+// targetClusterClient talks to a target cluster's Kubernetes API server to drive a CDI upload
+// DataVolume through to readiness, using the vendored client-go REST machinery directly rather
+// than a generated CDI clientset, since none is vendored in this repository.
+type targetClusterClient struct {
+	httpClient *http.Client
+	host       string
+}
+
+func newTargetClusterClient(kubeconfigFile string) (*targetClusterClient, error) {
+	kubeconfig, err := ioutil.ReadFile(kubeconfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &targetClusterClient{httpClient: httpClient, host: restConfig.Host}, nil
+}
+
+func (c *targetClusterClient) do(method, path string, body, out interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, c.host+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("request to %s failed with status %s: %s", path, resp.Status, string(respBody))
+	}
+
+	if out != nil {
+		return json.Unmarshal(respBody, out)
+	}
+	return nil
+}
+
+// createUploadDataVolume creates a DataVolume named name in namespace on the target cluster,
+// sized to hold size bytes, with an Upload source, so that it can be streamed into directly.
+func (c *targetClusterClient) createUploadDataVolume(namespace, name string, size int64) error {
+	dv := &cdiv1.DataVolume{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "cdi.kubevirt.io/v1beta1",
+			Kind:       "DataVolume",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdiv1.DataVolumeSpec{
+			Source: &cdiv1.DataVolumeSource{
+				Upload: &cdiv1.DataVolumeSourceUpload{},
+			},
+			Storage: &cdiv1.StorageSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: *k8sresource.NewQuantity(size, k8sresource.BinarySI),
+					},
+				},
+			},
+		},
+	}
+
+	path := fmt.Sprintf("/apis/cdi.kubevirt.io/v1beta1/namespaces/%s/datavolumes", namespace)
+	return c.do(http.MethodPost, path, dv, nil)
+}
+
+// waitForUploadReady polls the DataVolume named name in namespace until it reaches the
+// UploadReady phase, or returns an error if it reaches Failed or timeout elapses first.
+func (c *targetClusterClient) waitForUploadReady(namespace, name string, timeout time.Duration) error {
+	path := fmt.Sprintf("/apis/cdi.kubevirt.io/v1beta1/namespaces/%s/datavolumes/%s", namespace, name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		var dv cdiv1.DataVolume
+		if err := c.do(http.MethodGet, path, nil, &dv); err != nil {
+			return err
+		}
+
+		switch dv.Status.Phase {
+		case cdiv1.UploadReady:
+			return nil
+		case cdiv1.Failed:
+			return fmt.Errorf("DataVolume %s/%s failed", namespace, name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for DataVolume %s/%s to become ready for upload", namespace, name)
+		case <-time.After(dataVolumeUploadPollInterval):
+		}
+	}
+}
+
+// requestUploadToken requests a token authorizing an upload to the PVC backing the DataVolume
+// named pvcName, for use against the target cluster's cdi-uploadproxy.
+func (c *targetClusterClient) requestUploadToken(namespace, pvcName string) (string, error) {
+	utr := &cdiuploadv1.UploadTokenRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "upload.cdi.kubevirt.io/v1beta1",
+			Kind:       "UploadTokenRequest",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvcName,
+			Namespace: namespace,
+		},
+		Spec: cdiuploadv1.UploadTokenRequestSpec{
+			PvcName: pvcName,
+		},
+	}
+
+	path := fmt.Sprintf("/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/%s/uploadtokenrequests", namespace)
+	var result cdiuploadv1.UploadTokenRequest
+	if err := c.do(http.MethodPost, path, utr, &result); err != nil {
+		return "", err
+	}
+
+	return result.Status.Token, nil
+}
+
+// uploadImage PUTs body, of the given size, to the target cluster's cdi-uploadproxy, authenticated
+// with token.
+func uploadImage(cdiUploadProxyURL, token string, body io.Reader, size int64) error {
+	req, err := http.NewRequest(http.MethodPost, cdiUploadProxyURL+"/v1beta1/upload", body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("upload to cdi-uploadproxy failed with status %s", resp.Status)
+	}
+
+	return nil
+}
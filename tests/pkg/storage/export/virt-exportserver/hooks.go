@@ -0,0 +1,112 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"sync"
+
+	"kubevirt.io/client-go/log"
+)
+
+// HookConfig configures the external commands this server runs around serving its artifacts for
+// download. Both commands must already exist in the exporter image; there is no facility here for
+// supplying arbitrary scripts of the caller's own.
+type HookConfig struct {
+	// PreServeCommand, if set, is run once before any handler is registered, so nothing is
+	// downloadable until it succeeds.
+	PreServeCommand []string
+	// PostServeCommand, if set, is run once this server stops accepting new downloads.
+	PostServeCommand []string
+}
+
+const (
+	hookPending       = "Pending"
+	hookRunning       = "Running"
+	hookSucceeded     = "Succeeded"
+	hookFailed        = "Failed"
+	internalHooksPath = "/internal/hooks"
+
+	// preServeHookName and postServeHookName are the keys hookStatusStore tracks each hook's
+	// outcome under, since there is exactly one of each per exporter pod.
+	preServeHookName  = "preServe"
+	postServeHookName = "postServe"
+)
+
+// hookStatus is the outcome of running one of HookConfig's commands.
+type hookStatus struct {
+	Phase string `json:"phase"`
+	Error string `json:"error,omitempty"`
+}
+
+// hookStatusStore tracks hookStatus by hook name (preServeHookName or postServeHookName).
+type hookStatusStore struct {
+	mu     sync.Mutex
+	byName map[string]hookStatus
+}
+
+func newHookStatusStore() *hookStatusStore {
+	return &hookStatusStore{byName: make(map[string]hookStatus)}
+}
+
+func (s *hookStatusStore) set(name string, status hookStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byName[name] = status
+}
+
+func (s *hookStatusStore) snapshot() map[string]hookStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]hookStatus, len(s.byName))
+	for name, status := range s.byName {
+		result[name] = status
+	}
+	return result
+}
+
+func hooksHandler(store *hookStatusStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(store.snapshot()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+// runHook runs command, recording its outcome in store under name, and returns whether it
+// succeeded. command's first element is looked up on PATH, matching exec.Command; it is not run
+// through a shell.
+func runHook(name string, command []string, store *hookStatusStore) bool {
+	store.set(name, hookStatus{Phase: hookRunning})
+
+	cmd := exec.Command(command[0], command[1:]...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Log.Reason(err).Errorf("%s hook failed: %s", name, string(output))
+		store.set(name, hookStatus{Phase: hookFailed, Error: err.Error()})
+		return false
+	}
+
+	store.set(name, hookStatus{Phase: hookSucceeded})
+	return true
+}
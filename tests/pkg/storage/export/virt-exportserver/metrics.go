@@ -0,0 +1,94 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2024 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	bytesServedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmexport_server_bytes_served_total",
+			Help: "Number of bytes served by this exporter pod, by download artifact.",
+		},
+		[]string{"artifact"},
+	)
+
+	activeConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubevirt_vmexport_server_active_connections",
+			Help: "Number of download connections currently open on this exporter pod, by download artifact.",
+		},
+		[]string{"artifact"},
+	)
+
+	downloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmexport_server_downloads_total",
+			Help: "Number of completed downloads served by this exporter pod, by download artifact and outcome.",
+		},
+		[]string{"artifact", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(bytesServedTotal, activeConnections, downloadsTotal)
+}
+
+// metricsResponseWriter wraps a http.ResponseWriter to count the bytes written to it and record
+// its final status code, so metricsTracker can report kubevirt_vmexport_server_bytes_served_total
+// and kubevirt_vmexport_server_downloads_total for the artifact it serves.
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	artifact   string
+	statusCode int
+}
+
+func (w *metricsResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *metricsResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	bytesServedTotal.WithLabelValues(w.artifact).Add(float64(n))
+	return n, err
+}
+
+// metricsTracker instruments nextHandler with the exporter pod's per-artifact download metrics:
+// bytes served, active connections, and completed downloads by outcome.
+func metricsTracker(artifact string, nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		activeConnections.WithLabelValues(artifact).Inc()
+		defer activeConnections.WithLabelValues(artifact).Dec()
+
+		mw := &metricsResponseWriter{ResponseWriter: w, artifact: artifact, statusCode: http.StatusOK}
+		nextHandler.ServeHTTP(mw, r)
+
+		result := "success"
+		if mw.statusCode >= http.StatusBadRequest {
+			result = "error"
+		}
+		downloadsTotal.WithLabelValues(artifact, result).Inc()
+	})
+}
@@ -0,0 +1,154 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"kubevirt.io/client-go/log"
+)
+
+// OvaDisk is a single disk to include in an OVA bundle, identified by the file name it is given
+// inside the bundle (as referenced by the OVF descriptor) and the path of the raw disk image on
+// the exporter pod's local storage that it is converted from.
+type OvaDisk struct {
+	Name string
+	Path string
+}
+
+// OvaConfig configures the exporter pod to bundle a generated OVF descriptor and every disk of a
+// VirtualMachine source into a single downloadable OVA archive.
+type OvaConfig struct {
+	// URI is the path this server serves the OVA bundle at.
+	URI string
+
+	// DescriptorFile is the path to the OVF descriptor XML generated by the controller and
+	// mounted into the pod.
+	DescriptorFile string
+
+	// Disks lists, in OVF declaration order, the disks to include in the bundle.
+	Disks []OvaDisk
+}
+
+// newOvaReader streams an OVA archive containing config.DescriptorFile and, converted to
+// streamOptimized VMDK, every disk in config.Disks. Unlike newTarReader and newVmdkReader, the
+// tar format requires each entry's size up front, so each disk is converted to a temporary file
+// first rather than streamed directly; the temporary file is removed as soon as it has been
+// copied into the archive.
+func newOvaReader(config OvaConfig) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := writeOva(tw, config)
+		if closeErr := tw.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+func writeOva(tw *tar.Writer, config OvaConfig) error {
+	if err := addFileToTar(tw, filepath.Base(config.DescriptorFile), config.DescriptorFile); err != nil {
+		return err
+	}
+	for _, disk := range config.Disks {
+		if err := addDiskToOva(tw, disk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFileToTar writes the contents of path into tw as an entry named name.
+func addFileToTar(tw *tar.Writer, name, path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: fi.Size(), Mode: 0644}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func addDiskToOva(tw *tar.Writer, disk OvaDisk) error {
+	vmdkPath, err := convertToVmdkFile(disk.Path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(vmdkPath)
+
+	return addFileToTar(tw, disk.Name, vmdkPath)
+}
+
+// convertToVmdkFile converts diskPath to a streamOptimized VMDK file on local storage, returning
+// its path. The caller is responsible for removing it once it is no longer needed.
+func convertToVmdkFile(diskPath string) (string, error) {
+	tmpFile, err := ioutil.TempFile("", "ova-disk-*.vmdk")
+	if err != nil {
+		return "", err
+	}
+	tmpFile.Close()
+
+	cmd := exec.Command("/usr/bin/qemu-img", "convert", "-O", "vmdk", "-o", "subformat=streamOptimized", diskPath, tmpFile.Name())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(tmpFile.Name())
+		log.Log.Reason(err).Errorf("qemu-img convert failed: %s", string(out))
+		return "", err
+	}
+	return tmpFile.Name(), nil
+}
+
+func ovaHandler(config OvaConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ovaReader, err := newOvaReader(config)
+		if err != nil {
+			log.Log.Reason(err).Error("error building OVA bundle")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer ovaReader.Close()
+		n, err := io.Copy(w, ovaReader)
+		if err != nil {
+			log.Log.Reason(err).Error("error writing response body")
+		}
+		log.Log.Infof("Wrote %d bytes\n", n)
+	})
+}
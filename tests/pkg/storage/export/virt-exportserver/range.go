@@ -0,0 +1,106 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rangeRequest describes a client's requested byte range for a streamed, and therefore
+// non-seekable, response body: start is where to begin writing from, and end, if hasEnd is true,
+// is the last byte (inclusive) to write through. Unlike http.ServeContent's range support, the
+// total size of the underlying content isn't known ahead of time, so a suffix range
+// ("bytes=-500", the last N bytes) can't be resolved and isn't supported.
+type rangeRequest struct {
+	start  int64
+	end    int64
+	hasEnd bool
+}
+
+// parseRangeRequest parses r's Range header for a single byte range of the form "bytes=N-" or
+// "bytes=N-M". It returns ok=false, so the caller should fall back to serving the full content
+// from the start, if the header is absent, malformed, requests multiple ranges, or is a suffix
+// range. If r also has an If-Range header, it is compared against lastModified (the validator
+// most resumable download clients send back what they received in a prior Last-Modified header);
+// a mismatch, or an If-Range with no lastModified to validate against, likewise falls back to the
+// full content, since the underlying artifact may have changed since the client's earlier attempt.
+func parseRangeRequest(r *http.Request, lastModified time.Time) (rangeRequest, bool) {
+	header := r.Header.Get("Range")
+	if header == "" {
+		return rangeRequest{}, false
+	}
+	if ifRange := r.Header.Get("If-Range"); ifRange != "" {
+		t, err := http.ParseTime(ifRange)
+		if err != nil || lastModified.IsZero() || !t.Equal(lastModified.Truncate(time.Second)) {
+			return rangeRequest{}, false
+		}
+	}
+
+	spec := strings.TrimPrefix(header, "bytes=")
+	if spec == header || strings.Contains(spec, ",") {
+		return rangeRequest{}, false
+	}
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return rangeRequest{}, false
+	}
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || start < 0 {
+		return rangeRequest{}, false
+	}
+	if parts[1] == "" {
+		return rangeRequest{start: start}, true
+	}
+	end, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || end < start {
+		return rangeRequest{}, false
+	}
+	return rangeRequest{start: start, end: end, hasEnd: true}, true
+}
+
+// serveRange discards the portion of content before rr.start, since a streamed reader can't be
+// seeked directly, then writes the requested range to w as a 206 Partial Content response. The
+// total content length is unknown up front, so Content-Range's instance-length is reported as
+// "*", which RFC 7233 allows for a satisfiable range whose full extent hasn't been determined.
+func serveRange(w http.ResponseWriter, content io.Reader, rr rangeRequest) (int64, error) {
+	if _, err := io.CopyN(io.Discard, content, rr.start); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			w.Header().Set("Content-Range", "bytes */*")
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if rr.hasEnd {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/*", rr.start, rr.end))
+		w.WriteHeader(http.StatusPartialContent)
+		return io.CopyN(w, content, rr.end-rr.start+1)
+	}
+
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-/*", rr.start))
+	w.WriteHeader(http.StatusPartialContent)
+	return io.Copy(w, content)
+}
@@ -0,0 +1,534 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"kubevirt.io/client-go/log"
+)
+
+// RegistryUploadConfig configures the exporter pod to additionally build each exported volume's
+// raw artifact into a containerDisk image and push it to an OCI registry, alongside still
+// serving it for HTTPS download.
+//
+// Only volumes with a RawURI are uploaded, for the same reason as S3UploadConfig: RawGzURI and
+// ArchiveURI are produced by a streaming pipeline with no fixed length, and a containerDisk
+// layer needs a known digest and size computed before it can be pushed.
+type RegistryUploadConfig struct {
+	// Repository is the registry repository to push exported volumes to. Each volume is pushed
+	// as its own image, named Repository/<volume name>.
+	Repository string
+	Tag        string
+
+	UsernameFile string
+	PasswordFile string
+}
+
+const (
+	registryUploadPending      = "Pending"
+	registryUploadInProgress   = "InProgress"
+	registryUploadComplete     = "Complete"
+	registryUploadFailed       = "Failed"
+	internalRegistryUploadPath = "/internal/registryupload"
+
+	defaultRegistryTag = "latest"
+
+	dockerManifestSchema2MediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	dockerContainerImageMediaType  = "application/vnd.docker.container.image.v1+json"
+	dockerLayerMediaType           = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+)
+
+// registryUploadStatus mirrors kubevirt.io/api/export/v1alpha1.VirtualMachineExportRegistryUploadStatus.
+// It is kept as a separate type rather than importing the API package, consistent with this
+// package's existing internal status types (s3UploadStatus, artifactMetadata), which the
+// controller translates into API types rather than the exporter pod depending on the API.
+type registryUploadStatus struct {
+	Phase         string `json:"phase"`
+	BytesUploaded int64  `json:"bytesUploaded,omitempty"`
+	ImageUrl      string `json:"imageUrl,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// registryUploadStatusStore tracks registryUploadStatus by volume name.
+type registryUploadStatusStore struct {
+	mu       sync.Mutex
+	byVolume map[string]registryUploadStatus
+}
+
+func newRegistryUploadStatusStore() *registryUploadStatusStore {
+	return &registryUploadStatusStore{byVolume: make(map[string]registryUploadStatus)}
+}
+
+func (s *registryUploadStatusStore) set(name string, status registryUploadStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byVolume[name] = status
+}
+
+func (s *registryUploadStatusStore) snapshot() map[string]registryUploadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]registryUploadStatus, len(s.byVolume))
+	for name, status := range s.byVolume {
+		result[name] = status
+	}
+	return result
+}
+
+func registryUploadHandler(store *registryUploadStatusStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(store.snapshot()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+// registryProgressReader wraps an io.Reader to report every byte read from it to a
+// registryUploadStatusStore, so that an in-progress upload's BytesUploaded can be polled before
+// it completes.
+type registryProgressReader struct {
+	io.Reader
+	name  string
+	store *registryUploadStatusStore
+	n     int64
+}
+
+func (r *registryProgressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.n += int64(n)
+		r.store.set(r.name, registryUploadStatus{Phase: registryUploadInProgress, BytesUploaded: r.n})
+	}
+	return n, err
+}
+
+// computeRegistryUploads builds a containerDisk image from the raw artifact of every volume that
+// has one, and pushes it to config's OCI registry, recording progress and the outcome of each
+// push in store. It returns once every push has finished, successfully or not; callers are
+// expected to run it in a goroutine and poll store rather than wait for it.
+func computeRegistryUploads(config *RegistryUploadConfig, volumes []VolumeInfo, store *registryUploadStatusStore) {
+	username, err := getToken(config.UsernameFile)
+	if err != nil {
+		log.Log.Reason(err).Error("error reading registry username")
+		return
+	}
+	password, err := getToken(config.PasswordFile)
+	if err != nil {
+		log.Log.Reason(err).Error("error reading registry password")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, vi := range volumes {
+		if vi.RawURI == "" {
+			continue
+		}
+		vi := vi
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uploadVolumeToRegistry(config, username, password, vi, store)
+		}()
+	}
+	wg.Wait()
+}
+
+func uploadVolumeToRegistry(config *RegistryUploadConfig, username, password string, vi VolumeInfo, store *registryUploadStatusStore) {
+	store.set(vi.Name, registryUploadStatus{Phase: registryUploadInProgress})
+
+	f, err := openDiskImage(vi.Path)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error opening %s to upload volume %s to registry", vi.Path, vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+	defer f.Close()
+
+	layerFile, layerDigest, layerSize, diffID, err := buildContainerDiskLayer(f)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error building containerDisk layer for volume %s", vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+	defer os.Remove(layerFile.Name())
+	defer layerFile.Close()
+
+	configBlob, err := json.Marshal(containerImageConfig{
+		Architecture: "amd64",
+		OS:           "linux",
+		RootFS:       containerImageRootFS{Type: "layers", DiffIDs: []string{diffID}},
+	})
+	if err != nil {
+		log.Log.Reason(err).Errorf("error building image config for volume %s", vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+	configDigest := "sha256:" + hashHex(string(configBlob))
+
+	tag := config.Tag
+	if tag == "" {
+		tag = defaultRegistryTag
+	}
+	repository := fmt.Sprintf("%s/%s", config.Repository, vi.Name)
+	client := newRegistryClient(repository, username, password)
+
+	if err := client.authenticate(); err != nil {
+		log.Log.Reason(err).Errorf("error authenticating to registry to upload volume %s", vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+
+	if err := client.pushBlob(configDigest, int64(len(configBlob)), bytes.NewReader(configBlob)); err != nil {
+		log.Log.Reason(err).Errorf("error pushing image config for volume %s", vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+
+	if err := client.pushBlob(layerDigest, layerSize, &registryProgressReader{Reader: layerFile, name: vi.Name, store: store}); err != nil {
+		log.Log.Reason(err).Errorf("error pushing containerDisk layer for volume %s", vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+
+	err = client.pushManifest(tag,
+		registryManifestDescriptor{MediaType: dockerContainerImageMediaType, Size: int64(len(configBlob)), Digest: configDigest},
+		registryManifestDescriptor{MediaType: dockerLayerMediaType, Size: layerSize, Digest: layerDigest},
+	)
+	if err != nil {
+		log.Log.Reason(err).Errorf("error pushing manifest for volume %s", vi.Name)
+		store.set(vi.Name, registryUploadStatus{Phase: registryUploadFailed, Error: err.Error()})
+		return
+	}
+
+	store.set(vi.Name, registryUploadStatus{
+		Phase:         registryUploadComplete,
+		BytesUploaded: layerSize,
+		ImageUrl:      fmt.Sprintf("%s:%s", repository, tag),
+	})
+}
+
+// buildContainerDiskLayer packs diskImage into a single-file tar archive under disk/disk.img,
+// the layout containerDisk images use, gzip-compresses it into a spooled temporary file, and
+// returns that file, seeked to its start, along with the compressed layer's digest and size and
+// the diffID (the sha256 of the uncompressed tar, as OCI/Docker image configs require) needed to
+// push it. The caller is responsible for closing and removing the returned file.
+func buildContainerDiskLayer(diskImage *os.File) (layerFile *os.File, digest string, size int64, diffID string, err error) {
+	fi, err := diskImage.Stat()
+	if err != nil {
+		return nil, "", 0, "", err
+	}
+
+	tmp, err := ioutil.TempFile("", "containerdisk-layer-*.tar.gz")
+	if err != nil {
+		return nil, "", 0, "", err
+	}
+
+	compressedHash := sha256.New()
+	uncompressedHash := sha256.New()
+
+	gzWriter := gzip.NewWriter(io.MultiWriter(tmp, compressedHash))
+	tarWriter := tar.NewWriter(io.MultiWriter(gzWriter, uncompressedHash))
+
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "disk/disk.img", Mode: 0644, Size: fi.Size()}); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, "", err
+	}
+	if _, err := io.Copy(tarWriter, diskImage); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, "", err
+	}
+	if err := tarWriter.Close(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, "", err
+	}
+	if err := gzWriter.Close(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, "", err
+	}
+
+	tmpInfo, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, "", err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, "", err
+	}
+
+	return tmp, "sha256:" + hex.EncodeToString(compressedHash.Sum(nil)), tmpInfo.Size(), "sha256:" + hex.EncodeToString(uncompressedHash.Sum(nil)), nil
+}
+
+// containerImageConfig is the minimal subset of the OCI/Docker container image config JSON
+// needed to describe a containerDisk image, whose single layer isn't actually run as a
+// container.
+type containerImageConfig struct {
+	Architecture string               `json:"architecture"`
+	OS           string               `json:"os"`
+	RootFS       containerImageRootFS `json:"rootfs"`
+}
+
+type containerImageRootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// registryManifestDescriptor is an entry in a Docker v2 image manifest referring to a blob.
+type registryManifestDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Size      int64  `json:"size"`
+	Digest    string `json:"digest"`
+}
+
+type registryManifest struct {
+	SchemaVersion int                          `json:"schemaVersion"`
+	MediaType     string                       `json:"mediaType"`
+	Config        registryManifestDescriptor   `json:"config"`
+	Layers        []registryManifestDescriptor `json:"layers"`
+}
+
+// registryClient is a minimal Docker Registry HTTP API v2 client, handling just enough of the
+// protocol to push a single-layer image: monolithic blob upload and manifest push, authenticated
+// with HTTP Basic auth or, if the registry requires it, the Bearer token flow described at
+// https://docs.docker.com/registry/spec/auth/token/.
+type registryClient struct {
+	registryURL string
+	repository  string
+
+	username, password string
+	bearerToken        string
+}
+
+func newRegistryClient(repository, username, password string) *registryClient {
+	host := repository
+	path := ""
+	if idx := strings.Index(repository, "/"); idx >= 0 {
+		host = repository[:idx]
+		path = repository[idx+1:]
+	}
+	return &registryClient{
+		registryURL: "https://" + host,
+		repository:  path,
+		username:    username,
+		password:    password,
+	}
+}
+
+// authenticate probes the registry's base endpoint and, if it challenges with a Bearer token
+// requirement, fetches one up front, so later requests don't need to buffer and resend their
+// bodies after a 401.
+func (c *registryClient) authenticate() error {
+	req, err := http.NewRequest(http.MethodGet, c.registryURL+"/v2/", nil)
+	if err != nil {
+		return err
+	}
+	c.setAuth(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return nil
+	}
+
+	token, err := c.fetchBearerToken(resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return err
+	}
+	c.bearerToken = token
+	return nil
+}
+
+var bearerChallengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func (c *registryClient) fetchBearerToken(challenge string) (string, error) {
+	var realm, service, scope string
+	for _, m := range bearerChallengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		switch m[1] {
+		case "realm":
+			realm = m[2]
+		case "service":
+			service = m[2]
+		case "scope":
+			scope = m[2]
+		}
+	}
+	if realm == "" {
+		return "", fmt.Errorf("registry auth challenge is missing a realm: %q", challenge)
+	}
+
+	tokenUrl, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	q := tokenUrl.Query()
+	if service != "" {
+		q.Set("service", service)
+	}
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	tokenUrl.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, tokenUrl.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("registry token request to %s failed with status %s", realm, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+func (c *registryClient) setAuth(req *http.Request) {
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
+
+// pushBlob uploads body, of the given size, as a monolithic blob identified by digest, using the
+// two-step upload-then-PUT flow described at
+// https://docs.docker.com/registry/spec/api/#pushing-an-image.
+func (c *registryClient) pushBlob(digest string, size int64, body io.Reader) error {
+	initReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v2/%s/blobs/uploads/", c.registryURL, c.repository), nil)
+	if err != nil {
+		return err
+	}
+	c.setAuth(initReq)
+
+	initResp, err := http.DefaultClient.Do(initReq)
+	if err != nil {
+		return err
+	}
+	defer initResp.Body.Close()
+	if initResp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("registry blob upload initiation failed with status %s", initResp.Status)
+	}
+
+	uploadUrl, err := url.Parse(initResp.Header.Get("Location"))
+	if err != nil {
+		return err
+	}
+	if !uploadUrl.IsAbs() {
+		base, err := url.Parse(c.registryURL)
+		if err != nil {
+			return err
+		}
+		uploadUrl = base.ResolveReference(uploadUrl)
+	}
+	q := uploadUrl.Query()
+	q.Set("digest", digest)
+	uploadUrl.RawQuery = q.Encode()
+
+	putReq, err := http.NewRequest(http.MethodPut, uploadUrl.String(), body)
+	if err != nil {
+		return err
+	}
+	putReq.ContentLength = size
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+	c.setAuth(putReq)
+
+	putResp, err := http.DefaultClient.Do(putReq)
+	if err != nil {
+		return err
+	}
+	defer putResp.Body.Close()
+	if putResp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("registry blob upload of %s failed with status %s", digest, putResp.Status)
+	}
+	return nil
+}
+
+func (c *registryClient) pushManifest(tag string, config, layer registryManifestDescriptor) error {
+	body, err := json.Marshal(registryManifest{
+		SchemaVersion: 2,
+		MediaType:     dockerManifestSchema2MediaType,
+		Config:        config,
+		Layers:        []registryManifestDescriptor{layer},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v2/%s/manifests/%s", c.registryURL, c.repository, tag), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", dockerManifestSchema2MediaType)
+	c.setAuth(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("registry manifest push of %s:%s failed with status %s", c.repository, tag, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2022 Red Hat, Inc.
+ *
+ */
+
+package virtexportserver
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// sparseReader wraps an *os.File and, for holes reported by the filesystem via SEEK_HOLE and
+// SEEK_DATA, returns zero-filled bytes without actually reading them, since a hole is defined to
+// read back as zeros. This skips the disk I/O (and, for gzipHandler, the compression work) that
+// would otherwise be spent on the zero regions of a mostly-empty sparse disk image.
+type sparseReader struct {
+	f    *os.File
+	size int64
+	pos  int64
+
+	// boundary is the offset where the region containing pos, described by inHole, ends.
+	boundary      int64
+	boundaryKnown bool
+	inHole        bool
+}
+
+// newSparseReader returns an io.Reader that streams f from the beginning, skipping real reads for
+// any holes the filesystem reports via SEEK_HOLE/SEEK_DATA. If f's filesystem doesn't support
+// those (e.g. it isn't backed by a real block device), it falls back to reading f directly, so
+// callers don't need a separate non-sparse code path.
+func newSparseReader(f *os.File) (io.Reader, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, seekData); err != nil {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return &sparseReader{f: f, size: fi.Size()}, nil
+}
+
+func (r *sparseReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	if !r.boundaryKnown || r.pos >= r.boundary {
+		if err := r.findNextBoundary(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := r.boundary - r.pos
+	if int64(len(p)) < n {
+		n = int64(len(p))
+	}
+
+	if r.inHole {
+		for i := int64(0); i < n; i++ {
+			p[i] = 0
+		}
+		r.pos += n
+		return int(n), nil
+	}
+
+	read, err := r.f.Read(p[:n])
+	r.pos += int64(read)
+	return read, err
+}
+
+// findNextBoundary determines whether r.pos falls within a hole or real data, and how far that
+// region extends, leaving the underlying file descriptor positioned at r.pos so a subsequent real
+// Read picks up from the right place.
+func (r *sparseReader) findNextBoundary() error {
+	dataStart, err := r.f.Seek(r.pos, seekData)
+	if err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			// No more data after r.pos; the rest of the file is a hole.
+			r.inHole = true
+			r.boundary = r.size
+			r.boundaryKnown = true
+			return nil
+		}
+		return err
+	}
+
+	if dataStart > r.pos {
+		r.inHole = true
+		r.boundary = dataStart
+		r.boundaryKnown = true
+		return nil
+	}
+
+	holeStart, err := r.f.Seek(dataStart, seekHole)
+	if err != nil {
+		return err
+	}
+	if _, err := r.f.Seek(r.pos, io.SeekStart); err != nil {
+		return err
+	}
+
+	r.inHole = false
+	r.boundary = holeStart
+	r.boundaryKnown = true
+	return nil
+}
+
+const (
+	seekData = unix.SEEK_DATA
+	seekHole = unix.SEEK_HOLE
+)
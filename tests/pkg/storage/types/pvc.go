@@ -20,6 +20,7 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -35,6 +36,15 @@ import (
 
 const MiB = 1024 * 1024
 
+const (
+	ConfigName        = "config"
+	DefaultFSOverhead = cdiv1.Percent("0.055")
+	FSOverheadMsg     = "Using default 5.5%% filesystem overhead for pvc size"
+)
+
+var ErrFailedToFindCdi error = errors.New("No CDI instances found")
+var ErrMultipleCdiInstances error = errors.New("Detected more than one CDI instance")
+
 func IsPVCBlockFromStore(store cache.Store, namespace string, claimName string) (pvc *k8sv1.PersistentVolumeClaim, exists bool, isBlockDevice bool, err error) {
 	obj, exists, err := store.GetByKey(namespace + "/" + claimName)
 	if err != nil || !exists {
@@ -116,18 +126,37 @@ func VirtVolumesToPVCMap(volumes []*virtv1.Volume, pvcStore cache.Store, namespa
 	return volumeNamesPVCMap, nil
 }
 
-func GetFilesystemOverhead(volumeMode *k8sv1.PersistentVolumeMode, storageClass *string, cdiConfig *cdiv1.CDIConfig) cdiv1.Percent {
+// GetFilesystemOverhead returns the filesystem overhead to apply for the given volume mode and storage class. A
+// KubeVirtConfiguration override, if provided and it has an entry matching the storage class (or a global value if
+// the storage class has none), takes precedence over the value reported by CDI.
+func GetFilesystemOverhead(volumeMode *k8sv1.PersistentVolumeMode, storageClass *string, cdiConfig *cdiv1.CDIConfig, kvFSOverhead *cdiv1.FilesystemOverhead) cdiv1.Percent {
 	if IsPVCBlock(volumeMode) {
 		return "0"
 	}
-	if storageClass == nil {
-		return cdiConfig.Status.FilesystemOverhead.Global
+	if overhead, ok := lookupFilesystemOverhead(storageClass, kvFSOverhead); ok {
+		return overhead
+	}
+	if storageClass != nil {
+		if overhead, ok := cdiConfig.Status.FilesystemOverhead.StorageClass[*storageClass]; ok {
+			return overhead
+		}
+	}
+	return cdiConfig.Status.FilesystemOverhead.Global
+}
+
+func lookupFilesystemOverhead(storageClass *string, overhead *cdiv1.FilesystemOverhead) (cdiv1.Percent, bool) {
+	if overhead == nil {
+		return "", false
+	}
+	if storageClass != nil {
+		if fsOverhead, ok := overhead.StorageClass[*storageClass]; ok {
+			return fsOverhead, true
+		}
 	}
-	fsOverhead, ok := cdiConfig.Status.FilesystemOverhead.StorageClass[*storageClass]
-	if !ok {
-		return cdiConfig.Status.FilesystemOverhead.Global
+	if overhead.Global != "" {
+		return overhead.Global, true
 	}
-	return fsOverhead
+	return "", false
 }
 
 func roundUpToUnit(size, unit float64) float64 {
@@ -151,7 +180,34 @@ func GetSizeIncludingGivenOverhead(size *resource.Quantity, overhead cdiv1.Perce
 	return resource.NewQuantity(int64(totalSize), size.Format), nil
 }
 
-func GetSizeIncludingFSOverhead(size *resource.Quantity, storageClass *string, volumeMode *k8sv1.PersistentVolumeMode, cdiConfig *cdiv1.CDIConfig) (*resource.Quantity, error) {
-	cdiFSOverhead := GetFilesystemOverhead(volumeMode, storageClass, cdiConfig)
-	return GetSizeIncludingGivenOverhead(size, cdiFSOverhead)
+func GetSizeIncludingDefaultFSOverhead(size *resource.Quantity) (*resource.Quantity, error) {
+	return GetSizeIncludingGivenOverhead(size, DefaultFSOverhead)
+}
+
+func GetSizeIncludingFSOverhead(size *resource.Quantity, storageClass *string, volumeMode *k8sv1.PersistentVolumeMode, cdiConfig *cdiv1.CDIConfig, kvFSOverhead *cdiv1.FilesystemOverhead) (*resource.Quantity, error) {
+	fsOverhead := GetFilesystemOverhead(volumeMode, storageClass, cdiConfig, kvFSOverhead)
+	return GetSizeIncludingGivenOverhead(size, fsOverhead)
+}
+
+// GetDefaultVolumeAndAccessMode returns the volume mode and access mode recommended by the given
+// StorageProfile's first claim property set, so that callers rendering a PVC don't have to make
+// users hand-specify accessModes/volumeMode for every storage backend. It falls back to
+// Filesystem/ReadWriteOnce when the StorageProfile has no recommendation, e.g. because CDI hasn't
+// detected the storage class's capabilities yet.
+func GetDefaultVolumeAndAccessMode(storageProfile *cdiv1.StorageProfile) (*k8sv1.PersistentVolumeMode, k8sv1.PersistentVolumeAccessMode) {
+	defaultVolumeMode := k8sv1.PersistentVolumeFilesystem
+	if storageProfile == nil || len(storageProfile.Status.ClaimPropertySets) == 0 {
+		return &defaultVolumeMode, k8sv1.ReadWriteOnce
+	}
+
+	claimPropertySet := storageProfile.Status.ClaimPropertySets[0]
+	volumeMode := &defaultVolumeMode
+	if claimPropertySet.VolumeMode != nil {
+		volumeMode = claimPropertySet.VolumeMode
+	}
+	accessMode := k8sv1.ReadWriteOnce
+	if len(claimPropertySet.AccessModes) > 0 {
+		accessMode = claimPropertySet.AccessModes[0]
+	}
+	return volumeMode, accessMode
 }
@@ -25,6 +25,8 @@ import (
 	kubev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
 
 var _ = Describe("PVC utils test", func() {
@@ -98,4 +100,42 @@ var _ = Describe("PVC utils test", func() {
 		})
 	})
 
+	Context("GetFilesystemOverhead", func() {
+		storageClass := "my-storage-class"
+		cdiConfig := &cdiv1.CDIConfig{
+			Status: cdiv1.CDIConfigStatus{
+				FilesystemOverhead: &cdiv1.FilesystemOverhead{
+					Global:       "0.055",
+					StorageClass: map[string]cdiv1.Percent{storageClass: "0.1"},
+				},
+			},
+		}
+
+		It("should return 0 for a block volume regardless of any override", func() {
+			kvOverhead := &cdiv1.FilesystemOverhead{Global: "0.2"}
+			overhead := GetFilesystemOverhead(&modeBlock, &storageClass, cdiConfig, kvOverhead)
+			Expect(overhead).To(Equal(cdiv1.Percent("0")))
+		})
+
+		It("should fall back to the value reported by CDI when no KubeVirtConfiguration override is set", func() {
+			overhead := GetFilesystemOverhead(&modeFile, &storageClass, cdiConfig, nil)
+			Expect(overhead).To(Equal(cdiv1.Percent("0.1")))
+		})
+
+		It("should prefer a KubeVirtConfiguration override matching the storage class over the value reported by CDI", func() {
+			kvOverhead := &cdiv1.FilesystemOverhead{
+				Global:       "0.2",
+				StorageClass: map[string]cdiv1.Percent{storageClass: "0.3"},
+			}
+			overhead := GetFilesystemOverhead(&modeFile, &storageClass, cdiConfig, kvOverhead)
+			Expect(overhead).To(Equal(cdiv1.Percent("0.3")))
+		})
+
+		It("should fall back to the KubeVirtConfiguration global override when the storage class has no entry", func() {
+			kvOverhead := &cdiv1.FilesystemOverhead{Global: "0.2"}
+			overhead := GetFilesystemOverhead(&modeFile, &storageClass, cdiConfig, kvOverhead)
+			Expect(overhead).To(Equal(cdiv1.Percent("0.2")))
+		})
+	})
+
 })
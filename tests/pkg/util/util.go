@@ -1,7 +1,9 @@
 package util
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 
@@ -231,3 +233,19 @@ func CalcExpectedMemoryDumpSize(vmi *v1.VirtualMachineInstance) *resource.Quanti
 	expectedPvcSize.Add(*vmiMemoryReq)
 	return expectedPvcSize
 }
+
+const secureRandomStringAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// GenerateSecureRandomString returns a cryptographically secure random string of the given length,
+// suitable for use as a token.
+func GenerateSecureRandomString(length int) (string, error) {
+	result := make([]byte, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(secureRandomStringAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		result[i] = secureRandomStringAlphabet[n.Int64()]
+	}
+	return string(result), nil
+}
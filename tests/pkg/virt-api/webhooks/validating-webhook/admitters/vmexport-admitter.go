@@ -35,9 +35,12 @@ import (
 )
 
 const (
-	pvc            = "PersistentVolumeClaim"
-	vmSnapshotKind = "VirtualMachineSnapshot"
-	vmKind         = "VirtualMachine"
+	pvc                = "PersistentVolumeClaim"
+	vmSnapshotKind     = "VirtualMachineSnapshot"
+	vmKind             = "VirtualMachine"
+	dataVolumeKind     = "DataVolume"
+	volumeSnapshotKind = "VolumeSnapshot"
+	dataSourceKind     = "DataSource"
 )
 
 // VMExportAdmitter validates VirtualMachineExports
@@ -92,6 +95,21 @@ func (admitter *VMExportAdmitter) Admit(ar *admissionv1.AdmissionReview) *admiss
 			if err != nil {
 				return webhookutils.ToAdmissionResponseError(err)
 			}
+		case dataVolumeKind:
+			causes, err = admitter.validateDataVolume(sourceField.Child("name"), ar.Request.Namespace, vmExport.Spec.Source.Name)
+			if err != nil {
+				return webhookutils.ToAdmissionResponseError(err)
+			}
+		case volumeSnapshotKind:
+			causes, err = admitter.validateVolumeSnapshot(sourceField.Child("name"), ar.Request.Namespace, vmExport.Spec.Source.Name)
+			if err != nil {
+				return webhookutils.ToAdmissionResponseError(err)
+			}
+		case dataSourceKind:
+			causes, err = admitter.validateDataSource(sourceField.Child("name"), ar.Request.Namespace, vmExport.Spec.Source.Name)
+			if err != nil {
+				return webhookutils.ToAdmissionResponseError(err)
+			}
 		default:
 			causes = []metav1.StatusCause{
 				{
@@ -102,6 +120,8 @@ func (admitter *VMExportAdmitter) Admit(ar *admissionv1.AdmissionReview) *admiss
 			}
 		}
 
+		causes = append(causes, admitter.validateTTL(k8sfield.NewPath("spec", "ttlDuration"), vmExport.Spec.TTLDuration)...)
+
 	case admissionv1.Update:
 		prevObj := &exportv1.VirtualMachineExport{}
 		err = json.Unmarshal(ar.Request.OldObject.Raw, prevObj)
@@ -160,6 +180,64 @@ func (admitter *VMExportAdmitter) validateVMSnapshot(field *k8sfield.Path, names
 	return []metav1.StatusCause{}, nil
 }
 
+func (admitter *VMExportAdmitter) validateDataVolume(field *k8sfield.Path, namespace, name string) ([]metav1.StatusCause, error) {
+	if name == "" {
+		return []metav1.StatusCause{
+			{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "DataVolume name must not be empty",
+				Field:   field.String(),
+			},
+		}, nil
+	}
+
+	return []metav1.StatusCause{}, nil
+}
+
+func (admitter *VMExportAdmitter) validateVolumeSnapshot(field *k8sfield.Path, namespace, name string) ([]metav1.StatusCause, error) {
+	if name == "" {
+		return []metav1.StatusCause{
+			{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "VolumeSnapshot name must not be empty",
+				Field:   field.String(),
+			},
+		}, nil
+	}
+
+	return []metav1.StatusCause{}, nil
+}
+
+func (admitter *VMExportAdmitter) validateDataSource(field *k8sfield.Path, namespace, name string) ([]metav1.StatusCause, error) {
+	if name == "" {
+		return []metav1.StatusCause{
+			{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "DataSource name must not be empty",
+				Field:   field.String(),
+			},
+		}, nil
+	}
+
+	return []metav1.StatusCause{}, nil
+}
+
+func (admitter *VMExportAdmitter) validateTTL(field *k8sfield.Path, ttl *metav1.Duration) []metav1.StatusCause {
+	if ttl == nil {
+		return nil
+	}
+	if ttl.Duration <= 0 {
+		return []metav1.StatusCause{
+			{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "ttlDuration must be greater than 0",
+				Field:   field.String(),
+			},
+		}
+	}
+	return nil
+}
+
 func (admitter *VMExportAdmitter) validateVM(field *k8sfield.Path, namespace, name string) ([]metav1.StatusCause, error) {
 	if name == "" {
 		return []metav1.StatusCause{
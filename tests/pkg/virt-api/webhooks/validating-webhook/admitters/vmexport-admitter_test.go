@@ -41,6 +41,9 @@ import (
 var _ = Describe("Validating VirtualMachineExport Admitter", func() {
 	apiGroup := "v1"
 	kubevirtApiGroup := "kubevirt.io"
+	cdiApiGroup := "cdi.kubevirt.io"
+	snapshotApiGroup := "snapshot.storage.k8s.io"
+	dataSourceApiGroup := "cdi.kubevirt.io"
 
 	config, _, kvInformer := testutils.NewFakeClusterConfigUsingKVConfig(&v1.KubeVirtConfiguration{})
 
@@ -125,6 +128,30 @@ var _ = Describe("Validating VirtualMachineExport Admitter", func() {
 			}
 		}
 
+		createBlankDataVolumeObjectRef := func() corev1.TypedLocalObjectReference {
+			return corev1.TypedLocalObjectReference{
+				APIGroup: &cdiApiGroup,
+				Kind:     dataVolumeKind,
+				Name:     "",
+			}
+		}
+
+		createBlankVolumeSnapshotObjectRef := func() corev1.TypedLocalObjectReference {
+			return corev1.TypedLocalObjectReference{
+				APIGroup: &snapshotApiGroup,
+				Kind:     volumeSnapshotKind,
+				Name:     "",
+			}
+		}
+
+		createBlankDataSourceObjectRef := func() corev1.TypedLocalObjectReference {
+			return corev1.TypedLocalObjectReference{
+				APIGroup: &dataSourceApiGroup,
+				Kind:     dataSourceKind,
+				Name:     "",
+			}
+		}
+
 		DescribeTable("it should reject blank names", func(objectRefFunc func() corev1.TypedLocalObjectReference, errorString string) {
 			export := &exportv1.VirtualMachineExport{
 				Spec: exportv1.VirtualMachineExportSpec{
@@ -139,6 +166,9 @@ var _ = Describe("Validating VirtualMachineExport Admitter", func() {
 			Entry("persistent volume claim", createBlankPVCObjectRef, "PVC name must not be empty"),
 			Entry("virtual machine snapshot", createBlankVMSnapshotObjectRef, "VMSnapshot name must not be empty"),
 			Entry("virtual machine", createBlankVMObjectRef, "Virtual Machine name must not be empty"),
+			Entry("data volume", createBlankDataVolumeObjectRef, "DataVolume name must not be empty"),
+			Entry("volume snapshot", createBlankVolumeSnapshotObjectRef, "VolumeSnapshot name must not be empty"),
+			Entry("data source", createBlankDataSourceObjectRef, "DataSource name must not be empty"),
 		)
 
 		It("should reject unknown kind", func() {
@@ -233,6 +263,9 @@ var _ = Describe("Validating VirtualMachineExport Admitter", func() {
 		},
 			Entry("persistent volume claim", "v1", pvc),
 			Entry("virtual machine snapshot", kubevirtApiGroup, vmSnapshotKind),
+			Entry("data volume", cdiApiGroup, dataVolumeKind),
+			Entry("volume snapshot", snapshotApiGroup, volumeSnapshotKind),
+			Entry("data source", dataSourceApiGroup, dataSourceKind),
 		)
 
 	})
@@ -136,6 +136,55 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 		Expect(resp.Result.Details.Causes).To(HaveLen(1))
 		Expect(resp.Result.Message).To(ContainSubstring("no memory requested"))
 	})
+	It("should warn that SR-IOV interfaces still require root when NonRoot is enabled", func() {
+		enableFeatureGate(virtconfig.NonRoot)
+		vmi := api.NewMinimalVMI("testvmi")
+		vmi.Spec.Domain.Devices.Interfaces = append(vmi.Spec.Domain.Devices.Interfaces, v1.Interface{
+			Name:                   "sriov-net",
+			InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{}},
+		})
+		vmi.Spec.Networks = append(vmi.Spec.Networks, v1.Network{
+			Name:          "sriov-net",
+			NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "sriov-net"}},
+		})
+		vmiBytes, _ := json.Marshal(&vmi)
+
+		ar := &admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Resource: webhooks.VirtualMachineInstanceGroupVersionResource,
+				Object: runtime.RawExtension{
+					Raw: vmiBytes,
+				},
+			},
+		}
+		resp := vmiCreateAdmitter.Admit(ar)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(ContainElement(ContainSubstring("SR-IOV")))
+	})
+	It("should not warn about root-required features when NonRoot is disabled", func() {
+		vmi := api.NewMinimalVMI("testvmi")
+		vmi.Spec.Domain.Devices.Interfaces = append(vmi.Spec.Domain.Devices.Interfaces, v1.Interface{
+			Name:                   "sriov-net",
+			InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{}},
+		})
+		vmi.Spec.Networks = append(vmi.Spec.Networks, v1.Network{
+			Name:          "sriov-net",
+			NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "sriov-net"}},
+		})
+		vmiBytes, _ := json.Marshal(&vmi)
+
+		ar := &admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Resource: webhooks.VirtualMachineInstanceGroupVersionResource,
+				Object: runtime.RawExtension{
+					Raw: vmiBytes,
+				},
+			},
+		}
+		resp := vmiCreateAdmitter.Admit(ar)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(BeEmpty())
+	})
 
 	DescribeTable("path validation should fail", func(path string) {
 		Expect(validatePath(k8sfield.NewPath("fake"), path)).To(HaveLen(1))
@@ -2094,6 +2143,64 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
 			Expect(causes).To(BeEmpty())
 		})
+		It("should reject a custom selinuxContext when feature gate is disabled", func() {
+			vmi := api.NewMinimalVMI("testvm")
+			vmi.Spec.SelinuxContext = "system_u:object_r:container_file_t:s0"
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.selinuxContext"))
+		})
+		It("should allow a custom selinuxContext when feature gate is enabled", func() {
+			enableFeatureGate(virtconfig.SELinuxCustomTypeGate)
+			vmi := api.NewMinimalVMI("testvm")
+			vmi.Spec.SelinuxContext = "system_u:object_r:container_file_t:s0"
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+		It("should reject a localhost seccompProfile without a localhostProfile name", func() {
+			vmi := api.NewMinimalVMI("testvm")
+			vmi.Spec.SeccompProfile = &k8sv1.SeccompProfile{
+				Type: k8sv1.SeccompProfileTypeLocalhost,
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.seccompProfile.localhostProfile"))
+		})
+		It("should reject a localhost seccompProfile not installed by KubeVirt", func() {
+			vmi := api.NewMinimalVMI("testvm")
+			profile := "some-other-profile.json"
+			vmi.Spec.SeccompProfile = &k8sv1.SeccompProfile{
+				Type:             k8sv1.SeccompProfileTypeLocalhost,
+				LocalhostProfile: &profile,
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.seccompProfile.localhostProfile"))
+		})
+		It("should allow a localhost seccompProfile installed by KubeVirt", func() {
+			vmi := api.NewMinimalVMI("testvm")
+			profile := "kubevirt/virt-launcher-default.json"
+			vmi.Spec.SeccompProfile = &k8sv1.SeccompProfile{
+				Type:             k8sv1.SeccompProfileTypeLocalhost,
+				LocalhostProfile: &profile,
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+		It("should allow a RuntimeDefault seccompProfile", func() {
+			vmi := api.NewMinimalVMI("testvm")
+			vmi.Spec.SeccompProfile = &k8sv1.SeccompProfile{
+				Type: k8sv1.SeccompProfileTypeRuntimeDefault,
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
 		It("should accept legacy GPU devices if PermittedHostDevices aren't set", func() {
 			kvConfig := kv.DeepCopy()
 			kvConfig.Spec.Configuration.DeveloperConfiguration.FeatureGates = []string{virtconfig.GPUGate}
@@ -3027,6 +3134,10 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 				Name: "testdisk2",
 				IO:   "unsupported",
 			})
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name: "testdisk3",
+				IO:   v1.IOUring,
+			})
 
 			causes := validateDisks(k8sfield.NewPath("fake"), vmi.Spec.Domain.Devices.Disks)
 			Expect(causes).To(HaveLen(1))
@@ -3059,6 +3170,18 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Entry("writeback", v1.CacheWriteBack),
 		)
 
+		It("should reject a shareable disk using the writeback cache mode", func() {
+			vmi := api.NewMinimalVMI("testvmi")
+			shareable := true
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name: "testdisk", Cache: v1.CacheWriteBack, Shareable: &shareable, DiskDevice: v1.DiskDevice{
+					Disk: &v1.DiskTarget{}}})
+
+			causes := validateDisks(k8sfield.NewPath("fake"), vmi.Spec.Domain.Devices.Disks)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake[0].cache"))
+		})
+
 		It("should reject invalid SN characters", func() {
 			vmi := api.NewMinimalVMI("testvmi")
 			order := uint(1)
@@ -48,6 +48,20 @@ const (
 	NonRoot                    = "NonRoot"
 	ClusterProfiler            = "ClusterProfiler"
 	WorkloadEncryptionSEV      = "WorkloadEncryptionSEV"
+	// ContainerDiskCacheGate forces containerDisk and kernel boot images to be pulled with the
+	// IfNotPresent policy, so that nodes reuse the image layers already cached locally by the
+	// container runtime instead of re-pulling and re-extracting identical images for every VMI.
+	ContainerDiskCacheGate = "ContainerDiskCache"
+	// SELinuxCustomTypeGate allows VirtualMachineInstances to request a custom SELinux type
+	// for their compute container via spec.SelinuxContext, overriding the cluster-wide default.
+	SELinuxCustomTypeGate = "SELinuxCustomType"
+	// UserNamespacesGate reserves the config surface for running virt-launcher pods in a Linux
+	// user namespace, so that a qemu escape lands in an unprivileged host UID/GID range instead
+	// of on the node's real root. The vendored Kubernetes client in this tree predates
+	// PodSpec.HostUsers, so virt-handler can't yet request a user-namespaced pod from the
+	// kubelet; idmapped volume mounts (virt-chroot) and device ownership handling (the device
+	// manager) are expected to gate on this once that plumbing lands.
+	UserNamespacesGate = "UserNamespaces"
 )
 
 var deprecatedFeatureGates = [...]string{
@@ -167,3 +181,15 @@ func (config *ClusterConfig) ClusterProfilerEnabled() bool {
 func (config *ClusterConfig) WorkloadEncryptionSEVEnabled() bool {
 	return config.isFeatureGateEnabled(WorkloadEncryptionSEV)
 }
+
+func (config *ClusterConfig) ContainerDiskCacheEnabled() bool {
+	return config.isFeatureGateEnabled(ContainerDiskCacheGate)
+}
+
+func (config *ClusterConfig) SELinuxCustomTypeEnabled() bool {
+	return config.isFeatureGateEnabled(SELinuxCustomTypeGate)
+}
+
+func (config *ClusterConfig) UserNamespacesEnabled() bool {
+	return config.isFeatureGateEnabled(UserNamespacesGate)
+}
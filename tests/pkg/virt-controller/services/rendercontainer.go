@@ -31,6 +31,7 @@ type ContainerSpecRenderer struct {
 	ports           []k8sv1.ContainerPort
 	capabilities    *k8sv1.Capabilities
 	args            []string
+	seccompProfile  *k8sv1.SeccompProfile
 }
 
 type Option func(*ContainerSpecRenderer)
@@ -48,11 +49,13 @@ func NewContainerSpecRenderer(containerName string, launcherImg string, imgPullP
 }
 
 func (csr *ContainerSpecRenderer) Render(cmd []string) k8sv1.Container {
+	secCtx := securityContext(csr.userID, csr.isPrivileged, csr.capabilities)
+	secCtx.SeccompProfile = csr.seccompProfile
 	return k8sv1.Container{
 		Name:            csr.name,
 		Image:           csr.launcherImg,
 		ImagePullPolicy: csr.imgPullPolicy,
-		SecurityContext: securityContext(csr.userID, csr.isPrivileged, csr.capabilities),
+		SecurityContext: secCtx,
 		Command:         cmd,
 		VolumeDevices:   csr.volumeDevices,
 		VolumeMounts:    csr.volumeMounts,
@@ -117,6 +120,12 @@ func WithPorts(vmi *v1.VirtualMachineInstance) Option {
 	}
 }
 
+func WithSeccompProfile(seccompProfile *k8sv1.SeccompProfile) Option {
+	return func(renderer *ContainerSpecRenderer) {
+		renderer.seccompProfile = seccompProfile
+	}
+}
+
 func WithArgs(args []string) Option {
 	return func(renderer *ContainerSpecRenderer) {
 		renderer.args = args
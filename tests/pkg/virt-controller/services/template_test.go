@@ -42,6 +42,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 
+	exportv1 "kubevirt.io/api/export/v1alpha1"
+
 	k6tconfig "kubevirt.io/kubevirt/pkg/config"
 
 	v1 "kubevirt.io/api/core/v1"
@@ -3391,6 +3393,35 @@ var _ = Describe("Template", func() {
 		})
 	})
 
+	Describe("RenderExporterManifest", func() {
+
+		It("Should set readiness and liveness probes and a termination grace period", func() {
+			config, kvInformer, svc = configFactory(defaultArch)
+			vmExport := &exportv1.VirtualMachineExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-export",
+					Namespace: "default",
+				},
+			}
+
+			pod := svc.RenderExporterManifest(vmExport, "virt-exporter")
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			container := pod.Spec.Containers[0]
+
+			Expect(container.ReadinessProbe).ToNot(BeNil())
+			Expect(container.ReadinessProbe.HTTPGet).ToNot(BeNil())
+			Expect(container.ReadinessProbe.HTTPGet.Scheme).To(Equal(kubev1.URISchemeHTTPS))
+			Expect(container.ReadinessProbe.HTTPGet.Port).To(Equal(intstr.FromInt(8443)))
+
+			Expect(container.LivenessProbe).ToNot(BeNil())
+			Expect(container.LivenessProbe.HTTPGet).ToNot(BeNil())
+			Expect(container.LivenessProbe.HTTPGet.Scheme).To(Equal(kubev1.URISchemeHTTPS))
+
+			Expect(pod.Spec.TerminationGracePeriodSeconds).ToNot(BeNil())
+			Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeNumerically(">", 0))
+		})
+	})
+
 	Describe("ServiceAccountName", func() {
 
 		It("Should add service account if present", func() {
@@ -177,6 +177,16 @@ func (_mr *_MockConnectionRecorder) GetAllDomainStats(arg0, arg1 interface{}) *g
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetAllDomainStats", arg0, arg1)
 }
 
+func (_m *MockConnection) DomainRestoreFlags(srcFile string, xmlConf string, flags libvirt.DomainSaveRestoreFlags) error {
+	ret := _m.ctrl.Call(_m, "DomainRestoreFlags", srcFile, xmlConf, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockConnectionRecorder) DomainRestoreFlags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DomainRestoreFlags", arg0, arg1, arg2)
+}
+
 func (_m *MockConnection) GetDomainStats(statsTypes libvirt.DomainStatsTypes, l *stats.DomainJobInfo, flags libvirt.ConnectGetAllDomainStatsFlags) ([]*stats.DomainStats, error) {
 	ret := _m.ctrl.Call(_m, "GetDomainStats", statsTypes, l, flags)
 	ret0, _ := ret[0].([]*stats.DomainStats)
@@ -593,3 +603,13 @@ func (_m *MockVirDomain) CoreDumpWithFormat(to string, format libvirt.DomainCore
 func (_mr *_MockVirDomainRecorder) CoreDumpWithFormat(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CoreDumpWithFormat", arg0, arg1, arg2)
 }
+
+func (_m *MockVirDomain) SaveFlags(destFile string, destXml string, flags libvirt.DomainSaveRestoreFlags) error {
+	ret := _m.ctrl.Call(_m, "SaveFlags", destFile, destXml, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirDomainRecorder) SaveFlags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SaveFlags", arg0, arg1, arg2)
+}
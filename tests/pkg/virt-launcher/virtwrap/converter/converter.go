@@ -171,22 +171,24 @@ func Convert_v1_Disk_To_api_Disk(c *ConverterContext, diskDevice *v1.Disk, disk
 			disk.Model = translateModel(c, "virtio")
 		}
 		disk.ReadOnly = toApiReadOnly(diskDevice.Disk.ReadOnly)
-		disk.Serial = diskDevice.Serial
-		if diskDevice.Shareable != nil {
-			if *diskDevice.Shareable {
-				if diskDevice.Cache == "" {
-					diskDevice.Cache = v1.CacheNone
-				}
-				if diskDevice.Cache != v1.CacheNone {
-					return fmt.Errorf("a sharable disk requires cache = none got: %v", diskDevice.Cache)
-				}
-				disk.Shareable = &api.Shareable{}
-			}
-		}
 	} else if diskDevice.LUN != nil {
+		var unit int
 		disk.Device = "lun"
 		disk.Target.Bus = diskDevice.LUN.Bus
-		disk.Target.Device, _ = makeDeviceName(diskDevice.Name, diskDevice.LUN.Bus, prefixMap)
+		if diskDevice.LUN.Bus == "scsi" {
+			// Ensure we assign this disk to the correct scsi controller
+			if disk.Address == nil {
+				disk.Address = &api.Address{}
+			}
+			disk.Address.Type = "drive"
+			// This should be the index of the virtio-scsi controller, which is hard coded to 0
+			disk.Address.Controller = "0"
+			disk.Address.Bus = "0"
+		}
+		disk.Target.Device, unit = makeDeviceName(diskDevice.Name, diskDevice.LUN.Bus, prefixMap)
+		if diskDevice.LUN.Bus == "scsi" {
+			disk.Address.Unit = strconv.Itoa(unit)
+		}
 		disk.ReadOnly = toApiReadOnly(diskDevice.LUN.ReadOnly)
 	} else if diskDevice.CDRom != nil {
 		disk.Device = "cdrom"
@@ -199,6 +201,20 @@ func Convert_v1_Disk_To_api_Disk(c *ConverterContext, diskDevice *v1.Disk, disk
 			disk.ReadOnly = toApiReadOnly(true)
 		}
 	}
+	if diskDevice.Disk != nil || diskDevice.LUN != nil {
+		disk.Serial = diskDevice.Serial
+		if diskDevice.Shareable != nil {
+			if *diskDevice.Shareable {
+				if diskDevice.Cache == "" {
+					diskDevice.Cache = v1.CacheNone
+				}
+				if diskDevice.Cache != v1.CacheNone {
+					return fmt.Errorf("a sharable disk requires cache = none got: %v", diskDevice.Cache)
+				}
+				disk.Shareable = &api.Shareable{}
+			}
+		}
+	}
 	disk.Driver = &api.DiskDriver{
 		Name:        "qemu",
 		Cache:       string(diskDevice.Cache),
@@ -584,7 +600,7 @@ func Convert_v1_Volume_To_api_Disk(source *v1.Volume, disk *api.Disk, c *Convert
 	}
 
 	if source.HostDisk != nil {
-		return Convert_v1_HostDisk_To_api_Disk(source.Name, source.HostDisk.Path, disk)
+		return Convert_v1_HostDisk_To_api_Disk(source.Name, source.HostDisk, disk)
 	}
 
 	if source.PersistentVolumeClaim != nil {
@@ -758,11 +774,14 @@ func Convert_v1_Hotplug_BlockVolumeSource_To_api_Disk(volumeName string, disk *a
 	return nil
 }
 
-func Convert_v1_HostDisk_To_api_Disk(volumeName string, path string, disk *api.Disk) error {
+func Convert_v1_HostDisk_To_api_Disk(volumeName string, hostDisk *v1.HostDisk, disk *api.Disk) error {
 	disk.Type = "file"
 	disk.Driver.Type = "raw"
+	if hostDisk.Qcow2 != nil && *hostDisk.Qcow2 {
+		disk.Driver.Type = "qcow2"
+	}
 	disk.Driver.ErrorPolicy = "stop"
-	disk.Source.File = hostdisk.GetMountedHostDiskPath(volumeName, path)
+	disk.Source.File = hostdisk.GetMountedHostDiskPath(volumeName, hostDisk.Path)
 	return nil
 }
 
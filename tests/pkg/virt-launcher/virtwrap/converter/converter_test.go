@@ -137,6 +137,38 @@ var _ = Describe("Converter", func() {
 			Entry("Lower request than capacity", int64(1111), int64(9999)),
 		)
 
+		It("Should use the qcow2 driver for a HostDisk requesting a qcow2-backed image", func() {
+			apiDisk := api.Disk{}
+			qcow2 := true
+			hostDisk := &v1.HostDisk{
+				Path:  "/var/run/kubevirt-private/vmi-disks/myvolume/disk.qcow2",
+				Type:  v1.HostDiskExistsOrCreate,
+				Qcow2: &qcow2,
+			}
+			Expect(Convert_v1_HostDisk_To_api_Disk("myvolume", hostDisk, &apiDisk)).To(Succeed())
+			Expect(apiDisk.Driver.Type).To(Equal("qcow2"))
+		})
+
+		It("Should assign a scsi controller address and propagate serial for a hotplugged LUN disk", func() {
+			context := &ConverterContext{}
+			v1Disk := v1.Disk{
+				Name:   "mylun",
+				Serial: "SN-12345",
+				DiskDevice: v1.DiskDevice{
+					LUN: &v1.LunTarget{Bus: "scsi"},
+				},
+			}
+			apiDisk := api.Disk{}
+			devicePerBus := map[string]deviceNamer{}
+			Expect(Convert_v1_Disk_To_api_Disk(context, &v1Disk, &apiDisk, devicePerBus, nil, make(map[string]v1.VolumeStatus))).To(Succeed())
+			Expect(apiDisk.Device).To(Equal("lun"))
+			Expect(apiDisk.Serial).To(Equal("SN-12345"))
+			Expect(apiDisk.Address).ToNot(BeNil())
+			Expect(apiDisk.Address.Type).To(Equal("drive"))
+			Expect(apiDisk.Address.Controller).To(Equal("0"))
+			Expect(apiDisk.Address.Bus).To(Equal("0"))
+		})
+
 		It("Should add boot order when provided", func() {
 			order := uint(1)
 			kubevirtDisk := &v1.Disk{
@@ -290,3 +290,23 @@ func (_m *MockDomainManager) MemoryDump(vmi *v1.VirtualMachineInstance, dumpPath
 func (_mr *_MockDomainManagerRecorder) MemoryDump(arg0, arg1 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "MemoryDump", arg0, arg1)
 }
+
+func (_m *MockDomainManager) HibernateVMI(vmi *v1.VirtualMachineInstance, destFile string) error {
+	ret := _m.ctrl.Call(_m, "HibernateVMI", vmi, destFile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDomainManagerRecorder) HibernateVMI(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "HibernateVMI", arg0, arg1)
+}
+
+func (_m *MockDomainManager) ResumeVMIFromHibernation(vmi *v1.VirtualMachineInstance, srcFile string) error {
+	ret := _m.ctrl.Call(_m, "ResumeVMIFromHibernation", vmi, srcFile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDomainManagerRecorder) ResumeVMIFromHibernation(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ResumeVMIFromHibernation", arg0, arg1)
+}
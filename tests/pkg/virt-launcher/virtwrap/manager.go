@@ -37,8 +37,11 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"golang.org/x/sys/unix"
+
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/device/hostdevice/generic"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/device/hostdevice/gpu"
 
@@ -125,6 +128,11 @@ type DomainManager interface {
 	Exec(string, string, []string, int32) (string, error)
 	GuestPing(string) error
 	MemoryDump(vmi *v1.VirtualMachineInstance, dumpPath string) error
+	// HibernateVMI and ResumeVMIFromHibernation are the save/restore-to-file primitives a
+	// hibernate-to-PVC VirtualMachine API would be built on; they do not by themselves move the
+	// domain across nodes or manage the backing PVC.
+	HibernateVMI(vmi *v1.VirtualMachineInstance, destFile string) error
+	ResumeVMIFromHibernation(vmi *v1.VirtualMachineInstance, srcFile string) error
 }
 
 type LibvirtDomainManager struct {
@@ -1070,11 +1078,51 @@ func isHotplugBlockDeviceVolumeFunc(volumeName string) bool {
 
 var isBlockDeviceVolume = isBlockDeviceVolumeFunc
 
+// sysBlockDevDir is the sysfs directory holding per-device-number metadata, overridable for unit tests.
+var sysBlockDevDir = "/sys/dev/block"
+
+// isClaimedByMultipath checks whether the block device at path is an individual SCSI path that a
+// dm-multipath map has already claimed as one of its members, e.g. /dev/sdb while /dev/dm-3 (the
+// multipath aggregate) is the device that should actually be used so that I/O keeps failing over
+// across every physical path instead of pinning the guest to just this one.
+func isClaimedByMultipath(path string) (bool, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false, fmt.Errorf("unable to determine device number for %v", path)
+	}
+	return hasMultipathHolder(sysBlockDevDir, unix.Major(uint64(stat.Rdev)), unix.Minor(uint64(stat.Rdev)))
+}
+
+// hasMultipathHolder reports whether the device identified by major:minor has a dm-multipath map
+// listed among its sysfs holders, meaning some other device node is the one that should be used.
+func hasMultipathHolder(sysBlockDevDir string, major, minor uint32) (bool, error) {
+	holders, err := ioutil.ReadDir(filepath.Join(sysBlockDevDir, fmt.Sprintf("%d:%d", major, minor), "holders"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, holder := range holders {
+		if strings.HasPrefix(holder.Name(), "dm-") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func isBlockDeviceVolumeFunc(volumeName string) (bool, error) {
 	path := converter.GetBlockDeviceVolumePath(volumeName)
 	fileInfo, err := os.Stat(path)
 	if err == nil {
 		if (fileInfo.Mode() & os.ModeDevice) != 0 {
+			if claimed, multipathErr := isClaimedByMultipath(path); multipathErr == nil && claimed {
+				return false, fmt.Errorf("%v is an individual path already claimed by a dm-multipath device, refusing to use it directly so the guest does not lose path redundancy", path)
+			}
 			return true, nil
 		}
 		return false, fmt.Errorf("found %v, but it's not a block device", path)
@@ -1363,6 +1411,47 @@ func (l *LibvirtDomainManager) UnpauseVMI(vmi *v1.VirtualMachineInstance) error
 	return nil
 }
 
+func (l *LibvirtDomainManager) HibernateVMI(vmi *v1.VirtualMachineInstance, destFile string) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	logger := log.Log.Object(vmi)
+
+	domName := util.VMINamespaceKeyFunc(vmi)
+	dom, err := l.virConn.LookupDomainByName(domName)
+	if err != nil {
+		if domainerrors.IsNotFound(err) {
+			return fmt.Errorf("Domain not found.")
+		}
+		logger.Reason(err).Error("Getting the domain failed during hibernate.")
+		return err
+	}
+	defer dom.Free()
+
+	logger.Infof("Saving domain state to %s", destFile)
+	if err := dom.SaveFlags(destFile, "", libvirt.DOMAIN_SAVE_RUNNING); err != nil {
+		logger.Reason(err).Error("Saving domain state failed.")
+		return err
+	}
+	logger.Infof("Completed domain state save to %s", destFile)
+	return nil
+}
+
+func (l *LibvirtDomainManager) ResumeVMIFromHibernation(vmi *v1.VirtualMachineInstance, srcFile string) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	logger := log.Log.Object(vmi)
+
+	logger.Infof("Restoring domain state from %s", srcFile)
+	if err := l.virConn.DomainRestoreFlags(srcFile, "", libvirt.DOMAIN_SAVE_RUNNING); err != nil {
+		logger.Reason(err).Error("Restoring domain state failed.")
+		return err
+	}
+	logger.Infof("Completed domain state restore from %s", srcFile)
+	return nil
+}
+
 func (l *LibvirtDomainManager) scheduleSafetyVMIUnfreeze(vmi *v1.VirtualMachineInstance, unfreezeTimeout time.Duration) {
 	select {
 	case <-time.After(unfreezeTimeout):
@@ -18,8 +18,9 @@ import (
 )
 
 const (
-	VirtHandlerName = "virt-handler"
-	kubeletPodsPath = "/var/lib/kubelet/pods"
+	VirtHandlerName    = "virt-handler"
+	kubeletPodsPath    = "/var/lib/kubelet/pods"
+	kubeletSeccompPath = "/var/lib/kubelet/seccomp"
 )
 
 func NewHandlerDaemonSet(namespace string, repository string, imagePrefix string, version string, launcherVersion string, productName string, productVersion string, productComponent string, pullPolicy corev1.PullPolicy, migrationNetwork *string, verbosity string, extraEnv map[string]string) (*appsv1.DaemonSet, error) {
@@ -224,6 +225,7 @@ func NewHandlerDaemonSet(namespace string, repository string, imagePrefix string
 		{"kubelet-pods-shortened", kubeletPodsPath, "/pods", nil},
 		{"kubelet-pods", kubeletPodsPath, kubeletPodsPath, &bidi},
 		{"node-labeller", "/var/lib/kubevirt-node-labeller", "/var/lib/kubevirt-node-labeller", nil},
+		{"kubelet-seccomp", kubeletSeccompPath, kubeletSeccompPath, nil},
 	}
 
 	for _, volume := range volumes {
@@ -180,6 +180,17 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"get", "list", "watch", "delete", "update", "create", "patch",
 				},
 			},
+			{
+				APIGroups: []string{
+					"networking.k8s.io",
+				},
+				Resources: []string{
+					"networkpolicies",
+				},
+				Verbs: []string{
+					"get", "list", "watch", "delete", "update", "create", "patch",
+				},
+			},
 			{
 				APIGroups: []string{
 					"",
@@ -283,6 +294,17 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"get", "list", "watch", "create", "update", "delete", "patch",
 				},
 			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"persistentvolumes",
+				},
+				Verbs: []string{
+					"get", "list", "watch",
+				},
+			},
 			{
 				APIGroups: []string{
 					"snapshot.kubevirt.io",